@@ -6,10 +6,15 @@ import (
 
 	"ai-notetaking-be/internal/model"
 	"ai-notetaking-be/pkg/database"
+	"ai-notetaking-be/pkg/lexical"
 
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
+const backfillBatchSize = 500
+
 func main() {
 	// 1. Load Environment Variables
 	if err := godotenv.Load(); err != nil {
@@ -65,12 +70,22 @@ func main() {
 		&model.Notebook{},
 		&model.Note{},
 		&model.NoteEmbedding{},
+		&model.EmbeddingJob{}, // Durable embedding queue (outbox)
+		&model.NoteRevision{}, // Append-only note history
+		&model.Tag{},
+		&model.NoteTag{},   // Note <-> Tag join table
+		&model.NoteLink{},  // Note -> Note links parsed from content
+		&model.ImportJob{}, // Markdown vault imports
+		&model.ShareLink{}, // Public read-only share links
+		&model.NotebookMember{},
+		&model.NoteAttachment{}, // Files attached to notes (content lives in storage)
 		&model.ChatSession{},
 		&model.ChatMessage{},
 		&model.ChatMessage{},
 		&model.ChatMessageRaw{},
 		&model.ChatMessageReference{}, // NEW: Persistent User References
 		&model.ChatCitation{},         // NEW: RAG Citations
+		&model.ChatToolCall{},         // Tool calls of agent mode answers
 		&model.Feature{},
 		&model.SubscriptionPlan{},
 		&model.SubscriptionPlanFeature{}, // Explicit Join Table
@@ -113,6 +128,19 @@ func main() {
 		 FROM notes n JOIN note_embeddings ne ON n.id = ne.note_id
 		 WHERE n.deleted_at IS NULL;`,
 
+		// Full-text search: weighted tsvector over title (A) + markup-free content (B)
+		`ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector tsvector
+		 GENERATED ALWAYS AS (
+		   setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+		   setweight(to_tsvector('simple', coalesce(search_text, '')), 'B')
+		 ) STORED;`,
+		`CREATE INDEX IF NOT EXISTS idx_notes_search_vector ON notes USING GIN (search_vector);`,
+
+		// Tag names are unique per user regardless of case
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags (user_id, LOWER(name));`,
+		// One membership (or pending invitation) per email and notebook
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_notebook_members_notebook_email ON notebook_members (notebook_id, LOWER(email));`,
+
 		// View: user_payment_history
 		`CREATE OR REPLACE VIEW user_payment_history AS
 		 SELECT us.user_id, u.full_name, sp.name AS plan_name, sp.price, us.payment_status, us.midtrans_transaction_id, us.created_at AS payment_date
@@ -128,5 +156,48 @@ func main() {
 		}
 	}
 
+	// 6. Backfill search_text for notes written before it existed
+	log.Println("Step 4: Backfilling note search text...")
+	if err := backfillSearchText(db); err != nil {
+		log.Fatalf("Error: search_text backfill failed: %v", err)
+	}
+
 	log.Println("✅ Success: Database migration completed successfully via GORM.")
 }
+
+// backfillSearchText fills search_text for notes saved before the column existed, using the same
+// extraction as note saves. It works row by row, so content that is not valid Lexical JSON is
+// indexed as plain text instead of failing the whole statement.
+func backfillSearchText(db *gorm.DB) error {
+	type noteContent struct {
+		Id      uuid.UUID
+		Content string
+	}
+
+	total := 0
+	for {
+		var rows []noteContent
+		if err := db.Table("notes").Select("id, COALESCE(content, '') AS content").Where("search_text IS NULL").Limit(backfillBatchSize).Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, row := range rows {
+				if err := tx.Table("notes").Where("id = ?", row.Id).UpdateColumn("search_text", lexical.ExtractText(row.Content)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		total += len(rows)
+	}
+
+	log.Printf("Backfilled search_text for %d notes", total)
+	return nil
+}
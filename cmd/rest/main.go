@@ -35,6 +35,14 @@ func main() {
 			log.Printf("Background Consumer Error: %v", err)
 		}
 	}()
+	// Imports run in-process; any left unfinished by the previous run cannot resume
+	if err := container.ImportService.FailInterrupted(context.Background()); err != nil {
+		log.Printf("Failed to close interrupted imports: %v", err)
+	}
+	go func() {
+		log.Println("Background: Starting Trash Purger...")
+		container.TrashService.Run(context.Background())
+	}()
 
 	// 5. Initialize Server
 	srv := server.New(cfg, container)
@@ -7,6 +7,7 @@ import (
 
 	"ai-notetaking-be/internal/model"
 	"ai-notetaking-be/pkg/database"
+	"ai-notetaking-be/pkg/rag/history"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -67,6 +68,28 @@ func seedConfigurations(db *gorm.DB) {
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
+		{
+			Id:          uuid.New(),
+			Key:         "embedding_chunk_size",
+			Value:       "1500",
+			ValueType:   "number",
+			Description: "Maximum characters per embedded note chunk",
+			Category:    "rag",
+			IsSecret:    false,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		},
+		{
+			Id:          uuid.New(),
+			Key:         "embedding_chunk_overlap",
+			Value:       "200",
+			ValueType:   "number",
+			Description: "Characters carried over between consecutive chunks of the same section",
+			Category:    "rag",
+			IsSecret:    false,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		},
 		{
 			Id:          uuid.New(),
 			Key:         "llm_default_model",
@@ -89,6 +112,28 @@ func seedConfigurations(db *gorm.DB) {
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
+		{
+			Id:          uuid.New(),
+			Key:         "chat_history_token_budget",
+			Value:       "3000",
+			ValueType:   "number",
+			Description: "Estimated tokens of chat history sent to the LLM before older turns are folded into a running summary",
+			Category:    "llm",
+			IsSecret:    false,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		},
+		{
+			Id:          uuid.New(),
+			Key:         "chat_summary_prompt",
+			Value:       history.DefaultSummaryPrompt,
+			ValueType:   "string",
+			Description: "Instructions for merging older chat turns into the running conversation summary",
+			Category:    "llm",
+			IsSecret:    false,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		},
 		{
 			Id:          uuid.New(),
 			Key:         "bypass_enabled",
@@ -29,9 +29,17 @@ func (m *MockLLMProvider) Chat(ctx context.Context, history []llm.Message, optio
 	return "mock chat", nil
 }
 
+func (m *MockLLMProvider) ChatStream(ctx context.Context, history []llm.Message, onDelta llm.StreamHandler, options ...llm.Option) (string, error) {
+	return "mock chat", onDelta("mock chat")
+}
+
 // MockEmbeddingProvider for Grounder constructor
 type MockEmbeddingProvider struct{}
 
+func (m *MockEmbeddingProvider) ModelName() string {
+	return "mock-embedding"
+}
+
 func (m *MockEmbeddingProvider) Generate(text string, taskType string) (*embedding.EmbeddingResponse, error) {
 	return &embedding.EmbeddingResponse{
 		Embedding: embedding.EmbeddingResponseEmbedding{
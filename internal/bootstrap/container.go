@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"context"
 	"log"
+	"time"
 
 	"ai-notetaking-be/internal/config"
 	"ai-notetaking-be/internal/controller"
@@ -11,6 +12,7 @@ import (
 	"ai-notetaking-be/internal/pkg/mailer"
 	"ai-notetaking-be/internal/repository/implementation"
 	"ai-notetaking-be/internal/repository/memory"
+	"ai-notetaking-be/internal/repository/redisstore"
 	"ai-notetaking-be/internal/repository/unitofwork"
 	"ai-notetaking-be/internal/service"
 	"ai-notetaking-be/internal/websocket"
@@ -18,6 +20,7 @@ import (
 	"ai-notetaking-be/pkg/admin/dashboard"
 	adminEvents "ai-notetaking-be/pkg/admin/events"
 	"ai-notetaking-be/pkg/admin/feature"
+	"ai-notetaking-be/pkg/admin/indexing"
 	"ai-notetaking-be/pkg/admin/plan"
 	"ai-notetaking-be/pkg/admin/refund"
 	"ai-notetaking-be/pkg/admin/subscription"
@@ -26,6 +29,8 @@ import (
 	"ai-notetaking-be/pkg/embedding"
 	"ai-notetaking-be/pkg/embedding/jina"
 	"ai-notetaking-be/pkg/llm/factory"
+	"ai-notetaking-be/pkg/storage"
+	"ai-notetaking-be/pkg/store"
 
 	pktNats "ai-notetaking-be/pkg/nats"
 
@@ -37,19 +42,27 @@ import (
 
 type Container struct {
 	// Controllers
-	NotebookController controller.INotebookController
-	NoteController     controller.INoteController
-	UserController     controller.IUserController
-	AuthController     controller.IAuthController
-	OAuthController    controller.IOAuthController
-	AdminController    controller.IAdminController
-	PaymentController  controller.IPaymentController
-	ChatbotController  controller.IChatbotController
-	LocationController controller.ILocationController
-	PlanController     controller.PlanController
+	NotebookController   controller.INotebookController
+	NoteController       controller.INoteController
+	UserController       controller.IUserController
+	AuthController       controller.IAuthController
+	OAuthController      controller.IOAuthController
+	AdminController      controller.IAdminController
+	PaymentController    controller.IPaymentController
+	ChatbotController    controller.IChatbotController
+	LocationController   controller.ILocationController
+	PlanController       controller.PlanController
+	TrashController      controller.ITrashController
+	TagController        controller.ITagController
+	ShareController      controller.IShareController
+	ImportController     controller.IImportController
+	MemberController     controller.INotebookMemberController
+	AttachmentController controller.IAttachmentController
 
 	// Background Services (Exposed for main.go to run)
 	ConsumerService service.IConsumerService
+	TrashService    service.ITrashService
+	ImportService   service.IImportService
 
 	// WebSockets & Notification
 	NotificationHandler *handler.NotificationHandler
@@ -106,9 +119,6 @@ func NewContainer(db *gorm.DB, cfg *config.Config) *Container {
 	}
 	log.Printf("[INFO] Using LLM Provider: %s (%s)", cfg.Ai.LLMProvider, cfg.Ai.LLMModel)
 
-	// Initialize In-Memory Session Storage
-	sessionRepo := memory.NewSessionRepository()
-
 	// 2.5 Infrastructure (Moved up for dependency injection)
 	// NATS
 	natsPub, err := pktNats.NewPublisher(cfg.App.NatsURL)
@@ -129,14 +139,40 @@ func NewContainer(db *gorm.DB, cfg *config.Config) *Container {
 		}
 	}
 	rdb := redis.NewClient(opt)
-	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
-		log.Printf("[WARN] Failed to connect to Redis: %v", err)
+	redisErr := rdb.Ping(context.Background()).Err()
+	if redisErr != nil {
+		log.Printf("[WARN] Failed to connect to Redis: %v", redisErr)
 	}
 
-	// WebSocket Hub
-	wsLogger := logger.NewIsolatedLogger("logs/notification.log")
-	wsHub := websocket.NewHub(rdb, wsLogger)
-	go wsHub.Run()
+	// RAG conversation state (rebuilt from the chat history when missing)
+	sessionTTL := time.Duration(cfg.Ai.SessionTTLHours) * time.Hour
+	var sessionRepo store.SessionStore
+	if cfg.Ai.SessionStore == "redis" && redisErr == nil {
+		sessionRepo = redisstore.NewSessionRepository(rdb, sessionTTL)
+	} else {
+		if cfg.Ai.SessionStore == "redis" {
+			log.Printf("[WARN] Redis unavailable, keeping chat session state in memory")
+		}
+		sessionRepo = memory.NewSessionRepository(sessionTTL)
+	}
+
+	// Attachment Storage
+	var store storage.Storage = storage.NewLocal(cfg.Storage.LocalPath)
+	if cfg.Storage.Driver == "s3" {
+		s3Store, err := storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PathStyle: cfg.Storage.S3PathStyle,
+		})
+		if err != nil {
+			log.Fatalf("[FATAL] Failed to initialize S3 storage: %v", err)
+		}
+		store = s3Store
+	}
+	log.Printf("[INFO] Using Attachment Storage: %s", cfg.Storage.Driver)
 
 	publisherService := service.NewPublisherService(cfg.Keys.ExampleTopic, pubSub)
 	consumerService := service.NewConsumerService(
@@ -150,20 +186,39 @@ func NewContainer(db *gorm.DB, cfg *config.Config) *Container {
 	authService := service.NewAuthService(uowFactory, emailService, natsPub)
 	oauthService := service.NewOAuthService(uowFactory)
 
+	planService := service.NewPlanService(uowFactory)
 	notebookService := service.NewNotebookService(uowFactory, publisherService)
 	noteService := service.NewNoteService(
 		uowFactory,
 		publisherService,
 		embeddingProvider, // Injected
 		natsPub,
+		planService,
+	)
+
+	trashService := service.NewTrashService(
+		uowFactory,
+		publisherService,
+		store,
+		time.Duration(cfg.App.TrashRetentionDays)*24*time.Hour,
 	)
 
+	tagService := service.NewTagService(uowFactory)
+	shareService := service.NewShareService(uowFactory, cfg.App.ClientURL)
+	memberService := service.NewNotebookMemberService(uowFactory, emailService)
+
 	chatbotService := service.NewChatbotService(
 		uowFactory,
 		embeddingProvider, // Injected
 		llmProvider,       // Injected
 		sessionRepo,       // Injected
+		noteService,
 	)
+
+	// WebSocket Hub (notifications, note rooms and chat streaming; joining a room is checked by the note service)
+	wsLogger := logger.NewIsolatedLogger("logs/notification.log")
+	wsHub := websocket.NewHub(rdb, wsLogger, noteService, controller.NewChatStreamer(chatbotService))
+	go wsHub.Run()
 	paymentService := service.NewPaymentService(uowFactory, natsPub)
 
 	// Admin Domain Components
@@ -176,6 +231,7 @@ func NewContainer(db *gorm.DB, cfg *config.Config) *Container {
 	usageTracker := usage.NewTracker(sysLogger, adminEventPublisher)
 	dashboardAggregator := dashboard.NewAggregator(sysLogger)
 	aiConfigManager := aiconfig.NewManager()
+	indexingManager := indexing.NewManager(embeddingProvider.ModelName())
 
 	adminService := service.NewAdminService(
 		uowFactory,
@@ -189,10 +245,20 @@ func NewContainer(db *gorm.DB, cfg *config.Config) *Container {
 		dashboardAggregator,
 		adminEventPublisher,
 		aiConfigManager,
+		indexingManager,
+		store,
 	)
 
 	locationService := service.NewLocationService(cfg.Keys.Geoapify, cfg.Keys.Binderbyte)
-	planService := service.NewPlanService(uowFactory)
+	importService := service.NewImportService(uowFactory, planService, publisherService)
+	attachmentService := service.NewAttachmentService(
+		uowFactory,
+		store,
+		planService,
+		publisherService,
+		cfg.App.BaseURL,
+		cfg.Storage.MaxAttachmentMB,
+	)
 
 	// 3.5 Notification System Infrastructure
 	// Notification Domain
@@ -210,19 +276,27 @@ func NewContainer(db *gorm.DB, cfg *config.Config) *Container {
 	// 4. Controllers
 	// Note: We return the container with public fields for the server to register
 	return &Container{
-		NotificationHandler: notifHandler,
-		WebSocketHub:        wsHub,
-		NotebookController:  controller.NewNotebookController(notebookService),
-		NoteController:      controller.NewNoteController(noteService),
-		UserController:      controller.NewUserController(userService),
-		AuthController:      controller.NewAuthController(authService),
-		OAuthController:     controller.NewOAuthController(oauthService),
-		AdminController:     controller.NewAdminController(adminService, authService),
-		PaymentController:   controller.NewPaymentController(paymentService),
-		ChatbotController:   controller.NewChatbotController(chatbotService),
-		LocationController:  controller.NewLocationController(locationService),
-		PlanController:      controller.NewPlanController(planService),
+		NotificationHandler:  notifHandler,
+		WebSocketHub:         wsHub,
+		NotebookController:   controller.NewNotebookController(notebookService),
+		NoteController:       controller.NewNoteController(noteService),
+		UserController:       controller.NewUserController(userService),
+		AuthController:       controller.NewAuthController(authService),
+		OAuthController:      controller.NewOAuthController(oauthService),
+		AdminController:      controller.NewAdminController(adminService, authService),
+		PaymentController:    controller.NewPaymentController(paymentService),
+		ChatbotController:    controller.NewChatbotController(chatbotService),
+		LocationController:   controller.NewLocationController(locationService),
+		PlanController:       controller.NewPlanController(planService),
+		TrashController:      controller.NewTrashController(trashService),
+		TagController:        controller.NewTagController(tagService),
+		ShareController:      controller.NewShareController(shareService),
+		ImportController:     controller.NewImportController(importService),
+		MemberController:     controller.NewNotebookMemberController(memberService),
+		AttachmentController: controller.NewAttachmentController(attachmentService),
 
 		ConsumerService: consumerService,
+		TrashService:    trashService,
+		ImportService:   importService,
 	}
 }
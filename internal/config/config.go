@@ -14,6 +14,7 @@ type Config struct {
 	SMTP     SMTPConfig
 	Keys     APIKeys
 	Ai       AIConfig
+	Storage  StorageConfig
 }
 
 type AppConfig struct {
@@ -25,6 +26,20 @@ type AppConfig struct {
 	CorsAllowedOrigins string
 	NatsURL            string
 	RedisURL           string
+	TrashRetentionDays int // Trashed notes and notebooks are purged after this many days
+}
+
+// StorageConfig selects where note attachments are kept
+type StorageConfig struct {
+	Driver          string // "local" or "s3"
+	LocalPath       string // Root directory of the local driver; must not be served statically
+	MaxAttachmentMB int    // Per-file upload limit (requests are capped at 10MB by the server body limit)
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3PathStyle     bool
 }
 
 type DatabaseConfig struct {
@@ -55,6 +70,8 @@ type AIConfig struct {
 	OllamaModel       string
 	LLMProvider       string // "ollama", "openai", etc
 	LLMModel          string // e.g. "llama3", "qwen2.5"
+	SessionStore      string // "redis" or "memory": where RAG conversation state is kept
+	SessionTTLHours   int    // Idle conversation state expires after this many hours
 }
 
 func Load() *Config {
@@ -86,6 +103,7 @@ func Load() *Config {
 			CorsAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173"),
 			NatsURL:            getEnv("NATS_URL", "nats://localhost:4222"),
 			RedisURL:           getEnv("REDIS_URL", "redis://localhost:6379"),
+			TrashRetentionDays: getEnvAsInt("TRASH_RETENTION_DAYS", 30),
 		},
 		Database: DatabaseConfig{
 			Connection: dbConnString,
@@ -111,6 +129,19 @@ func Load() *Config {
 			OllamaModel:       getEnv("OLLAMA_EMBEDDING_MODEL", "nomic-embed-text"),
 			LLMProvider:       getEnv("LLM_PROVIDER", "ollama"),
 			LLMModel:          getEnv("LLM_MODEL", "llama3"),
+			SessionStore:      getEnv("CHAT_SESSION_STORE", "redis"),
+			SessionTTLHours:   getEnvAsInt("CHAT_SESSION_TTL_HOURS", 24),
+		},
+		Storage: StorageConfig{
+			Driver:          getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:       getEnv("STORAGE_LOCAL_PATH", "./storage"),
+			MaxAttachmentMB: getEnvAsInt("MAX_ATTACHMENT_MB", 10),
+			S3Endpoint:      getEnv("S3_ENDPOINT", ""),
+			S3Region:        getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:        getEnv("S3_BUCKET", ""),
+			S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle:     getEnv("S3_PATH_STYLE", "false") == "true",
 		},
 	}
 }
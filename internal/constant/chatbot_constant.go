@@ -214,3 +214,14 @@ Task: Answer the user's question using ONLY the provided notes.
 === NOTES DATABASE ===
 `
 )
+
+// Status of a tool call made in agent mode. Write tools stay pending until the user confirms
+// (done or failed) or rejects them; read tools are done or failed right away. A confirmed write
+// is confirming while it runs, so a second confirmation cannot run it again.
+const (
+	ChatToolCallStatusConfirming = "confirming"
+	ChatToolCallStatusDone       = "done"
+	ChatToolCallStatusFailed     = "failed"
+	ChatToolCallStatusPending    = "pending"
+	ChatToolCallStatusRejected   = "rejected"
+)
@@ -65,6 +65,10 @@ type IAdminController interface {
 	UpdateNuance(ctx *fiber.Ctx) error
 	DeleteNuance(ctx *fiber.Ctx) error
 
+	// Embedding Reindex
+	ReindexEmbeddings(ctx *fiber.Ctx) error
+	GetReindexProgress(ctx *fiber.Ctx) error
+
 	// Billing Management
 	GetUserBillingAddresses(ctx *fiber.Ctx) error
 	CreateBillingAddress(ctx *fiber.Ctx) error
@@ -206,6 +210,8 @@ func (c *adminController) RegisterRoutes(r fiber.Router) {
 	h.Post("/ai/nuances", c.CreateNuance)
 	h.Put("/ai/nuances/:id", c.UpdateNuance)
 	h.Delete("/ai/nuances/:id", c.DeleteNuance)
+	h.Post("/ai/reindex", c.ReindexEmbeddings)
+	h.Get("/ai/reindex/progress", c.GetReindexProgress)
 
 	// Billing Management
 	h.Get("/users/:id/billing", c.GetUserBillingAddresses)
@@ -914,6 +920,33 @@ func (c *adminController) DeleteNuance(ctx *fiber.Ctx) error {
 	return ctx.JSON(serverutils.SuccessResponse[any]("Nuance deleted", nil))
 }
 
+// --- Embedding Reindex Endpoints ---
+
+// ReindexEmbeddings queues notes for re-embedding (all, or only those on an old model)
+func (c *adminController) ReindexEmbeddings(ctx *fiber.Ctx) error {
+	var req dto.AdminReindexRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid request body"))
+		}
+	}
+
+	res, err := c.service.ReindexEmbeddings(ctx.Context(), req)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(serverutils.ErrorResponse(500, err.Error()))
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(serverutils.SuccessResponse("Reindex queued", res))
+}
+
+// GetReindexProgress returns embedding queue counts for tracking a reindex
+func (c *adminController) GetReindexProgress(ctx *fiber.Ctx) error {
+	res, err := c.service.GetReindexProgress(ctx.Context())
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(serverutils.ErrorResponse(500, err.Error()))
+	}
+	return ctx.JSON(serverutils.SuccessResponse("Reindex progress", res))
+}
+
 // --- Billing Management Endpoints ---
 
 // GetUserBillingAddresses returns all billing addresses for a user
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/pkg/serverutils"
+	"ai-notetaking-be/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IAttachmentController interface {
+	RegisterRoutes(r fiber.Router)
+	Upload(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	Download(ctx *fiber.Ctx) error
+}
+
+type attachmentController struct {
+	service service.IAttachmentService
+}
+
+func NewAttachmentController(service service.IAttachmentService) IAttachmentController {
+	return &attachmentController{service: service}
+}
+
+func (c *attachmentController) RegisterRoutes(r fiber.Router) {
+	h := r.Group("/attachment/v1")
+	h.Use(serverutils.JwtMiddleware)
+	h.Post("note/:noteId", c.Upload)
+	h.Get("note/:noteId", c.List)
+	h.Delete(":id", c.Delete)
+
+	// Authenticated by the signature of the URL handed out by List and Upload
+	p := r.Group("/public/attachment")
+	p.Get(":id", c.Download)
+}
+
+// Upload accepts a multipart form with the file in "file". The upload is bounded by the server body limit.
+func (c *attachmentController) Upload(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	noteId, err := uuid.Parse(ctx.Params("noteId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid note id"))
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Missing file"))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(serverutils.ErrorResponse(500, "Failed to open file"))
+	}
+	defer file.Close()
+
+	req := dto.UploadAttachmentRequest{FileName: fileHeader.Filename}
+	req.Data, err = io.ReadAll(file)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(serverutils.ErrorResponse(500, "Failed to read file"))
+	}
+
+	res, err := c.service.Upload(ctx.Context(), userId, noteId, &req)
+	if err != nil {
+		return attachmentErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(serverutils.SuccessWithCodeResponse("Success upload attachment", fiber.StatusCreated, res))
+}
+
+func (c *attachmentController) List(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	noteId, err := uuid.Parse(ctx.Params("noteId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid note id"))
+	}
+
+	res, err := c.service.List(ctx.Context(), userId, noteId)
+	if err != nil {
+		return attachmentErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get attachments", res))
+}
+
+func (c *attachmentController) Delete(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	if err := c.service.Delete(ctx.Context(), userId, id); err != nil {
+		return attachmentErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse[any]("Success delete attachment", nil))
+}
+
+// Download streams the file of a signed URL. Images and PDFs open inline, other files download.
+func (c *attachmentController) Download(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Attachment not found"))
+	}
+	expires, _ := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+
+	attachment, file, err := c.service.Open(ctx.Context(), id, expires, ctx.Query("signature"))
+	if err != nil {
+		return attachmentErrorResponse(ctx, err)
+	}
+
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") || attachment.ContentType == "application/pdf" {
+		disposition = "inline"
+	}
+
+	ctx.Set(fiber.HeaderContentType, attachment.ContentType)
+	ctx.Set(fiber.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	ctx.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return ctx.SendStream(file, int(attachment.Size))
+}
+
+func attachmentErrorResponse(ctx *fiber.Ctx, err error) error {
+	var limitErr *dto.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return ctx.Status(fiber.StatusForbidden).JSON(dto.LimitExceededResponse{
+			Success:   false,
+			Code:      403,
+			Message:   "Attachment storage limit exceeded",
+			ErrorType: "STORAGE_LIMIT_EXCEEDED",
+			Data: dto.LimitExceededData{
+				Limit:            limitErr.Limit,
+				Used:             limitErr.Used,
+				ShowModalPricing: true,
+			},
+		})
+	}
+
+	switch {
+	case errors.Is(err, service.ErrNoteNotFound), errors.Is(err, service.ErrAttachmentNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrNotebookAccessDenied):
+		return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, "Insufficient notebook role"))
+	case errors.Is(err, service.ErrAttachmentLinkInvalid):
+		return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, err.Error()))
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		return ctx.Status(fiber.StatusRequestEntityTooLarge).JSON(serverutils.ErrorResponse(413, err.Error()))
+	case errors.Is(err, service.ErrAttachmentEmpty), errors.Is(err, service.ErrAttachmentType):
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, err.Error()))
+	}
+	return err
+}
@@ -4,7 +4,13 @@ import (
 	"ai-notetaking-be/internal/dto"
 	"ai-notetaking-be/internal/pkg/serverutils"
 	"ai-notetaking-be/internal/service"
+	"ai-notetaking-be/internal/websocket"
+	"ai-notetaking-be/pkg/llm"
+	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -16,6 +22,16 @@ type IChatbotController interface {
 	GetAllSessions(ctx *fiber.Ctx) error
 	GetChatHistory(ctx *fiber.Ctx) error
 	SendChat(ctx *fiber.Ctx) error
+	SendChatStream(ctx *fiber.Ctx) error
+	RegenerateChat(ctx *fiber.Ctx) error
+	RegenerateChatStream(ctx *fiber.Ctx) error
+	EditChat(ctx *fiber.Ctx) error
+	EditChatStream(ctx *fiber.Ctx) error
+	SelectBranch(ctx *fiber.Ctx) error
+	SaveAsNote(ctx *fiber.Ctx) error
+	AppendToNote(ctx *fiber.Ctx) error
+	ConfirmToolCall(ctx *fiber.Ctx) error
+	RejectToolCall(ctx *fiber.Ctx) error
 	DeleteSession(ctx *fiber.Ctx) error
 	GetAvailableNuances(ctx *fiber.Ctx) error
 }
@@ -38,6 +54,16 @@ func (c *chatbotController) RegisterRoutes(r fiber.Router) {
 	h.Get("nuances", c.GetAvailableNuances) // NEW: Public nuance listing
 	h.Post("create-session", c.CreateSession)
 	h.Post("send-chat", c.SendChat)
+	h.Post("send-chat/stream", c.SendChatStream) // SSE: token* -> citations -> done
+	h.Post("regenerate-chat", c.RegenerateChat)
+	h.Post("regenerate-chat/stream", c.RegenerateChatStream)
+	h.Post("edit-chat", c.EditChat)
+	h.Post("edit-chat/stream", c.EditChatStream)
+	h.Post("select-branch", c.SelectBranch)
+	h.Post("messages/:id/save-as-note", c.SaveAsNote)
+	h.Post("messages/:id/append", c.AppendToNote)
+	h.Post("tool-calls/:id/confirm", c.ConfirmToolCall) // Runs a note write proposed in agent mode
+	h.Post("tool-calls/:id/reject", c.RejectToolCall)
 	h.Delete("delete-session", c.DeleteSession)
 }
 
@@ -102,30 +128,396 @@ func (c *chatbotController) SendChat(ctx *fiber.Ctx) error {
 	// ✅ PASS USER ID to Service for Guard Check
 	res, err := c.chatbotService.SendChat(ctx.Context(), userId, &request)
 	if err != nil {
-		// Check for limit exceeded error - return 429 with structured response
-		var limitErr *dto.LimitExceededError
-		if errors.As(err, &limitErr) {
-			return ctx.Status(fiber.StatusTooManyRequests).JSON(dto.LimitExceededResponse{
-				Success:   false,
-				Code:      429,
-				Message:   "Daily AI usage limit exceeded",
-				ErrorType: "LIMIT_EXCEEDED",
-				Data: dto.LimitExceededData{
-					Limit:            limitErr.Limit,
-					Used:             limitErr.Used,
-					ResetAfter:       limitErr.ResetAfter,
-					ShowModalPricing: true,
-				},
-			})
+		// Limit exceeded is a 429 with structured response, missing pro plan a 403
+		return chatErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success send chat", res))
+}
+
+// SendChatStream streams the AI reply as Server-Sent Events.
+// Events: "token" {delta}, then "citations" [CitationDTO], then "done" (SendChatResponse with persisted IDs).
+// Failures are reported as an "error" event carrying the same body SendChat would return.
+func (c *chatbotController) SendChatStream(ctx *fiber.Ctx) error {
+	var request dto.SendChatRequest
+
+	err := ctx.BodyParser(&request)
+	if err != nil {
+		return err
+	}
+
+	if err = serverutils.ValidateRequest(request); err != nil {
+		return err
+	}
+
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	return streamChat(ctx, func(streamCtx context.Context, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+		return c.chatbotService.SendChatStream(streamCtx, userId, &request, onDelta)
+	})
+}
+
+// RegenerateChat answers the question of a model message again as a new branch
+func (c *chatbotController) RegenerateChat(ctx *fiber.Ctx) error {
+	var request dto.RegenerateChatRequest
+
+	err := ctx.BodyParser(&request)
+	if err != nil {
+		return err
+	}
+
+	if err = serverutils.ValidateRequest(request); err != nil {
+		return err
+	}
+
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.chatbotService.RegenerateChat(ctx.Context(), userId, &request)
+	if err != nil {
+		return chatErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success regenerate chat", res))
+}
+
+// RegenerateChatStream streams the regenerated answer with the events of SendChatStream
+func (c *chatbotController) RegenerateChatStream(ctx *fiber.Ctx) error {
+	var request dto.RegenerateChatRequest
+
+	err := ctx.BodyParser(&request)
+	if err != nil {
+		return err
+	}
+
+	if err = serverutils.ValidateRequest(request); err != nil {
+		return err
+	}
+
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	return streamChat(ctx, func(streamCtx context.Context, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+		return c.chatbotService.RegenerateChatStream(streamCtx, userId, &request, onDelta)
+	})
+}
+
+// EditChat sends a new version of a user message as a new branch
+func (c *chatbotController) EditChat(ctx *fiber.Ctx) error {
+	var request dto.EditChatRequest
+
+	err := ctx.BodyParser(&request)
+	if err != nil {
+		return err
+	}
+
+	if err = serverutils.ValidateRequest(request); err != nil {
+		return err
+	}
+
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.chatbotService.EditChat(ctx.Context(), userId, &request)
+	if err != nil {
+		return chatErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success edit chat", res))
+}
+
+// EditChatStream streams the answer to the edited message with the events of SendChatStream
+func (c *chatbotController) EditChatStream(ctx *fiber.Ctx) error {
+	var request dto.EditChatRequest
+
+	err := ctx.BodyParser(&request)
+	if err != nil {
+		return err
+	}
+
+	if err = serverutils.ValidateRequest(request); err != nil {
+		return err
+	}
+
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	return streamChat(ctx, func(streamCtx context.Context, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+		return c.chatbotService.EditChatStream(streamCtx, userId, &request, onDelta)
+	})
+}
+
+// SelectBranch switches to the branch through a message and returns its history
+func (c *chatbotController) SelectBranch(ctx *fiber.Ctx) error {
+	var request dto.SelectBranchRequest
+
+	err := ctx.BodyParser(&request)
+	if err != nil {
+		return err
+	}
+
+	if err = serverutils.ValidateRequest(request); err != nil {
+		return err
+	}
+
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.chatbotService.SelectBranch(ctx.Context(), userId, &request)
+	if err != nil {
+		return chatErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success select branch", res))
+}
+
+// SaveAsNote creates a note in the chosen notebook from an assistant answer
+func (c *chatbotController) SaveAsNote(ctx *fiber.Ctx) error {
+	var request dto.SaveChatAsNoteRequest
+
+	err := ctx.BodyParser(&request)
+	if err != nil {
+		return err
+	}
+
+	if err = serverutils.ValidateRequest(request); err != nil {
+		return err
+	}
+
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	request.ChatMessageId, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid message id"))
+	}
+
+	res, err := c.chatbotService.SaveAsNote(ctx.Context(), userId, &request)
+	if err != nil {
+		return chatNoteErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(serverutils.SuccessWithCodeResponse("Success save answer as note", fiber.StatusCreated, res))
+}
+
+// AppendToNote appends an assistant answer to one of the notes it cites
+func (c *chatbotController) AppendToNote(ctx *fiber.Ctx) error {
+	var request dto.AppendChatToNoteRequest
+
+	err := ctx.BodyParser(&request)
+	if err != nil {
+		return err
+	}
+
+	if err = serverutils.ValidateRequest(request); err != nil {
+		return err
+	}
+
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	request.ChatMessageId, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid message id"))
+	}
+
+	res, err := c.chatbotService.AppendToNote(ctx.Context(), userId, &request)
+	if err != nil {
+		return chatNoteErrorResponse(ctx, err)
+	}
+
+	ctx.Set(fiber.HeaderETag, serverutils.ETag(res.Version))
+	return ctx.JSON(serverutils.SuccessResponse("Success append answer to note", res))
+}
+
+// ConfirmToolCall runs a note write the agent proposed
+func (c *chatbotController) ConfirmToolCall(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	toolCallId, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid tool call id"))
+	}
+
+	res, err := c.chatbotService.ConfirmToolCall(ctx.Context(), userId, toolCallId)
+	if err != nil {
+		return toolCallErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success confirm tool call", res))
+}
+
+// RejectToolCall drops a note write the agent proposed
+func (c *chatbotController) RejectToolCall(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	toolCallId, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid tool call id"))
+	}
+
+	res, err := c.chatbotService.RejectToolCall(ctx.Context(), userId, toolCallId)
+	if err != nil {
+		return toolCallErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success reject tool call", res))
+}
+
+// toolCallErrorResponse maps the errors of confirming a tool call, which include the note errors
+func toolCallErrorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrToolCallNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Tool call not found"))
+	case errors.Is(err, service.ErrToolCallNotPending):
+		return ctx.Status(fiber.StatusConflict).JSON(serverutils.ErrorResponse(409, err.Error()))
+	}
+	return chatNoteErrorResponse(ctx, err)
+}
+
+// chatNoteErrorResponse maps the errors of saving an answer to a note, which include the note errors
+func chatNoteErrorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrChatMessageNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Chat message not found"))
+	case errors.Is(err, service.ErrNoteNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Note not found"))
+	case errors.Is(err, service.ErrChatMessageNotModel), errors.Is(err, service.ErrNoteNotCited),
+		errors.Is(err, service.ErrNoteNotAppendable):
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, err.Error()))
+	}
+	return noteErrorResponse(ctx, err)
+}
+
+// streamChat runs a chat request as Server-Sent Events, with the events of runChatStream
+func streamChat(ctx *fiber.Ctx, send sendChatStream) error {
+	ctx.Set("Content-Type", "text/event-stream")
+	ctx.Set("Cache-Control", "no-cache")
+	ctx.Set("Connection", "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The fiber ctx is released once the handler returns, so the stream runs on its own context.
+		// A failed write (client gone) aborts generation; the partial reply is still persisted.
+		runChatStream(context.Background(), send, func(event string, data interface{}) error {
+			return writeSSE(w, event, data)
+		})
+	})
+
+	return nil
+}
+
+// sendChatStream runs a streaming chat request of the chatbot service
+type sendChatStream func(ctx context.Context, onDelta llm.StreamHandler) (*dto.SendChatResponse, error)
+
+// runChatStream runs a chat request, emitting "token" {delta}, then "citations" [CitationDTO],
+// then "done" with the response, or an "error" event with the body of chatErrorBody. A failed
+// emit stops generation.
+func runChatStream(ctx context.Context, send sendChatStream, emit func(event string, data interface{}) error) {
+	res, err := send(ctx, func(delta string) error {
+		return emit("token", fiber.Map{"delta": delta})
+	})
+	if err != nil {
+		_ = emit("error", chatErrorBody(err))
+		return
+	}
+
+	citations := res.Reply.Citations
+	if citations == nil {
+		citations = []dto.CitationDTO{}
+	}
+	if err := emit("citations", citations); err != nil {
+		return
+	}
+	_ = emit("done", res)
+}
+
+// chatStreamer answers the WebSocket's chat.stream messages like the send-chat/stream endpoint
+type chatStreamer struct {
+	chatbotService service.IChatbotService
+}
+
+func NewChatStreamer(chatbotService service.IChatbotService) websocket.ChatStreamer {
+	return &chatStreamer{
+		chatbotService: chatbotService,
+	}
+}
+
+func (s *chatStreamer) StreamChat(ctx context.Context, userId uuid.UUID, request json.RawMessage, emit func(event string, data interface{}) error) {
+	var req dto.SendChatRequest
+	if err := json.Unmarshal(request, &req); err != nil {
+		_ = emit("error", serverutils.ErrorResponse(400, "Invalid request body"))
+		return
+	}
+	if err := serverutils.ValidateRequest(req); err != nil {
+		var validationErr *serverutils.ValidationError
+		if errors.As(err, &validationErr) {
+			_ = emit("error", serverutils.ValidationErrorResponse(validationErr.ToErrorDetails()))
+			return
 		}
-		// Return 403 if it's a permission issue
-		if err.Error() == "feature requires pro plan" {
-			return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, "Feature requires Pro Plan"))
+		_ = emit("error", serverutils.ErrorResponse(400, err.Error()))
+		return
+	}
+
+	runChatStream(ctx, func(ctx context.Context, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+		return s.chatbotService.SendChatStream(ctx, userId, &req, onDelta)
+	}, emit)
+}
+
+// writeSSE writes a single Server-Sent Event and flushes it to the client
+func writeSSE(w *bufio.Writer, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// chatErrorBody maps a send-chat error to the response body used by SendChat
+func chatErrorBody(err error) interface{} {
+	var limitErr *dto.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return dto.LimitExceededResponse{
+			Success:   false,
+			Code:      429,
+			Message:   "Daily AI usage limit exceeded",
+			ErrorType: "LIMIT_EXCEEDED",
+			Data: dto.LimitExceededData{
+				Limit:            limitErr.Limit,
+				Used:             limitErr.Used,
+				ResetAfter:       limitErr.ResetAfter,
+				ShowModalPricing: true,
+			},
 		}
-		return ctx.Status(fiber.StatusInternalServerError).JSON(serverutils.ErrorResponse(500, err.Error()))
 	}
+	if err.Error() == "feature requires pro plan" {
+		return serverutils.ErrorResponse(403, "Feature requires Pro Plan")
+	}
+	if errors.Is(err, service.ErrTagNotFound) {
+		return serverutils.ErrorResponse(404, "Tag not found")
+	}
+	if errors.Is(err, service.ErrChatMessageNotFound) {
+		return serverutils.ErrorResponse(404, "Chat message not found")
+	}
+	if errors.Is(err, service.ErrChatMessageNotAnswer) || errors.Is(err, service.ErrChatMessageNotQuestion) {
+		return serverutils.ErrorResponse(400, err.Error())
+	}
+	return serverutils.ErrorResponse(500, err.Error())
+}
 
-	return ctx.JSON(serverutils.SuccessResponse("Success send chat", res))
+// chatErrorResponse writes the body of chatErrorBody with the status code it carries
+func chatErrorResponse(ctx *fiber.Ctx, err error) error {
+	body := chatErrorBody(err)
+	status := fiber.StatusInternalServerError
+	switch b := body.(type) {
+	case dto.LimitExceededResponse:
+		status = b.Code
+	case serverutils.BaseResponse[any]:
+		status = b.Code
+	}
+	return ctx.Status(status).JSON(body)
 }
 
 func (c *chatbotController) DeleteSession(ctx *fiber.Ctx) error {
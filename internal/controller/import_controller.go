@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/pkg/serverutils"
+	"ai-notetaking-be/internal/service"
+	"ai-notetaking-be/pkg/vault"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IImportController interface {
+	RegisterRoutes(r fiber.Router)
+	Start(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Show(ctx *fiber.Ctx) error
+}
+
+type importController struct {
+	service service.IImportService
+}
+
+func NewImportController(service service.IImportService) IImportController {
+	return &importController{service: service}
+}
+
+func (c *importController) RegisterRoutes(r fiber.Router) {
+	h := r.Group("/import/v1")
+	h.Use(serverutils.JwtMiddleware)
+	h.Post("", c.Start)
+	h.Get("", c.List)
+	h.Get(":id", c.Show)
+}
+
+// Start accepts a multipart form with a zip "file" and an optional "notebook_id" to import into.
+// The upload is bounded by the server body limit.
+func (c *importController) Start(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Missing file"))
+	}
+	if !strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".zip") {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "File must be a .zip archive"))
+	}
+
+	req := dto.StartImportRequest{FileName: fileHeader.Filename}
+	if notebookId := ctx.FormValue("notebook_id"); notebookId != "" {
+		id, err := uuid.Parse(notebookId)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid notebook id"))
+		}
+		req.ParentNotebookId = &id
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(serverutils.ErrorResponse(500, "Failed to open file"))
+	}
+	defer file.Close()
+
+	req.Data, err = io.ReadAll(file)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(serverutils.ErrorResponse(500, "Failed to read file"))
+	}
+
+	res, err := c.service.Start(ctx.Context(), userId, &req)
+	if err != nil {
+		return importErrorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(serverutils.SuccessResponse("Import started", res))
+}
+
+func (c *importController) List(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.service.List(ctx.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get imports", res))
+}
+
+func (c *importController) Show(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	res, err := c.service.Show(ctx.Context(), userId, id)
+	if err != nil {
+		return importErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get import", res))
+}
+
+func importErrorResponse(ctx *fiber.Ctx, err error) error {
+	var limitErr *dto.LimitExceededError
+	switch {
+	case errors.As(err, &limitErr):
+		return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, "Notebook limit reached for your plan"))
+	case errors.Is(err, service.ErrImportNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Import not found"))
+	case errors.Is(err, service.ErrImportNotebookNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Notebook not found"))
+	case errors.Is(err, service.ErrImportInProgress):
+		return ctx.Status(fiber.StatusConflict).JSON(serverutils.ErrorResponse(409, "An import is already in progress"))
+	case errors.Is(err, vault.ErrInvalidArchive), errors.Is(err, vault.ErrNoNotes):
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, err.Error()))
+	case errors.Is(err, vault.ErrTooLarge):
+		return ctx.Status(fiber.StatusRequestEntityTooLarge).JSON(serverutils.ErrorResponse(413, err.Error()))
+	}
+	return err
+}
@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"errors"
+
 	"ai-notetaking-be/internal/dto"
 	"ai-notetaking-be/internal/pkg/serverutils"
 	"ai-notetaking-be/internal/service"
+	"ai-notetaking-be/pkg/search"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -17,6 +20,12 @@ type INoteController interface {
 	Delete(ctx *fiber.Ctx) error
 	MoveNote(ctx *fiber.Ctx) error
 	SemanticSearch(ctx *fiber.Ctx) error
+	Reindex(ctx *fiber.Ctx) error
+	ListRevisions(ctx *fiber.Ctx) error
+	ShowRevision(ctx *fiber.Ctx) error
+	DiffRevisions(ctx *fiber.Ctx) error
+	RestoreRevision(ctx *fiber.Ctx) error
+	Graph(ctx *fiber.Ctx) error
 }
 
 type noteController struct {
@@ -33,10 +42,16 @@ func (c *noteController) RegisterRoutes(r fiber.Router) {
 	h := r.Group("/note/v1")
 	h.Use(serverutils.JwtMiddleware) // ✅ PROTECTED: Wajib login
 	h.Get("semantic-search", c.SemanticSearch)
+	h.Get("graph", c.Graph)
 	h.Post("", c.Create)
 	h.Get(":id", c.Show)
 	h.Put(":id", c.Update)
 	h.Put(":id/move", c.MoveNote)
+	h.Post(":id/reindex", c.Reindex)
+	h.Get(":id/revisions", c.ListRevisions)
+	h.Get(":id/revisions/diff", c.DiffRevisions)
+	h.Get(":id/revisions/:revisionId", c.ShowRevision)
+	h.Post(":id/revisions/:revisionId/restore", c.RestoreRevision)
 	h.Delete(":id", c.Delete)
 }
 
@@ -58,7 +73,7 @@ func (c *noteController) Create(ctx *fiber.Ctx) error {
 	// 2. Kirim userId ke Service
 	res, err := c.noteService.Create(ctx.Context(), userId, &req)
 	if err != nil {
-		return err
+		return noteErrorResponse(ctx, err)
 	}
 
 	return ctx.JSON(serverutils.SuccessResponse("Success create note", res))
@@ -77,6 +92,9 @@ func (c *noteController) Show(ctx *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	if res != nil {
+		ctx.Set(fiber.HeaderETag, serverutils.ETag(res.Version))
+	}
 
 	return ctx.JSON(serverutils.SuccessResponse("Success show note", res))
 }
@@ -100,10 +118,20 @@ func (c *noteController) Update(ctx *fiber.Ctx) error {
 		return err
 	}
 
+	// Edits must name the version they are based on, so concurrent saves can't overwrite each other
+	version, ok := serverutils.ParseIfMatch(ctx.Get(fiber.HeaderIfMatch))
+	if !ok {
+		return ctx.Status(fiber.StatusPreconditionRequired).JSON(serverutils.ErrorResponse(428, "If-Match header with the note version is required"))
+	}
+	req.Version = version
+
 	// 2. Kirim userId ke Service
 	res, err := c.noteService.Update(ctx.Context(), userId, &req)
 	if err != nil {
-		return err
+		return noteErrorResponse(ctx, err)
+	}
+	if res != nil {
+		ctx.Set(fiber.HeaderETag, serverutils.ETag(res.Version))
 	}
 
 	return ctx.JSON(serverutils.SuccessResponse("Success update note", res))
@@ -120,7 +148,7 @@ func (c *noteController) Delete(ctx *fiber.Ctx) error {
 	// 2. Kirim userId ke Service
 	err := c.noteService.Delete(ctx.Context(), userId, id)
 	if err != nil {
-		return err
+		return noteErrorResponse(ctx, err)
 	}
 
 	return ctx.JSON(serverutils.SuccessResponse[any]("Success delete note", nil))
@@ -148,28 +176,235 @@ func (c *noteController) MoveNote(ctx *fiber.Ctx) error {
 	// 2. Kirim userId ke Service
 	res, err := c.noteService.MoveNote(ctx.Context(), userId, &req)
 	if err != nil {
-		return err
+		return noteErrorResponse(ctx, err)
 	}
 
 	return ctx.JSON(serverutils.SuccessResponse("Success move note", res))
 }
 
+func (c *noteController) Reindex(ctx *fiber.Ctx) error {
+	// 1. Ambil User ID dari Token
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	idParam := ctx.Params("id")
+	id, _ := uuid.Parse(idParam)
+
+	// 2. Kirim userId ke Service
+	res, err := c.noteService.Reindex(ctx.Context(), userId, id)
+	if err != nil {
+		return noteErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success queue note reindex", res))
+}
+
+func (c *noteController) ListRevisions(ctx *fiber.Ctx) error {
+	// 1. Ambil User ID dari Token
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	idParam := ctx.Params("id")
+	id, _ := uuid.Parse(idParam)
+
+	// 2. Kirim userId ke Service
+	res, err := c.noteService.ListRevisions(ctx.Context(), userId, id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success list note revisions", res))
+}
+
+func (c *noteController) ShowRevision(ctx *fiber.Ctx) error {
+	// 1. Ambil User ID dari Token
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	id, _ := uuid.Parse(ctx.Params("id"))
+	revisionId, _ := uuid.Parse(ctx.Params("revisionId"))
+
+	// 2. Kirim userId ke Service
+	res, err := c.noteService.ShowRevision(ctx.Context(), userId, id, revisionId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success show note revision", res))
+}
+
+func (c *noteController) DiffRevisions(ctx *fiber.Ctx) error {
+	// 1. Ambil User ID dari Token
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	id, _ := uuid.Parse(ctx.Params("id"))
+
+	fromId, err := uuid.Parse(ctx.Query("from"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid or missing from revision"))
+	}
+	// Without "to", the revision is compared with the current note
+	var toId *uuid.UUID
+	if v := ctx.Query("to"); v != "" {
+		parsed, err := uuid.Parse(v)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid to revision"))
+		}
+		toId = &parsed
+	}
+
+	// 2. Kirim userId ke Service
+	res, err := c.noteService.DiffRevisions(ctx.Context(), userId, id, fromId, toId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success diff note revisions", res))
+}
+
+func (c *noteController) RestoreRevision(ctx *fiber.Ctx) error {
+	// 1. Ambil User ID dari Token
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	id, _ := uuid.Parse(ctx.Params("id"))
+	revisionId, _ := uuid.Parse(ctx.Params("revisionId"))
+
+	// 2. Kirim userId ke Service
+	res, err := c.noteService.RestoreRevision(ctx.Context(), userId, id, revisionId)
+	if err != nil {
+		return noteErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success restore note revision", res))
+}
+
 func (c *noteController) SemanticSearch(ctx *fiber.Ctx) error {
 	// 1. Ambil User ID dari Token
 	userIdStr := ctx.Locals("user_id").(string)
 	userId, _ := uuid.Parse(userIdStr)
 
-	q := ctx.Query("q", "")
+	req := dto.SemanticSearchRequest{
+		Query:           ctx.Query("q", ""),
+		Mode:            ctx.Query("mode", ""),
+		IncludeChildren: ctx.QueryBool("include_children", false),
+		Tag:             ctx.Query("tag", ""),
+		HasChecklist:    ctx.QueryBool("has_checklist", false),
+		Sort:            ctx.Query("sort", ""),
+		Limit:           ctx.QueryInt("limit", 0),
+		Cursor:          ctx.Query("cursor", ""),
+	}
+	if v := ctx.Query("notebook_id"); v != "" {
+		notebookId, err := uuid.Parse(v)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid notebook_id"))
+		}
+		req.NotebookId = &notebookId
+	}
+	if v := ctx.Query("after"); v != "" {
+		after, err := search.ParseDateBound(v, false)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid after date, use YYYY-MM-DD or RFC3339"))
+		}
+		req.After = &after
+	}
+	if v := ctx.Query("before"); v != "" {
+		before, err := search.ParseDateBound(v, true)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid before date, use YYYY-MM-DD or RFC3339"))
+		}
+		req.Before = &before
+	}
 
 	// 2. Kirim userId ke Service
-	res, err := c.noteService.SemanticSearch(ctx.Context(), userId, q)
+	res, err := c.noteService.SemanticSearch(ctx.Context(), userId, &req)
 	if err != nil {
 		// Handle Guard Error (403)
 		if err.Error() == "feature requires pro plan" {
 			return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, "Feature requires Pro Plan"))
 		}
+		if errors.Is(err, search.ErrInvalidCursor) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid cursor"))
+		}
 		return err
 	}
 
-	return ctx.JSON(serverutils.SuccessResponse("Success semantic search notes", res))
-}
\ No newline at end of file
+	// Results stay a plain array; the next page token travels in a header
+	if res.NextCursor != "" {
+		ctx.Set("X-Next-Cursor", res.NextCursor)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success semantic search notes", res.Results))
+}
+
+func (c *noteController) Graph(ctx *fiber.Ctx) error {
+	// 1. Ambil User ID dari Token
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	req := dto.NoteGraphRequest{
+		IncludeChildren: ctx.QueryBool("include_children", false),
+	}
+	if v := ctx.Query("notebook_id"); v != "" {
+		notebookId, err := uuid.Parse(v)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid notebook_id"))
+		}
+		req.NotebookId = &notebookId
+	}
+
+	// 2. Kirim userId ke Service
+	res, err := c.noteService.Graph(ctx.Context(), userId, &req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get note graph", res))
+}
+
+func noteErrorResponse(ctx *fiber.Ctx, err error) error {
+	var conflictErr *dto.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		return versionConflictResponse(ctx, conflictErr)
+	}
+	var limitErr *dto.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return noteLimitResponse(ctx, limitErr)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrNotebookNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Notebook not found"))
+	case errors.Is(err, service.ErrNotebookAccessDenied):
+		return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, "Insufficient notebook role"))
+	}
+	return err
+}
+
+// noteLimitResponse answers a note creation beyond the notes per notebook of the owner's plan
+func noteLimitResponse(ctx *fiber.Ctx, limitErr *dto.LimitExceededError) error {
+	return ctx.Status(fiber.StatusForbidden).JSON(dto.LimitExceededResponse{
+		Success:   false,
+		Code:      403,
+		Message:   "Note limit reached for this notebook",
+		ErrorType: "NOTE_LIMIT_EXCEEDED",
+		Data: dto.LimitExceededData{
+			Limit:            limitErr.Limit,
+			Used:             limitErr.Used,
+			ShowModalPricing: true,
+		},
+	})
+}
+
+// versionConflictResponse answers a stale update with the current version and what changed on the server
+func versionConflictResponse(ctx *fiber.Ctx, conflict *dto.VersionConflictError) error {
+	ctx.Set(fiber.HeaderETag, serverutils.ETag(conflict.Version))
+	return ctx.Status(fiber.StatusConflict).JSON(dto.VersionConflictResponse{
+		Success:   false,
+		Code:      409,
+		Message:   "Modified since the version in If-Match",
+		ErrorType: "VERSION_CONFLICT",
+		Data:      *conflict,
+	})
+}
@@ -1,9 +1,15 @@
 package controller
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+
 	"ai-notetaking-be/internal/dto"
 	"ai-notetaking-be/internal/pkg/serverutils"
 	"ai-notetaking-be/internal/service"
+	"ai-notetaking-be/pkg/export"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -15,8 +21,12 @@ type INotebookController interface {
 	Show(ctx *fiber.Ctx) error
 	Update(ctx *fiber.Ctx) error
 	Delete(ctx *fiber.Ctx) error
+	Duplicate(ctx *fiber.Ctx) error
 	GetAll(ctx *fiber.Ctx) error
 	MoveNotebook(ctx *fiber.Ctx) error
+	Reindex(ctx *fiber.Ctx) error
+	Export(ctx *fiber.Ctx) error
+	ExportAll(ctx *fiber.Ctx) error
 }
 
 type notebookController struct {
@@ -32,10 +42,14 @@ func (c *notebookController) RegisterRoutes(r fiber.Router) {
 	h.Use(serverutils.JwtMiddleware) // ✅ PROTECTED
 	h.Get("", c.GetAll)
 	h.Post("", c.Create)
+	h.Get("export", c.ExportAll)
 	h.Get(":id", c.Show)
 	h.Put(":id", c.Update)
 	h.Delete(":id", c.Delete)
 	h.Put(":id/move", c.MoveNotebook)
+	h.Post(":id/reindex", c.Reindex)
+	h.Post(":id/duplicate", c.Duplicate)
+	h.Get(":id/export", c.Export)
 }
 
 func (c *notebookController) GetAll(ctx *fiber.Ctx) error {
@@ -65,7 +79,7 @@ func (c *notebookController) Create(ctx *fiber.Ctx) error {
 
 	res, err := c.service.Create(ctx.Context(), userId, &req)
 	if err != nil {
-		return err
+		return notebookErrorResponse(ctx, err)
 	}
 
 	return ctx.JSON(serverutils.SuccessResponse("Success create notebook", res))
@@ -79,7 +93,10 @@ func (c *notebookController) Show(ctx *fiber.Ctx) error {
 
 	res, err := c.service.Show(ctx.Context(), userId, id)
 	if err != nil {
-		return err
+		return notebookErrorResponse(ctx, err)
+	}
+	if res != nil {
+		ctx.Set(fiber.HeaderETag, serverutils.ETag(res.Version))
 	}
 
 	return ctx.JSON(serverutils.SuccessResponse("Success show notebook", res))
@@ -97,9 +114,18 @@ func (c *notebookController) Update(ctx *fiber.Ctx) error {
 	}
 	req.Id = id
 
+	version, ok := serverutils.ParseIfMatch(ctx.Get(fiber.HeaderIfMatch))
+	if !ok {
+		return ctx.Status(fiber.StatusPreconditionRequired).JSON(serverutils.ErrorResponse(428, "If-Match header with the notebook version is required"))
+	}
+	req.Version = version
+
 	res, err := c.service.Update(ctx.Context(), userId, &req)
 	if err != nil {
-		return err
+		return notebookErrorResponse(ctx, err)
+	}
+	if res != nil {
+		ctx.Set(fiber.HeaderETag, serverutils.ETag(res.Version))
 	}
 
 	return ctx.JSON(serverutils.SuccessResponse("Success update notebook", res))
@@ -111,12 +137,40 @@ func (c *notebookController) Delete(ctx *fiber.Ctx) error {
 	idParam := ctx.Params("id")
 	id, _ := uuid.Parse(idParam)
 
-	err := c.service.Delete(ctx.Context(), userId, id)
+	req := dto.DeleteNotebookRequest{
+		Id:   id,
+		Mode: ctx.Query("mode", dto.NotebookDeleteCascade),
+	}
+
+	res, err := c.service.Delete(ctx.Context(), userId, &req)
 	if err != nil {
-		return err
+		return notebookErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success delete notebook", res))
+}
+
+func (c *notebookController) Duplicate(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	idParam := ctx.Params("id")
+	id, _ := uuid.Parse(idParam)
+
+	// Body is optional
+	var req dto.DuplicateNotebookRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return err
+		}
+	}
+	req.Id = id
+
+	res, err := c.service.Duplicate(ctx.Context(), userId, &req)
+	if err != nil {
+		return notebookErrorResponse(ctx, err)
 	}
 
-	return ctx.JSON(serverutils.SuccessResponse[any]("Success delete notebook", nil))
+	return ctx.JSON(serverutils.SuccessResponse("Success duplicate notebook", res))
 }
 
 func (c *notebookController) MoveNotebook(ctx *fiber.Ctx) error {
@@ -133,8 +187,94 @@ func (c *notebookController) MoveNotebook(ctx *fiber.Ctx) error {
 
 	res, err := c.service.MoveNotebook(ctx.Context(), userId, &req)
 	if err != nil {
-		return err
+		return notebookErrorResponse(ctx, err)
 	}
 
 	return ctx.JSON(serverutils.SuccessResponse("Success move notebook", res))
-}
\ No newline at end of file
+}
+
+func (c *notebookController) Reindex(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	idParam := ctx.Params("id")
+	id, _ := uuid.Parse(idParam)
+
+	res, err := c.service.Reindex(ctx.Context(), userId, id)
+	if err != nil {
+		return notebookErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success queue notebook reindex", res))
+}
+
+// Export streams a zip of the notebook and its descendants (?format=md|html|json, default md)
+func (c *notebookController) Export(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	return c.streamExport(ctx, userId, &id)
+}
+
+// ExportAll streams a zip of every notebook of the user (?format=md|html|json, default md)
+func (c *notebookController) ExportAll(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	return c.streamExport(ctx, userId, nil)
+}
+
+func (c *notebookController) streamExport(ctx *fiber.Ctx, userId uuid.UUID, id *uuid.UUID) error {
+	format := ctx.Query("format", export.FormatMarkdown)
+
+	archive, err := c.service.Export(ctx.Context(), userId, id, format)
+	if err != nil {
+		return notebookErrorResponse(ctx, err)
+	}
+
+	name := "notes"
+	if id != nil {
+		name = "notebook"
+	}
+	fileName := fmt.Sprintf("%s-export-%s-%s.zip", name, format, archive.ExportedAt.Format("20060102-150405"))
+
+	ctx.Set("Content-Type", "application/zip")
+	ctx.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Headers are already sent; a failure can only cut the archive short
+		if err := archive.Write(w); err != nil {
+			log.Printf("[WARN] Export for user %s aborted: %v", userId, err)
+			return
+		}
+		_ = w.Flush()
+	})
+
+	return nil
+}
+
+func notebookErrorResponse(ctx *fiber.Ctx, err error) error {
+	var conflictErr *dto.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		return versionConflictResponse(ctx, conflictErr)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrNotebookNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Notebook not found"))
+	case errors.Is(err, service.ErrNotebookAccessDenied):
+		return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, "Insufficient notebook role"))
+	case errors.Is(err, service.ErrInvalidNotebookDeleteMode):
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid mode, expected cascade, move_to_parent or refuse"))
+	case errors.Is(err, service.ErrNotebookNotEmpty):
+		return ctx.Status(fiber.StatusConflict).JSON(serverutils.ErrorResponse(409, "Notebook is not empty"))
+	case errors.Is(err, service.ErrNotebookHasNoParent):
+		return ctx.Status(fiber.StatusConflict).JSON(serverutils.ErrorResponse(409, "Notebook has no parent to move its notes to"))
+	case errors.Is(err, service.ErrInvalidExportFormat):
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid format, expected md, html or json"))
+	}
+	return err
+}
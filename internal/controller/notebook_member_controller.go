@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"errors"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/pkg/serverutils"
+	"ai-notetaking-be/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type INotebookMemberController interface {
+	RegisterRoutes(r fiber.Router)
+	List(ctx *fiber.Ctx) error
+	Invite(ctx *fiber.Ctx) error
+	UpdateRole(ctx *fiber.Ctx) error
+	Remove(ctx *fiber.Ctx) error
+	Invitations(ctx *fiber.Ctx) error
+	Accept(ctx *fiber.Ctx) error
+	Decline(ctx *fiber.Ctx) error
+}
+
+type notebookMemberController struct {
+	service service.INotebookMemberService
+}
+
+func NewNotebookMemberController(service service.INotebookMemberService) INotebookMemberController {
+	return &notebookMemberController{service: service}
+}
+
+func (c *notebookMemberController) RegisterRoutes(r fiber.Router) {
+	h := r.Group("/member/v1")
+	h.Use(serverutils.JwtMiddleware)
+	h.Get("invitations", c.Invitations)
+	h.Post("invitations/:id/accept", c.Accept)
+	h.Post("invitations/:id/decline", c.Decline)
+	h.Get("notebook/:notebookId", c.List)
+	h.Post("notebook/:notebookId", c.Invite)
+	h.Put(":id", c.UpdateRole)
+	h.Delete(":id", c.Remove)
+}
+
+func (c *notebookMemberController) List(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	notebookId, err := uuid.Parse(ctx.Params("notebookId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid notebook id"))
+	}
+
+	res, err := c.service.List(ctx.Context(), userId, notebookId)
+	if err != nil {
+		return memberErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get notebook members", res))
+}
+
+func (c *notebookMemberController) Invite(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	notebookId, err := uuid.Parse(ctx.Params("notebookId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid notebook id"))
+	}
+
+	var req dto.InviteNotebookMemberRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := serverutils.ValidateRequest(req); err != nil {
+		return err
+	}
+
+	res, err := c.service.Invite(ctx.Context(), userId, notebookId, &req)
+	if err != nil {
+		return memberErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success invite notebook member", res))
+}
+
+func (c *notebookMemberController) UpdateRole(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	var req dto.UpdateNotebookMemberRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := serverutils.ValidateRequest(req); err != nil {
+		return err
+	}
+
+	res, err := c.service.UpdateRole(ctx.Context(), userId, id, &req)
+	if err != nil {
+		return memberErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success update notebook member", res))
+}
+
+func (c *notebookMemberController) Remove(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	if err := c.service.Remove(ctx.Context(), userId, id); err != nil {
+		return memberErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse[any]("Success remove notebook member", nil))
+}
+
+func (c *notebookMemberController) Invitations(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.service.Invitations(ctx.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get invitations", res))
+}
+
+func (c *notebookMemberController) Accept(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	res, err := c.service.Accept(ctx.Context(), userId, id)
+	if err != nil {
+		return memberErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success accept invitation", res))
+}
+
+func (c *notebookMemberController) Decline(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	if err := c.service.Decline(ctx.Context(), userId, id); err != nil {
+		return memberErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse[any]("Success decline invitation", nil))
+}
+
+func memberErrorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrNotebookNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Notebook not found"))
+	case errors.Is(err, service.ErrMemberNotFound), errors.Is(err, service.ErrInvitationNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrNotebookAccessDenied):
+		return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, "Insufficient notebook role"))
+	case errors.Is(err, service.ErrMemberAlreadyInvited):
+		return ctx.Status(fiber.StatusConflict).JSON(serverutils.ErrorResponse(409, err.Error()))
+	case errors.Is(err, service.ErrMemberIsOwner):
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, err.Error()))
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/pkg/serverutils"
+	"ai-notetaking-be/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/google/uuid"
+)
+
+// SharePasswordHeader carries the password of a protected share link
+const SharePasswordHeader = "X-Share-Password"
+
+// Failed public share requests allowed per client IP and token within the window, so share
+// passwords cannot be brute forced
+const (
+	shareAttemptLimit  = 10
+	shareAttemptWindow = 15 * time.Minute
+)
+
+type IShareController interface {
+	RegisterRoutes(r fiber.Router)
+	Create(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Revoke(ctx *fiber.Ctx) error
+	View(ctx *fiber.Ctx) error
+	ViewNote(ctx *fiber.Ctx) error
+}
+
+type shareController struct {
+	service service.IShareService
+}
+
+func NewShareController(service service.IShareService) IShareController {
+	return &shareController{service: service}
+}
+
+func (c *shareController) RegisterRoutes(r fiber.Router) {
+	h := r.Group("/share/v1")
+	h.Use(serverutils.JwtMiddleware)
+	h.Post("", c.Create)
+	h.Get("", c.List)
+	h.Delete(":id", c.Revoke)
+
+	// Unauthenticated, read-only
+	p := r.Group("/public/share")
+	limit := shareAttemptLimiter()
+	p.Get(":token", limit, c.View)
+	p.Get(":token/notes/:noteId", limit, c.ViewNote)
+}
+
+func (c *shareController) Create(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	var req dto.CreateShareRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := serverutils.ValidateRequest(req); err != nil {
+		return err
+	}
+
+	res, err := c.service.Create(ctx.Context(), userId, &req)
+	if err != nil {
+		return shareErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success create share link", res))
+}
+
+func (c *shareController) List(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.service.List(ctx.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get share links", res))
+}
+
+func (c *shareController) Revoke(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	if err := c.service.Revoke(ctx.Context(), userId, id); err != nil {
+		return shareErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse[any]("Success revoke share link", nil))
+}
+
+func (c *shareController) View(ctx *fiber.Ctx) error {
+	res, err := c.service.View(ctx.Context(), ctx.Params("token"), ctx.Get(SharePasswordHeader))
+	if err != nil {
+		return shareErrorResponse(ctx, err)
+	}
+
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	return ctx.JSON(serverutils.SuccessResponse("Success get shared content", res))
+}
+
+func (c *shareController) ViewNote(ctx *fiber.Ctx) error {
+	noteId, err := uuid.Parse(ctx.Params("noteId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Share link not found"))
+	}
+
+	res, err := c.service.ViewNote(ctx.Context(), ctx.Params("token"), ctx.Get(SharePasswordHeader), noteId)
+	if err != nil {
+		return shareErrorResponse(ctx, err)
+	}
+
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	return ctx.JSON(serverutils.SuccessResponse("Success get shared note", res))
+}
+
+// shareAttemptLimiter counts failed requests (wrong or missing password, unknown token) per
+// client IP and token; successful views do not count against the limit.
+func shareAttemptLimiter() fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:                    shareAttemptLimit,
+		Expiration:             shareAttemptWindow,
+		SkipSuccessfulRequests: true,
+		KeyGenerator: func(ctx *fiber.Ctx) string {
+			return ctx.IP() + "|" + ctx.Params("token")
+		},
+		LimitReached: func(ctx *fiber.Ctx) error {
+			return ctx.Status(fiber.StatusTooManyRequests).JSON(serverutils.ErrorResponse(429, "Too many attempts, try again later"))
+		},
+	})
+}
+
+func shareErrorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrShareNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Share link not found"))
+	case errors.Is(err, service.ErrShareTargetNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrShareTargetInvalid), errors.Is(err, service.ErrShareExpiryInPast):
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, err.Error()))
+	case errors.Is(err, service.ErrSharePasswordRequired):
+		return ctx.Status(fiber.StatusUnauthorized).JSON(serverutils.ErrorResponse(401, "Password required"))
+	case errors.Is(err, service.ErrSharePasswordInvalid):
+		return ctx.Status(fiber.StatusForbidden).JSON(serverutils.ErrorResponse(403, "Invalid password"))
+	}
+	return err
+}
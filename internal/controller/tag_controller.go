@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"errors"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/pkg/serverutils"
+	"ai-notetaking-be/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ITagController interface {
+	RegisterRoutes(r fiber.Router)
+	List(ctx *fiber.Ctx) error
+	Create(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	Tag(ctx *fiber.Ctx) error
+	Untag(ctx *fiber.Ctx) error
+	SetNoteTags(ctx *fiber.Ctx) error
+}
+
+type tagController struct {
+	service service.ITagService
+}
+
+func NewTagController(service service.ITagService) ITagController {
+	return &tagController{service: service}
+}
+
+func (c *tagController) RegisterRoutes(r fiber.Router) {
+	h := r.Group("/tag/v1")
+	h.Use(serverutils.JwtMiddleware)
+	h.Get("", c.List)
+	h.Post("", c.Create)
+	h.Post("bulk/tag", c.Tag)
+	h.Post("bulk/untag", c.Untag)
+	h.Put("note/:noteId", c.SetNoteTags)
+	h.Put(":id", c.Update)
+	h.Delete(":id", c.Delete)
+}
+
+func (c *tagController) List(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.service.List(ctx.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get all tags", res))
+}
+
+func (c *tagController) Create(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	var req dto.CreateTagRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := serverutils.ValidateRequest(req); err != nil {
+		return err
+	}
+
+	res, err := c.service.Create(ctx.Context(), userId, &req)
+	if err != nil {
+		return tagErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success create tag", res))
+}
+
+func (c *tagController) Update(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	var req dto.UpdateTagRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return err
+	}
+	req.Id = id
+
+	if err := serverutils.ValidateRequest(req); err != nil {
+		return err
+	}
+
+	res, err := c.service.Update(ctx.Context(), userId, &req)
+	if err != nil {
+		return tagErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success update tag", res))
+}
+
+func (c *tagController) Delete(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	if err := c.service.Delete(ctx.Context(), userId, id); err != nil {
+		return tagErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse[any]("Success delete tag", nil))
+}
+
+func (c *tagController) Tag(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	var req dto.BulkTagRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := serverutils.ValidateRequest(req); err != nil {
+		return err
+	}
+
+	res, err := c.service.Tag(ctx.Context(), userId, &req)
+	if err != nil {
+		return tagErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success tag notes", res))
+}
+
+func (c *tagController) Untag(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	var req dto.BulkTagRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return err
+	}
+
+	if err := serverutils.ValidateRequest(req); err != nil {
+		return err
+	}
+
+	res, err := c.service.Untag(ctx.Context(), userId, &req)
+	if err != nil {
+		return tagErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success untag notes", res))
+}
+
+func (c *tagController) SetNoteTags(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	noteId, err := uuid.Parse(ctx.Params("noteId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid note id"))
+	}
+
+	var req dto.SetNoteTagsRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return err
+	}
+	req.NoteId = noteId
+
+	if err := serverutils.ValidateRequest(req); err != nil {
+		return err
+	}
+
+	res, err := c.service.SetNoteTags(ctx.Context(), userId, &req)
+	if err != nil {
+		return tagErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success set note tags", res))
+}
+
+func tagErrorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrTagNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Tag not found"))
+	case errors.Is(err, service.ErrTagNoteNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Note not found"))
+	case errors.Is(err, service.ErrTagNameTaken):
+		return ctx.Status(fiber.StatusConflict).JSON(serverutils.ErrorResponse(409, "Tag name already exists"))
+	case errors.Is(err, service.ErrTagNameInvalid):
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Tag name is empty"))
+	}
+	return err
+}
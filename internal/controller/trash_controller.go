@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"errors"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/pkg/serverutils"
+	"ai-notetaking-be/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ITrashController interface {
+	RegisterRoutes(r fiber.Router)
+	List(ctx *fiber.Ctx) error
+	Restore(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	Empty(ctx *fiber.Ctx) error
+}
+
+type trashController struct {
+	service service.ITrashService
+}
+
+func NewTrashController(service service.ITrashService) ITrashController {
+	return &trashController{service: service}
+}
+
+func (c *trashController) RegisterRoutes(r fiber.Router) {
+	h := r.Group("/trash/v1")
+	h.Use(serverutils.JwtMiddleware)
+	h.Get("", c.List)
+	h.Delete("", c.Empty)
+	h.Post(":id/restore", c.Restore)
+	h.Delete(":id", c.Delete)
+}
+
+func (c *trashController) List(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.service.List(ctx.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success get trash", res))
+}
+
+func (c *trashController) Restore(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	// Body is optional
+	var req dto.RestoreTrashRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return err
+		}
+	}
+
+	res, err := c.service.Restore(ctx.Context(), userId, id, &req)
+	if err != nil {
+		return trashErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success restore from trash", res))
+}
+
+func (c *trashController) Delete(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(serverutils.ErrorResponse(400, "Invalid id"))
+	}
+
+	if err := c.service.Delete(ctx.Context(), userId, id); err != nil {
+		return trashErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse[any]("Success permanently delete item", nil))
+}
+
+func (c *trashController) Empty(ctx *fiber.Ctx) error {
+	userIdStr := ctx.Locals("user_id").(string)
+	userId, _ := uuid.Parse(userIdStr)
+
+	res, err := c.service.Empty(ctx.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(serverutils.SuccessResponse("Success empty trash", res))
+}
+
+func trashErrorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrTrashItemNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(serverutils.ErrorResponse(404, "Trash item not found"))
+	case errors.Is(err, service.ErrTrashParentUnavailable):
+		return ctx.Status(fiber.StatusConflict).JSON(serverutils.ErrorResponse(409, "Original notebook is no longer available, choose a notebook to restore into"))
+	}
+	return err
+}
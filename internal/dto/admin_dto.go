@@ -155,6 +155,7 @@ type AdminUpdatePlanRequest struct {
 type PlanFeaturesDTO struct {
 	MaxNotebooks             int  `json:"max_notebooks"`
 	MaxNotesPerNotebook      int  `json:"max_notes_per_notebook"`
+	MaxStorageMB             int  `json:"max_storage_mb"`
 	SemanticSearch           bool `json:"semantic_search"`
 	AiChat                   bool `json:"ai_chat"`
 	AiChatDailyLimit         int  `json:"ai_chat_daily_limit"`
@@ -78,3 +78,30 @@ type AvailableNuanceResponse struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
+
+// ============================================================================
+// Embedding Reindex DTOs
+// ============================================================================
+
+// AdminReindexRequest for queueing notes for re-embedding
+type AdminReindexRequest struct {
+	OnlyOutdatedModel bool `json:"only_outdated_model"` // Skip notes already embedded with the current model
+}
+
+// AdminReindexResponse reports how many notes were queued
+type AdminReindexResponse struct {
+	Queued       int64  `json:"queued"`
+	CurrentModel string `json:"current_model"`
+}
+
+// AdminReindexProgressResponse summarizes the embedding queue
+type AdminReindexProgressResponse struct {
+	CurrentModel    string  `json:"current_model"`
+	TotalNotes      int64   `json:"total_notes"`
+	Pending         int64   `json:"pending"` // Includes jobs currently processing
+	Processing      int64   `json:"processing"`
+	Indexed         int64   `json:"indexed"`
+	Failed          int64   `json:"failed"`
+	OnCurrentModel  int64   `json:"on_current_model"`
+	ProgressPercent float64 `json:"progress_percent"` // Share of notes indexed with the current model
+}
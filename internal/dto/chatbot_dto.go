@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,12 +19,17 @@ type GetAllSessionsResponse struct {
 }
 
 type GetChatHistoryResponse struct {
-	Id         uuid.UUID              `json:"id"`
-	Role       string                 `json:"role"`
-	Chat       string                 `json:"chat"`
-	CreatedAt  time.Time              `json:"created_at"`
-	Citations  []CitationDTO          `json:"citations,omitempty"`
-	References []ResolvedReferenceDTO `json:"references,omitempty"`
+	Id           uuid.UUID              `json:"id"`
+	Role         string                 `json:"role"`
+	Chat         string                 `json:"chat"`
+	CreatedAt    time.Time              `json:"created_at"`
+	ParentId     *uuid.UUID             `json:"parent_id"`
+	SiblingIds   []uuid.UUID            `json:"sibling_ids"`   // Alternatives of this message, itself included, oldest first
+	SiblingIndex int                    `json:"sibling_index"` // Position of this message in SiblingIds
+	SiblingCount int                    `json:"sibling_count"`
+	Citations    []CitationDTO          `json:"citations,omitempty"`
+	References   []ResolvedReferenceDTO `json:"references,omitempty"`
+	ToolCalls    []ChatToolCallDTO      `json:"tool_calls,omitempty"` // Agent mode answers only
 }
 
 type CitationDTO struct {
@@ -35,6 +41,7 @@ type SendChatRequest struct {
 	ChatSessionId uuid.UUID          `json:"chat_session_id" validate:"required"`
 	Chat          string             `json:"chat" validate:"required"`
 	References    []NoteReferenceDTO `json:"references,omitempty" validate:"max=5"` // Pre-resolved note references
+	TagIds        []uuid.UUID        `json:"tag_ids,omitempty" validate:"max=10"`   // Only search notes carrying any of these tags
 }
 
 // NoteReferenceDTO represents a note reference in the chat request
@@ -48,8 +55,10 @@ type SendChatResponseChat struct {
 	Chat       string                 `json:"chat"`
 	Role       string                 `json:"role"`
 	CreatedAt  time.Time              `json:"created_at"`
+	ParentId   *uuid.UUID             `json:"parent_id,omitempty"`
 	Citations  []CitationDTO          `json:"citations,omitempty"`
 	References []ResolvedReferenceDTO `json:"references,omitempty"`
+	ToolCalls  []ChatToolCallDTO      `json:"tool_calls,omitempty"`
 }
 
 type SendChatResponse struct {
@@ -68,6 +77,48 @@ type ResolvedReferenceDTO struct {
 	Resolved bool      `json:"resolved"`
 }
 
+// RegenerateChatRequest asks for another answer to the question of a model message
+type RegenerateChatRequest struct {
+	ChatMessageId uuid.UUID `json:"chat_message_id" validate:"required"`
+}
+
+// EditChatRequest sends a new version of a user message as a sibling branch
+type EditChatRequest struct {
+	ChatMessageId uuid.UUID          `json:"chat_message_id" validate:"required"`
+	Chat          string             `json:"chat" validate:"required"`
+	References    []NoteReferenceDTO `json:"references,omitempty" validate:"max=5"`
+	TagIds        []uuid.UUID        `json:"tag_ids,omitempty" validate:"max=10"`
+}
+
+// SelectBranchRequest makes the branch through a message the active one
+type SelectBranchRequest struct {
+	ChatMessageId uuid.UUID `json:"chat_message_id" validate:"required"`
+}
+
+// SaveChatAsNoteRequest creates a note from an answer; the title defaults to the question
+type SaveChatAsNoteRequest struct {
+	ChatMessageId uuid.UUID
+	NotebookId    uuid.UUID `json:"notebook_id" validate:"required"`
+	Title         string    `json:"title" validate:"max=255"`
+}
+
+// AppendChatToNoteRequest appends an answer to one of the notes it cites
+type AppendChatToNoteRequest struct {
+	ChatMessageId uuid.UUID
+	NoteId        uuid.UUID `json:"note_id" validate:"required"`
+}
+
+// ChatToolCallDTO is a tool the assistant called in agent mode. Pending calls change notes only
+// once the user confirms them.
+type ChatToolCallDTO struct {
+	Id        uuid.UUID       `json:"id"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+	Result    json.RawMessage `json:"result"`
+	Status    string          `json:"status"` // "confirming" | "done" | "failed" | "pending" | "rejected"
+	CreatedAt time.Time       `json:"created_at"`
+}
+
 type DeleteSessionRequest struct {
 	ChatSessionId uuid.UUID `json:"chat_session_id"`
 }
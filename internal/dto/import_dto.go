@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StartImportRequest carries an uploaded vault archive (a zip of .md files)
+type StartImportRequest struct {
+	FileName         string
+	Data             []byte
+	ParentNotebookId *uuid.UUID // Notebook to import into; nil creates a top-level notebook
+}
+
+type ImportJobResponse struct {
+	Id                uuid.UUID  `json:"id"`
+	FileName          string     `json:"file_name"`
+	Status            string     `json:"status"`   // pending | running | completed | failed
+	Progress          int        `json:"progress"` // 0-100
+	TotalNotebooks    int        `json:"total_notebooks"`
+	TotalNotes        int        `json:"total_notes"`
+	ImportedNotebooks int        `json:"imported_notebooks"`
+	ImportedNotes     int        `json:"imported_notes"`
+	SkippedNotes      int        `json:"skipped_notes"`
+	Warnings          []string   `json:"warnings"`
+	Error             string     `json:"error,omitempty"`
+	RootNotebookId    *uuid.UUID `json:"root_notebook_id"`
+	ParentNotebookId  *uuid.UUID `json:"parent_notebook_id"`
+	StartedAt         *time.Time `json:"started_at"`
+	FinishedAt        *time.Time `json:"finished_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NoteAttachmentResponse struct {
+	Id                uuid.UUID `json:"id"`
+	NoteId            uuid.UUID `json:"note_id"`
+	FileName          string    `json:"file_name"`
+	ContentType       string    `json:"content_type"`
+	Size              int64     `json:"size"`
+	Indexed           bool      `json:"indexed"`      // Text was extracted and is searchable by chat
+	DownloadURL       string    `json:"download_url"` // Signed, works without the Authorization header (e.g. in <img>)
+	DownloadExpiresAt time.Time `json:"download_expires_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// UploadAttachmentRequest is a file read from the multipart "file" field
+type UploadAttachmentRequest struct {
+	FileName string
+	Data     []byte
+}
@@ -24,23 +24,58 @@ type BreadcrumbItem struct {
 }
 
 type ShowNoteResponse struct {
-	Id         uuid.UUID        `json:"id"`
-	Title      string           `json:"title"`
-	Content    string           `json:"content"`
-	NotebookId uuid.UUID        `json:"notebook_id"`
-	Breadcrumb []BreadcrumbItem `json:"breadcrumb"` // Notebook ancestry path from root to parent
-	CreatedAt  time.Time        `json:"created_at"`
-	UpdatedAt  *time.Time       `json:"updated_at"`
+	Id         uuid.UUID          `json:"id"`
+	Title      string             `json:"title"`
+	Content    string             `json:"content"`
+	NotebookId uuid.UUID          `json:"notebook_id"`
+	Breadcrumb []BreadcrumbItem   `json:"breadcrumb"` // Notebook ancestry path from root to parent
+	Indexing   NoteIndexingStatus `json:"indexing"`
+	Tags       []NoteTagResponse  `json:"tags"`
+	Backlinks  []NoteBacklink     `json:"backlinks"` // Notes linking to this one, by title
+	Version    int64              `json:"version"`   // Also sent as the ETag header; required in If-Match on update
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  *time.Time         `json:"updated_at"`
+}
+
+// NoteBacklink is a note whose content links to the shown note
+type NoteBacklink struct {
+	Id         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	NotebookId uuid.UUID `json:"notebook_id"`
+}
+
+const (
+	NoteIndexingPending    = "pending"
+	NoteIndexingIndexed    = "indexed"
+	NoteIndexingFailed     = "failed"
+	NoteIndexingNotIndexed = "not_indexed" // No embedding job and no embeddings (legacy notes)
+)
+
+// NoteIndexingStatus reports where the note is in the embedding pipeline
+type NoteIndexingStatus struct {
+	Status      string     `json:"status"` // "pending" | "indexed" | "failed" | "not_indexed"
+	ContentHash string     `json:"content_hash,omitempty"`
+	ModelName   string     `json:"model_name,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	Attempts    int        `json:"attempts"`
+	IndexedAt   *time.Time `json:"indexed_at,omitempty"`
+}
+
+// ReindexResponse is returned by the note/notebook reindex endpoints
+type ReindexResponse struct {
+	Queued int `json:"queued"` // Number of notes queued for re-embedding
 }
 
 type UpdateNoteRequest struct {
 	Id      uuid.UUID
+	Version int64  // From the If-Match header: the version the edit is based on
 	Title   string `json:"title" validate:"required"`
 	Content string `json:"content"`
 }
 
 type UpdateNoteResponse struct {
-	Id uuid.UUID `json:"id"`
+	Id      uuid.UUID `json:"id"`
+	Version int64     `json:"version"`
 }
 
 type MoveNoteRequest struct {
@@ -52,13 +87,77 @@ type MoveNoteResponse struct {
 	Id uuid.UUID `json:"id"`
 }
 
+// Search modes for SemanticSearchRequest.Mode
+const (
+	SearchModeHybrid   = "hybrid"   // Full-text + vector, fused with RRF
+	SearchModeSemantic = "semantic" // Vector only
+	SearchModeLiteral  = "literal"  // ILIKE only
+	SearchModeAuto     = "auto"     // Literal or semantic depending on the query shape (default)
+)
+
+type SemanticSearchRequest struct {
+	Query           string     // Free text, may contain slash commands (/nb:, /tag:, /after: ...)
+	Mode            string     // SearchMode*, empty = auto; hybrid is opt-in
+	NotebookId      *uuid.UUID // Restrict to a notebook
+	IncludeChildren bool       // With NotebookId: include descendant notebooks
+	Tag             string     // Tag name, case-insensitive
+	HasChecklist    bool
+	After           *time.Time // Last modified at or after
+	Before          *time.Time // Last modified before
+	Sort            string     // "relevance" | "updated" | "created"
+	Limit           int        // Page size, defaults to 10
+	Cursor          string     // Opaque token from the previous page
+}
+
+// SemanticSearchPage is one page of search results.
+// NextCursor is empty on the last page.
+type SemanticSearchPage struct {
+	Results    []*SemanticSearchResponse
+	NextCursor string
+}
+
 type SemanticSearchResponse struct {
-	Id             uuid.UUID  `json:"id"`
-	Title          string     `json:"title"`
-	Content        string     `json:"content"`
-	NotebookId     uuid.UUID  `json:"notebook_id"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      *time.Time `json:"updated_at"`
-	SearchType     string     `json:"search_type,omitempty"`     // "literal_filter" | "literal" | "semantic"
-	RelevanceScore *float64   `json:"relevance_score,omitempty"` // 0.0-1.0, only for semantic search
+	Id             uuid.UUID         `json:"id"`
+	Title          string            `json:"title"`
+	Content        string            `json:"content"`
+	NotebookId     uuid.UUID         `json:"notebook_id"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      *time.Time        `json:"updated_at"`
+	SearchType     string            `json:"search_type,omitempty"`     // "literal_filter" | "literal" | "semantic" | "hybrid"
+	RelevanceScore *float64          `json:"relevance_score,omitempty"` // 0.0-1.0, vector similarity (semantic/hybrid)
+	LexicalScore   *float64          `json:"lexical_score,omitempty"`   // 0.0-1.0, full-text rank (hybrid)
+	FusedScore     *float64          `json:"fused_score,omitempty"`     // Reciprocal Rank Fusion score (hybrid)
+	Snippet        string            `json:"snippet,omitempty"`         // Matching excerpt as escaped HTML, terms wrapped in <mark></mark>
+	Tags           []NoteTagResponse `json:"tags"`
+}
+
+type NoteGraphRequest struct {
+	NotebookId      *uuid.UUID // Restrict to a notebook, nil = whole workspace
+	IncludeChildren bool       // With NotebookId: include descendant notebooks
+}
+
+type NoteGraphNode struct {
+	Id         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	NotebookId uuid.UUID `json:"notebook_id"`
+	InScope    bool      `json:"in_scope"` // False for link targets outside the requested notebook
+}
+
+type NoteGraphEdge struct {
+	Source uuid.UUID `json:"source"`
+	Target uuid.UUID `json:"target"`
+	Kind   string    `json:"kind"` // "wiki" | "url"
+}
+
+// NoteGraphUnresolvedLink is a link whose target matches no live note
+type NoteGraphUnresolvedLink struct {
+	Source uuid.UUID `json:"source"`
+	Target string    `json:"target"` // Title or note id as written
+	Kind   string    `json:"kind"`
+}
+
+type NoteGraphResponse struct {
+	Nodes      []NoteGraphNode           `json:"nodes"`
+	Edges      []NoteGraphEdge           `json:"edges"`
+	Unresolved []NoteGraphUnresolvedLink `json:"unresolved"`
 }
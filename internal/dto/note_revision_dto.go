@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NoteRevisionResponse struct {
+	Id           uuid.UUID  `json:"id"`
+	Number       int        `json:"number"`
+	UserId       uuid.UUID  `json:"user_id"` // Author of the change
+	Title        string     `json:"title"`
+	Content      string     `json:"content,omitempty"` // Only when showing a single revision
+	Source       string     `json:"source"`            // "create" | "edit" | "restore" | "initial"
+	RestoredFrom *uuid.UUID `json:"restored_from,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// NoteRevisionDiffResponse compares two revisions (or a revision and the current note)
+type NoteRevisionDiffResponse struct {
+	FromId       uuid.UUID  `json:"from_id"`
+	FromNumber   int        `json:"from_number"`
+	ToId         *uuid.UUID `json:"to_id"`     // nil = current note
+	ToNumber     int        `json:"to_number"` // 0 = current note
+	TitleChanged bool       `json:"title_changed"`
+	OldTitle     string     `json:"old_title"`
+	NewTitle     string     `json:"new_title"`
+	Added        int        `json:"added"`   // Blocks only in the newer version
+	Removed      int        `json:"removed"` // Blocks only in the older version
+	Markdown     string     `json:"markdown"`
+}
+
+type RestoreNoteRevisionResponse struct {
+	Id         uuid.UUID `json:"id"`
+	RevisionId uuid.UUID `json:"revision_id"` // The new revision recording the restore
+	Number     int       `json:"number"`
+}
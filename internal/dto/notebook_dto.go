@@ -19,17 +19,22 @@ type ShowNotebookResponse struct {
 	Id        uuid.UUID  `json:"id"`
 	Name      string     `json:"name"`
 	ParentId  *uuid.UUID `json:"parent_id"`
+	Role      string     `json:"role"`    // viewer, editor or owner
+	Shared    bool       `json:"shared"`  // Reached through a membership rather than owned
+	Version   int64      `json:"version"` // Also sent as the ETag header; required in If-Match on update
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
 }
 
 type UpdateNotebookRequest struct {
-	Id   uuid.UUID
-	Name string `json:"name" validate:"required"`
+	Id      uuid.UUID
+	Version int64  // From the If-Match header: the version the edit is based on
+	Name    string `json:"name" validate:"required"`
 }
 
 type UpdateNotebookResponse struct {
-	Id uuid.UUID `json:"id"`
+	Id      uuid.UUID `json:"id"`
+	Version int64     `json:"version"`
 }
 
 type MoveNotebookRequest struct {
@@ -41,6 +46,39 @@ type MoveNotebookResponse struct {
 	Id uuid.UUID `json:"id"`
 }
 
+// Notebook delete modes
+const (
+	NotebookDeleteCascade      = "cascade"        // The notebook, its descendants and their notes go to the trash (default)
+	NotebookDeleteMoveToParent = "move_to_parent" // Child notebooks and notes move to the parent, then the notebook is trashed
+	NotebookDeleteRefuse       = "refuse"         // Only delete the notebook when it has no child notebooks or notes
+)
+
+type DeleteNotebookRequest struct {
+	Id   uuid.UUID
+	Mode string
+}
+
+type DeleteNotebookResponse struct {
+	Id               uuid.UUID `json:"id"`
+	Mode             string    `json:"mode"`
+	TrashedNotebooks int       `json:"trashed_notebooks"` // Including the notebook itself
+	TrashedNotes     int       `json:"trashed_notes"`
+	MovedNotebooks   int       `json:"moved_notebooks"`
+	MovedNotes       int       `json:"moved_notes"`
+}
+
+type DuplicateNotebookRequest struct {
+	Id       uuid.UUID
+	ParentId *uuid.UUID `json:"parent_id"` // Defaults to the source notebook's parent
+	Name     string     `json:"name"`      // Defaults to "<name> (Copy)"
+}
+
+type DuplicateNotebookResponse struct {
+	Id        uuid.UUID `json:"id"`
+	Notebooks int       `json:"notebooks"` // Including the copied notebook itself
+	Notes     int       `json:"notes"`
+}
+
 type GetAllNotebookResponseNote struct {
 	Id        uuid.UUID  `json:"id"`
 	Title     string     `json:"title"`
@@ -52,7 +90,9 @@ type GetAllNotebookResponseNote struct {
 type GetAllNotebookResponse struct {
 	Id        uuid.UUID  `json:"id"`
 	Name      string     `json:"name"`
-	ParentId  *uuid.UUID `json:"parent_id"`
+	ParentId  *uuid.UUID `json:"parent_id"` // nil for the top of a shared subtree
+	Role      string     `json:"role"`      // viewer, editor or owner
+	Shared    bool       `json:"shared"`    // Reached through a membership rather than owned
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
 
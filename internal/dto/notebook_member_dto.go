@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InviteNotebookMemberRequest invites an email address to a notebook and its sub-notebooks
+type InviteNotebookMemberRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	Role  string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+type UpdateNotebookMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+type NotebookMemberResponse struct {
+	Id         uuid.UUID  `json:"id"`
+	NotebookId uuid.UUID  `json:"notebook_id"`
+	Email      string     `json:"email"`
+	UserId     *uuid.UUID `json:"user_id"`   // Set once the invitation is accepted
+	FullName   string     `json:"full_name"` // Empty until accepted
+	Role       string     `json:"role"`
+	Status     string     `json:"status"` // pending | accepted
+	InvitedBy  uuid.UUID  `json:"invited_by"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NotebookInvitationResponse is a pending invitation addressed to the current user
+type NotebookInvitationResponse struct {
+	Id           uuid.UUID `json:"id"`
+	NotebookId   uuid.UUID `json:"notebook_id"`
+	NotebookName string    `json:"notebook_name"`
+	Role         string    `json:"role"`
+	InvitedBy    uuid.UUID `json:"invited_by"`
+	InviterName  string    `json:"inviter_name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
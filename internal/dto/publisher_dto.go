@@ -4,4 +4,5 @@ import "github.com/google/uuid"
 
 type PublishEmbedNoteMessage struct {
 	NoteId uuid.UUID `json:"note_id"`
+	Force  bool      `json:"force,omitempty"` // Re-embed every chunk instead of reusing unchanged vectors
 }
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateShareRequest shares either a note or a notebook (with its sub-notebooks)
+type CreateShareRequest struct {
+	NoteId     *uuid.UUID `json:"note_id"`
+	NotebookId *uuid.UUID `json:"notebook_id"`
+	ExpiresAt  *time.Time `json:"expires_at"`                                 // nil never expires
+	Password   string     `json:"password" validate:"omitempty,min=4,max=72"` // Empty for no password
+}
+
+type ShareResponse struct {
+	Id           uuid.UUID  `json:"id"`
+	Token        string     `json:"token"`
+	URL          string     `json:"url"`
+	ResourceType string     `json:"resource_type"` // note | notebook
+	NoteId       *uuid.UUID `json:"note_id"`
+	NotebookId   *uuid.UUID `json:"notebook_id"`
+	Title        string     `json:"title"` // Note title or notebook name
+	HasPassword  bool       `json:"has_password"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	ViewCount    int64      `json:"view_count"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// PublicShareResponse is what an unauthenticated visitor of a share link sees
+type PublicShareResponse struct {
+	ResourceType string                  `json:"resource_type"` // note | notebook
+	Note         *PublicNoteResponse     `json:"note,omitempty"`
+	Notebook     *PublicNotebookResponse `json:"notebook,omitempty"`
+}
+
+type PublicNoteResponse struct {
+	Id        uuid.UUID  `json:"id"`
+	Title     string     `json:"title"`
+	Html      string     `json:"html"` // Sanitized HTML rendered from the note content
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// PublicNotebookResponse is one level of a shared notebook tree; note contents are fetched one by one
+type PublicNotebookResponse struct {
+	Id       uuid.UUID                 `json:"id"`
+	Name     string                    `json:"name"`
+	Notes    []PublicNoteSummary       `json:"notes"`
+	Children []*PublicNotebookResponse `json:"children"`
+}
+
+type PublicNoteSummary struct {
+	Id        uuid.UUID  `json:"id"`
+	Title     string     `json:"title"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTagColor is used when a tag is created without a color
+const DefaultTagColor = "#6B7280"
+
+type CreateTagRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Color string `json:"color" validate:"omitempty,len=7,hexcolor"` // #RRGGBB; defaults to DefaultTagColor
+}
+
+type UpdateTagRequest struct {
+	Id    uuid.UUID
+	Name  string `json:"name" validate:"required,max=50"`
+	Color string `json:"color" validate:"omitempty,len=7,hexcolor"` // #RRGGBB; empty keeps the current color
+}
+
+type TagResponse struct {
+	Id        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Color     string     `json:"color"`
+	NoteCount int64      `json:"note_count"` // Live (not trashed) notes carrying the tag
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// NoteTagResponse is the short form of a tag embedded in note responses
+type NoteTagResponse struct {
+	Id    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Color string    `json:"color"`
+}
+
+// BulkTagRequest attaches (or detaches) every tag to (from) every note
+type BulkTagRequest struct {
+	TagIds  []uuid.UUID `json:"tag_ids" validate:"required,min=1,max=20"`
+	NoteIds []uuid.UUID `json:"note_ids" validate:"required,min=1,max=500"`
+}
+
+type BulkTagResponse struct {
+	Changed int64 `json:"changed"` // Links created (tag) or removed (untag)
+}
+
+// SetNoteTagsRequest replaces the full tag set of a note
+type SetNoteTagsRequest struct {
+	NoteId uuid.UUID
+	TagIds []uuid.UUID `json:"tag_ids" validate:"max=20"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trash item types
+const (
+	TrashItemNote     = "note"
+	TrashItemNotebook = "notebook"
+)
+
+// TrashItemResponse is something the user deleted; a notebook carries its whole subtree with it
+type TrashItemResponse struct {
+	Id            uuid.UUID  `json:"id"`
+	Type          string     `json:"type"` // "note" | "notebook"
+	Title         string     `json:"title"`
+	ParentId      *uuid.UUID `json:"parent_id"`      // Notebook the item was deleted from
+	NotebookCount int64      `json:"notebook_count"` // Notebooks in the deleted subtree, including itself
+	NoteCount     int64      `json:"note_count"`     // Notes deleted along with the item
+	DeletedAt     time.Time  `json:"deleted_at"`
+	PurgeAt       time.Time  `json:"purge_at"` // When the item will be permanently deleted
+}
+
+type RestoreTrashRequest struct {
+	// NotebookId restores into another notebook, required when the original one is gone
+	NotebookId *uuid.UUID `json:"notebook_id"`
+}
+
+type RestoreTrashResponse struct {
+	Id        uuid.UUID  `json:"id"`
+	Type      string     `json:"type"`
+	ParentId  *uuid.UUID `json:"parent_id"`
+	Notebooks int        `json:"notebooks"`
+	Notes     int        `json:"notes"`
+}
+
+type EmptyTrashResponse struct {
+	Notebooks int `json:"notebooks"`
+	Notes     int `json:"notes"`
+}
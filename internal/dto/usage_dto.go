@@ -16,10 +16,11 @@ type UsageLimit struct {
 	ResetsAt *time.Time `json:"resets_at,omitempty"` // For daily limits
 }
 
-// StorageLimits for cumulative resources (notebooks, notes)
+// StorageLimits for cumulative resources (notebooks, notes, attachments)
 type StorageLimits struct {
-	Notebooks UsageLimit `json:"notebooks"`
-	Notes     UsageLimit `json:"notes"` // Per notebook
+	Notebooks   UsageLimit `json:"notebooks"`
+	Notes       UsageLimit `json:"notes"`       // Per notebook
+	Attachments UsageLimit `json:"attachments"` // In MB
 }
 
 // DailyLimits for usage that resets daily
@@ -58,6 +59,7 @@ type PlanWithFeaturesResponse struct {
 type PlanLimitsDTO struct {
 	MaxNotebooks        int `json:"max_notebooks"`
 	MaxNotesPerNotebook int `json:"max_notes_per_notebook"`
+	MaxStorageMB        int `json:"max_storage_mb"`
 	AiChatDaily         int `json:"ai_chat_daily"`
 	SemanticSearchDaily int `json:"semantic_search_daily"`
 }
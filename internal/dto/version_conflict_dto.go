@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// VersionConflictError is returned when an update was based on an outdated version of a note or notebook
+type VersionConflictError struct {
+	Version   int64      `json:"version"` // Current server version, to send back in If-Match after merging
+	UpdatedAt *time.Time `json:"updated_at"`
+	Markdown  string     `json:"markdown"` // Changes from the client's copy to the server's
+}
+
+func (e *VersionConflictError) Error() string {
+	return "version conflict"
+}
+
+// VersionConflictResponse is the full 409 response structure
+type VersionConflictResponse struct {
+	Success   bool                 `json:"success"`
+	Code      int                  `json:"code"`
+	Message   string               `json:"message"`
+	ErrorType string               `json:"error_type"`
+	Data      VersionConflictError `json:"data"`
+}
@@ -54,8 +54,12 @@ const (
 const (
 	AiConfigKeyRAGSimilarityThreshold = "rag_similarity_threshold"
 	AiConfigKeyRAGMaxResults          = "rag_max_results"
+	AiConfigKeyEmbeddingChunkSize     = "embedding_chunk_size"
+	AiConfigKeyEmbeddingChunkOverlap  = "embedding_chunk_overlap"
 	AiConfigKeyLLMDefaultModel        = "llm_default_model"
 	AiConfigKeyLLMTemperature         = "llm_temperature"
 	AiConfigKeyBypassEnabled          = "bypass_enabled"
 	AiConfigKeyNuanceEnabled          = "nuance_enabled"
+	AiConfigKeyChatHistoryTokenBudget = "chat_history_token_budget"
+	AiConfigKeyChatSummaryPrompt      = "chat_summary_prompt"
 )
@@ -10,7 +10,8 @@ type ChatMessage struct {
 	Id            uuid.UUID `gorm:"type:uuid;primaryKey"`
 	Chat          string
 	Role          string
-	ChatSessionId uuid.UUID `gorm:"type:uuid;index"`
+	ChatSessionId uuid.UUID  `gorm:"type:uuid;index"`
+	ParentId      *uuid.UUID // Message this one follows; siblings are alternative branches. Nil for the first message
 	CreatedAt     time.Time
 	UpdatedAt     *time.Time
 	DeletedAt     *time.Time
@@ -11,6 +11,7 @@ type ChatMessageRaw struct {
 	Chat          string
 	Role          string
 	ChatSessionId uuid.UUID
+	ChatMessageId *uuid.UUID // Displayed message with the same content; nil for the seeded RAG prompts
 	CreatedAt     time.Time
 	UpdatedAt     *time.Time
 	DeletedAt     *time.Time
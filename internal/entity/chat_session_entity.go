@@ -14,4 +14,13 @@ type ChatSession struct {
 	UpdatedAt *time.Time
 	DeletedAt *time.Time
 	IsDeleted bool
+
+	// Running summary of the turns up to SummarizedUntil, which are no longer sent verbatim.
+	// SummaryMessageId is the last message it covers; the summary only applies to branches through it.
+	Summary          string
+	SummarizedUntil  *time.Time
+	SummaryMessageId *uuid.UUID
+
+	// ActiveMessageId is the tip of the branch shown and continued; nil follows the newest messages
+	ActiveMessageId *uuid.UUID
 }
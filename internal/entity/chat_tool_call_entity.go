@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatToolCall is a tool the assistant called while writing an answer in agent mode
+type ChatToolCall struct {
+	Id            uuid.UUID
+	ChatSessionId uuid.UUID
+	ChatMessageId uuid.UUID // Answer the call was made for
+	Position      int       // Order of the call within the answer
+	Name          string
+	Arguments     string // JSON object produced by the model
+	Result        string // JSON returned to the model, or the outcome of a confirmed write
+	Status        string // constant.ChatToolCallStatus*
+	CreatedAt     time.Time
+	UpdatedAt     *time.Time
+}
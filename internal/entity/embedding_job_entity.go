@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmbeddingJobStatus string
+
+const (
+	EmbeddingJobStatusPending    EmbeddingJobStatus = "pending"
+	EmbeddingJobStatusProcessing EmbeddingJobStatus = "processing"
+	EmbeddingJobStatusCompleted  EmbeddingJobStatus = "completed"
+	EmbeddingJobStatusDead       EmbeddingJobStatus = "dead" // Exhausted all retries
+)
+
+// EmbeddingJob is a durable (outbox-style) request to (re)embed a note.
+// There is at most one job per note: enqueueing again while a job is pending
+// simply refreshes it, so rapid successive edits collapse into one embedding run.
+type EmbeddingJob struct {
+	Id          uuid.UUID
+	NoteId      uuid.UUID
+	Status      EmbeddingJobStatus
+	Attempts    int
+	LastError   string
+	AvailableAt time.Time  // Not claimable before this time (retry backoff)
+	LockedAt    *time.Time // Set while a worker is processing the job
+	RequestedAt time.Time  // Bumped on every enqueue; detects edits made during processing
+	Force       bool       // Regenerate all chunk vectors on the next run
+	ContentHash string     // SHA-256 of the content that was last embedded
+	ModelName   string     // Embedding model that produced the stored vectors
+	IndexedAt   *time.Time // Last successful embedding run
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending   ImportJobStatus = "pending"
+	ImportJobStatusRunning   ImportJobStatus = "running"
+	ImportJobStatusCompleted ImportJobStatus = "completed"
+	ImportJobStatusFailed    ImportJobStatus = "failed"
+)
+
+// ImportJob tracks an asynchronous Markdown vault import
+type ImportJob struct {
+	Id                uuid.UUID
+	UserId            uuid.UUID
+	FileName          string
+	Status            ImportJobStatus
+	TotalNotebooks    int
+	TotalNotes        int
+	ImportedNotebooks int
+	ImportedNotes     int
+	SkippedNotes      int      // Not imported (plan limits or errors)
+	Warnings          []string // Why items were skipped
+	Error             string   // Set when the whole import failed
+	RootNotebookId    *uuid.UUID
+	ParentNotebookId  *uuid.UUID // Notebook the vault was imported into, nil for top level
+	StartedAt         *time.Time
+	FinishedAt        *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteAttachment is a file uploaded to a note. The bytes live in object storage under StorageKey;
+// the size is charged to UserId, the owner of the note.
+type NoteAttachment struct {
+	Id            uuid.UUID
+	NoteId        uuid.UUID
+	UserId        uuid.UUID
+	UploadedBy    uuid.UUID
+	FileName      string
+	ContentType   string
+	Size          int64
+	StorageKey    string
+	ExtractedText string // Plain text of text and PDF files, embedded together with the note; empty otherwise
+	CreatedAt     time.Time
+}
@@ -12,6 +12,8 @@ type NoteEmbedding struct {
 	EmbeddingValue []float32
 	NoteId         uuid.UUID
 	ChunkIndex     int
+	HeadingPath    string // Section the chunk belongs to, e.g. "Setup > Install"
+	ContentHash    string // SHA-256 of Document; unchanged chunks reuse their vector
 	CreatedAt      time.Time
 	UpdatedAt      *time.Time
 	DeletedAt      *time.Time
@@ -16,4 +16,6 @@ type Note struct {
 	UpdatedAt  *time.Time
 	DeletedAt  *time.Time
 	IsDeleted  bool
+	TrashRoot  *uuid.UUID // Set while in the trash: the note itself, or the notebook whose deletion took it along
+	Version    int64      // Incremented by every update; clients send it back in If-Match
 }
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteLink is a link found in a note's content pointing at another note
+type NoteLink struct {
+	Id           uuid.UUID
+	UserId       uuid.UUID
+	SourceNoteId uuid.UUID
+	TargetNoteId *uuid.UUID // Nil while no note matches Target
+	Kind         string     // lexical.LinkKindWiki | lexical.LinkKindURL
+	Target       string     // Title or note id as written in the content
+	CreatedAt    time.Time
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NoteRevisionSource string
+
+const (
+	NoteRevisionSourceCreate  NoteRevisionSource = "create"
+	NoteRevisionSourceEdit    NoteRevisionSource = "edit"
+	NoteRevisionSourceRestore NoteRevisionSource = "restore"
+	NoteRevisionSourceInitial NoteRevisionSource = "initial" // State found on first edit of a note created before history existed
+	NoteRevisionSourceImport  NoteRevisionSource = "import"  // Created by a Markdown vault import
+)
+
+// NoteRevision is an append-only snapshot of a note's title and content after a change
+type NoteRevision struct {
+	Id           uuid.UUID
+	NoteId       uuid.UUID
+	Number       int       // 1-based, per note
+	UserId       uuid.UUID // Author of the change
+	Title        string
+	Content      string
+	Source       NoteRevisionSource
+	RestoredFrom *uuid.UUID // Revision that was restored (Source = restore)
+	CreatedAt    time.Time
+}
@@ -16,4 +16,6 @@ type Notebook struct {
 	UpdatedAt *time.Time
 	DeletedAt *time.Time
 	IsDeleted bool
+	TrashRoot *uuid.UUID // Set while in the trash: the notebook whose deletion took it along (possibly itself)
+	Version   int64      // Incremented by every update; clients send it back in If-Match
 }
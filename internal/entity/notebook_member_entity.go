@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotebookRole string
+type NotebookMemberStatus string
+
+const (
+	NotebookRoleViewer NotebookRole = "viewer"
+	NotebookRoleEditor NotebookRole = "editor"
+	NotebookRoleOwner  NotebookRole = "owner"
+
+	NotebookMemberStatusPending  NotebookMemberStatus = "pending"
+	NotebookMemberStatusAccepted NotebookMemberStatus = "accepted"
+)
+
+var notebookRoleRank = map[NotebookRole]int{
+	NotebookRoleViewer: 1,
+	NotebookRoleEditor: 2,
+	NotebookRoleOwner:  3,
+}
+
+// Valid reports whether the role is one of viewer, editor or owner
+func (r NotebookRole) Valid() bool {
+	return notebookRoleRank[r] > 0
+}
+
+// Allows reports whether the role grants at least the given role
+func (r NotebookRole) Allows(min NotebookRole) bool {
+	return r.Valid() && notebookRoleRank[r] >= notebookRoleRank[min]
+}
+
+// NotebookMember gives another user access to a notebook and its whole subtree.
+// The invitation is addressed to an email; UserId is set when the invitation is accepted.
+// Content stays owned by the notebook's owner, whatever member creates it.
+type NotebookMember struct {
+	Id         uuid.UUID
+	NotebookId uuid.UUID
+	Email      string
+	UserId     *uuid.UUID
+	Role       NotebookRole
+	Status     NotebookMemberStatus
+	InvitedBy  uuid.UUID
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLink publishes one note or notebook (with its subtree) read-only under a random token.
+// Exactly one of NoteId and NotebookId is set.
+type ShareLink struct {
+	Id           uuid.UUID
+	UserId       uuid.UUID
+	Token        string
+	NoteId       *uuid.UUID
+	NotebookId   *uuid.UUID
+	PasswordHash *string // bcrypt hash; nil when the link is not password protected
+	ExpiresAt    *time.Time
+	RevokedAt    *time.Time
+	ViewCount    int64
+	LastViewedAt *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// IsActive reports whether the link can still be opened at the given time
+func (s *ShareLink) IsActive(at time.Time) bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(at))
+}
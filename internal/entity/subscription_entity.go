@@ -36,6 +36,7 @@ type SubscriptionPlan struct {
 	// Storage Limits (cumulative)
 	MaxNotebooks        int // Max folders allowed, -1 = unlimited
 	MaxNotesPerNotebook int // Max notes per folder, -1 = unlimited
+	MaxStorageMB        int // Total size of note attachments, 0 = disabled, -1 = unlimited
 	// Daily Usage Limits (reset daily)
 	AiChatDailyLimit         int // Max AI chat messages per day, 0 = disabled, -1 = unlimited
 	SemanticSearchDailyLimit int // Max semantic searches per day, 0 = disabled, -1 = unlimited
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag is a user-scoped label that can be attached to any number of notes
+type Tag struct {
+	Id        uuid.UUID
+	UserId    uuid.UUID
+	Name      string // Unique per user, case-insensitive
+	Color     string // Hex color, e.g. #3B82F6
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
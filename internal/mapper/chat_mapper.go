@@ -35,13 +35,17 @@ func (m *ChatMapper) ChatSessionToEntity(s *model.ChatSession) *entity.ChatSessi
 	}
 
 	return &entity.ChatSession{
-		Id:        s.Id,
-		UserId:    s.UserId,
-		Title:     s.Title,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: updatedAt,
-		DeletedAt: deletedAt,
-		IsDeleted: s.DeletedAt.Valid,
+		Id:               s.Id,
+		UserId:           s.UserId,
+		Title:            s.Title,
+		Summary:          s.Summary,
+		SummarizedUntil:  s.SummarizedUntil,
+		SummaryMessageId: s.SummaryMessageId,
+		ActiveMessageId:  s.ActiveMessageId,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        updatedAt,
+		DeletedAt:        deletedAt,
+		IsDeleted:        s.DeletedAt.Valid,
 	}
 }
 
@@ -63,12 +67,16 @@ func (m *ChatMapper) ChatSessionToModel(s *entity.ChatSession) *model.ChatSessio
 	}
 
 	return &model.ChatSession{
-		Id:        s.Id,
-		UserId:    s.UserId,
-		Title:     s.Title,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: updatedAt,
-		DeletedAt: deletedAt,
+		Id:               s.Id,
+		UserId:           s.UserId,
+		Title:            s.Title,
+		Summary:          s.Summary,
+		SummarizedUntil:  s.SummarizedUntil,
+		SummaryMessageId: s.SummaryMessageId,
+		ActiveMessageId:  s.ActiveMessageId,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        updatedAt,
+		DeletedAt:        deletedAt,
 	}
 }
 
@@ -96,6 +104,7 @@ func (m *ChatMapper) ChatMessageToEntity(msg *model.ChatMessage) *entity.ChatMes
 		Chat:          msg.Chat,
 		Role:          msg.Role,
 		ChatSessionId: msg.ChatSessionId,
+		ParentId:      msg.ParentId,
 		CreatedAt:     msg.CreatedAt,
 		UpdatedAt:     updatedAt,
 		DeletedAt:     deletedAt,
@@ -125,6 +134,7 @@ func (m *ChatMapper) ChatMessageToModel(msg *entity.ChatMessage) *model.ChatMess
 		Chat:          msg.Chat,
 		Role:          msg.Role,
 		ChatSessionId: msg.ChatSessionId,
+		ParentId:      msg.ParentId,
 		CreatedAt:     msg.CreatedAt,
 		UpdatedAt:     updatedAt,
 		DeletedAt:     deletedAt,
@@ -155,6 +165,7 @@ func (m *ChatMapper) ChatMessageRawToEntity(msg *model.ChatMessageRaw) *entity.C
 		Chat:          msg.Chat,
 		Role:          msg.Role,
 		ChatSessionId: msg.ChatSessionId,
+		ChatMessageId: msg.ChatMessageId,
 		CreatedAt:     msg.CreatedAt,
 		UpdatedAt:     updatedAt,
 		DeletedAt:     deletedAt,
@@ -184,10 +195,62 @@ func (m *ChatMapper) ChatMessageRawToModel(msg *entity.ChatMessageRaw) *model.Ch
 		Chat:          msg.Chat,
 		Role:          msg.Role,
 		ChatSessionId: msg.ChatSessionId,
+		ChatMessageId: msg.ChatMessageId,
 		CreatedAt:     msg.CreatedAt,
 		UpdatedAt:     updatedAt,
 		DeletedAt:     deletedAt,
 	}
 }
 
+// Tool Call Mappers
+
+func (m *ChatMapper) ChatToolCallToEntity(c *model.ChatToolCall) *entity.ChatToolCall {
+	if c == nil {
+		return nil
+	}
+
+	var updatedAt *time.Time
+	if !c.UpdatedAt.IsZero() {
+		t := c.UpdatedAt
+		updatedAt = &t
+	}
+
+	return &entity.ChatToolCall{
+		Id:            c.Id,
+		ChatSessionId: c.ChatSessionId,
+		ChatMessageId: c.ChatMessageId,
+		Position:      c.Position,
+		Name:          c.Name,
+		Arguments:     c.Arguments,
+		Result:        c.Result,
+		Status:        c.Status,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+func (m *ChatMapper) ChatToolCallToModel(c *entity.ChatToolCall) *model.ChatToolCall {
+	if c == nil {
+		return nil
+	}
+
+	var updatedAt time.Time
+	if c.UpdatedAt != nil {
+		updatedAt = *c.UpdatedAt
+	}
+
+	return &model.ChatToolCall{
+		Id:            c.Id,
+		ChatSessionId: c.ChatSessionId,
+		ChatMessageId: c.ChatMessageId,
+		Position:      c.Position,
+		Name:          c.Name,
+		Arguments:     c.Arguments,
+		Result:        c.Result,
+		Status:        c.Status,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     updatedAt,
+	}
+}
+
 // Batch methods (Optional, add if needed)
@@ -0,0 +1,71 @@
+package mapper
+
+import (
+	"time"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/model"
+)
+
+type EmbeddingJobMapper struct{}
+
+func NewEmbeddingJobMapper() *EmbeddingJobMapper {
+	return &EmbeddingJobMapper{}
+}
+
+func (m *EmbeddingJobMapper) ToEntity(j *model.EmbeddingJob) *entity.EmbeddingJob {
+	if j == nil {
+		return nil
+	}
+
+	var updatedAt *time.Time
+	if !j.UpdatedAt.IsZero() {
+		t := j.UpdatedAt
+		updatedAt = &t
+	}
+
+	return &entity.EmbeddingJob{
+		Id:          j.Id,
+		NoteId:      j.NoteId,
+		Status:      entity.EmbeddingJobStatus(j.Status),
+		Attempts:    j.Attempts,
+		LastError:   j.LastError,
+		AvailableAt: j.AvailableAt,
+		LockedAt:    j.LockedAt,
+		RequestedAt: j.RequestedAt,
+		Force:       j.Force,
+		ContentHash: j.ContentHash,
+		ModelName:   j.ModelName,
+		IndexedAt:   j.IndexedAt,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+func (m *EmbeddingJobMapper) ToModel(j *entity.EmbeddingJob) *model.EmbeddingJob {
+	if j == nil {
+		return nil
+	}
+
+	var updatedAt time.Time
+	if j.UpdatedAt != nil {
+		updatedAt = *j.UpdatedAt
+	}
+
+	return &model.EmbeddingJob{
+		Id:          j.Id,
+		NoteId:      j.NoteId,
+		Status:      string(j.Status),
+		Attempts:    j.Attempts,
+		LastError:   j.LastError,
+		AvailableAt: j.AvailableAt,
+		LockedAt:    j.LockedAt,
+		RequestedAt: j.RequestedAt,
+		Force:       j.Force,
+		ContentHash: j.ContentHash,
+		ModelName:   j.ModelName,
+		IndexedAt:   j.IndexedAt,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   updatedAt,
+	}
+}
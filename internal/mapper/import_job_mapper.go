@@ -0,0 +1,70 @@
+package mapper
+
+import (
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/model"
+)
+
+type ImportJobMapper struct{}
+
+func NewImportJobMapper() *ImportJobMapper {
+	return &ImportJobMapper{}
+}
+
+func (m *ImportJobMapper) ToEntity(j *model.ImportJob) *entity.ImportJob {
+	if j == nil {
+		return nil
+	}
+	return &entity.ImportJob{
+		Id:                j.Id,
+		UserId:            j.UserId,
+		FileName:          j.FileName,
+		Status:            entity.ImportJobStatus(j.Status),
+		TotalNotebooks:    j.TotalNotebooks,
+		TotalNotes:        j.TotalNotes,
+		ImportedNotebooks: j.ImportedNotebooks,
+		ImportedNotes:     j.ImportedNotes,
+		SkippedNotes:      j.SkippedNotes,
+		Warnings:          []string(j.Warnings),
+		Error:             j.Error,
+		RootNotebookId:    j.RootNotebookId,
+		ParentNotebookId:  j.ParentNotebookId,
+		StartedAt:         j.StartedAt,
+		FinishedAt:        j.FinishedAt,
+		CreatedAt:         j.CreatedAt,
+		UpdatedAt:         j.UpdatedAt,
+	}
+}
+
+func (m *ImportJobMapper) ToModel(j *entity.ImportJob) *model.ImportJob {
+	if j == nil {
+		return nil
+	}
+	return &model.ImportJob{
+		Id:                j.Id,
+		UserId:            j.UserId,
+		FileName:          j.FileName,
+		Status:            string(j.Status),
+		TotalNotebooks:    j.TotalNotebooks,
+		TotalNotes:        j.TotalNotes,
+		ImportedNotebooks: j.ImportedNotebooks,
+		ImportedNotes:     j.ImportedNotes,
+		SkippedNotes:      j.SkippedNotes,
+		Warnings:          j.Warnings,
+		Error:             j.Error,
+		RootNotebookId:    j.RootNotebookId,
+		ParentNotebookId:  j.ParentNotebookId,
+		StartedAt:         j.StartedAt,
+		FinishedAt:        j.FinishedAt,
+		CreatedAt:         j.CreatedAt,
+		UpdatedAt:         j.UpdatedAt,
+	}
+}
+
+func (m *ImportJobMapper) ToEntities(jobs []*model.ImportJob) []*entity.ImportJob {
+	res := make([]*entity.ImportJob, len(jobs))
+	for i, j := range jobs {
+		res[i] = m.ToEntity(j)
+	}
+	return res
+}
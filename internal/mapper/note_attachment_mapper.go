@@ -0,0 +1,56 @@
+package mapper
+
+import (
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/model"
+)
+
+type NoteAttachmentMapper struct{}
+
+func NewNoteAttachmentMapper() *NoteAttachmentMapper {
+	return &NoteAttachmentMapper{}
+}
+
+func (m *NoteAttachmentMapper) ToEntity(a *model.NoteAttachment) *entity.NoteAttachment {
+	if a == nil {
+		return nil
+	}
+	return &entity.NoteAttachment{
+		Id:            a.Id,
+		NoteId:        a.NoteId,
+		UserId:        a.UserId,
+		UploadedBy:    a.UploadedBy,
+		FileName:      a.FileName,
+		ContentType:   a.ContentType,
+		Size:          a.Size,
+		StorageKey:    a.StorageKey,
+		ExtractedText: a.ExtractedText,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+func (m *NoteAttachmentMapper) ToModel(a *entity.NoteAttachment) *model.NoteAttachment {
+	if a == nil {
+		return nil
+	}
+	return &model.NoteAttachment{
+		Id:            a.Id,
+		NoteId:        a.NoteId,
+		UserId:        a.UserId,
+		UploadedBy:    a.UploadedBy,
+		FileName:      a.FileName,
+		ContentType:   a.ContentType,
+		Size:          a.Size,
+		StorageKey:    a.StorageKey,
+		ExtractedText: a.ExtractedText,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+func (m *NoteAttachmentMapper) ToEntities(attachments []*model.NoteAttachment) []*entity.NoteAttachment {
+	res := make([]*entity.NoteAttachment, len(attachments))
+	for i, a := range attachments {
+		res[i] = m.ToEntity(a)
+	}
+	return res
+}
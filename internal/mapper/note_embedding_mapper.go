@@ -39,6 +39,8 @@ func (m *NoteEmbeddingMapper) ToEntity(e *model.NoteEmbedding) *entity.NoteEmbed
 		EmbeddingValue: e.EmbeddingValue.Slice(),
 		NoteId:         e.NoteId,
 		ChunkIndex:     e.ChunkIndex, // Added
+		HeadingPath:    e.HeadingPath,
+		ContentHash:    e.ContentHash,
 		CreatedAt:      e.CreatedAt,
 		UpdatedAt:      updatedAt,
 		DeletedAt:      deletedAt,
@@ -69,6 +71,8 @@ func (m *NoteEmbeddingMapper) ToModel(e *entity.NoteEmbedding) *model.NoteEmbedd
 		EmbeddingValue: pgvector.NewVector(e.EmbeddingValue),
 		NoteId:         e.NoteId,
 		ChunkIndex:     e.ChunkIndex, // Added
+		HeadingPath:    e.HeadingPath,
+		ContentHash:    e.ContentHash,
 		CreatedAt:      e.CreatedAt,
 		UpdatedAt:      updatedAt,
 		DeletedAt:      deletedAt,
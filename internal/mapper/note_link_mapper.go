@@ -0,0 +1,50 @@
+package mapper
+
+import (
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/model"
+)
+
+type NoteLinkMapper struct{}
+
+func NewNoteLinkMapper() *NoteLinkMapper {
+	return &NoteLinkMapper{}
+}
+
+func (m *NoteLinkMapper) ToEntity(l *model.NoteLink) *entity.NoteLink {
+	if l == nil {
+		return nil
+	}
+	return &entity.NoteLink{
+		Id:           l.Id,
+		UserId:       l.UserId,
+		SourceNoteId: l.SourceNoteId,
+		TargetNoteId: l.TargetNoteId,
+		Kind:         l.Kind,
+		Target:       l.Target,
+		CreatedAt:    l.CreatedAt,
+	}
+}
+
+func (m *NoteLinkMapper) ToModel(l *entity.NoteLink) *model.NoteLink {
+	if l == nil {
+		return nil
+	}
+	return &model.NoteLink{
+		Id:           l.Id,
+		UserId:       l.UserId,
+		SourceNoteId: l.SourceNoteId,
+		TargetNoteId: l.TargetNoteId,
+		Kind:         l.Kind,
+		Target:       l.Target,
+		CreatedAt:    l.CreatedAt,
+	}
+}
+
+func (m *NoteLinkMapper) ToEntities(links []*model.NoteLink) []*entity.NoteLink {
+	res := make([]*entity.NoteLink, len(links))
+	for i, l := range links {
+		res[i] = m.ToEntity(l)
+	}
+	return res
+}
@@ -5,6 +5,7 @@ import (
 
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/pkg/lexical"
 
 	"gorm.io/gorm"
 )
@@ -42,6 +43,8 @@ func (m *NoteMapper) ToEntity(n *model.Note) *entity.Note {
 		UpdatedAt:  updatedAt,
 		DeletedAt:  deletedAt,
 		IsDeleted:  n.DeletedAt.Valid,
+		TrashRoot:  n.TrashRoot,
+		Version:    n.Version,
 	}
 }
 
@@ -66,11 +69,14 @@ func (m *NoteMapper) ToModel(n *entity.Note) *model.Note {
 		Id:         n.Id,
 		Title:      n.Title,
 		Content:    n.Content,
+		SearchText: lexical.ExtractText(n.Content),
 		NotebookId: n.NotebookId,
 		UserId:     n.UserId,
 		CreatedAt:  n.CreatedAt,
 		UpdatedAt:  updatedAt,
 		DeletedAt:  deletedAt,
+		TrashRoot:  n.TrashRoot,
+		Version:    n.Version,
 	}
 }
 
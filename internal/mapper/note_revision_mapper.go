@@ -0,0 +1,54 @@
+package mapper
+
+import (
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/model"
+)
+
+type NoteRevisionMapper struct{}
+
+func NewNoteRevisionMapper() *NoteRevisionMapper {
+	return &NoteRevisionMapper{}
+}
+
+func (m *NoteRevisionMapper) ToEntity(r *model.NoteRevision) *entity.NoteRevision {
+	if r == nil {
+		return nil
+	}
+	return &entity.NoteRevision{
+		Id:           r.Id,
+		NoteId:       r.NoteId,
+		Number:       r.Number,
+		UserId:       r.UserId,
+		Title:        r.Title,
+		Content:      r.Content,
+		Source:       entity.NoteRevisionSource(r.Source),
+		RestoredFrom: r.RestoredFrom,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+func (m *NoteRevisionMapper) ToModel(r *entity.NoteRevision) *model.NoteRevision {
+	if r == nil {
+		return nil
+	}
+	return &model.NoteRevision{
+		Id:           r.Id,
+		NoteId:       r.NoteId,
+		Number:       r.Number,
+		UserId:       r.UserId,
+		Title:        r.Title,
+		Content:      r.Content,
+		Source:       string(r.Source),
+		RestoredFrom: r.RestoredFrom,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+func (m *NoteRevisionMapper) ToEntities(revisions []*model.NoteRevision) []*entity.NoteRevision {
+	res := make([]*entity.NoteRevision, len(revisions))
+	for i, r := range revisions {
+		res[i] = m.ToEntity(r)
+	}
+	return res
+}
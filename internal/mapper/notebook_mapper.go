@@ -41,6 +41,8 @@ func (m *NotebookMapper) ToEntity(n *model.Notebook) *entity.Notebook {
 		UpdatedAt: updatedAt,
 		DeletedAt: deletedAt,
 		IsDeleted: n.DeletedAt.Valid,
+		TrashRoot: n.TrashRoot,
+		Version:   n.Version,
 	}
 }
 
@@ -69,6 +71,8 @@ func (m *NotebookMapper) ToModel(n *entity.Notebook) *model.Notebook {
 		CreatedAt: n.CreatedAt,
 		UpdatedAt: updatedAt,
 		DeletedAt: deletedAt,
+		TrashRoot: n.TrashRoot,
+		Version:   n.Version,
 	}
 }
 
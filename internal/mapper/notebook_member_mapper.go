@@ -0,0 +1,56 @@
+package mapper
+
+import (
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/model"
+)
+
+type NotebookMemberMapper struct{}
+
+func NewNotebookMemberMapper() *NotebookMemberMapper {
+	return &NotebookMemberMapper{}
+}
+
+func (m *NotebookMemberMapper) ToEntity(n *model.NotebookMember) *entity.NotebookMember {
+	if n == nil {
+		return nil
+	}
+	return &entity.NotebookMember{
+		Id:         n.Id,
+		NotebookId: n.NotebookId,
+		Email:      n.Email,
+		UserId:     n.UserId,
+		Role:       entity.NotebookRole(n.Role),
+		Status:     entity.NotebookMemberStatus(n.Status),
+		InvitedBy:  n.InvitedBy,
+		AcceptedAt: n.AcceptedAt,
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+	}
+}
+
+func (m *NotebookMemberMapper) ToModel(n *entity.NotebookMember) *model.NotebookMember {
+	if n == nil {
+		return nil
+	}
+	return &model.NotebookMember{
+		Id:         n.Id,
+		NotebookId: n.NotebookId,
+		Email:      n.Email,
+		UserId:     n.UserId,
+		Role:       string(n.Role),
+		Status:     string(n.Status),
+		InvitedBy:  n.InvitedBy,
+		AcceptedAt: n.AcceptedAt,
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+	}
+}
+
+func (m *NotebookMemberMapper) ToEntities(members []*model.NotebookMember) []*entity.NotebookMember {
+	res := make([]*entity.NotebookMember, len(members))
+	for i, n := range members {
+		res[i] = m.ToEntity(n)
+	}
+	return res
+}
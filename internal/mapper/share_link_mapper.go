@@ -0,0 +1,60 @@
+package mapper
+
+import (
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/model"
+)
+
+type ShareLinkMapper struct{}
+
+func NewShareLinkMapper() *ShareLinkMapper {
+	return &ShareLinkMapper{}
+}
+
+func (m *ShareLinkMapper) ToEntity(s *model.ShareLink) *entity.ShareLink {
+	if s == nil {
+		return nil
+	}
+	return &entity.ShareLink{
+		Id:           s.Id,
+		UserId:       s.UserId,
+		Token:        s.Token,
+		NoteId:       s.NoteId,
+		NotebookId:   s.NotebookId,
+		PasswordHash: s.PasswordHash,
+		ExpiresAt:    s.ExpiresAt,
+		RevokedAt:    s.RevokedAt,
+		ViewCount:    s.ViewCount,
+		LastViewedAt: s.LastViewedAt,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+func (m *ShareLinkMapper) ToModel(s *entity.ShareLink) *model.ShareLink {
+	if s == nil {
+		return nil
+	}
+	return &model.ShareLink{
+		Id:           s.Id,
+		UserId:       s.UserId,
+		Token:        s.Token,
+		NoteId:       s.NoteId,
+		NotebookId:   s.NotebookId,
+		PasswordHash: s.PasswordHash,
+		ExpiresAt:    s.ExpiresAt,
+		RevokedAt:    s.RevokedAt,
+		ViewCount:    s.ViewCount,
+		LastViewedAt: s.LastViewedAt,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+func (m *ShareLinkMapper) ToEntities(links []*model.ShareLink) []*entity.ShareLink {
+	res := make([]*entity.ShareLink, len(links))
+	for i, s := range links {
+		res[i] = m.ToEntity(s)
+	}
+	return res
+}
@@ -30,6 +30,7 @@ func (m *SubscriptionMapper) PlanToEntity(p *model.SubscriptionPlan) *entity.Sub
 		BillingPeriod:            entity.BillingPeriod(p.BillingPeriod),
 		MaxNotebooks:             p.MaxNotebooks,
 		MaxNotesPerNotebook:      p.MaxNotesPerNotebook,
+		MaxStorageMB:             p.MaxStorageMB,
 		AiChatDailyLimit:         p.AiChatDailyLimit,
 		SemanticSearchDailyLimit: p.SemanticSearchDailyLimit,
 		SemanticSearchEnabled:    p.SemanticSearchEnabled,
@@ -56,6 +57,7 @@ func (m *SubscriptionMapper) PlanToModel(p *entity.SubscriptionPlan) *model.Subs
 		BillingPeriod:            string(p.BillingPeriod),
 		MaxNotebooks:             p.MaxNotebooks,
 		MaxNotesPerNotebook:      p.MaxNotesPerNotebook,
+		MaxStorageMB:             p.MaxStorageMB,
 		AiChatDailyLimit:         p.AiChatDailyLimit,
 		SemanticSearchDailyLimit: p.SemanticSearchDailyLimit,
 		SemanticSearchEnabled:    p.SemanticSearchEnabled,
@@ -12,6 +12,7 @@ type ChatMessage struct {
 	Chat          string         `gorm:"type:text;not null"`
 	Role          string         `gorm:"type:varchar(50);not null"`
 	ChatSessionId uuid.UUID      `gorm:"type:uuid;not null;index"`
+	ParentId      *uuid.UUID     `gorm:"type:uuid;index"` // Previous message on the branch
 	CreatedAt     time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt     time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt     gorm.DeletedAt `gorm:"index"`
@@ -12,6 +12,7 @@ type ChatMessageRaw struct {
 	Chat          string         `gorm:"type:text;not null"`
 	Role          string         `gorm:"type:varchar(50);not null"`
 	ChatSessionId uuid.UUID      `gorm:"type:uuid;not null;index"`
+	ChatMessageId *uuid.UUID     `gorm:"type:uuid;index"` // Displayed message it mirrors
 	CreatedAt     time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt     time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt     gorm.DeletedAt `gorm:"index"`
@@ -8,12 +8,16 @@ import (
 )
 
 type ChatSession struct {
-	Id        uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserId    uuid.UUID      `gorm:"type:uuid;not null;index"` // User ownership for data isolation
-	Title     string         `gorm:"type:text;not null"`
-	CreatedAt time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	Id               uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserId           uuid.UUID      `gorm:"type:uuid;not null;index"` // User ownership for data isolation
+	Title            string         `gorm:"type:text;not null"`
+	Summary          string         `gorm:"type:text;not null;default:''"` // Running summary of the older turns
+	SummarizedUntil  *time.Time     // Created at of the newest raw message folded into Summary
+	SummaryMessageId *uuid.UUID     `gorm:"type:uuid"` // Last chat message covered by Summary
+	ActiveMessageId  *uuid.UUID     `gorm:"type:uuid"` // Tip of the selected branch
+	CreatedAt        time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
 }
 
 func (ChatSession) TableName() string {
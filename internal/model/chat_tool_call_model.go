@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChatToolCall struct {
+	Id            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ChatSessionId uuid.UUID `gorm:"type:uuid;not null;index"`
+	ChatMessageId uuid.UUID `gorm:"type:uuid;not null;index"`
+	Position      int       `gorm:"not null;default:0"`
+	Name          string    `gorm:"type:varchar(50);not null"`
+	Arguments     string    `gorm:"type:text;not null"`
+	Result        string    `gorm:"type:text"`
+	Status        string    `gorm:"type:varchar(20);not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+func (ChatToolCall) TableName() string {
+	return "chat_tool_calls"
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmbeddingJob struct {
+	Id          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	NoteId      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex"` // One job per note (de-duplication)
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_embedding_jobs_claim,priority:1"`
+	Attempts    int        `gorm:"not null;default:0"`
+	LastError   string     `gorm:"type:text"`
+	AvailableAt time.Time  `gorm:"not null;index:idx_embedding_jobs_claim,priority:2"`
+	LockedAt    *time.Time `gorm:"default:null"`
+	RequestedAt time.Time  `gorm:"not null"`
+	Force       bool       `gorm:"not null;default:false"`
+	ContentHash string     `gorm:"type:varchar(64)"`
+	ModelName   string     `gorm:"type:varchar(100);index"`
+	IndexedAt   *time.Time `gorm:"default:null"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (EmbeddingJob) TableName() string {
+	return "embedding_jobs"
+}
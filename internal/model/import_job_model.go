@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+type ImportJob struct {
+	Id                uuid.UUID                   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserId            uuid.UUID                   `gorm:"type:uuid;not null;index"`
+	FileName          string                      `gorm:"type:varchar(255);not null"`
+	Status            string                      `gorm:"type:varchar(20);not null;default:'pending';index"`
+	TotalNotebooks    int                         `gorm:"not null;default:0"`
+	TotalNotes        int                         `gorm:"not null;default:0"`
+	ImportedNotebooks int                         `gorm:"not null;default:0"`
+	ImportedNotes     int                         `gorm:"not null;default:0"`
+	SkippedNotes      int                         `gorm:"not null;default:0"`
+	Warnings          datatypes.JSONSlice[string] `gorm:"type:jsonb"`
+	Error             string                      `gorm:"type:text"`
+	RootNotebookId    *uuid.UUID                  `gorm:"type:uuid"`
+	ParentNotebookId  *uuid.UUID                  `gorm:"type:uuid"`
+	StartedAt         *time.Time
+	FinishedAt        *time.Time
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
+}
+
+func (ImportJob) TableName() string {
+	return "import_jobs"
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NoteAttachment struct {
+	Id            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	NoteId        uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserId        uuid.UUID `gorm:"type:uuid;not null;index"`
+	UploadedBy    uuid.UUID `gorm:"type:uuid;not null"`
+	FileName      string    `gorm:"type:varchar(255);not null"`
+	ContentType   string    `gorm:"type:varchar(255);not null"`
+	Size          int64     `gorm:"not null"`
+	StorageKey    string    `gorm:"type:varchar(512);not null"`
+	ExtractedText string    `gorm:"type:text"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (NoteAttachment) TableName() string {
+	return "note_attachments"
+}
@@ -14,6 +14,8 @@ type NoteEmbedding struct {
 	EmbeddingValue pgvector.Vector `gorm:"type:vector(768)"` // Gemini text-embedding-004 uses 768 dimensions
 	NoteId         uuid.UUID       `gorm:"type:uuid;not null;index"`
 	ChunkIndex     int             `gorm:"default:0"` // 0-based index for ordering
+	HeadingPath    string          `gorm:"type:text"`
+	ContentHash    string          `gorm:"type:varchar(64)"`
 	CreatedAt      time.Time       `gorm:"autoCreateTime"`
 	UpdatedAt      time.Time       `gorm:"autoUpdateTime"`
 	DeletedAt      gorm.DeletedAt  `gorm:"index"`
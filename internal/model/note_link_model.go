@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NoteLink struct {
+	Id           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserId       uuid.UUID  `gorm:"type:uuid;not null;index"`
+	SourceNoteId uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TargetNoteId *uuid.UUID `gorm:"type:uuid;index"`
+	Kind         string     `gorm:"type:varchar(10);not null"`
+	Target       string     `gorm:"type:varchar(255);not null"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+}
+
+func (NoteLink) TableName() string {
+	return "note_links"
+}
@@ -11,11 +11,14 @@ type Note struct {
 	Id         uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 	Title      string         `gorm:"type:varchar(255);not null"`
 	Content    string         `gorm:"type:text"`
+	SearchText string         `gorm:"type:text"` // Markup-free content feeding the search_vector column
 	NotebookId uuid.UUID      `gorm:"type:uuid;not null;index"`
 	UserId     uuid.UUID      `gorm:"type:uuid;not null;index"`
 	CreatedAt  time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt  time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	TrashRoot  *uuid.UUID     `gorm:"type:uuid;index"`    // Item the user deleted that took this note to the trash
+	Version    int64          `gorm:"not null;default:1"` // Bumped on every update, for optimistic concurrency
 }
 
 func (Note) TableName() string {
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NoteRevision struct {
+	Id           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	NoteId       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_note_revisions_number,priority:1"`
+	Number       int        `gorm:"not null;uniqueIndex:idx_note_revisions_number,priority:2"`
+	UserId       uuid.UUID  `gorm:"type:uuid;not null"`
+	Title        string     `gorm:"type:varchar(255);not null"`
+	Content      string     `gorm:"type:text"`
+	Source       string     `gorm:"type:varchar(20);not null"`
+	RestoredFrom *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+}
+
+func (NoteRevision) TableName() string {
+	return "note_revisions"
+}
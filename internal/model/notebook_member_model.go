@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotebookMember struct {
+	Id         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	NotebookId uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Email      string     `gorm:"type:varchar(255);not null;index"`
+	UserId     *uuid.UUID `gorm:"type:uuid;index"`
+	Role       string     `gorm:"type:varchar(20);not null"`
+	Status     string     `gorm:"type:varchar(20);not null;default:'pending'"`
+	InvitedBy  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	AcceptedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (NotebookMember) TableName() string {
+	return "notebook_members"
+}
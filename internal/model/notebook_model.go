@@ -15,6 +15,8 @@ type Notebook struct {
 	CreatedAt time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt gorm.DeletedAt `gorm:"index"`
+	TrashRoot *uuid.UUID     `gorm:"type:uuid;index"`    // Item the user deleted that took this notebook to the trash
+	Version   int64          `gorm:"not null;default:1"` // Bumped on every update, for optimistic concurrency
 }
 
 func (Notebook) TableName() string {
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ShareLink struct {
+	Id           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserId       uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Token        string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	NoteId       *uuid.UUID `gorm:"type:uuid;index"`
+	NotebookId   *uuid.UUID `gorm:"type:uuid;index"`
+	PasswordHash *string    `gorm:"type:varchar(255)"`
+	ExpiresAt    *time.Time
+	RevokedAt    *time.Time
+	ViewCount    int64 `gorm:"not null;default:0"`
+	LastViewedAt *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (ShareLink) TableName() string {
+	return "share_links"
+}
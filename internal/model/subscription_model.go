@@ -16,8 +16,9 @@ type SubscriptionPlan struct {
 	TaxRate       float64   `gorm:"type:decimal(5,4);default:0"`
 	BillingPeriod string    `gorm:"type:billing_period;not null"`
 	// Storage Limits
-	MaxNotebooks        int `gorm:"default:3"`  // -1 = unlimited
-	MaxNotesPerNotebook int `gorm:"default:10"` // -1 = unlimited
+	MaxNotebooks        int `gorm:"default:3"`   // -1 = unlimited
+	MaxNotesPerNotebook int `gorm:"default:10"`  // -1 = unlimited
+	MaxStorageMB        int `gorm:"default:100"` // Attachment storage, -1 = unlimited
 	// Daily Usage Limits
 	AiChatDailyLimit         int `gorm:"default:0"` // 0 = disabled, -1 = unlimited
 	SemanticSearchDailyLimit int `gorm:"default:0"` // 0 = disabled, -1 = unlimited
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	Id        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserId    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name      string    `gorm:"type:varchar(50);not null"` // Case-insensitive unique index created in migrate
+	Color     string    `gorm:"type:varchar(7);not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// NoteTag is the join table between notes and tags
+type NoteTag struct {
+	NoteId    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TagId     uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (NoteTag) TableName() string {
+	return "note_tags"
+}
@@ -3,6 +3,7 @@ package mailer
 
 import (
 	"fmt"
+	"html"
 	"os"
 
 	"gopkg.in/gomail.v2"
@@ -11,6 +12,7 @@ import (
 type IEmailService interface {
 	SendOTP(toEmail, otp string) error
 	SendResetToken(toEmail, token string) error
+	SendNotebookInvitation(toEmail, inviterName, notebookName, role string) error
 }
 
 type emailService struct {
@@ -89,4 +91,35 @@ func (s *emailService) SendResetToken(toEmail, token string) error {
 
 	fmt.Printf("[MAILER] Reset Token sent to %s\n", toEmail)
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *emailService) SendNotebookInvitation(toEmail, inviterName, notebookName, role string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", s.senderEmail)
+	m.SetHeader("To", toEmail)
+	m.SetHeader("Subject", fmt.Sprintf("%s shared a notebook with you", inviterName))
+
+	// Invitations are accepted from the FRONTEND after signing in with this email
+	invitationsLink := fmt.Sprintf("%s/invitations", s.frontendURL)
+
+	body := fmt.Sprintf(`
+		<div style="font-family: Arial, sans-serif; padding: 20px; color: #333;">
+			<h2>You're invited to a notebook</h2>
+			<p><strong>%s</strong> invited you to the notebook <strong>%s</strong> as %s.</p>
+			<a href="%s" style="background-color: #007BFF; color: white; padding: 10px 20px; text-decoration: none; border-radius: 5px; display: inline-block;">View Invitation</a>
+			<p>Or copy this link:</p>
+			<p>%s</p>
+			<p>Sign in or create an account with this email address to accept.</p>
+		</div>
+	`, html.EscapeString(inviterName), html.EscapeString(notebookName), html.EscapeString(role), invitationsLink, invitationsLink)
+
+	m.SetBody("text/html", body)
+
+	if err := s.dialer.DialAndSend(m); err != nil {
+		fmt.Printf("[MAILER ERROR] Failed to send Notebook Invitation to %s: %v\n", toEmail, err)
+		return err
+	}
+
+	fmt.Printf("[MAILER] Notebook Invitation sent to %s\n", toEmail)
+	return nil
+}
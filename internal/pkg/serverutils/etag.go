@@ -0,0 +1,25 @@
+package serverutils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag formats a record version as a strong entity tag
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ParseIfMatch reads the version from an If-Match header produced by ETag. Weak tags are accepted,
+// a list or a wildcard is not; ok is false when the header is missing or malformed.
+func ParseIfMatch(header string) (version int64, ok bool) {
+	tag := strings.TrimPrefix(strings.TrimSpace(header), "W/")
+	if len(tag) < 3 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
@@ -2,6 +2,7 @@ package contract
 
 import (
 	"context"
+	"time"
 
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/repository/specification"
@@ -12,6 +13,10 @@ import (
 type ChatSessionRepository interface {
 	Create(ctx context.Context, session *entity.ChatSession) error
 	Update(ctx context.Context, session *entity.ChatSession) error
+	// UpdateSummary stores the running conversation summary without touching the other columns
+	UpdateSummary(ctx context.Context, id uuid.UUID, summary string, summarizedUntil time.Time, messageId *uuid.UUID) error
+	// UpdateActiveMessage selects the branch ending at messageId without touching the other columns
+	UpdateActiveMessage(ctx context.Context, id uuid.UUID, messageId uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error // Hard delete all
 	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.ChatSession, error)
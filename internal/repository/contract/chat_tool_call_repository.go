@@ -0,0 +1,21 @@
+package contract
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type ChatToolCallRepository interface {
+	CreateBulk(ctx context.Context, calls []*entity.ChatToolCall) error
+	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.ChatToolCall, error)
+	// FindAllByMessageIds returns the calls of the answers in call order
+	FindAllByMessageIds(ctx context.Context, messageIds []uuid.UUID) ([]*entity.ChatToolCall, error)
+	// Transition moves a call from one status to another, storing result when it is not empty.
+	// It reports false when the call was no longer in the from status, e.g. because a concurrent
+	// request moved it first.
+	Transition(ctx context.Context, id uuid.UUID, from string, to string, result string) (bool, error)
+}
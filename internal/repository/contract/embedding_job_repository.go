@@ -0,0 +1,35 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type EmbeddingJobRepository interface {
+	// Enqueue creates or refreshes the job for a note (upsert on note_id).
+	// force requests a full rebuild; it sticks until the job completes.
+	Enqueue(ctx context.Context, noteId uuid.UUID, force bool) error
+	// EnqueueMany upserts the jobs of several notes in one statement; force works as for Enqueue
+	EnqueueMany(ctx context.Context, noteIds []uuid.UUID, force bool) error
+	// EnqueueAll queues a full rebuild of every live note. When exceptModel is set, notes already
+	// embedded with that model are skipped. Returns the number of jobs queued.
+	EnqueueAll(ctx context.Context, exceptModel string) (int64, error)
+	// ClaimBatch locks up to limit due jobs for processing (FOR UPDATE SKIP LOCKED).
+	// Jobs stuck in processing longer than staleAfter are reclaimed.
+	ClaimBatch(ctx context.Context, limit int, staleAfter time.Duration) ([]*entity.EmbeddingJob, error)
+	// MarkCompleted finishes a claimed job, or re-queues it if the note was edited meanwhile
+	MarkCompleted(ctx context.Context, job *entity.EmbeddingJob) error
+	// MarkFailed schedules a retry at retryAt, or moves the job to dead when dead is true
+	MarkFailed(ctx context.Context, job *entity.EmbeddingJob, errMsg string, retryAt time.Time, dead bool) error
+	// DeleteByNoteIds drops the jobs of permanently deleted notes
+	DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error
+	CountByStatus(ctx context.Context, specs ...specification.Specification) (map[entity.EmbeddingJobStatus]int64, error)
+	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.EmbeddingJob, error)
+	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.EmbeddingJob, error)
+	Count(ctx context.Context, specs ...specification.Specification) (int64, error)
+}
@@ -0,0 +1,6 @@
+package contract
+
+import "errors"
+
+// ErrStaleVersion is returned by versioned updates when the record changed since it was loaded
+var ErrStaleVersion = errors.New("record was modified concurrently")
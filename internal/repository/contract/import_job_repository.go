@@ -0,0 +1,21 @@
+package contract
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *entity.ImportJob) error
+	Update(ctx context.Context, job *entity.ImportJob) error
+	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.ImportJob, error)
+	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.ImportJob, error)
+	// FailUnfinished marks pending and running jobs as failed (their upload is gone after a restart).
+	// Returns the number of jobs marked.
+	FailUnfinished(ctx context.Context, reason string) (int64, error)
+	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error
+}
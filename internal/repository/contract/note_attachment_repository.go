@@ -0,0 +1,22 @@
+package contract
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type NoteAttachmentRepository interface {
+	Create(ctx context.Context, attachment *entity.NoteAttachment) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.NoteAttachment, error)
+	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NoteAttachment, error)
+	// SumSize totals the bytes of the matching attachments
+	SumSize(ctx context.Context, specs ...specification.Specification) (int64, error)
+	// DeleteByNoteIds removes the attachment rows of permanently deleted notes; the stored files are left to the caller
+	DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error
+	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error
+}
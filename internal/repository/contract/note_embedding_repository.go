@@ -22,12 +22,17 @@ type NoteEmbeddingRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error // Hard delete embeddings
 	DeleteByNoteId(ctx context.Context, noteId uuid.UUID) error
+	DeleteByIds(ctx context.Context, ids []uuid.UUID) error
 	DeleteByNotebookId(ctx context.Context, notebookId uuid.UUID) error
+	DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error
+	DeleteByNoteIdsUnscoped(ctx context.Context, noteIds []uuid.UUID) error // Hard delete
 	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.NoteEmbedding, error)
 	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NoteEmbedding, error)
 	Count(ctx context.Context, specs ...specification.Specification) (int64, error)
 	// Advanced
 	SearchSimilar(ctx context.Context, embedding []float32, limit int, userId uuid.UUID) ([]*entity.NoteEmbedding, error)
-	// SearchSimilarWithScore returns embeddings with their similarity scores, filtered by threshold
-	SearchSimilarWithScore(ctx context.Context, embedding []float32, limit int, userId uuid.UUID, threshold float64) ([]*ScoredNoteEmbedding, error)
+	// SearchSimilarWithScore returns embeddings with their similarity scores, filtered by threshold.
+	// Both searches cover the notes the user owns and the notes of notebooks shared with them.
+	// Extra specs are applied to the query joined with notes (e.g. NoteHasAnyTag).
+	SearchSimilarWithScore(ctx context.Context, embedding []float32, limit int, userId uuid.UUID, threshold float64, specs ...specification.Specification) ([]*ScoredNoteEmbedding, error)
 }
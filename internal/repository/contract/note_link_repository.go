@@ -0,0 +1,22 @@
+package contract
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type NoteLinkRepository interface {
+	// ReplaceForNote swaps the stored outgoing links of the note for links
+	ReplaceForNote(ctx context.Context, sourceNoteId uuid.UUID, links []*entity.NoteLink) error
+	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NoteLink, error)
+	// ResolveTitle points the user's unresolved wiki links with the given title at the note.
+	// Returns the number of links resolved.
+	ResolveTitle(ctx context.Context, userId uuid.UUID, title string, noteId uuid.UUID) (int64, error)
+	// DeleteByNoteIds removes the links of permanently deleted notes and unresolves links pointing at them
+	DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error
+	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error
+}
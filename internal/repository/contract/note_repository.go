@@ -2,6 +2,7 @@ package contract
 
 import (
 	"context"
+	"time"
 
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/repository/specification"
@@ -9,12 +10,32 @@ import (
 	"github.com/google/uuid"
 )
 
+// ScoredNote wraps Note with its full-text rank
+type ScoredNote struct {
+	Note *entity.Note
+	Rank float64 // ts_rank_cd normalized to 0.0 - 1.0
+}
+
 type NoteRepository interface {
 	Create(ctx context.Context, note *entity.Note) error
+	// Update saves an edit (title and content) and bumps the version; it fails with ErrStaleVersion
+	// when the stored version no longer matches note.Version. Other columns are left as stored.
 	Update(ctx context.Context, note *entity.Note) error
+	// Move saves note.NotebookId and bumps the version, with the version check of Update
+	Move(ctx context.Context, note *entity.Note) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error // Hard delete all
+	// MoveToTrash soft-deletes the live notes among ids, recording rootId as the deletion they belong to
+	// and deletedAt as its time
+	MoveToTrash(ctx context.Context, ids []uuid.UUID, rootId uuid.UUID, deletedAt time.Time) error
+	// Restore brings trashed notes back and clears their trash root
+	Restore(ctx context.Context, ids []uuid.UUID) error
+	DeleteUnscopedByIds(ctx context.Context, ids []uuid.UUID) error // Hard delete
 	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.Note, error)
 	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.Note, error)
 	Count(ctx context.Context, specs ...specification.Specification) (int64, error)
+	// FullTextSearch ranks notes matching query (websearch syntax) against the search_vector index
+	FullTextSearch(ctx context.Context, query string, limit int, specs ...specification.Specification) ([]*ScoredNote, error)
+	// Headlines returns an HTML-escaped snippet per note with query terms wrapped in <mark></mark>
+	Headlines(ctx context.Context, ids []uuid.UUID, query string) (map[uuid.UUID]string, error)
 }
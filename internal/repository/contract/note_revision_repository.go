@@ -0,0 +1,23 @@
+package contract
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type NoteRevisionRepository interface {
+	// Create appends a revision, assigning the next Number for its note. It locks the note row,
+	// so it has to run in a transaction for concurrent saves to get distinct numbers.
+	Create(ctx context.Context, revision *entity.NoteRevision) error
+	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.NoteRevision, error)
+	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NoteRevision, error)
+	Count(ctx context.Context, specs ...specification.Specification) (int64, error)
+	// DeleteAllByUserIdUnscoped removes the history of every note owned by the user
+	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error
+	// DeleteByNoteIds removes the history of permanently deleted notes
+	DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error
+}
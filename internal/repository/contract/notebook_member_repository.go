@@ -0,0 +1,20 @@
+package contract
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type NotebookMemberRepository interface {
+	Create(ctx context.Context, member *entity.NotebookMember) error
+	Update(ctx context.Context, member *entity.NotebookMember) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.NotebookMember, error)
+	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NotebookMember, error)
+	// DeleteAllByUserIdUnscoped removes the user's memberships and every membership of the user's notebooks
+	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error
+}
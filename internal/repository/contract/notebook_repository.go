@@ -2,6 +2,7 @@ package contract
 
 import (
 	"context"
+	"time"
 
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/repository/specification"
@@ -11,9 +12,19 @@ import (
 
 type NotebookRepository interface {
 	Create(ctx context.Context, notebook *entity.Notebook) error
+	// Update saves the notebook and bumps its version; it fails with ErrStaleVersion when the stored
+	// version no longer matches notebook.Version
 	Update(ctx context.Context, notebook *entity.Notebook) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error // Hard delete all
+	// MoveToTrash soft-deletes the live notebooks among ids, recording rootId as the deletion they belong to
+	// and deletedAt as its time
+	MoveToTrash(ctx context.Context, ids []uuid.UUID, rootId uuid.UUID, deletedAt time.Time) error
+	// Restore brings trashed notebooks back and clears their trash root
+	Restore(ctx context.Context, ids []uuid.UUID) error
+	DeleteUnscopedByIds(ctx context.Context, ids []uuid.UUID) error // Hard delete
+	// FindSubtreeIds returns rootId and the ids of all its live descendants
+	FindSubtreeIds(ctx context.Context, rootId uuid.UUID) ([]uuid.UUID, error)
 	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.Notebook, error)
 	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.Notebook, error)
 	Count(ctx context.Context, specs ...specification.Specification) (int64, error)
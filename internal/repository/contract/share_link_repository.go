@@ -0,0 +1,21 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *entity.ShareLink) error
+	Update(ctx context.Context, link *entity.ShareLink) error
+	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.ShareLink, error)
+	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.ShareLink, error)
+	// RecordView atomically increments the view count and sets the last view time
+	RecordView(ctx context.Context, id uuid.UUID, at time.Time) error
+	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error
+}
@@ -0,0 +1,31 @@
+package contract
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+)
+
+type TagRepository interface {
+	Create(ctx context.Context, tag *entity.Tag) error
+	Update(ctx context.Context, tag *entity.Tag) error
+	// Delete removes the tag and detaches it from every note
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindOne(ctx context.Context, specs ...specification.Specification) (*entity.Tag, error)
+	FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.Tag, error)
+	Count(ctx context.Context, specs ...specification.Specification) (int64, error)
+
+	// Attach links every tag to every note, ignoring links that already exist. Returns the number of new links.
+	Attach(ctx context.Context, tagIds []uuid.UUID, noteIds []uuid.UUID) (int64, error)
+	// Detach removes the links between the tags and the notes. Returns the number of removed links.
+	Detach(ctx context.Context, tagIds []uuid.UUID, noteIds []uuid.UUID) (int64, error)
+	// FindByNoteIds returns the tags of each note, ordered by name
+	FindByNoteIds(ctx context.Context, noteIds []uuid.UUID) (map[uuid.UUID][]*entity.Tag, error)
+	// CountNotes returns the number of live notes carrying each tag
+	CountNotes(ctx context.Context, tagIds []uuid.UUID) (map[uuid.UUID]int64, error)
+	DetachAllFromNotes(ctx context.Context, noteIds []uuid.UUID) error
+	DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error // Hard delete tags and their links
+}
@@ -3,6 +3,7 @@ package implementation
 import (
 	"context"
 	"errors"
+	"time"
 
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/mapper"
@@ -51,6 +52,22 @@ func (r *ChatSessionRepositoryImpl) Update(ctx context.Context, session *entity.
 	return nil
 }
 
+func (r *ChatSessionRepositoryImpl) UpdateSummary(ctx context.Context, id uuid.UUID, summary string, summarizedUntil time.Time, messageId *uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&model.ChatSession{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"summary":            summary,
+			"summarized_until":   summarizedUntil,
+			"summary_message_id": messageId,
+		}).Error
+}
+
+func (r *ChatSessionRepositoryImpl) UpdateActiveMessage(ctx context.Context, id uuid.UUID, messageId uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&model.ChatSession{}).
+		Where("id = ?", id).
+		UpdateColumn("active_message_id", messageId).Error
+}
+
 func (r *ChatSessionRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&model.ChatSession{}, id).Error
 }
@@ -0,0 +1,94 @@
+package implementation
+
+import (
+	"context"
+	"errors"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ChatToolCallRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.ChatMapper
+}
+
+func NewChatToolCallRepository(db *gorm.DB) contract.ChatToolCallRepository {
+	return &ChatToolCallRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewChatMapper(),
+	}
+}
+
+func (r *ChatToolCallRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+func (r *ChatToolCallRepositoryImpl) CreateBulk(ctx context.Context, calls []*entity.ChatToolCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	models := make([]*model.ChatToolCall, len(calls))
+	for i, call := range calls {
+		models[i] = r.mapper.ChatToolCallToModel(call)
+	}
+	if err := r.db.WithContext(ctx).Create(&models).Error; err != nil {
+		return err
+	}
+	for i, m := range models {
+		*calls[i] = *r.mapper.ChatToolCallToEntity(m)
+	}
+	return nil
+}
+
+func (r *ChatToolCallRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.ChatToolCall, error) {
+	var m model.ChatToolCall
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.mapper.ChatToolCallToEntity(&m), nil
+}
+
+func (r *ChatToolCallRepositoryImpl) FindAllByMessageIds(ctx context.Context, messageIds []uuid.UUID) ([]*entity.ChatToolCall, error) {
+	if len(messageIds) == 0 {
+		return []*entity.ChatToolCall{}, nil
+	}
+	var models []*model.ChatToolCall
+	err := r.db.WithContext(ctx).
+		Where("chat_message_id IN ?", messageIds).
+		Order("position").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	entities := make([]*entity.ChatToolCall, len(models))
+	for i, m := range models {
+		entities[i] = r.mapper.ChatToolCallToEntity(m)
+	}
+	return entities, nil
+}
+
+func (r *ChatToolCallRepositoryImpl) Transition(ctx context.Context, id uuid.UUID, from string, to string, result string) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	if result != "" {
+		updates["result"] = result
+	}
+	res := r.db.WithContext(ctx).Model(&model.ChatToolCall{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	return res.RowsAffected > 0, res.Error
+}
@@ -0,0 +1,243 @@
+package implementation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmbeddingJobRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.EmbeddingJobMapper
+}
+
+func NewEmbeddingJobRepository(db *gorm.DB) contract.EmbeddingJobRepository {
+	return &EmbeddingJobRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewEmbeddingJobMapper(),
+	}
+}
+
+func (r *EmbeddingJobRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+// Enqueue upserts the note's job. A job that is currently processing keeps its lock;
+// only requested_at is bumped so the worker re-queues it after finishing (see MarkCompleted).
+func (r *EmbeddingJobRepositoryImpl) Enqueue(ctx context.Context, noteId uuid.UUID, force bool) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO embedding_jobs (id, note_id, status, attempts, last_error, force, available_at, requested_at, created_at, updated_at)
+		VALUES (gen_random_uuid(), @note_id, 'pending', 0, '', @force, NOW(), clock_timestamp(), NOW(), NOW())
+		ON CONFLICT (note_id) DO UPDATE SET
+			status       = CASE WHEN embedding_jobs.status = 'processing' THEN 'processing' ELSE 'pending' END,
+			attempts     = CASE WHEN embedding_jobs.status = 'processing' THEN embedding_jobs.attempts ELSE 0 END,
+			force        = embedding_jobs.force OR EXCLUDED.force,
+			available_at = NOW(),
+			requested_at = clock_timestamp(),
+			last_error   = '',
+			updated_at   = NOW()`,
+		map[string]interface{}{"note_id": noteId, "force": force},
+	).Error
+}
+
+func (r *EmbeddingJobRepositoryImpl) EnqueueMany(ctx context.Context, noteIds []uuid.UUID, force bool) error {
+	if len(noteIds) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO embedding_jobs (id, note_id, status, attempts, last_error, force, available_at, requested_at, created_at, updated_at)
+		SELECT gen_random_uuid(), n.id, 'pending', 0, '', @force, NOW(), clock_timestamp(), NOW(), NOW()
+		FROM notes n
+		WHERE n.id IN @note_ids
+		ON CONFLICT (note_id) DO UPDATE SET
+			status       = CASE WHEN embedding_jobs.status = 'processing' THEN 'processing' ELSE 'pending' END,
+			attempts     = CASE WHEN embedding_jobs.status = 'processing' THEN embedding_jobs.attempts ELSE 0 END,
+			force        = embedding_jobs.force OR EXCLUDED.force,
+			available_at = NOW(),
+			requested_at = clock_timestamp(),
+			last_error   = '',
+			updated_at   = NOW()`,
+		map[string]interface{}{"note_ids": noteIds, "force": force},
+	).Error
+}
+
+func (r *EmbeddingJobRepositoryImpl) EnqueueAll(ctx context.Context, exceptModel string) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO embedding_jobs (id, note_id, status, attempts, last_error, force, available_at, requested_at, created_at, updated_at)
+		SELECT gen_random_uuid(), n.id, 'pending', 0, '', TRUE, NOW(), clock_timestamp(), NOW(), NOW()
+		FROM notes n
+		WHERE n.deleted_at IS NULL
+		  AND (@except_model = '' OR NOT EXISTS (
+			SELECT 1 FROM embedding_jobs j
+			WHERE j.note_id = n.id AND j.status = 'completed' AND j.model_name = @except_model
+		  ))
+		ON CONFLICT (note_id) DO UPDATE SET
+			status       = CASE WHEN embedding_jobs.status = 'processing' THEN 'processing' ELSE 'pending' END,
+			attempts     = CASE WHEN embedding_jobs.status = 'processing' THEN embedding_jobs.attempts ELSE 0 END,
+			force        = TRUE,
+			available_at = NOW(),
+			requested_at = clock_timestamp(),
+			last_error   = '',
+			updated_at   = NOW()`,
+		map[string]interface{}{"except_model": exceptModel},
+	)
+	return result.RowsAffected, result.Error
+}
+
+func (r *EmbeddingJobRepositoryImpl) ClaimBatch(ctx context.Context, limit int, staleAfter time.Duration) ([]*entity.EmbeddingJob, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var models []*model.EmbeddingJob
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE embedding_jobs SET
+			status     = 'processing',
+			locked_at  = NOW(),
+			attempts   = attempts + 1,
+			updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM embedding_jobs
+			WHERE (status = 'pending' AND available_at <= NOW())
+			   OR (status = 'processing' AND locked_at < @stale_before)
+			ORDER BY available_at
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		map[string]interface{}{
+			"stale_before": time.Now().Add(-staleAfter),
+			"limit":        limit,
+		},
+	).Scan(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.EmbeddingJob, len(models))
+	for i, m := range models {
+		entities[i] = r.mapper.ToEntity(m)
+	}
+	return entities, nil
+}
+
+func (r *EmbeddingJobRepositoryImpl) MarkCompleted(ctx context.Context, job *entity.EmbeddingJob) error {
+	// If requested_at moved, the note changed while we were embedding: run again.
+	// The stored vectors are still the ones just written, so hash/model are recorded either way.
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE embedding_jobs SET
+			status       = CASE WHEN requested_at = @requested_at THEN 'completed' ELSE 'pending' END,
+			attempts     = CASE WHEN requested_at = @requested_at THEN attempts ELSE 0 END,
+			force        = CASE WHEN requested_at = @requested_at THEN FALSE ELSE force END,
+			available_at = NOW(),
+			locked_at    = NULL,
+			last_error   = '',
+			content_hash = @content_hash,
+			model_name   = @model_name,
+			indexed_at   = NOW(),
+			updated_at   = NOW()
+		WHERE id = @id`,
+		map[string]interface{}{
+			"id":           job.Id,
+			"requested_at": job.RequestedAt,
+			"content_hash": job.ContentHash,
+			"model_name":   job.ModelName,
+		},
+	).Error
+}
+
+func (r *EmbeddingJobRepositoryImpl) MarkFailed(ctx context.Context, job *entity.EmbeddingJob, errMsg string, retryAt time.Time, dead bool) error {
+	// A newer request resets the retry budget - the new content may not hit the same failure
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE embedding_jobs SET
+			status       = CASE WHEN requested_at <> @requested_at THEN 'pending'
+			                    WHEN @dead THEN 'dead'
+			                    ELSE 'pending' END,
+			attempts     = CASE WHEN requested_at <> @requested_at THEN 0 ELSE attempts END,
+			available_at = CASE WHEN requested_at <> @requested_at THEN NOW() ELSE @retry_at END,
+			locked_at    = NULL,
+			last_error   = @last_error,
+			updated_at   = NOW()
+		WHERE id = @id`,
+		map[string]interface{}{
+			"id":           job.Id,
+			"requested_at": job.RequestedAt,
+			"dead":         dead,
+			"retry_at":     retryAt,
+			"last_error":   errMsg,
+		},
+	).Error
+}
+
+func (r *EmbeddingJobRepositoryImpl) DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error {
+	if len(noteIds) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("note_id IN ?", noteIds).Delete(&model.EmbeddingJob{}).Error
+}
+
+func (r *EmbeddingJobRepositoryImpl) CountByStatus(ctx context.Context, specs ...specification.Specification) (map[entity.EmbeddingJobStatus]int64, error) {
+	var rows []struct {
+		Status string
+		Total  int64
+	}
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	err := query.
+		Model(&model.EmbeddingJob{}).
+		Select("status, COUNT(*) AS total").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entity.EmbeddingJobStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[entity.EmbeddingJobStatus(row.Status)] = row.Total
+	}
+	return counts, nil
+}
+
+func (r *EmbeddingJobRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.EmbeddingJob, error) {
+	var m model.EmbeddingJob
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.mapper.ToEntity(&m), nil
+}
+
+func (r *EmbeddingJobRepositoryImpl) FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.EmbeddingJob, error) {
+	var models []*model.EmbeddingJob
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	entities := make([]*entity.EmbeddingJob, len(models))
+	for i, m := range models {
+		entities[i] = r.mapper.ToEntity(m)
+	}
+	return entities, nil
+}
+
+func (r *EmbeddingJobRepositoryImpl) Count(ctx context.Context, specs ...specification.Specification) (int64, error) {
+	var count int64
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	err := query.Model(&model.EmbeddingJob{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,86 @@
+package implementation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ImportJobRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.ImportJobMapper
+}
+
+func NewImportJobRepository(db *gorm.DB) contract.ImportJobRepository {
+	return &ImportJobRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewImportJobMapper(),
+	}
+}
+
+func (r *ImportJobRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+func (r *ImportJobRepositoryImpl) Create(ctx context.Context, job *entity.ImportJob) error {
+	m := r.mapper.ToModel(job)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	*job = *r.mapper.ToEntity(m)
+	return nil
+}
+
+func (r *ImportJobRepositoryImpl) Update(ctx context.Context, job *entity.ImportJob) error {
+	m := r.mapper.ToModel(job)
+	return r.db.WithContext(ctx).Save(m).Error
+}
+
+func (r *ImportJobRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.ImportJob, error) {
+	var m model.ImportJob
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.mapper.ToEntity(&m), nil
+}
+
+func (r *ImportJobRepositoryImpl) FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.ImportJob, error) {
+	var models []*model.ImportJob
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToEntities(models), nil
+}
+
+func (r *ImportJobRepositoryImpl) FailUnfinished(ctx context.Context, reason string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.ImportJob{}).
+		Where("status IN ?", []string{string(entity.ImportJobStatusPending), string(entity.ImportJobStatusRunning)}).
+		Updates(map[string]interface{}{
+			"status":      string(entity.ImportJobStatusFailed),
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *ImportJobRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userId).Delete(&model.ImportJob{}).Error
+}
@@ -0,0 +1,88 @@
+package implementation
+
+import (
+	"context"
+	"errors"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NoteAttachmentRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.NoteAttachmentMapper
+}
+
+func NewNoteAttachmentRepository(db *gorm.DB) contract.NoteAttachmentRepository {
+	return &NoteAttachmentRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewNoteAttachmentMapper(),
+	}
+}
+
+func (r *NoteAttachmentRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+func (r *NoteAttachmentRepositoryImpl) Create(ctx context.Context, attachment *entity.NoteAttachment) error {
+	m := r.mapper.ToModel(attachment)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	*attachment = *r.mapper.ToEntity(m)
+	return nil
+}
+
+func (r *NoteAttachmentRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.NoteAttachment{}, id).Error
+}
+
+func (r *NoteAttachmentRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.NoteAttachment, error) {
+	var m model.NoteAttachment
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.mapper.ToEntity(&m), nil
+}
+
+func (r *NoteAttachmentRepositoryImpl) FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NoteAttachment, error) {
+	var models []*model.NoteAttachment
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToEntities(models), nil
+}
+
+func (r *NoteAttachmentRepositoryImpl) SumSize(ctx context.Context, specs ...specification.Specification) (int64, error) {
+	var total int64
+	query := r.applySpecifications(r.db.WithContext(ctx).Model(&model.NoteAttachment{}), specs...)
+	if err := query.Select("COALESCE(SUM(size), 0)").Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *NoteAttachmentRepositoryImpl) DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error {
+	if len(noteIds) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("note_id IN ?", noteIds).Delete(&model.NoteAttachment{}).Error
+}
+
+func (r *NoteAttachmentRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userId).Delete(&model.NoteAttachment{}).Error
+}
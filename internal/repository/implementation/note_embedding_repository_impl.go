@@ -60,6 +60,13 @@ func (r *NoteEmbeddingRepositoryImpl) DeleteByNoteId(ctx context.Context, noteId
 	return r.db.WithContext(ctx).Where("note_id = ?", noteId).Delete(&model.NoteEmbedding{}).Error
 }
 
+func (r *NoteEmbeddingRepositoryImpl) DeleteByIds(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.NoteEmbedding{}).Error
+}
+
 func (r *NoteEmbeddingRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error {
 	// Subquery to find note IDs for the user
 	subQuery := r.db.Table("notes").Select("id").Where("user_id = ?", userId)
@@ -71,6 +78,20 @@ func (r *NoteEmbeddingRepositoryImpl) DeleteByNotebookId(ctx context.Context, no
 	return r.db.WithContext(ctx).Where("note_id IN (?)", subQuery).Delete(&model.NoteEmbedding{}).Error
 }
 
+func (r *NoteEmbeddingRepositoryImpl) DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error {
+	if len(noteIds) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("note_id IN ?", noteIds).Delete(&model.NoteEmbedding{}).Error
+}
+
+func (r *NoteEmbeddingRepositoryImpl) DeleteByNoteIdsUnscoped(ctx context.Context, noteIds []uuid.UUID) error {
+	if len(noteIds) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Unscoped().Where("note_id IN ?", noteIds).Delete(&model.NoteEmbedding{}).Error
+}
+
 func (r *NoteEmbeddingRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.NoteEmbedding, error) {
 	var m model.NoteEmbedding
 	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
@@ -110,11 +131,11 @@ func (r *NoteEmbeddingRepositoryImpl) SearchSimilar(ctx context.Context, embeddi
 	var models []*model.NoteEmbedding
 
 	// Using pgvector cosine distance: embedding_value <=> vector
-	// We MUST join with 'notes' to filter by user_id
+	// We MUST join with 'notes' to filter by owner or notebook membership
 	// CRITICAL: Filter out soft-deleted embeddings AND notes
 	err := r.db.WithContext(ctx).
 		Joins("JOIN notes ON notes.id = note_embeddings.note_id").
-		Where("notes.user_id = ?", userId).
+		Scopes(specification.NoteAccessibleBy{UserID: userId}.Apply).
 		Where("note_embeddings.deleted_at IS NULL").
 		Where("notes.deleted_at IS NULL").
 		Order(gorm.Expr("embedding_value <=> ?", pgvector.NewVector(embedding))).
@@ -133,7 +154,7 @@ func (r *NoteEmbeddingRepositoryImpl) SearchSimilar(ctx context.Context, embeddi
 }
 
 // SearchSimilarWithScore returns embeddings with similarity scores, filtered by threshold
-func (r *NoteEmbeddingRepositoryImpl) SearchSimilarWithScore(ctx context.Context, embedding []float32, limit int, userId uuid.UUID, threshold float64) ([]*contract.ScoredNoteEmbedding, error) {
+func (r *NoteEmbeddingRepositoryImpl) SearchSimilarWithScore(ctx context.Context, embedding []float32, limit int, userId uuid.UUID, threshold float64, specs ...specification.Specification) ([]*contract.ScoredNoteEmbedding, error) {
 	if limit <= 0 {
 		limit = 5
 	}
@@ -149,14 +170,16 @@ func (r *NoteEmbeddingRepositoryImpl) SearchSimilarWithScore(ctx context.Context
 
 	queryVector := pgvector.NewVector(embedding)
 
-	err := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx).
 		Table("note_embeddings").
 		Select("note_embeddings.*, 1 - (embedding_value <=> ?) as similarity", queryVector).
 		Joins("JOIN notes ON notes.id = note_embeddings.note_id").
-		Where("notes.user_id = ?", userId).
+		Scopes(specification.NoteAccessibleBy{UserID: userId}.Apply).
 		Where("note_embeddings.deleted_at IS NULL").
 		Where("notes.deleted_at IS NULL").
-		Where("1 - (embedding_value <=> ?) >= ?", queryVector, threshold).
+		Where("1 - (embedding_value <=> ?) >= ?", queryVector, threshold)
+
+	err := r.applySpecifications(query, specs...).
 		Order("similarity DESC").
 		Limit(limit).
 		Scan(&results).Error
@@ -0,0 +1,83 @@
+package implementation
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NoteLinkRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.NoteLinkMapper
+}
+
+func NewNoteLinkRepository(db *gorm.DB) contract.NoteLinkRepository {
+	return &NoteLinkRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewNoteLinkMapper(),
+	}
+}
+
+func (r *NoteLinkRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+func (r *NoteLinkRepositoryImpl) ReplaceForNote(ctx context.Context, sourceNoteId uuid.UUID, links []*entity.NoteLink) error {
+	if err := r.db.WithContext(ctx).Where("source_note_id = ?", sourceNoteId).Delete(&model.NoteLink{}).Error; err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	models := make([]*model.NoteLink, len(links))
+	for i, link := range links {
+		models[i] = r.mapper.ToModel(link)
+	}
+	return r.db.WithContext(ctx).Create(&models).Error
+}
+
+func (r *NoteLinkRepositoryImpl) FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NoteLink, error) {
+	var models []*model.NoteLink
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToEntities(models), nil
+}
+
+func (r *NoteLinkRepositoryImpl) ResolveTitle(ctx context.Context, userId uuid.UUID, title string, noteId uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.NoteLink{}).
+		Where("user_id = ? AND target_note_id IS NULL AND kind = ? AND LOWER(target) = LOWER(TRIM(?))", userId, "wiki", title).
+		Where("source_note_id <> ?", noteId).
+		Update("target_note_id", noteId)
+	return result.RowsAffected, result.Error
+}
+
+func (r *NoteLinkRepositoryImpl) DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error {
+	if len(noteIds) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Where("source_note_id IN ?", noteIds).Delete(&model.NoteLink{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.NoteLink{}).
+		Where("target_note_id IN ?", noteIds).
+		Update("target_note_id", nil).Error
+}
+
+func (r *NoteLinkRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&model.NoteLink{}).Error
+}
@@ -3,6 +3,9 @@ package implementation
 import (
 	"context"
 	"errors"
+	"html"
+	"strings"
+	"time"
 
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/mapper"
@@ -44,10 +47,40 @@ func (r *NoteRepositoryImpl) Create(ctx context.Context, note *entity.Note) erro
 
 func (r *NoteRepositoryImpl) Update(ctx context.Context, note *entity.Note) error {
 	m := r.mapper.ToModel(note)
-	if err := r.db.WithContext(ctx).Save(m).Error; err != nil {
-		return err
+	return r.updateVersioned(ctx, note, map[string]interface{}{
+		"title":       m.Title,
+		"content":     m.Content,
+		"search_text": m.SearchText,
+	})
+}
+
+func (r *NoteRepositoryImpl) Move(ctx context.Context, note *entity.Note) error {
+	return r.updateVersioned(ctx, note, map[string]interface{}{
+		"notebook_id": note.NotebookId,
+	})
+}
+
+// updateVersioned writes only the given columns, so a save racing a trash, move or restore cannot
+// undo it, and bumps the version in SQL when the stored version is still note.Version
+func (r *NoteRepositoryImpl) updateVersioned(ctx context.Context, note *entity.Note, columns map[string]interface{}) error {
+	updatedAt := time.Now()
+	if note.UpdatedAt != nil {
+		updatedAt = *note.UpdatedAt
 	}
-	*note = *r.mapper.ToEntity(m)
+	columns["updated_at"] = updatedAt
+	columns["version"] = gorm.Expr("version + 1")
+
+	res := r.db.WithContext(ctx).Model(&model.Note{}).
+		Where("id = ? AND version = ?", note.Id, note.Version).
+		Updates(columns)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return contract.ErrStaleVersion
+	}
+	note.UpdatedAt = &updatedAt
+	note.Version++
 	return nil
 }
 
@@ -59,6 +92,31 @@ func (r *NoteRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, user
 	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userId).Delete(&model.Note{}).Error
 }
 
+func (r *NoteRepositoryImpl) MoveToTrash(ctx context.Context, ids []uuid.UUID, rootId uuid.UUID, deletedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&model.Note{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"deleted_at": deletedAt, "trash_root": rootId}).Error
+}
+
+func (r *NoteRepositoryImpl) Restore(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Unscoped().Model(&model.Note{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"deleted_at": nil, "trash_root": nil}).Error
+}
+
+func (r *NoteRepositoryImpl) DeleteUnscopedByIds(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Delete(&model.Note{}).Error
+}
+
 func (r *NoteRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.Note, error) {
 	var m model.Note
 	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
@@ -88,3 +146,74 @@ func (r *NoteRepositoryImpl) Count(ctx context.Context, specs ...specification.S
 	}
 	return count, nil
 }
+
+func (r *NoteRepositoryImpl) FullTextSearch(ctx context.Context, query string, limit int, specs ...specification.Specification) ([]*contract.ScoredNote, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	type result struct {
+		model.Note
+		Rank float64
+	}
+	var results []result
+
+	// Normalization 32 maps the rank into 0..1 (rank / (rank + 1))
+	db := r.applySpecifications(r.db.WithContext(ctx).Model(&model.Note{}), specs...)
+	err := db.
+		Select("notes.*, ts_rank_cd(notes.search_vector, websearch_to_tsquery('simple', ?), 32) AS rank", query).
+		Where("notes.deleted_at IS NULL").
+		Where("notes.search_vector @@ websearch_to_tsquery('simple', ?)", query).
+		Order("rank DESC").
+		Limit(limit).
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	scored := make([]*contract.ScoredNote, len(results))
+	for i, res := range results {
+		scored[i] = &contract.ScoredNote{
+			Note: r.mapper.ToEntity(&res.Note),
+			Rank: res.Rank,
+		}
+	}
+	return scored, nil
+}
+
+// Markers ts_headline puts around matches. Snippets are escaped before they become <mark> tags,
+// since note text is returned as HTML to everyone who can read the note.
+const (
+	headlineStartSel = "\x02"
+	headlineStopSel  = "\x03"
+)
+
+var headlineMarks = strings.NewReplacer(headlineStartSel, "<mark>", headlineStopSel, "</mark>")
+
+func (r *NoteRepositoryImpl) Headlines(ctx context.Context, ids []uuid.UUID, query string) (map[uuid.UUID]string, error) {
+	headlines := make(map[uuid.UUID]string, len(ids))
+	if len(ids) == 0 {
+		return headlines, nil
+	}
+
+	var rows []struct {
+		Id      uuid.UUID
+		Snippet string
+	}
+	err := r.db.WithContext(ctx).
+		Table("notes").
+		Select(`id, ts_headline('simple', coalesce(search_text, ''), websearch_to_tsquery('simple', ?), ?) AS snippet`,
+			query,
+			`StartSel="`+headlineStartSel+`", StopSel="`+headlineStopSel+`", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" ... "`,
+		).
+		Where("id IN ?", ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		headlines[row.Id] = headlineMarks.Replace(html.EscapeString(row.Snippet))
+	}
+	return headlines, nil
+}
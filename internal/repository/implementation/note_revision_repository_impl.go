@@ -0,0 +1,104 @@
+package implementation
+
+import (
+	"context"
+	"errors"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NoteRevisionRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.NoteRevisionMapper
+}
+
+func NewNoteRevisionRepository(db *gorm.DB) contract.NoteRevisionRepository {
+	return &NoteRevisionRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewNoteRevisionMapper(),
+	}
+}
+
+func (r *NoteRevisionRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+func (r *NoteRevisionRepositoryImpl) Create(ctx context.Context, revision *entity.NoteRevision) error {
+	// Serialize numbering per note: concurrent saves wait here until the first one commits
+	var locked []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw("SELECT id FROM notes WHERE id = ? FOR UPDATE", revision.NoteId).
+		Scan(&locked).Error
+	if err != nil {
+		return err
+	}
+
+	var next int
+	err = r.db.WithContext(ctx).
+		Raw("SELECT COALESCE(MAX(number), 0) + 1 FROM note_revisions WHERE note_id = ?", revision.NoteId).
+		Scan(&next).Error
+	if err != nil {
+		return err
+	}
+	revision.Number = next
+
+	m := r.mapper.ToModel(revision)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	*revision = *r.mapper.ToEntity(m)
+	return nil
+}
+
+func (r *NoteRevisionRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.NoteRevision, error) {
+	var m model.NoteRevision
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.mapper.ToEntity(&m), nil
+}
+
+func (r *NoteRevisionRepositoryImpl) FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NoteRevision, error) {
+	var models []*model.NoteRevision
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToEntities(models), nil
+}
+
+func (r *NoteRevisionRepositoryImpl) Count(ctx context.Context, specs ...specification.Specification) (int64, error) {
+	var count int64
+	query := r.applySpecifications(r.db.WithContext(ctx).Model(&model.NoteRevision{}), specs...)
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *NoteRevisionRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("note_id IN (SELECT id FROM notes WHERE user_id = ?)", userId).
+		Delete(&model.NoteRevision{}).Error
+}
+
+func (r *NoteRevisionRepositoryImpl) DeleteByNoteIds(ctx context.Context, noteIds []uuid.UUID) error {
+	if len(noteIds) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("note_id IN ?", noteIds).Delete(&model.NoteRevision{}).Error
+}
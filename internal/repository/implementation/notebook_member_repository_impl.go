@@ -0,0 +1,80 @@
+package implementation
+
+import (
+	"context"
+	"errors"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotebookMemberRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.NotebookMemberMapper
+}
+
+func NewNotebookMemberRepository(db *gorm.DB) contract.NotebookMemberRepository {
+	return &NotebookMemberRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewNotebookMemberMapper(),
+	}
+}
+
+func (r *NotebookMemberRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+func (r *NotebookMemberRepositoryImpl) Create(ctx context.Context, member *entity.NotebookMember) error {
+	m := r.mapper.ToModel(member)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	*member = *r.mapper.ToEntity(m)
+	return nil
+}
+
+func (r *NotebookMemberRepositoryImpl) Update(ctx context.Context, member *entity.NotebookMember) error {
+	m := r.mapper.ToModel(member)
+	return r.db.WithContext(ctx).Save(m).Error
+}
+
+func (r *NotebookMemberRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.NotebookMember{}).Error
+}
+
+func (r *NotebookMemberRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.NotebookMember, error) {
+	var m model.NotebookMember
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.mapper.ToEntity(&m), nil
+}
+
+func (r *NotebookMemberRepositoryImpl) FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.NotebookMember, error) {
+	var models []*model.NotebookMember
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToEntities(models), nil
+}
+
+func (r *NotebookMemberRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error {
+	subQuery := r.db.Table("notebooks").Select("id").Where("user_id = ?", userId)
+	return r.db.WithContext(ctx).
+		Where("user_id = ? OR notebook_id IN (?)", userId, subQuery).
+		Delete(&model.NotebookMember{}).Error
+}
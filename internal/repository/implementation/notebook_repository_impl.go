@@ -3,6 +3,7 @@ package implementation
 import (
 	"context"
 	"errors"
+	"time"
 
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/mapper"
@@ -44,10 +45,14 @@ func (r *NotebookRepositoryImpl) Create(ctx context.Context, notebook *entity.No
 
 func (r *NotebookRepositoryImpl) Update(ctx context.Context, notebook *entity.Notebook) error {
 	m := r.mapper.ToModel(notebook)
-	// Use Select("*") or explicit fields if you want to update zero values too,
-	// but generally GORM Updates ignores zero values. Save() updates all fields including zero values if primary key exists.
-	if err := r.db.WithContext(ctx).Save(m).Error; err != nil {
-		return err
+	m.Version = notebook.Version + 1
+	// Select("*") writes zero values too (like Save), while the version condition rejects stale copies
+	res := r.db.WithContext(ctx).Select("*").Where("version = ?", notebook.Version).Updates(m)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return contract.ErrStaleVersion
 	}
 	*notebook = *r.mapper.ToEntity(m)
 	return nil
@@ -61,6 +66,43 @@ func (r *NotebookRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context,
 	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userId).Delete(&model.Notebook{}).Error
 }
 
+func (r *NotebookRepositoryImpl) MoveToTrash(ctx context.Context, ids []uuid.UUID, rootId uuid.UUID, deletedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&model.Notebook{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"deleted_at": deletedAt, "trash_root": rootId}).Error
+}
+
+func (r *NotebookRepositoryImpl) Restore(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Unscoped().Model(&model.Notebook{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"deleted_at": nil, "trash_root": nil}).Error
+}
+
+func (r *NotebookRepositoryImpl) DeleteUnscopedByIds(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Delete(&model.Notebook{}).Error
+}
+
+func (r *NotebookRepositoryImpl) FindSubtreeIds(ctx context.Context, rootId uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM notebooks WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT n.id FROM notebooks n JOIN subtree s ON n.parent_id = s.id WHERE n.deleted_at IS NULL
+		)
+		SELECT id FROM subtree`, rootId).Scan(&ids).Error
+	return ids, err
+}
+
 func (r *NotebookRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.Notebook, error) {
 	var m model.Notebook
 	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
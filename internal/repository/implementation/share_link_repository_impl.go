@@ -0,0 +1,84 @@
+package implementation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShareLinkRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.ShareLinkMapper
+}
+
+func NewShareLinkRepository(db *gorm.DB) contract.ShareLinkRepository {
+	return &ShareLinkRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewShareLinkMapper(),
+	}
+}
+
+func (r *ShareLinkRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+func (r *ShareLinkRepositoryImpl) Create(ctx context.Context, link *entity.ShareLink) error {
+	m := r.mapper.ToModel(link)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	*link = *r.mapper.ToEntity(m)
+	return nil
+}
+
+func (r *ShareLinkRepositoryImpl) Update(ctx context.Context, link *entity.ShareLink) error {
+	m := r.mapper.ToModel(link)
+	return r.db.WithContext(ctx).Save(m).Error
+}
+
+func (r *ShareLinkRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.ShareLink, error) {
+	var m model.ShareLink
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.mapper.ToEntity(&m), nil
+}
+
+func (r *ShareLinkRepositoryImpl) FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.ShareLink, error) {
+	var models []*model.ShareLink
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToEntities(models), nil
+}
+
+func (r *ShareLinkRepositoryImpl) RecordView(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.ShareLink{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"view_count":     gorm.Expr("view_count + 1"),
+			"last_viewed_at": at,
+		}).Error
+}
+
+func (r *ShareLinkRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userId).Delete(&model.ShareLink{}).Error
+}
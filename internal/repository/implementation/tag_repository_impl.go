@@ -0,0 +1,185 @@
+package implementation
+
+import (
+	"context"
+	"errors"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/mapper"
+	"ai-notetaking-be/internal/model"
+	"ai-notetaking-be/internal/repository/contract"
+	"ai-notetaking-be/internal/repository/specification"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepositoryImpl struct {
+	db     *gorm.DB
+	mapper *mapper.TagMapper
+}
+
+func NewTagRepository(db *gorm.DB) contract.TagRepository {
+	return &TagRepositoryImpl{
+		db:     db,
+		mapper: mapper.NewTagMapper(),
+	}
+}
+
+func (r *TagRepositoryImpl) applySpecifications(db *gorm.DB, specs ...specification.Specification) *gorm.DB {
+	for _, spec := range specs {
+		db = spec.Apply(db)
+	}
+	return db
+}
+
+func (r *TagRepositoryImpl) Create(ctx context.Context, tag *entity.Tag) error {
+	m := r.mapper.ToModel(tag)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	*tag = *r.mapper.ToEntity(m)
+	return nil
+}
+
+func (r *TagRepositoryImpl) Update(ctx context.Context, tag *entity.Tag) error {
+	m := r.mapper.ToModel(tag)
+	if err := r.db.WithContext(ctx).Save(m).Error; err != nil {
+		return err
+	}
+	*tag = *r.mapper.ToEntity(m)
+	return nil
+}
+
+func (r *TagRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Where("tag_id = ?", id).Delete(&model.NoteTag{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Delete(&model.Tag{}, id).Error
+}
+
+func (r *TagRepositoryImpl) FindOne(ctx context.Context, specs ...specification.Specification) (*entity.Tag, error) {
+	var m model.Tag
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.mapper.ToEntity(&m), nil
+}
+
+func (r *TagRepositoryImpl) FindAll(ctx context.Context, specs ...specification.Specification) ([]*entity.Tag, error) {
+	var models []*model.Tag
+	query := r.applySpecifications(r.db.WithContext(ctx), specs...)
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.mapper.ToEntities(models), nil
+}
+
+func (r *TagRepositoryImpl) Count(ctx context.Context, specs ...specification.Specification) (int64, error) {
+	var count int64
+	query := r.applySpecifications(r.db.WithContext(ctx).Model(&model.Tag{}), specs...)
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *TagRepositoryImpl) Attach(ctx context.Context, tagIds []uuid.UUID, noteIds []uuid.UUID) (int64, error) {
+	if len(tagIds) == 0 || len(noteIds) == 0 {
+		return 0, nil
+	}
+	links := make([]model.NoteTag, 0, len(tagIds)*len(noteIds))
+	for _, noteId := range noteIds {
+		for _, tagId := range tagIds {
+			links = append(links, model.NoteTag{NoteId: noteId, TagId: tagId})
+		}
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&links)
+	return result.RowsAffected, result.Error
+}
+
+func (r *TagRepositoryImpl) Detach(ctx context.Context, tagIds []uuid.UUID, noteIds []uuid.UUID) (int64, error) {
+	if len(tagIds) == 0 || len(noteIds) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Where("tag_id IN ? AND note_id IN ?", tagIds, noteIds).
+		Delete(&model.NoteTag{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *TagRepositoryImpl) FindByNoteIds(ctx context.Context, noteIds []uuid.UUID) (map[uuid.UUID][]*entity.Tag, error) {
+	res := make(map[uuid.UUID][]*entity.Tag)
+	if len(noteIds) == 0 {
+		return res, nil
+	}
+
+	type row struct {
+		model.Tag
+		NoteId uuid.UUID
+	}
+	var rows []row
+	err := r.db.WithContext(ctx).
+		Table("note_tags").
+		Select("tags.*, note_tags.note_id").
+		Joins("JOIN tags ON tags.id = note_tags.tag_id").
+		Where("note_tags.note_id IN ?", noteIds).
+		Order("LOWER(tags.name)").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		res[rows[i].NoteId] = append(res[rows[i].NoteId], r.mapper.ToEntity(&rows[i].Tag))
+	}
+	return res, nil
+}
+
+func (r *TagRepositoryImpl) CountNotes(ctx context.Context, tagIds []uuid.UUID) (map[uuid.UUID]int64, error) {
+	res := make(map[uuid.UUID]int64)
+	if len(tagIds) == 0 {
+		return res, nil
+	}
+
+	var rows []struct {
+		TagId uuid.UUID
+		Count int64
+	}
+	err := r.db.WithContext(ctx).
+		Table("note_tags").
+		Select("note_tags.tag_id, COUNT(*) AS count").
+		Joins("JOIN notes ON notes.id = note_tags.note_id AND notes.deleted_at IS NULL").
+		Where("note_tags.tag_id IN ?", tagIds).
+		Group("note_tags.tag_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		res[row.TagId] = row.Count
+	}
+	return res, nil
+}
+
+func (r *TagRepositoryImpl) DetachAllFromNotes(ctx context.Context, noteIds []uuid.UUID) error {
+	if len(noteIds) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("note_id IN ?", noteIds).Delete(&model.NoteTag{}).Error
+}
+
+func (r *TagRepositoryImpl) DeleteAllByUserIdUnscoped(ctx context.Context, userId uuid.UUID) error {
+	if err := r.db.WithContext(ctx).
+		Where("tag_id IN (SELECT id FROM tags WHERE user_id = ?)", userId).
+		Delete(&model.NoteTag{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&model.Tag{}).Error
+}
@@ -1,36 +1,52 @@
 package memory
 
 import (
-	"ai-notetaking-be/pkg/store"
+	"context"
+	"log"
 	"time"
 
+	"ai-notetaking-be/pkg/store"
+
 	"github.com/patrickmn/go-cache"
 )
 
+// SessionRepository keeps chat session state in process memory.
+// It only suits a single instance; state is lost on restart and rebuilt from the chat history.
 type SessionRepository struct {
 	cache *cache.Cache
 }
 
-func NewSessionRepository() *SessionRepository {
-	// Create a cache with a default expiration time of 1 hour, and which
-	// purges expired items every 10 minutes
-	c := cache.New(1*time.Hour, 10*time.Minute)
+func NewSessionRepository(ttl time.Duration) *SessionRepository {
+	// Purge expired items every 10 minutes
+	c := cache.New(ttl, 10*time.Minute)
 	return &SessionRepository{
 		cache: c,
 	}
 }
 
-func (r *SessionRepository) Save(session *store.Session) {
-	r.cache.Set(session.ID, session, cache.DefaultExpiration)
+// Save stores an encoded copy, so later changes to the session are only kept by saving again (as with Redis)
+func (r *SessionRepository) Save(ctx context.Context, session *store.Session) {
+	data, err := store.EncodeSession(session)
+	if err != nil {
+		log.Printf("[WARN] Failed to encode chat session %s: %v", session.ID, err)
+		return
+	}
+	r.cache.Set(session.ID, data, cache.DefaultExpiration)
 }
 
-func (r *SessionRepository) Get(sessionID string) (*store.Session, bool) {
-	if x, found := r.cache.Get(sessionID); found {
-		return x.(*store.Session), true
+func (r *SessionRepository) Get(ctx context.Context, sessionID string) (*store.Session, bool) {
+	x, found := r.cache.Get(sessionID)
+	if !found {
+		return nil, false
+	}
+	session, err := store.DecodeSession(x.([]byte))
+	if err != nil {
+		log.Printf("[WARN] Discarding chat session %s: %v", sessionID, err)
+		return nil, false
 	}
-	return nil, false
+	return session, true
 }
 
-func (r *SessionRepository) Delete(sessionID string) {
+func (r *SessionRepository) Delete(ctx context.Context, sessionID string) {
 	r.cache.Delete(sessionID)
 }
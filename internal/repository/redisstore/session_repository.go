@@ -0,0 +1,63 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"ai-notetaking-be/pkg/store"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const sessionKeyPrefix = "chat:session:"
+
+// SessionRepository keeps chat session state in Redis, shared by all instances and surviving deploys.
+// Every save renews the TTL, so only idle sessions expire.
+type SessionRepository struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewSessionRepository(rdb *redis.Client, ttl time.Duration) *SessionRepository {
+	return &SessionRepository{
+		rdb: rdb,
+		ttl: ttl,
+	}
+}
+
+func (r *SessionRepository) Save(ctx context.Context, session *store.Session) {
+	data, err := store.EncodeSession(session)
+	if err != nil {
+		log.Printf("[WARN] Failed to encode chat session %s: %v", session.ID, err)
+		return
+	}
+	if err := r.rdb.Set(ctx, sessionKeyPrefix+session.ID, data, r.ttl).Err(); err != nil {
+		log.Printf("[WARN] Failed to save chat session %s: %v", session.ID, err)
+	}
+}
+
+func (r *SessionRepository) Get(ctx context.Context, sessionID string) (*store.Session, bool) {
+	data, err := r.rdb.Get(ctx, sessionKeyPrefix+sessionID).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("[WARN] Failed to load chat session %s: %v", sessionID, err)
+		return nil, false
+	}
+
+	session, err := store.DecodeSession(data)
+	if err != nil {
+		log.Printf("[WARN] Discarding chat session %s: %v", sessionID, err)
+		return nil, false
+	}
+	return session, true
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, sessionID string) {
+	if err := r.rdb.Del(ctx, sessionKeyPrefix+sessionID).Err(); err != nil {
+		log.Printf("[WARN] Failed to delete chat session %s: %v", sessionID, err)
+	}
+}
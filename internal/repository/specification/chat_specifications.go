@@ -1,6 +1,8 @@
 package specification
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,3 +14,12 @@ type ByChatSessionID struct {
 func (s ByChatSessionID) Apply(db *gorm.DB) *gorm.DB {
 	return db.Where("chat_session_id = ?", s.ChatSessionID)
 }
+
+// CreatedAfter filters rows created strictly after the given time
+type CreatedAfter struct {
+	Time time.Time
+}
+
+func (s CreatedAfter) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("created_at > ?", s.Time)
+}
@@ -0,0 +1,37 @@
+package specification
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ByNoteID struct {
+	NoteID uuid.UUID
+}
+
+func (s ByNoteID) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("note_id = ?", s.NoteID)
+}
+
+type ByEmbeddingJobStatus struct {
+	Status string
+}
+
+func (s ByEmbeddingJobStatus) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ?", s.Status)
+}
+
+type ByEmbeddingModel struct {
+	ModelName string
+}
+
+func (s ByEmbeddingModel) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("model_name = ?", s.ModelName)
+}
+
+// ForLiveNotes excludes jobs whose note has been deleted
+type ForLiveNotes struct{}
+
+func (s ForLiveNotes) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("note_id IN (SELECT id FROM notes WHERE deleted_at IS NULL)")
+}
@@ -0,0 +1,13 @@
+package specification
+
+import (
+	"gorm.io/gorm"
+)
+
+type ByImportJobStatuses struct {
+	Statuses []string
+}
+
+func (s ByImportJobStatuses) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("status IN ?", s.Statuses)
+}
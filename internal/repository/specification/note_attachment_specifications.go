@@ -0,0 +1,21 @@
+package specification
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ByAttachmentNoteIDs struct {
+	NoteIDs []uuid.UUID
+}
+
+func (s ByAttachmentNoteIDs) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("note_attachments.note_id IN ?", s.NoteIDs)
+}
+
+// WithExtractedText filters attachments whose text was extracted for indexing
+type WithExtractedText struct{}
+
+func (s WithExtractedText) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("note_attachments.extracted_text <> ''")
+}
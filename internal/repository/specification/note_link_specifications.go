@@ -0,0 +1,33 @@
+package specification
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ByLinkSources filters note links found in any of the notes
+type ByLinkSources struct {
+	NoteIDs []uuid.UUID
+}
+
+func (s ByLinkSources) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("note_links.source_note_id IN ?", s.NoteIDs)
+}
+
+// ByLinkTarget filters note links pointing at the note
+type ByLinkTarget struct {
+	NoteID uuid.UUID
+}
+
+func (s ByLinkTarget) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("note_links.target_note_id = ?", s.NoteID)
+}
+
+// ByNoteTitleExact filters notes whose title equals Title, ignoring case and surrounding spaces
+type ByNoteTitleExact struct {
+	Title string
+}
+
+func (s ByNoteTitleExact) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("LOWER(TRIM(notes.title)) = LOWER(TRIM(?))", s.Title)
+}
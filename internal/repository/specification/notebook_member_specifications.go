@@ -0,0 +1,86 @@
+package specification
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// sharedNotebookIds selects the notebooks shared with a user: the notebooks of their accepted
+// memberships and all live descendants. UNION stops the recursion if the tree ever contains a cycle.
+const sharedNotebookIds = `WITH RECURSIVE shared AS (
+		SELECT notebook_id AS id FROM notebook_members WHERE user_id = ? AND status = 'accepted'
+		UNION
+		SELECT child.id FROM notebooks child JOIN shared ON child.parent_id = shared.id
+		WHERE child.deleted_at IS NULL
+	)
+	SELECT id FROM shared`
+
+// NotebookAccessibleBy filters notebooks the user owns or reaches through a membership
+type NotebookAccessibleBy struct {
+	UserID uuid.UUID
+}
+
+func (s NotebookAccessibleBy) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("(notebooks.user_id = ? OR notebooks.id IN ("+sharedNotebookIds+"))", s.UserID, s.UserID)
+}
+
+// NoteAccessibleBy filters notes the user owns or whose notebook is shared with them
+type NoteAccessibleBy struct {
+	UserID uuid.UUID
+}
+
+func (s NoteAccessibleBy) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("(notes.user_id = ? OR notes.notebook_id IN ("+sharedNotebookIds+"))", s.UserID, s.UserID)
+}
+
+// LinkSourceAccessibleBy filters note links found in notes the user owns or has been shared
+type LinkSourceAccessibleBy struct {
+	UserID uuid.UUID
+}
+
+func (s LinkSourceAccessibleBy) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where(`note_links.source_note_id IN (
+		SELECT notes.id FROM notes WHERE notes.user_id = ? OR notes.notebook_id IN (`+sharedNotebookIds+`))`, s.UserID, s.UserID)
+}
+
+// ByMemberUser filters memberships accepted by the user
+type ByMemberUser struct {
+	UserID uuid.UUID
+}
+
+func (s ByMemberUser) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("user_id = ?", s.UserID)
+}
+
+// ByMemberEmail filters memberships addressed to the email (case-insensitive)
+type ByMemberEmail struct {
+	Email string
+}
+
+func (s ByMemberEmail) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("LOWER(email) = LOWER(?)", s.Email)
+}
+
+type ByMemberStatus struct {
+	Status string
+}
+
+func (s ByMemberStatus) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ?", s.Status)
+}
+
+// OnNotebookOrAncestors filters memberships of the notebook or any notebook above it,
+// since a membership covers the whole subtree
+type OnNotebookOrAncestors struct {
+	NotebookID uuid.UUID
+}
+
+func (s OnNotebookOrAncestors) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where(`notebook_id IN (
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM notebooks WHERE id = ?
+			UNION
+			SELECT parent.id, parent.parent_id FROM notebooks parent JOIN ancestors ON parent.id = ancestors.parent_id
+		)
+		SELECT id FROM ancestors)`, s.NotebookID)
+}
@@ -1,6 +1,11 @@
 package specification
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 // NoteSearchQuery filters notes by title or content explicitly
 type NoteSearchQuery struct {
@@ -21,11 +26,8 @@ type ByNotebookName struct {
 
 func (s ByNotebookName) Apply(db *gorm.DB) *gorm.DB {
 	pattern := "%" + s.Name + "%"
-	// Requires JOIN with notebooks table unless already joined
-	// We'll assume the repository handles the join or we add it safely if missing?
-	// Actually, GORM helps distinct joins. Let's add the JOIN here to be safe.
-	return db.Joins("JOIN notebooks ON notebooks.id = notes.notebook_id").
-		Where("notebooks.name ILIKE ?", pattern)
+	// Subquery instead of JOIN keeps column names unambiguous when combined with other specs
+	return db.Where("notes.notebook_id IN (SELECT id FROM notebooks WHERE name ILIKE ? AND deleted_at IS NULL)", pattern)
 }
 
 // ByNoteTitle filters notes by exact title match (case-insensitive)
@@ -37,3 +39,76 @@ func (s ByNoteTitle) Apply(db *gorm.DB) *gorm.DB {
 	pattern := "%" + s.Title + "%"
 	return db.Where("title ILIKE ?", pattern)
 }
+
+// InNotebookSubtree filters notes inside the root notebook(s) and all their descendants.
+// The root is selected by RootID, or by Name (case-insensitive, partial) when RootID is nil,
+// among the notebooks the user owns or has been shared.
+type InNotebookSubtree struct {
+	UserID uuid.UUID
+	RootID *uuid.UUID
+	Name   string
+}
+
+func (s InNotebookSubtree) Apply(db *gorm.DB) *gorm.DB {
+	rootCond, rootArg := "name ILIKE ?", interface{}("%"+s.Name+"%")
+	if s.RootID != nil {
+		rootCond, rootArg = "id = ?", *s.RootID
+	}
+	// UNION (not UNION ALL) stops the recursion if the tree ever contains a cycle
+	return db.Where(`notes.notebook_id IN (
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM notebooks
+			WHERE (user_id = ? OR id IN (`+sharedNotebookIds+`)) AND deleted_at IS NULL AND `+rootCond+`
+			UNION
+			SELECT child.id FROM notebooks child JOIN subtree ON child.parent_id = subtree.id
+			WHERE child.deleted_at IS NULL
+		)
+		SELECT id FROM subtree)`, s.UserID, s.UserID, rootArg)
+}
+
+// NoteHasTag filters notes carrying the user's tag with the given name (case-insensitive)
+type NoteHasTag struct {
+	UserID uuid.UUID
+	Tag    string
+}
+
+func (s NoteHasTag) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where(`notes.id IN (
+		SELECT nt.note_id FROM note_tags nt JOIN tags t ON t.id = nt.tag_id
+		WHERE t.user_id = ? AND LOWER(t.name) = LOWER(?))`, s.UserID, s.Tag)
+}
+
+// NoteHasChecklist filters notes containing a checklist (Lexical check list or Markdown task items)
+type NoteHasChecklist struct{}
+
+func (s NoteHasChecklist) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where(`(notes.content LIKE ? OR notes.content ~ ?)`,
+		`%"listType":"check"%`, `(^|\n)\s*[-*+] \[[ xX]\]`)
+}
+
+// NoteUpdatedBetween filters notes by last modification: After inclusive, Before exclusive
+type NoteUpdatedBetween struct {
+	After  *time.Time
+	Before *time.Time
+}
+
+func (s NoteUpdatedBetween) Apply(db *gorm.DB) *gorm.DB {
+	if s.After != nil {
+		db = db.Where("notes.updated_at >= ?", *s.After)
+	}
+	if s.Before != nil {
+		db = db.Where("notes.updated_at < ?", *s.Before)
+	}
+	return db
+}
+
+// NoteKeysetAfter continues a listing ordered by (Column DESC, notes.id DESC) after the given row
+type NoteKeysetAfter struct {
+	Column string // notes.updated_at | notes.created_at
+	Value  time.Time
+	Id     uuid.UUID
+}
+
+func (s NoteKeysetAfter) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("("+s.Column+", notes.id) < (?, ?)", s.Value, s.Id)
+}
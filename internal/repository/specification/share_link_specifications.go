@@ -0,0 +1,24 @@
+package specification
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ByShareToken struct {
+	Token string
+}
+
+func (s ByShareToken) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("token = ?", s.Token)
+}
+
+// ShareLinkActive filters share links that are neither revoked nor expired at the given time
+type ShareLinkActive struct {
+	At time.Time
+}
+
+func (s ShareLinkActive) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", s.At)
+}
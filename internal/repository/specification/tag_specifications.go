@@ -0,0 +1,24 @@
+package specification
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ByTagName filters tags by name, case-insensitively
+type ByTagName struct {
+	Name string
+}
+
+func (s ByTagName) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("LOWER(tags.name) = LOWER(?)", s.Name)
+}
+
+// NoteHasAnyTag filters notes (or rows joined with notes) carrying at least one of the tags
+type NoteHasAnyTag struct {
+	TagIDs []uuid.UUID
+}
+
+func (s NoteHasAnyTag) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("notes.id IN (SELECT note_id FROM note_tags WHERE tag_id IN ?)", s.TagIDs)
+}
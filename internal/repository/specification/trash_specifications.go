@@ -0,0 +1,45 @@
+package specification
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Trashed selects every soft-deleted item
+type Trashed struct{}
+
+func (s Trashed) Apply(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("deleted_at IS NOT NULL")
+}
+
+// TrashedRoots selects soft-deleted items the user deleted directly (not those taken along
+// with a deleted notebook). Rows deleted before the trash existed have no trash_root and count as roots.
+type TrashedRoots struct{}
+
+func (s TrashedRoots) Apply(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Where("trash_root IS NULL OR trash_root = id")
+}
+
+// ByTrashRoot selects every trashed item that belongs to the deletion of RootID
+type ByTrashRoot struct {
+	RootID uuid.UUID
+}
+
+func (s ByTrashRoot) Apply(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Where("trash_root = ? OR (id = ? AND trash_root IS NULL)", s.RootID, s.RootID)
+}
+
+// DeletedBefore selects soft-deleted items whose deletion is older than Time
+type DeletedBefore struct {
+	Time time.Time
+}
+
+func (s DeletedBefore) Apply(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", s.Time)
+}
@@ -15,12 +15,21 @@ type UnitOfWork interface {
 	NotebookRepository() contract.NotebookRepository
 	NoteRepository() contract.NoteRepository
 	NoteEmbeddingRepository() contract.NoteEmbeddingRepository
+	EmbeddingJobRepository() contract.EmbeddingJobRepository
+	NoteRevisionRepository() contract.NoteRevisionRepository
+	TagRepository() contract.TagRepository
+	NoteLinkRepository() contract.NoteLinkRepository
+	ImportJobRepository() contract.ImportJobRepository
+	ShareLinkRepository() contract.ShareLinkRepository
+	NotebookMemberRepository() contract.NotebookMemberRepository
+	NoteAttachmentRepository() contract.NoteAttachmentRepository
 
 	ChatSessionRepository() contract.ChatSessionRepository
 	ChatMessageRepository() contract.ChatMessageRepository
 	ChatMessageRawRepository() contract.ChatMessageRawRepository
 	ChatMessageReferenceRepository() contract.ChatMessageReferenceRepository
 	ChatCitationRepository() contract.ChatCitationRepository
+	ChatToolCallRepository() contract.ChatToolCallRepository
 	SubscriptionRepository() contract.SubscriptionRepository // Restored
 	FeatureRepository() contract.FeatureRepository
 	BillingRepository() contract.BillingRepository
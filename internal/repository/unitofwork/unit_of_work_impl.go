@@ -72,6 +72,38 @@ func (u *UnitOfWorkImpl) NoteEmbeddingRepository() contract.NoteEmbeddingReposit
 	return implementation.NewNoteEmbeddingRepository(u.getDB())
 }
 
+func (u *UnitOfWorkImpl) EmbeddingJobRepository() contract.EmbeddingJobRepository {
+	return implementation.NewEmbeddingJobRepository(u.getDB())
+}
+
+func (u *UnitOfWorkImpl) NoteRevisionRepository() contract.NoteRevisionRepository {
+	return implementation.NewNoteRevisionRepository(u.getDB())
+}
+
+func (u *UnitOfWorkImpl) TagRepository() contract.TagRepository {
+	return implementation.NewTagRepository(u.getDB())
+}
+
+func (u *UnitOfWorkImpl) NoteLinkRepository() contract.NoteLinkRepository {
+	return implementation.NewNoteLinkRepository(u.getDB())
+}
+
+func (u *UnitOfWorkImpl) ImportJobRepository() contract.ImportJobRepository {
+	return implementation.NewImportJobRepository(u.getDB())
+}
+
+func (u *UnitOfWorkImpl) ShareLinkRepository() contract.ShareLinkRepository {
+	return implementation.NewShareLinkRepository(u.getDB())
+}
+
+func (u *UnitOfWorkImpl) NotebookMemberRepository() contract.NotebookMemberRepository {
+	return implementation.NewNotebookMemberRepository(u.getDB())
+}
+
+func (u *UnitOfWorkImpl) NoteAttachmentRepository() contract.NoteAttachmentRepository {
+	return implementation.NewNoteAttachmentRepository(u.getDB())
+}
+
 func (u *UnitOfWorkImpl) ChatSessionRepository() contract.ChatSessionRepository {
 	return implementation.NewChatSessionRepository(u.getDB())
 }
@@ -92,6 +124,10 @@ func (u *UnitOfWorkImpl) ChatCitationRepository() contract.ChatCitationRepositor
 	return implementation.NewChatCitationRepository(u.getDB())
 }
 
+func (u *UnitOfWorkImpl) ChatToolCallRepository() contract.ChatToolCallRepository {
+	return implementation.NewChatToolCallRepository(u.getDB())
+}
+
 func (u *UnitOfWorkImpl) SubscriptionRepository() contract.SubscriptionRepository {
 	return implementation.NewSubscriptionRepository(u.getDB())
 }
@@ -28,9 +28,9 @@ func New(cfg *config.Config, container *bootstrap.Container) *Server {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.App.CorsAllowedOrigins,
 		AllowCredentials: true,
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Share-Password, If-Match",
 		AllowMethods:     "GET, POST, PUT, PATCH, DELETE, OPTIONS",
-		ExposeHeaders:    "Content-Length, Content-Type, Authorization",
+		ExposeHeaders:    "Content-Length, Content-Type, Authorization, X-Next-Cursor, ETag",
 	}))
 
 	// OpenTelemetry tracing middleware - DISABLED
@@ -76,6 +76,12 @@ func registerRoutes(app *fiber.App, c *bootstrap.Container) {
 
 	c.NotebookController.RegisterRoutes(api)
 	c.NoteController.RegisterRoutes(api)
+	c.TrashController.RegisterRoutes(api)
+	c.TagController.RegisterRoutes(api)
+	c.ShareController.RegisterRoutes(api)
+	c.ImportController.RegisterRoutes(api)
+	c.MemberController.RegisterRoutes(api)
+	c.AttachmentController.RegisterRoutes(api)
 	c.ChatbotController.RegisterRoutes(api)
 
 	c.PaymentController.RegisterRoutes(api)
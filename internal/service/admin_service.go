@@ -15,12 +15,14 @@ import (
 	"ai-notetaking-be/pkg/admin/dashboard"
 	adminEvents "ai-notetaking-be/pkg/admin/events"
 	"ai-notetaking-be/pkg/admin/feature"
+	"ai-notetaking-be/pkg/admin/indexing"
 	"ai-notetaking-be/pkg/admin/mapper"
 	"ai-notetaking-be/pkg/admin/plan"
 	"ai-notetaking-be/pkg/admin/refund"
 	"ai-notetaking-be/pkg/admin/subscription"
 	"ai-notetaking-be/pkg/admin/usage"
 	"ai-notetaking-be/pkg/admin/user"
+	"ai-notetaking-be/pkg/storage"
 
 	"github.com/google/uuid"
 )
@@ -87,6 +89,10 @@ type IAdminService interface {
 	UpdateNuance(ctx context.Context, id uuid.UUID, req dto.UpdateAiNuanceRequest) (*dto.AiNuanceResponse, error)
 	DeleteNuance(ctx context.Context, id uuid.UUID) error
 
+	// Embedding Reindex
+	ReindexEmbeddings(ctx context.Context, req dto.AdminReindexRequest) (*dto.AdminReindexResponse, error)
+	GetReindexProgress(ctx context.Context) (*dto.AdminReindexProgressResponse, error)
+
 	// Billing Management
 	GetUserBillingAddresses(ctx context.Context, userId uuid.UUID) ([]*dto.AdminBillingListResponse, error)
 	CreateBillingAddress(ctx context.Context, userId uuid.UUID, req dto.AdminBillingCreateRequest) (*dto.AdminBillingListResponse, error)
@@ -112,6 +118,8 @@ type adminService struct {
 	dashboardAggregator *dashboard.Aggregator
 	eventPublisher      adminEvents.Publisher
 	aiConfigManager     *aiconfig.Manager
+	indexingManager     *indexing.Manager
+	storage             storage.Storage
 }
 
 func NewAdminService(
@@ -126,6 +134,8 @@ func NewAdminService(
 	dashboardAggregator *dashboard.Aggregator,
 	eventPublisher adminEvents.Publisher,
 	aiConfigManager *aiconfig.Manager,
+	indexingManager *indexing.Manager,
+	store storage.Storage,
 ) IAdminService {
 	return &adminService{
 		uowFactory:          uowFactory,
@@ -139,6 +149,8 @@ func NewAdminService(
 		dashboardAggregator: dashboardAggregator,
 		eventPublisher:      eventPublisher,
 		aiConfigManager:     aiConfigManager,
+		indexingManager:     indexingManager,
+		storage:             store,
 	}
 }
 
@@ -261,6 +273,9 @@ func (s *adminService) PurgeUsers(ctx context.Context, req dto.AdminPurgeUsersRe
 			continue
 		}
 
+		// Attachment files are removed only once the rows are gone for good
+		var attachmentKeys []string
+
 		err := func() error {
 			// 1. Delete Chat Citations (via Messages) - Handled solely by ChatMessageRepository if implemented
 			if err := uow.ChatMessageRepository().DeleteAllCitationsByUserIdUnscoped(ctx, userId); err != nil {
@@ -282,6 +297,48 @@ func (s *adminService) PurgeUsers(ctx context.Context, req dto.AdminPurgeUsersRe
 				return fmt.Errorf("purge embeddings: %w", err)
 			}
 
+			// 4b. Delete Note Revisions (must run while notes still identify the owner)
+			if err := uow.NoteRevisionRepository().DeleteAllByUserIdUnscoped(ctx, userId); err != nil {
+				return fmt.Errorf("purge note revisions: %w", err)
+			}
+
+			// 4c. Delete Tags and their note links
+			if err := uow.TagRepository().DeleteAllByUserIdUnscoped(ctx, userId); err != nil {
+				return fmt.Errorf("purge tags: %w", err)
+			}
+
+			// 4d. Delete Note Links
+			if err := uow.NoteLinkRepository().DeleteAllByUserIdUnscoped(ctx, userId); err != nil {
+				return fmt.Errorf("purge note links: %w", err)
+			}
+
+			// 4e. Delete Import Jobs
+			if err := uow.ImportJobRepository().DeleteAllByUserIdUnscoped(ctx, userId); err != nil {
+				return fmt.Errorf("purge import jobs: %w", err)
+			}
+
+			// 4f. Delete Share Links
+			if err := uow.ShareLinkRepository().DeleteAllByUserIdUnscoped(ctx, userId); err != nil {
+				return fmt.Errorf("purge share links: %w", err)
+			}
+
+			// 4g. Delete Notebook Memberships (own and on the user's notebooks)
+			if err := uow.NotebookMemberRepository().DeleteAllByUserIdUnscoped(ctx, userId); err != nil {
+				return fmt.Errorf("purge notebook members: %w", err)
+			}
+
+			// 4h. Delete Note Attachments, remembering their files
+			attachments, err := uow.NoteAttachmentRepository().FindAll(ctx, specification.UserOwnedBy{UserID: userId})
+			if err != nil {
+				return fmt.Errorf("purge attachments: %w", err)
+			}
+			for _, attachment := range attachments {
+				attachmentKeys = append(attachmentKeys, attachment.StorageKey)
+			}
+			if err := uow.NoteAttachmentRepository().DeleteAllByUserIdUnscoped(ctx, userId); err != nil {
+				return fmt.Errorf("purge attachments: %w", err)
+			}
+
 			// 5. Delete Notes
 			if err := uow.NoteRepository().DeleteAllByUserIdUnscoped(ctx, userId); err != nil {
 				return fmt.Errorf("purge notes: %w", err)
@@ -344,6 +401,7 @@ func (s *adminService) PurgeUsers(ctx context.Context, req dto.AdminPurgeUsersRe
 				s.logger.Error("ADMIN_PURGE", "Failed to commit purge", map[string]interface{}{"user_id": userId, "error": err.Error()})
 				res.FailedUsers = append(res.FailedUsers, dto.PurgeUserFailResult{UserId: userId, Error: "Commit failed"})
 			} else {
+				removeAttachmentFiles(ctx, s.storage, attachmentKeys)
 				res.DeletedCount++
 				s.logger.Info("ADMIN_PURGE", "Successfully purged user", map[string]interface{}{"user_id": userId})
 			}
@@ -642,6 +700,20 @@ func (s *adminService) DeleteNuance(ctx context.Context, id uuid.UUID) error {
 	return s.aiConfigManager.DeleteNuance(ctx, uow, id)
 }
 
+// ============================================================================
+// Embedding Reindex
+// ============================================================================
+
+func (s *adminService) ReindexEmbeddings(ctx context.Context, req dto.AdminReindexRequest) (*dto.AdminReindexResponse, error) {
+	uow := s.uowFactory.NewUnitOfWork(ctx)
+	return s.indexingManager.Reindex(ctx, uow, req)
+}
+
+func (s *adminService) GetReindexProgress(ctx context.Context) (*dto.AdminReindexProgressResponse, error) {
+	uow := s.uowFactory.NewUnitOfWork(ctx)
+	return s.indexingManager.GetProgress(ctx, uow)
+}
+
 // ============================================================================
 // Billing Management
 // ============================================================================
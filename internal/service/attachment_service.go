@@ -0,0 +1,345 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/extract"
+	"ai-notetaking-be/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("attachment is too large")
+	ErrAttachmentEmpty       = errors.New("attachment is empty")
+	ErrAttachmentType        = errors.New("only images, PDFs and text files can be attached")
+	ErrAttachmentLinkInvalid = errors.New("download link is invalid or has expired")
+)
+
+// attachmentLinkTTL is how long a signed download URL stays valid
+const attachmentLinkTTL = 15 * time.Minute
+
+// Image types are served inline; SVG is left out on purpose since it can carry scripts
+var attachmentImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type IAttachmentService interface {
+	Upload(ctx context.Context, userId uuid.UUID, noteId uuid.UUID, req *dto.UploadAttachmentRequest) (*dto.NoteAttachmentResponse, error)
+	List(ctx context.Context, userId uuid.UUID, noteId uuid.UUID) ([]*dto.NoteAttachmentResponse, error)
+	Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	// Open checks a signed download URL and opens the file it points to
+	Open(ctx context.Context, id uuid.UUID, expires int64, signature string) (*entity.NoteAttachment, io.ReadCloser, error)
+}
+
+type attachmentService struct {
+	uowFactory       unitofwork.RepositoryFactory
+	storage          storage.Storage
+	planService      PlanService
+	publisherService IPublisherService
+	baseURL          string
+	maxSize          int64
+}
+
+func NewAttachmentService(
+	uowFactory unitofwork.RepositoryFactory,
+	store storage.Storage,
+	planService PlanService,
+	publisherService IPublisherService,
+	baseURL string,
+	maxSizeMB int,
+) IAttachmentService {
+	return &attachmentService{
+		uowFactory:       uowFactory,
+		storage:          store,
+		planService:      planService,
+		publisherService: publisherService,
+		baseURL:          strings.TrimRight(baseURL, "/"),
+		maxSize:          int64(maxSizeMB) << 20,
+	}
+}
+
+// Upload stores a file on a note the user can edit. The size counts against the note owner's quota.
+// Text is extracted from text and PDF files and embedded with the note.
+func (c *attachmentService) Upload(ctx context.Context, userId uuid.UUID, noteId uuid.UUID, req *dto.UploadAttachmentRequest) (*dto.NoteAttachmentResponse, error) {
+	if len(req.Data) == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	if int64(len(req.Data)) > c.maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+	note, err := findNoteWithRole(ctx, uow, userId, noteId, entity.NotebookRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, ErrNoteNotFound
+	}
+
+	contentType := detectAttachmentType(req.FileName, req.Data)
+	if contentType == "" {
+		return nil, ErrAttachmentType
+	}
+
+	if err := c.planService.CheckCanStoreAttachment(ctx, note.UserId, int64(len(req.Data))); err != nil {
+		return nil, err
+	}
+
+	var text string
+	if extract.Supported(contentType) {
+		text, err = extract.Text(contentType, req.Data)
+		if err != nil {
+			// Still attach the file, it just won't be searchable
+			log.Printf("[WARN] No text extracted from attachment %q of note %s: %v", req.FileName, note.Id, err)
+		}
+	}
+
+	attachment := &entity.NoteAttachment{
+		Id:            uuid.New(),
+		NoteId:        note.Id,
+		UserId:        note.UserId,
+		UploadedBy:    userId,
+		FileName:      attachmentFileName(req.FileName),
+		ContentType:   contentType,
+		Size:          int64(len(req.Data)),
+		ExtractedText: text,
+	}
+	attachment.StorageKey = fmt.Sprintf("attachments/%s/%s/%s", note.UserId, note.Id, attachment.Id)
+
+	if err := c.storage.Put(ctx, attachment.StorageKey, bytes.NewReader(req.Data), attachment.Size, contentType); err != nil {
+		return nil, err
+	}
+	if err := c.saveAttachment(ctx, uow, attachment); err != nil {
+		removeAttachmentFiles(ctx, c.storage, []string{attachment.StorageKey})
+		return nil, err
+	}
+
+	return c.toResponse(attachment), nil
+}
+
+func (c *attachmentService) List(ctx context.Context, userId uuid.UUID, noteId uuid.UUID) ([]*dto.NoteAttachmentResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+	note, err := findNoteWithRole(ctx, uow, userId, noteId, entity.NotebookRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, ErrNoteNotFound
+	}
+
+	attachments, err := uow.NoteAttachmentRepository().FindAll(ctx,
+		specification.ByNoteID{NoteID: note.Id},
+		specification.OrderBy{Field: "created_at"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.NoteAttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		res = append(res, c.toResponse(attachment))
+	}
+	return res, nil
+}
+
+// Delete removes an attachment from a note the user can edit, releasing its storage
+func (c *attachmentService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+	attachment, err := uow.NoteAttachmentRepository().FindOne(ctx, specification.ByID{ID: id})
+	if err != nil {
+		return err
+	}
+	if attachment == nil {
+		return ErrAttachmentNotFound
+	}
+
+	note, err := findNoteWithRole(ctx, uow, userId, attachment.NoteId, entity.NotebookRoleEditor)
+	if err != nil {
+		return err
+	}
+	if note == nil {
+		return ErrAttachmentNotFound
+	}
+
+	if err := uow.Begin(ctx); err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	if err := uow.NoteAttachmentRepository().Delete(ctx, attachment.Id); err != nil {
+		return err
+	}
+	if attachment.ExtractedText != "" {
+		if err := uow.EmbeddingJobRepository().Enqueue(ctx, note.Id, false); err != nil {
+			return err
+		}
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+	if attachment.ExtractedText != "" {
+		c.publisherService.Notify(note.Id)
+	}
+	removeAttachmentFiles(ctx, c.storage, []string{attachment.StorageKey})
+	return nil
+}
+
+func (c *attachmentService) Open(ctx context.Context, id uuid.UUID, expires int64, signature string) (*entity.NoteAttachment, io.ReadCloser, error) {
+	if time.Now().Unix() > expires || !hmac.Equal([]byte(signature), []byte(signAttachmentLink(id, expires))) {
+		return nil, nil, ErrAttachmentLinkInvalid
+	}
+
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+	attachment, err := uow.NoteAttachmentRepository().FindOne(ctx, specification.ByID{ID: id})
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+
+	// Links stop working once the note is in the trash
+	note, err := uow.NoteRepository().FindOne(ctx, specification.ByID{ID: attachment.NoteId})
+	if err != nil {
+		return nil, nil, err
+	}
+	if note == nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+
+	file, err := c.storage.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, file, nil
+}
+
+func (c *attachmentService) toResponse(attachment *entity.NoteAttachment) *dto.NoteAttachmentResponse {
+	expiresAt := time.Now().Add(attachmentLinkTTL).Truncate(time.Second)
+	expires := expiresAt.Unix()
+
+	return &dto.NoteAttachmentResponse{
+		Id:          attachment.Id,
+		NoteId:      attachment.NoteId,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Indexed:     attachment.ExtractedText != "",
+		DownloadURL: fmt.Sprintf("%s/api/public/attachment/%s?expires=%d&signature=%s",
+			c.baseURL, attachment.Id, expires, signAttachmentLink(attachment.Id, expires)),
+		DownloadExpiresAt: expiresAt,
+		CreatedAt:         attachment.CreatedAt,
+	}
+}
+
+// saveAttachment stores the attachment row; one with text also queues its note for embedding,
+// in the same transaction, so its attachment chunks follow the change
+func (c *attachmentService) saveAttachment(ctx context.Context, uow unitofwork.UnitOfWork, attachment *entity.NoteAttachment) error {
+	if err := uow.Begin(ctx); err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	if err := uow.NoteAttachmentRepository().Create(ctx, attachment); err != nil {
+		return err
+	}
+	if attachment.ExtractedText != "" {
+		if err := uow.EmbeddingJobRepository().Enqueue(ctx, attachment.NoteId, false); err != nil {
+			return err
+		}
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+	if attachment.ExtractedText != "" {
+		c.publisherService.Notify(attachment.NoteId)
+	}
+	return nil
+}
+
+// removeAttachmentFiles deletes stored files best-effort; a leftover file only costs storage space
+func removeAttachmentFiles(ctx context.Context, store storage.Storage, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("[WARN] Failed to delete attachment file %s: %v", key, err)
+		}
+	}
+}
+
+// signAttachmentLink authenticates an attachment id and expiry with the server secret
+func signAttachmentLink(id uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("JWT_SECRET")))
+	mac.Write([]byte("attachment:" + id.String() + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// detectAttachmentType sniffs the content and returns the type to store, or "" when the file is not allowed.
+// Text files keep the type of their extension (text/markdown, text/csv, ...).
+func detectAttachmentType(fileName string, data []byte) string {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+
+	switch {
+	case attachmentImageTypes[sniffed], sniffed == "application/pdf":
+		return sniffed
+	case sniffed == "text/plain":
+		ext := strings.ToLower(filepath.Ext(fileName))
+		if ext == ".md" || ext == ".markdown" {
+			return "text/markdown; charset=utf-8"
+		}
+		if byExt, _, err := mime.ParseMediaType(mime.TypeByExtension(ext)); err == nil &&
+			strings.HasPrefix(byExt, "text/") && byExt != "text/html" && byExt != "text/xml" {
+			return byExt + "; charset=utf-8"
+		}
+		return "text/plain; charset=utf-8"
+	}
+	return ""
+}
+
+// attachmentFileName keeps the base name of the upload without control characters, within the column size
+func attachmentFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		name = "attachment"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
@@ -2,24 +2,28 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"ai-notetaking-be/internal/constant"
 	"ai-notetaking-be/internal/dto"
 	"ai-notetaking-be/internal/entity"
-	"ai-notetaking-be/internal/repository/memory"
 	"ai-notetaking-be/internal/repository/specification"
 	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/ai/agent"
 	"ai-notetaking-be/pkg/ai/pipeline"
 	"ai-notetaking-be/pkg/ai/router"
 	"ai-notetaking-be/pkg/embedding"
 	"ai-notetaking-be/pkg/lexical"
 	"ai-notetaking-be/pkg/llm"
 	"ai-notetaking-be/pkg/rag/access"
+	"ai-notetaking-be/pkg/rag/branch"
 	"ai-notetaking-be/pkg/rag/executor"
 	"ai-notetaking-be/pkg/rag/history"
 	"ai-notetaking-be/pkg/rag/message"
@@ -32,12 +36,49 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrChatMessageNotFound means the message does not exist or belongs to another user's session
+var ErrChatMessageNotFound = errors.New("chat message not found")
+
+// ErrChatMessageNotAnswer means a regenerate targets a message that does not answer a user question
+var ErrChatMessageNotAnswer = errors.New("only answers to a question can be regenerated")
+
+// ErrChatMessageNotQuestion means an edit targets a message the user did not write
+var ErrChatMessageNotQuestion = errors.New("only user messages can be edited")
+
+// ErrChatMessageNotModel means a note action targets a message the assistant did not write
+var ErrChatMessageNotModel = errors.New("only assistant answers can be saved to notes")
+
+// ErrNoteNotCited means an answer is appended to a note it does not cite
+var ErrNoteNotCited = errors.New("note is not cited by the answer")
+
+// ErrNoteNotAppendable means the content of a note cannot be extended without rewriting it
+var ErrNoteNotAppendable = errors.New("note content cannot be appended to")
+
+// ErrToolCallNotFound means the tool call does not exist or belongs to another user's session
+var ErrToolCallNotFound = errors.New("tool call not found")
+
+// ErrToolCallNotPending means the tool call does not wait for a confirmation (anymore)
+var ErrToolCallNotPending = errors.New("tool call is not waiting for confirmation")
+
+// answerTitleRunes bounds the note title taken from the question of a saved answer
+const answerTitleRunes = 80
+
 // IChatbotService defines the chatbot service interface
 type IChatbotService interface {
 	CreateSession(ctx context.Context, userId uuid.UUID) (*dto.CreateSessionResponse, error)
 	GetAllSessions(ctx context.Context, userId uuid.UUID) ([]*dto.GetAllSessionsResponse, error)
 	GetChatHistory(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) ([]*dto.GetChatHistoryResponse, error)
 	SendChat(ctx context.Context, userId uuid.UUID, request *dto.SendChatRequest) (*dto.SendChatResponse, error)
+	SendChatStream(ctx context.Context, userId uuid.UUID, request *dto.SendChatRequest, onDelta llm.StreamHandler) (*dto.SendChatResponse, error)
+	RegenerateChat(ctx context.Context, userId uuid.UUID, request *dto.RegenerateChatRequest) (*dto.SendChatResponse, error)
+	RegenerateChatStream(ctx context.Context, userId uuid.UUID, request *dto.RegenerateChatRequest, onDelta llm.StreamHandler) (*dto.SendChatResponse, error)
+	EditChat(ctx context.Context, userId uuid.UUID, request *dto.EditChatRequest) (*dto.SendChatResponse, error)
+	EditChatStream(ctx context.Context, userId uuid.UUID, request *dto.EditChatRequest, onDelta llm.StreamHandler) (*dto.SendChatResponse, error)
+	SelectBranch(ctx context.Context, userId uuid.UUID, request *dto.SelectBranchRequest) ([]*dto.GetChatHistoryResponse, error)
+	SaveAsNote(ctx context.Context, userId uuid.UUID, request *dto.SaveChatAsNoteRequest) (*dto.CreateNoteResponse, error)
+	AppendToNote(ctx context.Context, userId uuid.UUID, request *dto.AppendChatToNoteRequest) (*dto.UpdateNoteResponse, error)
+	ConfirmToolCall(ctx context.Context, userId uuid.UUID, toolCallId uuid.UUID) (*dto.ChatToolCallDTO, error)
+	RejectToolCall(ctx context.Context, userId uuid.UUID, toolCallId uuid.UUID) (*dto.ChatToolCallDTO, error)
 	DeleteSession(ctx context.Context, userId uuid.UUID, request *dto.DeleteSessionRequest) error
 	GetAvailableNuances(ctx context.Context) ([]*dto.AvailableNuanceResponse, error)
 }
@@ -46,7 +87,8 @@ type IChatbotService interface {
 type chatbotService struct {
 	uowFactory  unitofwork.RepositoryFactory
 	llmProvider llm.LLMProvider
-	sessionRepo *memory.SessionRepository
+	sessionRepo store.SessionStore
+	noteService INoteService // Notes saved from answers go through the normal create/update path
 	llmLogger   *log.Logger
 
 	// Domain components
@@ -59,7 +101,7 @@ type chatbotService struct {
 	historyLoader      *history.Loader
 	sessionManager     *session.Manager
 	pipelineExecutor   *executor.PipelineExecutor
-	pipelineRouter     *router.Router             // Routes between RAG and Bypass pipelines
+	pipelineRouter     *router.Router             // Routes between RAG, Bypass and Agent pipelines
 	explicitExecutor   *executor.ExplicitExecutor // For pre-resolved references
 	refResolver        *router.ReferenceResolver  // Resolves note references
 }
@@ -69,7 +111,8 @@ func NewChatbotService(
 	uowFactory unitofwork.RepositoryFactory,
 	embeddingProvider embedding.EmbeddingProvider,
 	llmProvider llm.LLMProvider,
-	sessionRepo *memory.SessionRepository,
+	sessionRepo store.SessionStore,
+	noteService INoteService,
 ) IChatbotService {
 
 	llmLogger := initLLMLogger()
@@ -80,13 +123,15 @@ func NewChatbotService(
 	// Initialize pipeline router (new routing layer)
 	ragPipeline := pipeline.NewRAGPipeline(pipelineExecutor)
 	bypassPipeline := pipeline.NewBypassPipeline(llmProvider, llmLogger)
+	noteAgent := agent.NewAgent(llmProvider, searchOrchestrator, sessionRepo, llmLogger)
 	nuanceResolver := router.NewNuanceResolver()
-	pipelineRouter := router.NewRouter(ragPipeline, bypassPipeline, nuanceResolver, llmLogger)
+	pipelineRouter := router.NewRouter(ragPipeline, bypassPipeline, noteAgent, nuanceResolver, llmLogger)
 
 	return &chatbotService{
 		uowFactory:  uowFactory,
 		llmProvider: llmProvider,
 		sessionRepo: sessionRepo,
+		noteService: noteService,
 		llmLogger:   llmLogger,
 
 		// Initialize all domain components
@@ -95,7 +140,7 @@ func NewChatbotService(
 		responseGenerator:  response.NewGenerator(llmProvider, llmLogger),
 		messageFactory:     message.NewFactory(),
 		accessVerifier:     access.NewVerifier(),
-		historyLoader:      history.NewLoader(uowFactory, sessionRepo),
+		historyLoader:      history.NewLoader(uowFactory, sessionRepo, llmProvider, llmLogger),
 		sessionManager:     session.NewManager(sessionRepo),
 		pipelineExecutor:   pipelineExecutor,
 		pipelineRouter:     pipelineRouter,
@@ -202,7 +247,8 @@ func (cs *chatbotService) GetAllSessions(ctx context.Context, userId uuid.UUID)
 	return response, nil
 }
 
-// GetChatHistory retrieves chat history for a session
+// GetChatHistory retrieves the active branch of a session. Each message carries its alternatives
+// (regenerated answers, edited questions) so the client can switch branches with SelectBranch.
 func (cs *chatbotService) GetChatHistory(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) ([]*dto.GetChatHistoryResponse, error) {
 	uow := cs.uowFactory.NewUnitOfWork(ctx)
 
@@ -224,6 +270,8 @@ func (cs *chatbotService) GetChatHistory(ctx context.Context, userId uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
+	tree := branch.NewTree(chatMessages)
+	chatMessages = tree.Path(tree.Active(sess.ActiveMessageId))
 
 	messageIds := make([]uuid.UUID, len(chatMessages))
 	for i, msg := range chatMessages {
@@ -261,15 +309,44 @@ func (cs *chatbotService) GetChatHistory(ctx context.Context, userId uuid.UUID,
 		}
 	}
 
+	toolCalls, err := uow.ChatToolCallRepository().FindAllByMessageIds(ctx, messageIds)
+	if err != nil {
+		return nil, err
+	}
+	toolCallsByMsgId := make(map[uuid.UUID][]dto.ChatToolCallDTO)
+	for _, call := range toolCalls {
+		toolCallsByMsgId[call.ChatMessageId] = append(toolCallsByMsgId[call.ChatMessageId], toolCallDTO(call))
+	}
+
 	resp := make([]*dto.GetChatHistoryResponse, 0, len(chatMessages))
 	for _, msg := range chatMessages {
+		siblings := tree.Siblings(msg.Id)
+		siblingIds := make([]uuid.UUID, len(siblings))
+		siblingIndex := 0
+		for i, sibling := range siblings {
+			siblingIds[i] = sibling.Id
+			if sibling.Id == msg.Id {
+				siblingIndex = i
+			}
+		}
+
+		var parentId *uuid.UUID
+		if parent := tree.Parent(msg.Id); parent != nil {
+			parentId = &parent.Id
+		}
+
 		resp = append(resp, &dto.GetChatHistoryResponse{
-			Id:         msg.Id,
-			Role:       msg.Role,
-			Chat:       msg.Chat,
-			CreatedAt:  msg.CreatedAt,
-			Citations:  citationsByMsgId[msg.Id],
-			References: refsByMsgId[msg.Id], // Attach references
+			Id:           msg.Id,
+			Role:         msg.Role,
+			Chat:         msg.Chat,
+			CreatedAt:    msg.CreatedAt,
+			ParentId:     parentId,
+			SiblingIds:   siblingIds,
+			SiblingIndex: siblingIndex,
+			SiblingCount: len(siblings),
+			Citations:    citationsByMsgId[msg.Id],
+			References:   refsByMsgId[msg.Id], // Attach references
+			ToolCalls:    toolCallsByMsgId[msg.Id],
 		})
 	}
 
@@ -278,6 +355,445 @@ func (cs *chatbotService) GetChatHistory(ctx context.Context, userId uuid.UUID,
 
 // SendChat processes user message and returns AI response
 func (cs *chatbotService) SendChat(ctx context.Context, userId uuid.UUID, request *dto.SendChatRequest) (*dto.SendChatResponse, error) {
+	return cs.sendChat(ctx, userId, chatTurn{request: request}, nil)
+}
+
+// SendChatStream processes user message and streams the AI response through onDelta.
+// Messages are persisted once generation ends, including when onDelta aborts the stream
+// (client disconnect) - in that case the partial answer is saved as the model reply.
+func (cs *chatbotService) SendChatStream(ctx context.Context, userId uuid.UUID, request *dto.SendChatRequest, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+	return cs.sendChat(ctx, userId, chatTurn{request: request}, onDelta)
+}
+
+// RegenerateChat answers the question of a model message again. The new answer is a sibling of
+// the old one and becomes the active branch.
+func (cs *chatbotService) RegenerateChat(ctx context.Context, userId uuid.UUID, request *dto.RegenerateChatRequest) (*dto.SendChatResponse, error) {
+	return cs.regenerateChat(ctx, userId, request, nil)
+}
+
+// RegenerateChatStream is RegenerateChat streaming the answer through onDelta, like SendChatStream
+func (cs *chatbotService) RegenerateChatStream(ctx context.Context, userId uuid.UUID, request *dto.RegenerateChatRequest, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+	return cs.regenerateChat(ctx, userId, request, onDelta)
+}
+
+func (cs *chatbotService) regenerateChat(ctx context.Context, userId uuid.UUID, request *dto.RegenerateChatRequest, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+	answer, err := cs.findChatMessage(ctx, userId, request.ChatMessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.sendChat(ctx, userId, chatTurn{
+		request:    &dto.SendChatRequest{ChatSessionId: answer.ChatSessionId},
+		regenerate: &answer.Id,
+	}, onDelta)
+}
+
+// EditChat sends a new version of a user message. The new question is a sibling of the old one,
+// so the conversation that followed the old question stays reachable as another branch.
+func (cs *chatbotService) EditChat(ctx context.Context, userId uuid.UUID, request *dto.EditChatRequest) (*dto.SendChatResponse, error) {
+	return cs.editChat(ctx, userId, request, nil)
+}
+
+// EditChatStream is EditChat streaming the answer through onDelta, like SendChatStream
+func (cs *chatbotService) EditChatStream(ctx context.Context, userId uuid.UUID, request *dto.EditChatRequest, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+	return cs.editChat(ctx, userId, request, onDelta)
+}
+
+func (cs *chatbotService) editChat(ctx context.Context, userId uuid.UUID, request *dto.EditChatRequest, onDelta llm.StreamHandler) (*dto.SendChatResponse, error) {
+	question, err := cs.findChatMessage(ctx, userId, request.ChatMessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.sendChat(ctx, userId, chatTurn{
+		request: &dto.SendChatRequest{
+			ChatSessionId: question.ChatSessionId,
+			Chat:          request.Chat,
+			References:    request.References,
+			TagIds:        request.TagIds,
+		},
+		edit: &question.Id,
+	}, onDelta)
+}
+
+// SelectBranch makes the branch through a message active, following the newest messages below it,
+// and returns the history of that branch. The RAG session state is rebuilt from the branch.
+func (cs *chatbotService) SelectBranch(ctx context.Context, userId uuid.UUID, request *dto.SelectBranchRequest) ([]*dto.GetChatHistoryResponse, error) {
+	message, err := cs.findChatMessage(ctx, userId, request.ChatMessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	uow := cs.uowFactory.NewUnitOfWork(ctx)
+	tree, err := branch.Load(ctx, uow, message.ChatSessionId)
+	if err != nil {
+		return nil, err
+	}
+	leafId := tree.Leaf(message.Id)
+
+	if err := uow.ChatSessionRepository().UpdateActiveMessage(ctx, message.ChatSessionId, leafId); err != nil {
+		return nil, err
+	}
+	cs.sessionManager.Rebuild(ctx, uow, userId, message.ChatSessionId, leafId)
+
+	return cs.GetChatHistory(ctx, userId, message.ChatSessionId)
+}
+
+// SaveAsNote creates a note from an answer. The Markdown answer becomes Lexical content, followed by
+// the chat it comes from and links to the notes it cites (which makes it a backlink of those notes).
+// The note service applies the notebook role, plan limits, revisions and embedding as for any note.
+func (cs *chatbotService) SaveAsNote(ctx context.Context, userId uuid.UUID, request *dto.SaveChatAsNoteRequest) (*dto.CreateNoteResponse, error) {
+	answer, chatSession, sources, err := cs.findAnswer(ctx, userId, request.ChatMessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		if title, err = cs.answerTitle(ctx, answer, chatSession); err != nil {
+			return nil, err
+		}
+	}
+
+	return cs.noteService.Create(ctx, userId, &dto.CreateNoteRequest{
+		Title:      title,
+		Content:    lexical.FromMarkdown(answerMarkdown(answer, chatSession, sources)),
+		NotebookId: request.NotebookId,
+	})
+}
+
+// AppendToNote adds an answer at the end of one of the notes it cites, through the note service
+func (cs *chatbotService) AppendToNote(ctx context.Context, userId uuid.UUID, request *dto.AppendChatToNoteRequest) (*dto.UpdateNoteResponse, error) {
+	answer, chatSession, sources, err := cs.findAnswer(ctx, userId, request.ChatMessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *entity.Note
+	var others []*entity.Note
+	for _, note := range sources {
+		if note.Id == request.NoteId {
+			target = note
+		} else {
+			others = append(others, note)
+		}
+	}
+	if target == nil {
+		return nil, ErrNoteNotCited
+	}
+
+	content, err := lexical.AppendMarkdown(target.Content, "---\n\n"+answerMarkdown(answer, chatSession, others))
+	if err != nil {
+		return nil, ErrNoteNotAppendable
+	}
+
+	res, err := cs.noteService.Update(ctx, userId, &dto.UpdateNoteRequest{
+		Id:      target.Id,
+		Version: target.Version,
+		Title:   target.Title,
+		Content: content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ErrNoteNotFound
+	}
+	return res, nil
+}
+
+// findAnswer returns an answer of one of the user's sessions, its session and the notes it cites
+// that the user can still access, in citation order
+func (cs *chatbotService) findAnswer(ctx context.Context, userId uuid.UUID, messageId uuid.UUID) (*entity.ChatMessage, *entity.ChatSession, []*entity.Note, error) {
+	answer, err := cs.findChatMessage(ctx, userId, messageId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if answer.Role != constant.ChatMessageRoleModel {
+		return nil, nil, nil, ErrChatMessageNotModel
+	}
+
+	uow := cs.uowFactory.NewUnitOfWork(ctx)
+	chatSession, err := uow.ChatSessionRepository().FindOne(ctx, specification.ByID{ID: answer.ChatSessionId})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if chatSession == nil {
+		return nil, nil, nil, ErrChatMessageNotFound
+	}
+
+	citations, err := uow.ChatMessageRepository().FindCitationsByMessageIds(ctx, []uuid.UUID{answer.Id})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(citations) == 0 {
+		return answer, chatSession, nil, nil
+	}
+	noteIds := make([]uuid.UUID, 0, len(citations))
+	for _, citation := range citations {
+		noteIds = append(noteIds, citation.NoteId)
+	}
+	noteIds = uniqueIds(noteIds)
+
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByIDs{IDs: noteIds},
+		specification.NoteAccessibleBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	byId := make(map[uuid.UUID]*entity.Note, len(notes))
+	for _, note := range notes {
+		byId[note.Id] = note
+	}
+	sources := make([]*entity.Note, 0, len(notes))
+	for _, id := range noteIds {
+		if note, ok := byId[id]; ok {
+			sources = append(sources, note)
+		}
+	}
+	return answer, chatSession, sources, nil
+}
+
+// answerTitle names a note saved from an answer after the first line of its question,
+// or after the session when the question is empty
+func (cs *chatbotService) answerTitle(ctx context.Context, answer *entity.ChatMessage, chatSession *entity.ChatSession) (string, error) {
+	tree, err := branch.Load(ctx, cs.uowFactory.NewUnitOfWork(ctx), answer.ChatSessionId)
+	if err != nil {
+		return "", err
+	}
+
+	if question := tree.Parent(answer.Id); question != nil && question.Role == constant.ChatMessageRoleUser {
+		line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(question.Chat), "\n", 2)[0])
+		if runes := []rune(line); len(runes) > answerTitleRunes {
+			line = strings.TrimSpace(string(runes[:answerTitleRunes])) + "…"
+		}
+		if line != "" {
+			return line, nil
+		}
+	}
+	return chatSession.Title, nil
+}
+
+// markdownLabel keeps titles from breaking the Markdown they are embedded in
+var markdownLabel = strings.NewReplacer("[", "(", "]", ")", "*", "", "`", "")
+
+// answerMarkdown renders an answer for a note: the answer, a link back to the chat it was saved
+// from and the notes it cites as note links
+func answerMarkdown(answer *entity.ChatMessage, chatSession *entity.ChatSession, sources []*entity.Note) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(answer.Chat))
+	fmt.Fprintf(&b, "\n\n*Saved from the chat [%s](/chat/%s), %s*\n",
+		markdownLabel.Replace(chatSession.Title), chatSession.Id, answer.CreatedAt.Format("2 Jan 2006 15:04"))
+
+	if len(sources) > 0 {
+		b.WriteString("\n**Sources**\n\n")
+		for _, note := range sources {
+			fmt.Fprintf(&b, "- [%s](/notes/%s)\n", markdownLabel.Replace(note.Title), note.Id)
+		}
+	}
+	return b.String()
+}
+
+// findChatMessage returns a message of one of the user's sessions
+func (cs *chatbotService) findChatMessage(ctx context.Context, userId uuid.UUID, messageId uuid.UUID) (*entity.ChatMessage, error) {
+	uow := cs.uowFactory.NewUnitOfWork(ctx)
+
+	message, err := uow.ChatMessageRepository().FindOne(ctx, specification.ByID{ID: messageId})
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, ErrChatMessageNotFound
+	}
+
+	sess, err := uow.ChatSessionRepository().FindOne(ctx,
+		specification.ByID{ID: message.ChatSessionId},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if sess == nil {
+		return nil, ErrChatMessageNotFound
+	}
+	return message, nil
+}
+
+// ConfirmToolCall runs a write the agent proposed, through the note service and its checks. The call
+// is claimed before the write so concurrent confirmations run it once. A write the note service refuses
+// (plan limit, notebook role, edit conflict) goes back to pending so it can be retried.
+func (cs *chatbotService) ConfirmToolCall(ctx context.Context, userId uuid.UUID, toolCallId uuid.UUID) (*dto.ChatToolCallDTO, error) {
+	call, err := cs.findPendingToolCall(ctx, userId, toolCallId)
+	if err != nil {
+		return nil, err
+	}
+
+	toolCallRepo := cs.uowFactory.NewUnitOfWork(ctx).ChatToolCallRepository()
+	claimed, err := toolCallRepo.Transition(ctx, call.Id, constant.ChatToolCallStatusPending, constant.ChatToolCallStatusConfirming, "")
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrToolCallNotPending
+	}
+	release := func(err error) (*dto.ChatToolCallDTO, error) {
+		if _, releaseErr := toolCallRepo.Transition(ctx, call.Id, constant.ChatToolCallStatusConfirming, constant.ChatToolCallStatusPending, ""); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+
+	var outcome interface{}
+	switch call.Name {
+	case agent.ToolCreateNote:
+		args, err := agent.DecodeCreateNote(json.RawMessage(call.Arguments))
+		if err != nil {
+			return cs.resolveToolCall(ctx, call, constant.ChatToolCallStatusConfirming, constant.ChatToolCallStatusFailed, map[string]string{"error": err.Error()})
+		}
+		res, err := cs.noteService.Create(ctx, userId, &dto.CreateNoteRequest{
+			Title:      args.Title,
+			Content:    lexical.FromMarkdown(args.Content),
+			NotebookId: args.NotebookId,
+		})
+		if err != nil {
+			return release(err)
+		}
+		outcome = map[string]interface{}{"note_id": res.Id}
+
+	case agent.ToolAppendToNote:
+		args, err := agent.DecodeAppendToNote(json.RawMessage(call.Arguments))
+		if err != nil {
+			return cs.resolveToolCall(ctx, call, constant.ChatToolCallStatusConfirming, constant.ChatToolCallStatusFailed, map[string]string{"error": err.Error()})
+		}
+		note, err := cs.uowFactory.NewUnitOfWork(ctx).NoteRepository().FindOne(ctx,
+			specification.ByID{ID: args.NoteId},
+			specification.NoteAccessibleBy{UserID: userId},
+		)
+		if err != nil {
+			return release(err)
+		}
+		if note == nil {
+			return release(ErrNoteNotFound)
+		}
+		content, err := lexical.AppendMarkdown(note.Content, args.Content)
+		if err != nil {
+			return release(ErrNoteNotAppendable)
+		}
+		res, err := cs.noteService.Update(ctx, userId, &dto.UpdateNoteRequest{
+			Id:      note.Id,
+			Version: note.Version,
+			Title:   note.Title,
+			Content: content,
+		})
+		if err != nil {
+			return release(err)
+		}
+		if res == nil {
+			return release(ErrNoteNotFound)
+		}
+		outcome = map[string]interface{}{"note_id": res.Id, "version": res.Version}
+
+	default:
+		return release(ErrToolCallNotPending)
+	}
+
+	return cs.resolveToolCall(ctx, call, constant.ChatToolCallStatusConfirming, constant.ChatToolCallStatusDone, outcome)
+}
+
+// RejectToolCall drops a write the agent proposed
+func (cs *chatbotService) RejectToolCall(ctx context.Context, userId uuid.UUID, toolCallId uuid.UUID) (*dto.ChatToolCallDTO, error) {
+	call, err := cs.findPendingToolCall(ctx, userId, toolCallId)
+	if err != nil {
+		return nil, err
+	}
+	return cs.resolveToolCall(ctx, call, constant.ChatToolCallStatusPending, constant.ChatToolCallStatusRejected, map[string]string{"status": "rejected by the user"})
+}
+
+// findPendingToolCall returns a tool call of one of the user's sessions that waits for confirmation
+func (cs *chatbotService) findPendingToolCall(ctx context.Context, userId uuid.UUID, toolCallId uuid.UUID) (*entity.ChatToolCall, error) {
+	uow := cs.uowFactory.NewUnitOfWork(ctx)
+
+	call, err := uow.ChatToolCallRepository().FindOne(ctx, specification.ByID{ID: toolCallId})
+	if err != nil {
+		return nil, err
+	}
+	if call == nil {
+		return nil, ErrToolCallNotFound
+	}
+
+	sess, err := uow.ChatSessionRepository().FindOne(ctx,
+		specification.ByID{ID: call.ChatSessionId},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if sess == nil {
+		return nil, ErrToolCallNotFound
+	}
+
+	if call.Status != constant.ChatToolCallStatusPending || !agent.IsWriteTool(call.Name) {
+		return nil, ErrToolCallNotPending
+	}
+	return call, nil
+}
+
+// resolveToolCall stores the outcome of a tool call that is still in the from status
+func (cs *chatbotService) resolveToolCall(ctx context.Context, call *entity.ChatToolCall, from string, status string, outcome interface{}) (*dto.ChatToolCallDTO, error) {
+	result, err := json.Marshal(outcome)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := cs.uowFactory.NewUnitOfWork(ctx).ChatToolCallRepository().Transition(ctx, call.Id, from, status, string(result))
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, ErrToolCallNotPending
+	}
+
+	call.Status = status
+	call.Result = string(result)
+	res := toolCallDTO(call)
+	return &res, nil
+}
+
+// toolCallDTO exposes a tool call; arguments and results are stored as JSON
+func toolCallDTO(call *entity.ChatToolCall) dto.ChatToolCallDTO {
+	return dto.ChatToolCallDTO{
+		Id:        call.Id,
+		Name:      call.Name,
+		Arguments: rawJSON(call.Arguments),
+		Result:    rawJSON(call.Result),
+		Status:    call.Status,
+		CreatedAt: call.CreatedAt,
+	}
+}
+
+// rawJSON embeds stored JSON in a response, quoting it in the unlikely case it is not valid
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return json.RawMessage("null")
+	}
+	if !json.Valid([]byte(s)) {
+		quoted, _ := json.Marshal(s)
+		return quoted
+	}
+	return json.RawMessage(s)
+}
+
+// chatTurn places a question in the conversation tree. A plain turn continues the active branch;
+// regenerate and edit start a new branch next to an existing answer or question.
+type chatTurn struct {
+	request    *dto.SendChatRequest
+	regenerate *uuid.UUID // Answer to replace; the request is filled from its question
+	edit       *uuid.UUID // Question to replace with request.Chat
+}
+
+func (cs *chatbotService) sendChat(ctx context.Context, userId uuid.UUID, turn chatTurn, onDelta llm.StreamHandler) (_ *dto.SendChatResponse, err error) {
+	request := turn.request
 	uow := cs.uowFactory.NewUnitOfWork(ctx)
 
 	// Verify access using domain component
@@ -296,6 +812,26 @@ func (cs *chatbotService) SendChat(ctx context.Context, userId uuid.UUID, reques
 		return nil, err
 	}
 
+	tree, err := branch.Load(ctx, uow, request.ChatSessionId)
+	if err != nil {
+		return nil, err
+	}
+	parentId, question, err := cs.resolveTurn(ctx, uow, tree, chatSession, turn)
+	if err != nil {
+		return nil, err
+	}
+
+	if turn.regenerate != nil || turn.edit != nil {
+		// The mode and focus of the branch left behind must not steer the new answer
+		cs.sessionManager.Rebuild(ctx, uow, userId, request.ChatSessionId, parentId)
+		defer func() {
+			if err != nil {
+				// The active branch did not change; the next request rebuilds its state
+				cs.sessionRepo.Delete(ctx, request.ChatSessionId.String())
+			}
+		}()
+	}
+
 	existingRawChats, err := uow.ChatMessageRawRepository().FindAll(ctx,
 		specification.ByChatSessionID{ChatSessionID: request.ChatSessionId},
 		specification.OrderBy{Field: "created_at", Desc: false},
@@ -307,24 +843,47 @@ func (cs *chatbotService) SendChat(ctx context.Context, userId uuid.UUID, reques
 	updateSessionTitle := len(existingRawChats) == 2
 	now := time.Now()
 
-	// Create and save user message using domain component
-	userMessage := cs.messageFactory.CreateUserMessage(request, now)
-	if err := cs.messageFactory.SaveUserMessage(ctx, uow, userMessage); err != nil {
-		return nil, err
+	// Create and save user message using domain component; a regenerate answers the existing one
+	userMessage := question
+	if userMessage == nil {
+		created := cs.messageFactory.CreateUserMessage(request, parentId, now)
+		if err := cs.messageFactory.SaveUserMessage(ctx, uow, created); err != nil {
+			return nil, err
+		}
+		userMessage = &created
 	}
 
 	// Execute RAG flow (3-phase pipeline)
-	pipelineResult, err := cs.executePipeline(ctx, uow, userId, request)
+	pipelineResult, err := cs.executePipeline(ctx, uow, userId, parentId, request, onDelta)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create and save model message using domain component
-	modelMessage := cs.messageFactory.CreateModelMessage(request.ChatSessionId, pipelineResult.Reply, now)
+	modelMessage := cs.messageFactory.CreateModelMessage(request.ChatSessionId, userMessage.Id, pipelineResult.Reply, now)
 	if err := cs.messageFactory.SaveModelMessage(ctx, uow, modelMessage, pipelineResult.Citations); err != nil {
 		return nil, err
 	}
 
+	// Record the tools the agent called for this reply; pending writes wait for ConfirmToolCall
+	toolCalls := make([]*entity.ChatToolCall, len(pipelineResult.ToolRuns))
+	for i, run := range pipelineResult.ToolRuns {
+		toolCalls[i] = &entity.ChatToolCall{
+			Id:            uuid.New(),
+			ChatSessionId: request.ChatSessionId,
+			ChatMessageId: modelMessage.Id,
+			Position:      i,
+			Name:          run.Name,
+			Arguments:     string(run.Arguments),
+			Result:        run.Result,
+			Status:        run.Status,
+			CreatedAt:     now,
+		}
+	}
+	if err := uow.ChatToolCallRepository().CreateBulk(ctx, toolCalls); err != nil {
+		return nil, err
+	}
+
 	// Update session title if needed
 	if updateSessionTitle {
 		if err := cs.sessionManager.UpdateTitle(ctx, uow, chatSession, request.Chat, now); err != nil {
@@ -332,6 +891,11 @@ func (cs *chatbotService) SendChat(ctx context.Context, userId uuid.UUID, reques
 		}
 	}
 
+	// The reply ends the branch the session continues from
+	if err := uow.ChatSessionRepository().UpdateActiveMessage(ctx, request.ChatSessionId, modelMessage.Id); err != nil {
+		return nil, err
+	}
+
 	// Increment usage using domain component
 	if err := cs.accessVerifier.IncrementUserUsage(ctx, uow, userId); err != nil {
 		return nil, err
@@ -342,14 +906,16 @@ func (cs *chatbotService) SendChat(ctx context.Context, userId uuid.UUID, reques
 	if len(pipelineResult.ResolvedReferences) > 0 {
 		var referencesToSave []*entity.ChatMessageReference
 		for _, ref := range pipelineResult.ResolvedReferences {
-			// Only save resolved references
+			// Only save resolved references; a regenerated question already has them
 			if ref.Resolved {
-				referencesToSave = append(referencesToSave, &entity.ChatMessageReference{
-					Id:            uuid.New(),
-					ChatMessageId: userMessage.Id, // Link to USER message
-					NoteId:        ref.NoteId,
-					CreatedAt:     now,
-				})
+				if question == nil {
+					referencesToSave = append(referencesToSave, &entity.ChatMessageReference{
+						Id:            uuid.New(),
+						ChatMessageId: userMessage.Id, // Link to USER message
+						NoteId:        ref.NoteId,
+						CreatedAt:     now,
+					})
+				}
 				persistedReferences = append(persistedReferences, ref)
 			}
 		}
@@ -364,6 +930,11 @@ func (cs *chatbotService) SendChat(ctx context.Context, userId uuid.UUID, reques
 		return nil, err
 	}
 
+	replyToolCalls := make([]dto.ChatToolCallDTO, len(toolCalls))
+	for i, call := range toolCalls {
+		replyToolCalls[i] = toolCallDTO(call)
+	}
+
 	return &dto.SendChatResponse{
 		ChatSessionId:      chatSession.Id,
 		ChatSessionTitle:   chatSession.Title,
@@ -374,6 +945,7 @@ func (cs *chatbotService) SendChat(ctx context.Context, userId uuid.UUID, reques
 			Chat:       userMessage.Chat,
 			Role:       userMessage.Role,
 			CreatedAt:  userMessage.CreatedAt,
+			ParentId:   &parentId,
 			References: persistedReferences,
 		},
 		Reply: &dto.SendChatResponseChat{
@@ -381,11 +953,85 @@ func (cs *chatbotService) SendChat(ctx context.Context, userId uuid.UUID, reques
 			Chat:      modelMessage.Chat,
 			Role:      modelMessage.Role,
 			CreatedAt: modelMessage.CreatedAt,
+			ParentId:  modelMessage.ParentId,
 			Citations: pipelineResult.Citations,
+			ToolCalls: replyToolCalls,
 		},
 	}, nil
 }
 
+// resolveTurn returns the message the question of a turn follows and, for a regenerate, the
+// question to answer again. A regenerate fills the request from that question.
+func (cs *chatbotService) resolveTurn(
+	ctx context.Context,
+	uow unitofwork.UnitOfWork,
+	tree *branch.Tree,
+	chatSession *entity.ChatSession,
+	turn chatTurn,
+) (uuid.UUID, *entity.ChatMessage, error) {
+	switch {
+	case turn.regenerate != nil:
+		answer := tree.Get(*turn.regenerate)
+		if answer == nil {
+			return uuid.Nil, nil, ErrChatMessageNotFound
+		}
+		question := tree.Parent(answer.Id)
+		if answer.Role != constant.ChatMessageRoleModel || question == nil || question.Role != constant.ChatMessageRoleUser {
+			return uuid.Nil, nil, ErrChatMessageNotAnswer
+		}
+		if err := cs.replayQuestion(ctx, uow, turn.request, question); err != nil {
+			return uuid.Nil, nil, err
+		}
+		return parentOrNil(tree, question.Id), question, nil
+
+	case turn.edit != nil:
+		question := tree.Get(*turn.edit)
+		if question == nil {
+			return uuid.Nil, nil, ErrChatMessageNotFound
+		}
+		if question.Role != constant.ChatMessageRoleUser {
+			return uuid.Nil, nil, ErrChatMessageNotQuestion
+		}
+		return parentOrNil(tree, question.Id), nil, nil
+	}
+
+	return tree.Active(chatSession.ActiveMessageId), nil, nil
+}
+
+// replayQuestion fills a request with a question as it was sent: its text, the references sent
+// with it and the current tag scope of the session
+func (cs *chatbotService) replayQuestion(ctx context.Context, uow unitofwork.UnitOfWork, request *dto.SendChatRequest, question *entity.ChatMessage) error {
+	request.Chat = question.Chat
+
+	// Inline references are resolved from the text again
+	if !router.ParseReferences(question.Chat).HasRefs {
+		references, err := uow.ChatMessageReferenceRepository().FindAllByMessageIds(ctx, []uuid.UUID{question.Id})
+		if err != nil {
+			return err
+		}
+		for _, ref := range references {
+			request.References = append(request.References, dto.NoteReferenceDTO{NoteId: ref.NoteId})
+		}
+	}
+
+	if sess, found := cs.sessionRepo.Get(ctx, request.ChatSessionId.String()); found {
+		for _, id := range sess.TagIDs {
+			if tagId, err := uuid.Parse(id); err == nil {
+				request.TagIds = append(request.TagIds, tagId)
+			}
+		}
+	}
+	return nil
+}
+
+// parentOrNil returns the id of the message a message follows, uuid.Nil for the root
+func parentOrNil(tree *branch.Tree, id uuid.UUID) uuid.UUID {
+	if parent := tree.Parent(id); parent != nil {
+		return parent.Id
+	}
+	return uuid.Nil
+}
+
 // DeleteSession removes a chat session
 func (cs *chatbotService) DeleteSession(ctx context.Context, userId uuid.UUID, request *dto.DeleteSessionRequest) error {
 	uow := cs.uowFactory.NewUnitOfWork(ctx)
@@ -416,7 +1062,7 @@ func (cs *chatbotService) DeleteSession(ctx context.Context, userId uuid.UUID, r
 		return err
 	}
 
-	cs.sessionRepo.Delete(request.ChatSessionId.String())
+	cs.sessionRepo.Delete(ctx, request.ChatSessionId.String())
 
 	return uow.Commit()
 }
@@ -426,31 +1072,37 @@ func (cs *chatbotService) executePipeline(
 	ctx context.Context,
 	uow unitofwork.UnitOfWork,
 	userId uuid.UUID,
+	parentId uuid.UUID, // Message the question follows; history and session state come from its branch
 	request *dto.SendChatRequest,
+	onDelta llm.StreamHandler, // nil for blocking (non-streaming) execution
 ) (*executor.ExecutionResult, error) {
 
 	sessionIdStr := request.ChatSessionId.String()
 
-	// Get existing session mode (if any)
-	var sessionMode string
-	if sess, found := cs.sessionRepo.Get(sessionIdStr); found {
-		sessionMode = sess.Mode
+	// Get existing session mode (if any), rebuilding lost state from the chat history
+	sessionMode := cs.sessionManager.LoadOrCreate(ctx, uow, userId, request.ChatSessionId, parentId).Mode
+
+	// Scope note search to the requested tags for this message
+	if err := cs.applyTagScope(ctx, uow, userId, request); err != nil {
+		return nil, err
 	}
 
 	// Parse prompt FIRST to detect mode (BYPASS, NUANCE, or RAG)
 	parsed := router.Parse(request.Chat)
 
 	// Determine effective mode for history loading
+	// /agent applies to this message only, whatever the session mode
+	agentMode := parsed.Mode == router.ModeAgent
 	effectiveMode := string(parsed.Mode)
-	if sessionMode == "BYPASS" {
+	if sessionMode == "BYPASS" && !agentMode {
 		effectiveMode = "BYPASS"
-	} else if sessionMode == "NUANCE" {
+	} else if sessionMode == "NUANCE" && !agentMode {
 		effectiveMode = "NUANCE"
 	}
 
 	// Load history with mode-aware filtering
 	// BYPASS mode: RAG system prompts are filtered out
-	hist, err := cs.historyLoader.LoadConversationHistory(ctx, userId, request.ChatSessionId, effectiveMode)
+	hist, err := cs.historyLoader.LoadConversationHistory(ctx, userId, request.ChatSessionId, parentId, effectiveMode)
 	if err != nil {
 		cs.llmLogger.Printf("[WARN] Failed to load history: %v", err)
 		hist = []llm.Message{}
@@ -462,13 +1114,14 @@ func (cs *chatbotService) executePipeline(
 	var explicitNotes []executor.ExplicitContext
 	var resolvedRefs []router.ResolvedReference
 
-	if len(request.References) > 0 {
+	// The agent finds and reads notes itself through its tools
+	if len(request.References) > 0 && !agentMode {
 		// References from DTO (export from semantic search)
 		cs.llmLogger.Printf("[EXPLICIT] Found %d DTO references", len(request.References))
 		for _, ref := range request.References {
 			note, err := uow.NoteRepository().FindOne(ctx,
 				specification.ByID{ID: ref.NoteId},
-				specification.UserOwnedBy{UserID: userId},
+				specification.NoteAccessibleBy{UserID: userId},
 			)
 			if err != nil || note == nil {
 				resolvedRefs = append(resolvedRefs, router.ResolvedReference{
@@ -488,7 +1141,7 @@ func (cs *chatbotService) executePipeline(
 				Found:  true,
 			})
 		}
-	} else {
+	} else if !agentMode {
 		// Check for inline references in prompt
 		parsedRefs := router.ParseReferences(request.Chat)
 		if parsedRefs.HasRefs {
@@ -511,8 +1164,8 @@ func (cs *chatbotService) executePipeline(
 	// If we have explicit notes, use explicit RAG pipeline
 	if len(explicitNotes) > 0 {
 		cs.llmLogger.Printf("[EXPLICIT] Executing explicit RAG with %d notes", len(explicitNotes))
-		explicitResult, err := cs.explicitExecutor.ExecuteWithContext(
-			ctx, userId, request.ChatSessionId, request.Chat, explicitNotes, hist,
+		explicitResult, err := cs.explicitExecutor.ExecuteWithContextStream(
+			ctx, userId, request.ChatSessionId, request.Chat, explicitNotes, hist, onDelta,
 		)
 		if err != nil {
 			return nil, err
@@ -538,7 +1191,7 @@ func (cs *chatbotService) executePipeline(
 
 	// EXECUTE PIPELINE VIA ROUTER
 	// Router handles /bypass, /nuance:X, or default RAG mode
-	result, err := cs.pipelineRouter.Execute(
+	result, err := cs.pipelineRouter.ExecuteStream(
 		ctx,
 		userId,
 		request.ChatSessionId,
@@ -546,6 +1199,7 @@ func (cs *chatbotService) executePipeline(
 		hist,
 		uow,
 		sessionMode, // Pass existing session mode
+		onDelta,
 	)
 	if err != nil {
 		return nil, err
@@ -558,16 +1212,12 @@ func (cs *chatbotService) executePipeline(
 		if sessionMode != newMode {
 			cs.llmLogger.Printf("[SERVICE] Session mode changed: %s -> %s", sessionMode, newMode)
 			// Get or create session and update mode
-			sess, found := cs.sessionRepo.Get(sessionIdStr)
+			sess, found := cs.sessionRepo.Get(ctx, sessionIdStr)
 			if !found {
-				sess = &store.Session{
-					ID:     sessionIdStr,
-					UserID: userId.String(),
-					State:  store.StateBrowsing,
-				}
+				sess = session.NewSession(userId, request.ChatSessionId)
 			}
 			sess.Mode = newMode
-			cs.sessionRepo.Save(sess)
+			cs.sessionRepo.Save(ctx, sess)
 		}
 	}
 
@@ -575,9 +1225,68 @@ func (cs *chatbotService) executePipeline(
 		Reply:     result.Reply,
 		Citations: result.Citations,
 		Mode:      string(result.Mode),
+		ToolRuns:  result.ToolRuns,
 	}, nil
 }
 
+// applyTagScope stores the request's tag scope on the in-memory session, where the RAG search reads it.
+// A request without tags clears the scope.
+func (cs *chatbotService) applyTagScope(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, request *dto.SendChatRequest) error {
+	tagIds := uniqueIds(request.TagIds)
+	if len(tagIds) > 0 {
+		count, err := uow.TagRepository().Count(ctx,
+			specification.ByIDs{IDs: tagIds},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return err
+		}
+		if count != int64(len(tagIds)) {
+			return ErrTagNotFound
+		}
+	}
+
+	sessionIdStr := request.ChatSessionId.String()
+	sess, found := cs.sessionRepo.Get(ctx, sessionIdStr)
+	if !found {
+		if len(tagIds) == 0 {
+			return nil
+		}
+		sess = session.NewSession(userId, request.ChatSessionId)
+	}
+
+	scope := make([]string, len(tagIds))
+	for i, id := range tagIds {
+		scope[i] = id.String()
+	}
+	if !sameTagScope(sess.TagIDs, scope) {
+		cs.llmLogger.Printf("[SERVICE] Session tag scope changed: %v -> %v", sess.TagIDs, scope)
+		// Candidates found under another scope must not leak into this one
+		sess.Candidates = nil
+		sess.FocusedNote = nil
+		sess.State = store.StateBrowsing
+	}
+	sess.TagIDs = scope
+	cs.sessionRepo.Save(ctx, sess)
+	return nil
+}
+
+func sameTagScope(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	for _, id := range b {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
+
 // GetAvailableNuances returns all active nuances for public consumption
 func (cs *chatbotService) GetAvailableNuances(ctx context.Context) ([]*dto.AvailableNuanceResponse, error) {
 	uow := cs.uowFactory.NewUnitOfWork(ctx)
@@ -3,19 +3,19 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
-	"ai-notetaking-be/internal/dto"
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/repository/specification"
 	"ai-notetaking-be/internal/repository/unitofwork"
 	"ai-notetaking-be/pkg/embedding"
-	"ai-notetaking-be/pkg/utils"
+	"ai-notetaking-be/pkg/lexical"
 
-	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
 	"github.com/google/uuid"
 )
@@ -45,54 +45,130 @@ func NewConsumerService(
 	}
 }
 
+// Embedding queue tuning
+const (
+	embeddingPollInterval = 5 * time.Second  // Fallback polling when no doorbell arrives
+	embeddingClaimBatch   = 10               // Jobs claimed per round-trip
+	embeddingStaleAfter   = 10 * time.Minute // Reclaim jobs whose worker died mid-processing
+	embeddingMaxAttempts  = 5                // After this, the job is moved to dead
+	embeddingBaseBackoff  = 30 * time.Second
+	embeddingMaxBackoff   = 30 * time.Minute
+)
+
+// Consume starts the embedding worker. Jobs are read from the embedding_jobs table;
+// GoChannel messages only wake the worker so fresh edits are processed without waiting for the poll.
 func (cs *consumerService) Consume(ctx context.Context) error {
 	messages, err := cs.pubSub.Subscribe(ctx, cs.topicName)
 	if err != nil {
 		return err
 	}
 
+	wake := make(chan struct{}, 1)
+
 	go func() {
 		for msg := range messages {
-			cs.processMessage(ctx, msg)
+			msg.Ack()
+			select {
+			case wake <- struct{}{}:
+			default: // A wake-up is already pending
+			}
 		}
 	}()
 
+	go cs.runWorker(ctx, wake)
+
 	return nil
 }
 
-func (cs *consumerService) processMessage(ctx context.Context, msg *message.Message) {
-	// CRITICAL FIX: Proper error handling without defer Nack
-	var payload dto.PublishEmbedNoteMessage
-	err := json.Unmarshal(msg.Payload, &payload)
-	if err != nil {
-		log.Printf("[ERROR] Failed to unmarshal message: %v", err)
-		msg.Ack() // Ack invalid messages to prevent infinite retry
+func (cs *consumerService) runWorker(ctx context.Context, wake <-chan struct{}) {
+	ticker := time.NewTicker(embeddingPollInterval)
+	defer ticker.Stop()
+
+	for {
+		cs.drainQueue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+// drainQueue claims and processes due jobs until none are left
+func (cs *consumerService) drainQueue(ctx context.Context) {
+	for {
+		uow := cs.uowFactory.NewUnitOfWork(ctx)
+		jobs, err := uow.EmbeddingJobRepository().ClaimBatch(ctx, embeddingClaimBatch, embeddingStaleAfter)
+		if err != nil {
+			log.Printf("[ERROR] Failed to claim embedding jobs: %v", err)
+			return
+		}
+		if len(jobs) == 0 {
+			return
+		}
+
+		for _, job := range jobs {
+			cs.processJob(ctx, job)
+		}
+	}
+}
+
+func (cs *consumerService) processJob(ctx context.Context, job *entity.EmbeddingJob) {
+	err := cs.embedNote(ctx, job)
+	if err == nil {
 		return
 	}
 
-	log.Printf("[INFO] Processing note embedding for NoteId: %s", payload.NoteId)
+	dead := job.Attempts >= embeddingMaxAttempts
+	retryAt := time.Now().Add(embeddingBackoff(job.Attempts))
+	if dead {
+		log.Printf("[ERROR] Embedding job for note %s moved to dead-letter after %d attempts: %v", job.NoteId, job.Attempts, err)
+	} else {
+		log.Printf("[WARN] Embedding job for note %s failed (attempt %d/%d), retrying at %s: %v",
+			job.NoteId, job.Attempts, embeddingMaxAttempts, retryAt.Format(time.RFC3339), err)
+	}
+
+	uow := cs.uowFactory.NewUnitOfWork(ctx)
+	if err := uow.EmbeddingJobRepository().MarkFailed(ctx, job, err.Error(), retryAt, dead); err != nil {
+		log.Printf("[ERROR] Failed to record embedding job failure for note %s: %v", job.NoteId, err)
+	}
+}
+
+// embeddingBackoff returns an exponential delay: 30s, 1m, 2m, 4m ... capped at 30m
+func embeddingBackoff(attempts int) time.Duration {
+	delay := embeddingBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= embeddingMaxBackoff {
+			return embeddingMaxBackoff
+		}
+	}
+	return delay
+}
+
+// embedNote brings the note's embeddings up to date, only calling the provider for chunks whose text changed.
+// Returning an error schedules a retry; success marks the job completed in the same transaction.
+func (cs *consumerService) embedNote(ctx context.Context, job *entity.EmbeddingJob) error {
+	log.Printf("[INFO] Processing note embedding for NoteId: %s (attempt %d)", job.NoteId, job.Attempts)
 
 	uow := cs.uowFactory.NewUnitOfWork(ctx)
 
 	// Fetch Note (Global, no user restrictions)
-	note, err := uow.NoteRepository().FindOne(ctx, specification.ByID{ID: payload.NoteId})
+	note, err := uow.NoteRepository().FindOne(ctx, specification.ByID{ID: job.NoteId})
 	if err != nil {
-		log.Printf("[ERROR] Failed to get note %s: %v", payload.NoteId, err)
-		msg.Nack() // Nack for retriable errors
-		return
+		return fmt.Errorf("failed to get note: %w", err)
 	}
 	if note == nil {
-		log.Printf("[ERROR] Note not found: %s", payload.NoteId)
-		msg.Ack() // Note deleted? Ack.
-		return
+		log.Printf("[INFO] Note not found (deleted?): %s", job.NoteId)
+		return uow.EmbeddingJobRepository().MarkCompleted(ctx, job)
 	}
 
 	// Fetch Notebook
 	notebook, err := uow.NotebookRepository().FindOne(ctx, specification.ByID{ID: note.NotebookId})
 	if err != nil {
-		log.Printf("[ERROR] Failed to get notebook %s: %v", note.NotebookId, err)
-		msg.Nack()
-		return
+		return fmt.Errorf("failed to get notebook %s: %w", note.NotebookId, err)
 	}
 	// Notebook might be null if parent check fails? FindOne doesn't fail on null, just returns nil.
 	// But note exists, so notebook MUST exist in valid state (FK).
@@ -104,83 +180,162 @@ func (cs *consumerService) processMessage(ctx context.Context, msg *message.Mess
 		log.Printf("[WARN] Note %s has no notebook (implied id %s)", note.Id, note.NotebookId)
 	}
 
-	noteUpdatedAt := "-"
-	if note.UpdatedAt != nil {
-		noteUpdatedAt = note.UpdatedAt.Format(time.RFC3339)
+	size, overlap := cs.chunkingOptions(ctx, uow)
+	chunker := lexical.NewChunker(size, overlap)
+	chunks := chunker.Chunk(note.Content)
+	if len(chunks) == 0 {
+		// Empty note: still index the title so it can be found
+		chunks = []lexical.Chunk{{}}
 	}
 
-	content := fmt.Sprintf(`Note Title: %s
-Notebook Title: %s
-
-%s
-
-Created At: %s
-Updated At: %s`,
-		note.Title,
-		notebookName,
-		note.Content,
-		note.CreatedAt.Format(time.RFC3339),
-		noteUpdatedAt,
+	// Text extracted from attachments is chunked like note content, under the file name
+	attachments, err := uow.NoteAttachmentRepository().FindAll(ctx,
+		specification.ByNoteID{NoteID: note.Id},
+		specification.WithExtractedText{},
+		specification.OrderBy{Field: "created_at"},
 	)
+	if err != nil {
+		return fmt.Errorf("failed to get attachments: %w", err)
+	}
+	for _, attachment := range attachments {
+		for _, chunk := range chunker.Chunk(attachment.ExtractedText) {
+			chunk.HeadingPath = append([]string{"Attachment: " + attachment.FileName}, chunk.HeadingPath...)
+			chunks = append(chunks, chunk)
+		}
+	}
+	log.Printf("[INFO] Note %s split into %d chunks (size %d, overlap %d)", job.NoteId, len(chunks), size, overlap)
 
-	log.Printf("[INFO] Generating embeddings for note %s (content length: %d)", payload.NoteId, len(content))
-
-	// 1. Split Text
-	// ChunkSize: 1500 chars (approx 375 tokens) - Ultra safe for context limits
-	// Overlap: 200 chars
-	chunks := utils.SplitText(content, 1500, 200)
-	log.Printf("[INFO] Content split into %d chunks", len(chunks))
+	// Existing vectors can be reused for unchanged chunks, unless a full rebuild was requested
+	// or they were produced by a different model
+	existing, err := uow.NoteEmbeddingRepository().FindAll(ctx, specification.ByNoteID{NoteID: note.Id})
+	if err != nil {
+		return fmt.Errorf("failed to get existing embeddings: %w", err)
+	}
+	reusable := make(map[string][]*entity.NoteEmbedding)
+	if !job.Force && job.ModelName == cs.embeddingProvider.ModelName() {
+		for _, e := range existing {
+			if e.ContentHash != "" {
+				reusable[e.ContentHash] = append(reusable[e.ContentHash], e)
+			}
+		}
+	}
 
-	var newEmbeddings []*entity.NoteEmbedding
+	var newEmbeddings, movedEmbeddings []*entity.NoteEmbedding
+	kept := make(map[uuid.UUID]bool)
+	hash := sha256.New()
 
-	// 2. Process each chunk
 	for i, chunk := range chunks {
-		res, err := cs.embeddingProvider.Generate(chunk, "RETRIEVAL_DOCUMENT")
+		document := embeddingDocument(note.Title, notebookName, chunk)
+		hash.Write([]byte(document))
+		chunkHash := hashText(document)
+		headingPath := chunk.HeadingPathString()
+
+		if candidates := reusable[chunkHash]; len(candidates) > 0 {
+			prev := candidates[0]
+			reusable[chunkHash] = candidates[1:]
+			kept[prev.Id] = true
+			if prev.ChunkIndex != i || prev.HeadingPath != headingPath {
+				prev.ChunkIndex = i
+				prev.HeadingPath = headingPath
+				movedEmbeddings = append(movedEmbeddings, prev)
+			}
+			continue
+		}
+
+		res, err := cs.embeddingProvider.Generate(document, "RETRIEVAL_DOCUMENT")
 		if err != nil {
-			log.Printf("[ERROR] Failed to generate embedding for chunk %d of note %s: %v", i, payload.NoteId, err)
-			msg.Nack()
-			return
+			return fmt.Errorf("failed to generate embedding for chunk %d: %w", i, err)
 		}
 
 		newEmbeddings = append(newEmbeddings, &entity.NoteEmbedding{
 			Id:             uuid.New(),
-			Document:       chunk, // Store specific chunk
+			Document:       document,
 			EmbeddingValue: res.Embedding.Values,
 			NoteId:         note.Id,
 			ChunkIndex:     i,
+			HeadingPath:    headingPath,
+			ContentHash:    chunkHash,
 			CreatedAt:      time.Now(),
 		})
 	}
 
+	var orphanIds []uuid.UUID
+	for _, e := range existing {
+		if !kept[e.Id] {
+			orphanIds = append(orphanIds, e.Id)
+		}
+	}
+
+	log.Printf("[INFO] Note %s: %d chunks embedded, %d reused, %d removed",
+		job.NoteId, len(newEmbeddings), len(kept), len(orphanIds))
+
 	if err := uow.Begin(ctx); err != nil {
-		log.Printf("[ERROR] Failed to begin transaction: %v", err)
-		msg.Nack()
-		return
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer uow.Rollback()
 
-	log.Printf("[INFO] Deleting old embeddings for note %s", payload.NoteId)
-	if err := uow.NoteEmbeddingRepository().DeleteByNoteId(ctx, note.Id); err != nil {
-		log.Printf("[ERROR] Failed to delete old embeddings: %v", err)
-		msg.Nack()
-		return
+	if err := uow.NoteEmbeddingRepository().DeleteByIds(ctx, orphanIds); err != nil {
+		return fmt.Errorf("failed to delete stale embeddings: %w", err)
+	}
+
+	for _, e := range movedEmbeddings {
+		if err := uow.NoteEmbeddingRepository().Update(ctx, e); err != nil {
+			return fmt.Errorf("failed to update embedding position: %w", err)
+		}
 	}
 
-	log.Printf("[INFO] Creating %d new embeddings for note %s", len(newEmbeddings), payload.NoteId)
 	if len(newEmbeddings) > 0 {
 		if err := uow.NoteEmbeddingRepository().CreateBulk(ctx, newEmbeddings); err != nil {
-			log.Printf("[ERROR] Failed to create bulk embeddings: %v", err)
-			msg.Nack()
-			return
+			return fmt.Errorf("failed to create bulk embeddings: %w", err)
 		}
 	}
 
+	job.ContentHash = hex.EncodeToString(hash.Sum(nil))
+	job.ModelName = cs.embeddingProvider.ModelName()
+	if err := uow.EmbeddingJobRepository().MarkCompleted(ctx, job); err != nil {
+		return fmt.Errorf("failed to complete embedding job: %w", err)
+	}
+
 	if err := uow.Commit(); err != nil {
-		log.Printf("[ERROR] Failed to commit transaction: %v", err)
-		msg.Nack()
-		return
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("[SUCCESS] Note processed: %d chunks for NoteId: %s", len(chunks), job.NoteId)
+	return nil
+}
+
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// embeddingDocument prefixes a chunk with the note context so every chunk is retrievable on its own
+func embeddingDocument(title, notebookName string, chunk lexical.Chunk) string {
+	header := fmt.Sprintf("Note Title: %s\nNotebook Title: %s", title, notebookName)
+	if path := chunk.HeadingPathString(); path != "" {
+		header += "\nSection: " + path
+	}
+	if chunk.Text == "" {
+		return header
+	}
+	return header + "\n\n" + chunk.Text
+}
+
+// chunkingOptions reads chunk size/overlap from ai_configurations, falling back to the defaults
+func (cs *consumerService) chunkingOptions(ctx context.Context, uow unitofwork.UnitOfWork) (int, int) {
+	size := lexical.DefaultChunkSize
+	overlap := lexical.DefaultChunkOverlap
+
+	if config, err := uow.AiConfigRepository().FindConfigurationByKey(ctx, entity.AiConfigKeyEmbeddingChunkSize); err == nil && config != nil {
+		if val, err := strconv.Atoi(config.Value); err == nil && val > 0 {
+			size = val
+		}
+	}
+	if config, err := uow.AiConfigRepository().FindConfigurationByKey(ctx, entity.AiConfigKeyEmbeddingChunkOverlap); err == nil && config != nil {
+		if val, err := strconv.Atoi(config.Value); err == nil && val >= 0 {
+			overlap = val
+		}
 	}
 
-	log.Printf("[SUCCESS] Note processed: %d chunks for NoteId: %s", len(newEmbeddings), payload.NoteId)
-	msg.Ack()
+	return size, overlap
 }
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/lexical"
+	"ai-notetaking-be/pkg/vault"
+
+	"github.com/google/uuid"
+)
+
+// ErrImportNotFound means the import job does not exist or belongs to another user
+var ErrImportNotFound = errors.New("import not found")
+
+// ErrImportInProgress means the user already has an import that has not finished
+var ErrImportInProgress = errors.New("an import is already in progress")
+
+// ErrImportNotebookNotFound means the target notebook is not a live notebook of the user
+var ErrImportNotebookNotFound = errors.New("notebook not found")
+
+const (
+	importBatchSize   = 50  // Notes per transaction / embedding batch
+	maxImportWarnings = 100 // Further warnings are summarized
+	maxTagNameRunes   = 50
+)
+
+type IImportService interface {
+	// Start validates the archive and imports it in the background; poll Show for progress
+	Start(ctx context.Context, userId uuid.UUID, req *dto.StartImportRequest) (*dto.ImportJobResponse, error)
+	List(ctx context.Context, userId uuid.UUID) ([]*dto.ImportJobResponse, error)
+	Show(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ImportJobResponse, error)
+	// FailInterrupted marks imports left unfinished by a previous process as failed
+	FailInterrupted(ctx context.Context) error
+}
+
+type importService struct {
+	uowFactory       unitofwork.RepositoryFactory
+	planService      PlanService
+	publisherService IPublisherService
+}
+
+func NewImportService(uowFactory unitofwork.RepositoryFactory, planService PlanService, publisherService IPublisherService) IImportService {
+	return &importService{
+		uowFactory:       uowFactory,
+		planService:      planService,
+		publisherService: publisherService,
+	}
+}
+
+func (c *importService) Start(ctx context.Context, userId uuid.UUID, req *dto.StartImportRequest) (*dto.ImportJobResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	if req.ParentNotebookId != nil {
+		parent, err := uow.NotebookRepository().FindOne(ctx,
+			specification.ByID{ID: *req.ParentNotebookId},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, ErrImportNotebookNotFound
+		}
+	}
+
+	running, err := uow.ImportJobRepository().FindOne(ctx,
+		specification.UserOwnedBy{UserID: userId},
+		specification.ByImportJobStatuses{Statuses: []string{
+			string(entity.ImportJobStatusPending),
+			string(entity.ImportJobStatusRunning),
+		}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return nil, ErrImportInProgress
+	}
+
+	v, err := vault.Read(bytes.NewReader(req.Data), int64(len(req.Data)), req.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The vault needs at least its root notebook
+	if err := c.planService.CheckCanCreateNotebook(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	fileName := req.FileName
+	if utf8.RuneCountInString(fileName) > 255 {
+		fileName = string([]rune(fileName)[:255])
+	}
+	job := &entity.ImportJob{
+		Id:               uuid.New(),
+		UserId:           userId,
+		FileName:         fileName,
+		Status:           entity.ImportJobStatusPending,
+		TotalNotebooks:   len(v.Folders) + 1,
+		TotalNotes:       len(v.Notes),
+		ParentNotebookId: req.ParentNotebookId,
+		CreatedAt:        time.Now(),
+	}
+	if err := uow.ImportJobRepository().Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// The request context ends with the response
+	go c.run(context.Background(), job, v)
+
+	return toImportJobResponse(job), nil
+}
+
+func (c *importService) List(ctx context.Context, userId uuid.UUID) ([]*dto.ImportJobResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	jobs, err := uow.ImportJobRepository().FindAll(ctx,
+		specification.UserOwnedBy{UserID: userId},
+		specification.OrderBy{Field: "created_at", Desc: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.ImportJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		res = append(res, toImportJobResponse(job))
+	}
+	return res, nil
+}
+
+func (c *importService) Show(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ImportJobResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	job, err := uow.ImportJobRepository().FindOne(ctx,
+		specification.ByID{ID: id},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrImportNotFound
+	}
+	return toImportJobResponse(job), nil
+}
+
+func (c *importService) FailInterrupted(ctx context.Context) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	count, err := uow.ImportJobRepository().FailUnfinished(ctx, "import interrupted by a server restart")
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("[INFO] Marked %d interrupted import(s) as failed", count)
+	}
+	return nil
+}
+
+// run imports the vault, saving progress on the job after the notebooks and after every note batch.
+// Notebooks beyond the plan limit are merged into their parent; notes beyond the per-notebook
+// limit are skipped with a warning.
+func (c *importService) run(ctx context.Context, job *entity.ImportJob, v *vault.Vault) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[ERROR] Import %s panicked: %v", job.Id, r)
+			c.finish(ctx, job, fmt.Errorf("internal error"))
+		}
+	}()
+
+	now := time.Now()
+	job.Status = entity.ImportJobStatusRunning
+	job.StartedAt = &now
+	if err := uow.ImportJobRepository().Update(ctx, job); err != nil {
+		log.Printf("[ERROR] Import %s: %v", job.Id, err)
+		return
+	}
+
+	c.finish(ctx, job, c.importVault(ctx, job, v))
+}
+
+func (c *importService) importVault(ctx context.Context, job *entity.ImportJob, v *vault.Vault) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	limits, err := c.planService.GetUserPlanLimits(ctx, job.UserId)
+	if err != nil {
+		return err
+	}
+	notebookCount, err := uow.NotebookRepository().Count(ctx, specification.UserOwnedBy{UserID: job.UserId})
+	if err != nil {
+		return err
+	}
+	notebooksLeft := -1 // Unlimited
+	if limits.MaxNotebooks >= 0 {
+		notebooksLeft = limits.MaxNotebooks - int(notebookCount)
+		if notebooksLeft <= 0 {
+			return &dto.LimitExceededError{Limit: limits.MaxNotebooks, Used: int(notebookCount)}
+		}
+	}
+
+	// 1. Notebooks: the vault root, then its folders (parents first)
+	now := time.Now()
+	root := entity.Notebook{
+		Id:        uuid.New(),
+		Name:      v.Name,
+		ParentId:  job.ParentNotebookId,
+		UserId:    job.UserId,
+		CreatedAt: now,
+	}
+	if err := uow.NotebookRepository().Create(ctx, &root); err != nil {
+		return err
+	}
+	if notebooksLeft > 0 {
+		notebooksLeft--
+	}
+	job.RootNotebookId = &root.Id
+	job.ImportedNotebooks = 1
+
+	notebookOf := map[string]uuid.UUID{"": root.Id}
+	for _, folder := range v.Folders {
+		parentId := notebookOf[folder.Parent]
+		if notebooksLeft == 0 {
+			notebookOf[folder.Path] = parentId
+			addImportWarning(job, fmt.Sprintf("Folder %q merged into its parent: notebook limit (%d) reached", folder.Path, limits.MaxNotebooks))
+			continue
+		}
+
+		notebook := entity.Notebook{
+			Id:        uuid.New(),
+			Name:      folder.Name,
+			ParentId:  &parentId,
+			UserId:    job.UserId,
+			CreatedAt: now,
+		}
+		if err := uow.NotebookRepository().Create(ctx, &notebook); err != nil {
+			return err
+		}
+		if notebooksLeft > 0 {
+			notebooksLeft--
+		}
+		notebookOf[folder.Path] = notebook.Id
+		job.ImportedNotebooks++
+	}
+	if err := uow.ImportJobRepository().Update(ctx, job); err != nil {
+		return err
+	}
+
+	// 2. Notes, in batches; each batch is committed together with its embedding jobs
+	notesIn := make(map[uuid.UUID]int)
+	tagIds := make(map[string]uuid.UUID)
+	for start := 0; start < len(v.Notes); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(v.Notes) {
+			end = len(v.Notes)
+		}
+
+		noteIds, err := c.importBatch(ctx, job, v.Notes[start:end], notebookOf, notesIn, tagIds, limits.MaxNotesPerNotebook)
+		if err != nil {
+			return err
+		}
+		c.publisherService.Notify(noteIds...)
+		if err := uow.ImportJobRepository().Update(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importBatch creates one batch of notes in a transaction and returns the ids created
+func (c *importService) importBatch(
+	ctx context.Context,
+	job *entity.ImportJob,
+	notes []vault.Note,
+	notebookOf map[string]uuid.UUID,
+	notesIn map[uuid.UUID]int,
+	tagIds map[string]uuid.UUID,
+	maxNotesPerNotebook int,
+) ([]uuid.UUID, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	if err := uow.Begin(ctx); err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	imported, skipped := 0, 0
+	warnings := len(job.Warnings)
+	noteIds := make([]uuid.UUID, 0, len(notes))
+	for _, vn := range notes {
+		notebookId := notebookOf[vn.Folder]
+		if maxNotesPerNotebook >= 0 && notesIn[notebookId] >= maxNotesPerNotebook {
+			skipped++
+			addImportWarning(job, fmt.Sprintf("Note %q skipped: notes per notebook limit (%d) reached", importNotePath(vn), maxNotesPerNotebook))
+			continue
+		}
+
+		now := time.Now()
+		note := entity.Note{
+			Id:         uuid.New(),
+			Title:      vn.Title,
+			Content:    lexical.FromMarkdown(vn.Markdown),
+			NotebookId: notebookId,
+			UserId:     job.UserId,
+			CreatedAt:  now,
+		}
+		if err := uow.NoteRepository().Create(ctx, &note); err != nil {
+			return nil, err
+		}
+		if err := uow.NoteRevisionRepository().Create(ctx, &entity.NoteRevision{
+			Id:        uuid.New(),
+			NoteId:    note.Id,
+			UserId:    job.UserId,
+			Title:     note.Title,
+			Content:   note.Content,
+			Source:    entity.NoteRevisionSourceImport,
+			CreatedAt: now,
+		}); err != nil {
+			return nil, err
+		}
+		// Links to notes later in the vault resolve when those notes are synced
+		if err := syncNoteLinks(ctx, uow, &note); err != nil {
+			return nil, err
+		}
+		if err := c.tagNote(ctx, uow, job.UserId, note.Id, vn.Tags, tagIds); err != nil {
+			return nil, err
+		}
+
+		notesIn[notebookId]++
+		imported++
+		noteIds = append(noteIds, note.Id)
+	}
+	if err := uow.EmbeddingJobRepository().EnqueueMany(ctx, noteIds, false); err != nil {
+		job.Warnings = job.Warnings[:warnings]
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		// Nothing of this batch was saved
+		job.Warnings = job.Warnings[:warnings]
+		return nil, err
+	}
+
+	job.ImportedNotes += imported
+	job.SkippedNotes += skipped
+	return noteIds, nil
+}
+
+// tagNote attaches the note's front-matter tags, reusing the user's tags by name (case-insensitive)
+// and creating missing ones. tagIds caches lowercased name -> id across batches.
+func (c *importService) tagNote(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, noteId uuid.UUID, names []string, tagIds map[string]uuid.UUID) error {
+	ids := make([]uuid.UUID, 0, len(names))
+	for _, name := range names {
+		name = normalizeTagName(name)
+		if utf8.RuneCountInString(name) > maxTagNameRunes {
+			name = string([]rune(name)[:maxTagNameRunes])
+		}
+		if name == "" {
+			continue
+		}
+
+		key := strings.ToLower(name)
+		id, ok := tagIds[key]
+		if !ok {
+			tag, err := uow.TagRepository().FindOne(ctx,
+				specification.UserOwnedBy{UserID: userId},
+				specification.ByTagName{Name: name},
+			)
+			if err != nil {
+				return err
+			}
+			if tag == nil {
+				tag = &entity.Tag{
+					Id:        uuid.New(),
+					UserId:    userId,
+					Name:      name,
+					Color:     dto.DefaultTagColor,
+					CreatedAt: time.Now(),
+				}
+				if err := uow.TagRepository().Create(ctx, tag); err != nil {
+					return err
+				}
+			}
+			id = tag.Id
+			tagIds[key] = id
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := uow.TagRepository().Attach(ctx, uniqueIds(ids), []uuid.UUID{noteId})
+	return err
+}
+
+// finish records the outcome of the import
+func (c *importService) finish(ctx context.Context, job *entity.ImportJob, importErr error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = entity.ImportJobStatusCompleted
+	if importErr != nil {
+		job.Status = entity.ImportJobStatusFailed
+		job.Error = importErr.Error()
+		var limitErr *dto.LimitExceededError
+		if errors.As(importErr, &limitErr) {
+			job.Error = fmt.Sprintf("notebook limit (%d) reached", limitErr.Limit)
+		}
+		log.Printf("[WARN] Import %s failed: %v", job.Id, importErr)
+	}
+
+	if err := uow.ImportJobRepository().Update(ctx, job); err != nil {
+		log.Printf("[ERROR] Import %s: failed to save status: %v", job.Id, err)
+	}
+}
+
+// addImportWarning records why an item was not imported as-is, summarizing past maxImportWarnings
+func addImportWarning(job *entity.ImportJob, warning string) {
+	switch {
+	case len(job.Warnings) < maxImportWarnings:
+		job.Warnings = append(job.Warnings, warning)
+	case len(job.Warnings) == maxImportWarnings:
+		job.Warnings = append(job.Warnings, "More items were skipped or merged; warnings are truncated")
+	}
+}
+
+func importNotePath(note vault.Note) string {
+	if note.Folder == "" {
+		return note.Title
+	}
+	return note.Folder + "/" + note.Title
+}
+
+func toImportJobResponse(job *entity.ImportJob) *dto.ImportJobResponse {
+	// Notebooks are created up front, so progress follows the notes
+	progress := 0
+	if job.Status == entity.ImportJobStatusCompleted {
+		progress = 100
+	} else if job.TotalNotes > 0 {
+		progress = (job.ImportedNotes + job.SkippedNotes) * 100 / job.TotalNotes
+	}
+
+	warnings := job.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	return &dto.ImportJobResponse{
+		Id:                job.Id,
+		FileName:          job.FileName,
+		Status:            string(job.Status),
+		Progress:          progress,
+		TotalNotebooks:    job.TotalNotebooks,
+		TotalNotes:        job.TotalNotes,
+		ImportedNotebooks: job.ImportedNotebooks,
+		ImportedNotes:     job.ImportedNotes,
+		SkippedNotes:      job.SkippedNotes,
+		Warnings:          warnings,
+		Error:             job.Error,
+		RootNotebookId:    job.RootNotebookId,
+		ParentNotebookId:  job.ParentNotebookId,
+		StartedAt:         job.StartedAt,
+		FinishedAt:        job.FinishedAt,
+		CreatedAt:         job.CreatedAt,
+	}
+}
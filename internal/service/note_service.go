@@ -3,13 +3,16 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"ai-notetaking-be/internal/dto"
 	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/contract"
 	"ai-notetaking-be/internal/repository/specification"
 	"ai-notetaking-be/internal/repository/unitofwork"
 	"ai-notetaking-be/pkg/embedding"
@@ -22,13 +25,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrNoteNotFound is returned when the note is not a live note the user can access
+var ErrNoteNotFound = errors.New("note not found")
+
 type INoteService interface {
 	Create(ctx context.Context, userId uuid.UUID, req *dto.CreateNoteRequest) (*dto.CreateNoteResponse, error)
 	Show(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ShowNoteResponse, error)
 	Update(ctx context.Context, userId uuid.UUID, req *dto.UpdateNoteRequest) (*dto.UpdateNoteResponse, error)
 	Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
 	MoveNote(ctx context.Context, userId uuid.UUID, req *dto.MoveNoteRequest) (*dto.MoveNoteResponse, error)
-	SemanticSearch(ctx context.Context, userId uuid.UUID, search string) ([]*dto.SemanticSearchResponse, error)
+	SemanticSearch(ctx context.Context, userId uuid.UUID, req *dto.SemanticSearchRequest) (*dto.SemanticSearchPage, error)
+	Reindex(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ReindexResponse, error)
+	ListRevisions(ctx context.Context, userId uuid.UUID, noteId uuid.UUID) ([]*dto.NoteRevisionResponse, error)
+	ShowRevision(ctx context.Context, userId uuid.UUID, noteId uuid.UUID, revisionId uuid.UUID) (*dto.NoteRevisionResponse, error)
+	DiffRevisions(ctx context.Context, userId uuid.UUID, noteId uuid.UUID, fromId uuid.UUID, toId *uuid.UUID) (*dto.NoteRevisionDiffResponse, error)
+	RestoreRevision(ctx context.Context, userId uuid.UUID, noteId uuid.UUID, revisionId uuid.UUID) (*dto.RestoreNoteRevisionResponse, error)
+	Graph(ctx context.Context, userId uuid.UUID, req *dto.NoteGraphRequest) (*dto.NoteGraphResponse, error)
+	// AuthorizeCollaboration checks whether the user may join the note's live editing room; canEdit
+	// is true for editors and owners, who may also send editor updates
+	AuthorizeCollaboration(ctx context.Context, userId uuid.UUID, noteId uuid.UUID) (canEdit bool, err error)
 }
 
 type noteService struct {
@@ -36,6 +51,7 @@ type noteService struct {
 	publisherService  IPublisherService
 	embeddingProvider embedding.EmbeddingProvider
 	eventPublisher    *pktNats.Publisher
+	planService       PlanService
 	accessVerifier    *access.Verifier
 }
 
@@ -44,44 +60,65 @@ func NewNoteService(
 	publisherService IPublisherService,
 	embeddingProvider embedding.EmbeddingProvider,
 	eventPublisher *pktNats.Publisher,
+	planService PlanService,
 ) INoteService {
 	return &noteService{
 		uowFactory:        uowFactory,
 		publisherService:  publisherService,
 		embeddingProvider: embeddingProvider,
 		eventPublisher:    eventPublisher,
+		planService:       planService,
 		accessVerifier:    access.NewVerifier(),
 	}
 }
 
 func (c *noteService) Create(ctx context.Context, userId uuid.UUID, req *dto.CreateNoteRequest) (*dto.CreateNoteResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	// Members need the editor role; the note belongs to the notebook's owner
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, req.NotebookId, entity.NotebookRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if notebook == nil {
+		return nil, ErrNotebookNotFound
+	}
+	// The notebook owner's plan limits the notes per notebook
+	if err := c.planService.CheckCanCreateNote(ctx, notebook.UserId, notebook.Id); err != nil {
+		return nil, err
+	}
+
 	note := entity.Note{
 		Id:         uuid.New(),
 		Title:      req.Title,
 		Content:    req.Content,
-		NotebookId: req.NotebookId,
-		UserId:     userId,
+		NotebookId: notebook.Id,
+		UserId:     notebook.UserId,
 		CreatedAt:  time.Now(),
 	}
 
-	err := uow.NoteRepository().Create(ctx, &note)
-	if err != nil {
+	if err := uow.Begin(ctx); err != nil {
 		return nil, err
 	}
+	defer uow.Rollback()
 
-	msgPayload := dto.PublishEmbedNoteMessage{
-		NoteId: note.Id,
+	if err := uow.NoteRepository().Create(ctx, &note); err != nil {
+		return nil, err
 	}
-	msgJson, err := json.Marshal(msgPayload)
-	if err != nil {
+	if _, err := c.recordRevision(ctx, uow, &note, userId, entity.NoteRevisionSourceCreate, nil); err != nil {
+		return nil, err
+	}
+	if err := syncNoteLinks(ctx, uow, &note); err != nil {
+		return nil, err
+	}
+	if err := uow.EmbeddingJobRepository().Enqueue(ctx, note.Id, false); err != nil {
 		return nil, err
 	}
 
-	err = c.publisherService.Publish(ctx, msgJson)
-	if err != nil {
+	if err := uow.Commit(); err != nil {
 		return nil, err
 	}
+	c.publisherService.Notify(note.Id)
 
 	// Publish Event for Notification System
 	if c.eventPublisher != nil {
@@ -107,10 +144,7 @@ func (c *noteService) Create(ctx context.Context, userId uuid.UUID, req *dto.Cre
 
 func (c *noteService) Show(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ShowNoteResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
-	note, err := uow.NoteRepository().FindOne(ctx,
-		specification.ByID{ID: id},
-		specification.UserOwnedBy{UserID: userId},
-	)
+	note, err := findNoteWithRole(ctx, uow, userId, id, entity.NotebookRoleViewer)
 	if err != nil {
 		return nil, err
 	}
@@ -124,12 +158,31 @@ func (c *noteService) Show(ctx context.Context, userId uuid.UUID, id uuid.UUID)
 		return nil, err
 	}
 
+	indexing, err := c.indexingStatus(ctx, uow, note.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tagsByNote, err := uow.TagRepository().FindByNoteIds(ctx, []uuid.UUID{note.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	backlinks, err := c.backlinks(ctx, uow, userId, note)
+	if err != nil {
+		return nil, err
+	}
+
 	res := dto.ShowNoteResponse{
 		Id:         note.Id,
 		Title:      note.Title,
 		Content:    note.Content,
 		NotebookId: note.NotebookId,
 		Breadcrumb: breadcrumb,
+		Indexing:   indexing,
+		Tags:       toNoteTagResponses(tagsByNote[note.Id]),
+		Backlinks:  backlinks,
+		Version:    note.Version,
 		CreatedAt:  note.CreatedAt,
 		UpdatedAt:  note.UpdatedAt,
 	}
@@ -137,6 +190,75 @@ func (c *noteService) Show(ctx context.Context, userId uuid.UUID, id uuid.UUID)
 	return &res, nil
 }
 
+// backlinks lists the live notes visible to the user whose content links to the note, by title
+func (c *noteService) backlinks(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, note *entity.Note) ([]dto.NoteBacklink, error) {
+	res := make([]dto.NoteBacklink, 0)
+
+	// Links are stored under the owner of the notes
+	links, err := uow.NoteLinkRepository().FindAll(ctx,
+		specification.ByLinkTarget{NoteID: note.Id},
+		specification.UserOwnedBy{UserID: note.UserId},
+	)
+	if err != nil || len(links) == 0 {
+		return res, err
+	}
+
+	sourceIds := make([]uuid.UUID, len(links))
+	for i, link := range links {
+		sourceIds[i] = link.SourceNoteId
+	}
+	sources, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByIDs{IDs: sourceIds},
+		specification.NoteAccessibleBy{UserID: userId},
+		specification.OrderBy{Field: "title"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range sources {
+		res = append(res, dto.NoteBacklink{Id: source.Id, Title: source.Title, NotebookId: source.NotebookId})
+	}
+	return res, nil
+}
+
+// indexingStatus maps the note's embedding job onto the user-facing indexing status
+func (c *noteService) indexingStatus(ctx context.Context, uow unitofwork.UnitOfWork, noteId uuid.UUID) (dto.NoteIndexingStatus, error) {
+	job, err := uow.EmbeddingJobRepository().FindOne(ctx, specification.ByNoteID{NoteID: noteId})
+	if err != nil {
+		return dto.NoteIndexingStatus{}, err
+	}
+
+	if job == nil {
+		// Notes embedded before jobs were tracked have embeddings but no job row
+		count, err := uow.NoteEmbeddingRepository().Count(ctx, specification.ByNoteID{NoteID: noteId})
+		if err != nil {
+			return dto.NoteIndexingStatus{}, err
+		}
+		if count > 0 {
+			return dto.NoteIndexingStatus{Status: dto.NoteIndexingIndexed}, nil
+		}
+		return dto.NoteIndexingStatus{Status: dto.NoteIndexingNotIndexed}, nil
+	}
+
+	status := dto.NoteIndexingPending
+	switch job.Status {
+	case entity.EmbeddingJobStatusCompleted:
+		status = dto.NoteIndexingIndexed
+	case entity.EmbeddingJobStatusDead:
+		status = dto.NoteIndexingFailed
+	}
+
+	return dto.NoteIndexingStatus{
+		Status:      status,
+		ContentHash: job.ContentHash,
+		ModelName:   job.ModelName,
+		LastError:   job.LastError,
+		Attempts:    job.Attempts,
+		IndexedAt:   job.IndexedAt,
+	}, nil
+}
+
 // buildBreadcrumb traverses notebook parent_id chain to build ancestry path from root to parent.
 // This enables deep linking: frontend can display breadcrumbs and auto-expand sidebar tree.
 func (c *noteService) buildBreadcrumb(ctx context.Context, uow unitofwork.UnitOfWork, notebookId uuid.UUID, userId uuid.UUID) ([]dto.BreadcrumbItem, error) {
@@ -146,13 +268,13 @@ func (c *noteService) buildBreadcrumb(ctx context.Context, uow unitofwork.UnitOf
 	for currentId != nil {
 		notebook, err := uow.NotebookRepository().FindOne(ctx,
 			specification.ByID{ID: *currentId},
-			specification.UserOwnedBy{UserID: userId},
+			specification.NotebookAccessibleBy{UserID: userId},
 		)
 		if err != nil {
 			return nil, err
 		}
 		if notebook == nil {
-			break // Safety: orphaned reference or ownership mismatch
+			break // Safety: orphaned reference, or above the notebook shared with the user
 		}
 
 		// Prepend to build root-first order
@@ -170,49 +292,318 @@ func (c *noteService) buildBreadcrumb(ctx context.Context, uow unitofwork.UnitOf
 func (c *noteService) Update(ctx context.Context, userId uuid.UUID, req *dto.UpdateNoteRequest) (*dto.UpdateNoteResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
 
-	note, err := uow.NoteRepository().FindOne(ctx,
-		specification.ByID{ID: req.Id},
-		specification.UserOwnedBy{UserID: userId},
-	)
+	note, err := findNoteWithRole(ctx, uow, userId, req.Id, entity.NotebookRoleEditor)
 	if err != nil {
 		return nil, err
 	}
 	if note == nil {
 		return nil, nil
 	}
+	if note.Version != req.Version {
+		return nil, noteVersionConflict(note, req)
+	}
 
+	changed := note.Title != req.Title || note.Content != req.Content
 	now := time.Now()
 
+	if err := uow.Begin(ctx); err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	if changed {
+		if err := c.ensureInitialRevision(ctx, uow, note); err != nil {
+			return nil, err
+		}
+	}
+
 	note.Title = req.Title
 	note.Content = req.Content
 	note.UpdatedAt = &now
 
 	err = uow.NoteRepository().Update(ctx, note)
 	if err != nil {
+		if errors.Is(err, contract.ErrStaleVersion) {
+			// Another save landed since the version check
+			if current, _ := uow.NoteRepository().FindOne(ctx, specification.ByID{ID: req.Id}); current != nil {
+				return nil, noteVersionConflict(current, req)
+			}
+		}
 		return nil, err
 	}
 
-	payload := dto.PublishEmbedNoteMessage{
-		NoteId: note.Id,
+	// No-op saves (e.g. autosave without changes) don't add history
+	if changed {
+		if _, err := c.recordRevision(ctx, uow, note, userId, entity.NoteRevisionSourceEdit, nil); err != nil {
+			return nil, err
+		}
+		if err := syncNoteLinks(ctx, uow, note); err != nil {
+			return nil, err
+		}
+		if err := uow.EmbeddingJobRepository().Enqueue(ctx, note.Id, false); err != nil {
+			return nil, err
+		}
 	}
-	payloadJson, _ := json.Marshal(payload)
-	err = c.publisherService.Publish(ctx, payloadJson)
-	if err != nil {
+
+	if err := uow.Commit(); err != nil {
 		return nil, err
 	}
+	if changed {
+		c.publisherService.Notify(note.Id)
+	}
 
 	return &dto.UpdateNoteResponse{
-		Id: note.Id,
+		Id:      note.Id,
+		Version: note.Version,
 	}, nil
 }
 
-func (c *noteService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+// noteVersionConflict describes how the stored note differs from the stale copy in req
+func noteVersionConflict(note *entity.Note, req *dto.UpdateNoteRequest) *dto.VersionConflictError {
+	markdown := lexical.DiffContent(req.Content, note.Content).Markdown
+	if req.Title != note.Title {
+		markdown = fmt.Sprintf("**Title:** ~~%s~~ → %s\n\n", lexical.EscapeMarkdown(req.Title), lexical.EscapeMarkdown(note.Title)) + markdown
+	}
+	return &dto.VersionConflictError{
+		Version:   note.Version,
+		UpdatedAt: note.UpdatedAt,
+		Markdown:  markdown,
+	}
+}
+
+// Reindex forces the note to be embedded again, e.g. after a failed job
+func (c *noteService) Reindex(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ReindexResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
 
-	note, err := uow.NoteRepository().FindOne(ctx,
-		specification.ByID{ID: id},
-		specification.UserOwnedBy{UserID: userId},
+	note, err := findNoteWithRole(ctx, uow, userId, id, entity.NotebookRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, nil
+	}
+
+	if err := uow.EmbeddingJobRepository().Enqueue(ctx, note.Id, true); err != nil {
+		return nil, err
+	}
+	c.publisherService.Notify(note.Id)
+
+	return &dto.ReindexResponse{Queued: 1}, nil
+}
+
+func (c *noteService) AuthorizeCollaboration(ctx context.Context, userId uuid.UUID, noteId uuid.UUID) (bool, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	note, err := findNoteWithRole(ctx, uow, userId, noteId, entity.NotebookRoleEditor)
+	if errors.Is(err, ErrNotebookAccessDenied) {
+		return false, nil // Viewer
+	}
+	if err != nil {
+		return false, err
+	}
+	if note == nil {
+		return false, ErrNoteNotFound
+	}
+	return true, nil
+}
+
+// ============================================================================
+// Revisions
+// ============================================================================
+
+// recordRevision appends a snapshot of the note's current title and content
+func (c *noteService) recordRevision(ctx context.Context, uow unitofwork.UnitOfWork, note *entity.Note, userId uuid.UUID, source entity.NoteRevisionSource, restoredFrom *uuid.UUID) (*entity.NoteRevision, error) {
+	rev := &entity.NoteRevision{
+		Id:           uuid.New(),
+		NoteId:       note.Id,
+		UserId:       userId,
+		Title:        note.Title,
+		Content:      note.Content,
+		Source:       source,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now(),
+	}
+	if err := uow.NoteRevisionRepository().Create(ctx, rev); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// ensureInitialRevision snapshots notes created before history existed, so their first edit can be undone
+func (c *noteService) ensureInitialRevision(ctx context.Context, uow unitofwork.UnitOfWork, note *entity.Note) error {
+	count, err := uow.NoteRevisionRepository().Count(ctx, specification.ByNoteID{NoteID: note.Id})
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = c.recordRevision(ctx, uow, note, note.UserId, entity.NoteRevisionSourceInitial, nil)
+	return err
+}
+
+func (c *noteService) ListRevisions(ctx context.Context, userId uuid.UUID, noteId uuid.UUID) ([]*dto.NoteRevisionResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	note, err := findNoteWithRole(ctx, uow, userId, noteId, entity.NotebookRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, nil
+	}
+
+	revisions, err := uow.NoteRevisionRepository().FindAll(ctx,
+		specification.ByNoteID{NoteID: noteId},
+		specification.OrderBy{Field: "number", Desc: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.NoteRevisionResponse, 0, len(revisions))
+	for _, rev := range revisions {
+		res = append(res, toNoteRevisionResponse(rev, false))
+	}
+	return res, nil
+}
+
+func (c *noteService) ShowRevision(ctx context.Context, userId uuid.UUID, noteId uuid.UUID, revisionId uuid.UUID) (*dto.NoteRevisionResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	_, rev, err := c.findRevision(ctx, uow, userId, noteId, revisionId, entity.NotebookRoleViewer)
+	if err != nil || rev == nil {
+		return nil, err
+	}
+	return toNoteRevisionResponse(rev, true), nil
+}
+
+// DiffRevisions compares revision fromId with toId, or with the current note when toId is nil
+func (c *noteService) DiffRevisions(ctx context.Context, userId uuid.UUID, noteId uuid.UUID, fromId uuid.UUID, toId *uuid.UUID) (*dto.NoteRevisionDiffResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	note, from, err := c.findRevision(ctx, uow, userId, noteId, fromId, entity.NotebookRoleViewer)
+	if err != nil || from == nil {
+		return nil, err
+	}
+
+	res := &dto.NoteRevisionDiffResponse{
+		FromId:     from.Id,
+		FromNumber: from.Number,
+		OldTitle:   from.Title,
+		NewTitle:   note.Title,
+	}
+	newContent := note.Content
+
+	if toId != nil {
+		_, to, err := c.findRevision(ctx, uow, userId, noteId, *toId, entity.NotebookRoleViewer)
+		if err != nil || to == nil {
+			return nil, err
+		}
+		res.ToId = &to.Id
+		res.ToNumber = to.Number
+		res.NewTitle = to.Title
+		newContent = to.Content
+	}
+
+	diff := lexical.DiffContent(from.Content, newContent)
+	res.TitleChanged = res.OldTitle != res.NewTitle
+	res.Added = diff.Added
+	res.Removed = diff.Removed
+	res.Markdown = diff.Markdown
+	if res.TitleChanged {
+		res.Markdown = fmt.Sprintf("**Title:** ~~%s~~ → %s\n\n", lexical.EscapeMarkdown(res.OldTitle), lexical.EscapeMarkdown(res.NewTitle)) + res.Markdown
+	}
+
+	return res, nil
+}
+
+// RestoreRevision copies a revision back into the note. The restore itself is recorded
+// as a new revision, so it can be undone like any other change.
+func (c *noteService) RestoreRevision(ctx context.Context, userId uuid.UUID, noteId uuid.UUID, revisionId uuid.UUID) (*dto.RestoreNoteRevisionResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	note, rev, err := c.findRevision(ctx, uow, userId, noteId, revisionId, entity.NotebookRoleEditor)
+	if err != nil || rev == nil {
+		return nil, err
+	}
+
+	if err := uow.Begin(ctx); err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	if err := c.ensureInitialRevision(ctx, uow, note); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	note.Title = rev.Title
+	note.Content = rev.Content
+	note.UpdatedAt = &now
+	if err := uow.NoteRepository().Update(ctx, note); err != nil {
+		return nil, err
+	}
+
+	restored, err := c.recordRevision(ctx, uow, note, userId, entity.NoteRevisionSourceRestore, &rev.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := syncNoteLinks(ctx, uow, note); err != nil {
+		return nil, err
+	}
+	if err := uow.EmbeddingJobRepository().Enqueue(ctx, note.Id, false); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	c.publisherService.Notify(note.Id)
+
+	return &dto.RestoreNoteRevisionResponse{
+		Id:         note.Id,
+		RevisionId: restored.Id,
+		Number:     restored.Number,
+	}, nil
+}
+
+// findRevision loads a note the user can access with the given role and one of its revisions
+// (nil, nil, nil if either is missing)
+func (c *noteService) findRevision(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, noteId uuid.UUID, revisionId uuid.UUID, min entity.NotebookRole) (*entity.Note, *entity.NoteRevision, error) {
+	note, err := findNoteWithRole(ctx, uow, userId, noteId, min)
+	if err != nil || note == nil {
+		return nil, nil, err
+	}
+
+	rev, err := uow.NoteRevisionRepository().FindOne(ctx,
+		specification.ByID{ID: revisionId},
+		specification.ByNoteID{NoteID: noteId},
 	)
+	if err != nil || rev == nil {
+		return nil, nil, err
+	}
+	return note, rev, nil
+}
+
+func toNoteRevisionResponse(rev *entity.NoteRevision, withContent bool) *dto.NoteRevisionResponse {
+	res := &dto.NoteRevisionResponse{
+		Id:           rev.Id,
+		Number:       rev.Number,
+		UserId:       rev.UserId,
+		Title:        rev.Title,
+		Source:       string(rev.Source),
+		RestoredFrom: rev.RestoredFrom,
+		CreatedAt:    rev.CreatedAt,
+	}
+	if withContent {
+		res.Content = rev.Content
+	}
+	return res
+}
+
+func (c *noteService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	// Trashed notes of a shared notebook go to the owner's trash
+	note, err := findNoteWithRole(ctx, uow, userId, id, entity.NotebookRoleEditor)
 	if err != nil {
 		return err
 	}
@@ -225,7 +616,8 @@ func (c *noteService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID
 	}
 	defer uow.Rollback()
 
-	if err := uow.NoteRepository().Delete(ctx, id); err != nil {
+	// Move to trash; embeddings are rebuilt on restore
+	if err := uow.NoteRepository().MoveToTrash(ctx, []uuid.UUID{id}, id, time.Now()); err != nil {
 		return err
 	}
 
@@ -238,10 +630,7 @@ func (c *noteService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID
 
 func (c *noteService) MoveNote(ctx context.Context, userId uuid.UUID, req *dto.MoveNoteRequest) (*dto.MoveNoteResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
-	note, err := uow.NoteRepository().FindOne(ctx,
-		specification.ByID{ID: req.Id},
-		specification.UserOwnedBy{UserID: userId},
-	)
+	note, err := findNoteWithRole(ctx, uow, userId, req.Id, entity.NotebookRoleEditor)
 	if err != nil {
 		return nil, err
 	}
@@ -249,23 +638,40 @@ func (c *noteService) MoveNote(ctx context.Context, userId uuid.UUID, req *dto.M
 		return nil, nil
 	}
 
+	target, _, err := findNotebookWithRole(ctx, uow, userId, req.NotebookId, entity.NotebookRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrNotebookNotFound
+	}
+	// Notes cannot change owner by moving between notebooks of different users
+	if target.UserId != note.UserId {
+		return nil, ErrNotebookAccessDenied
+	}
+
 	now := time.Now()
 	note.NotebookId = req.NotebookId
 	note.UpdatedAt = &now
 
-	err = uow.NoteRepository().Update(ctx, note)
-	if err != nil {
+	if err := uow.Begin(ctx); err != nil {
 		return nil, err
 	}
+	defer uow.Rollback()
 
-	payload := dto.PublishEmbedNoteMessage{
-		NoteId: note.Id,
-	}
-	payloadJson, _ := json.Marshal(payload)
-	err = c.publisherService.Publish(ctx, payloadJson)
+	err = uow.NoteRepository().Move(ctx, note)
 	if err != nil {
 		return nil, err
 	}
+	// The note is embedded with its notebook name
+	if err := uow.EmbeddingJobRepository().Enqueue(ctx, note.Id, false); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	c.publisherService.Notify(note.Id)
 
 	return &dto.MoveNoteResponse{
 		Id: note.Id,
@@ -290,7 +696,15 @@ func (c *noteService) getSemanticSearchThreshold(ctx context.Context, uow unitof
 	return val // Respect configured value without floor
 }
 
-func (c *noteService) SemanticSearch(ctx context.Context, userId uuid.UUID, search string) ([]*dto.SemanticSearchResponse, error) {
+// Search paging bounds
+const (
+	defaultSearchLimit   = 10
+	maxSearchLimit       = 50
+	maxSearchDepth       = 200 // Ranked results are never paged beyond this many notes
+	semanticChunksPerHit = 3   // Chunk candidates fetched per wanted note (several chunks may hit one note)
+)
+
+func (c *noteService) SemanticSearch(ctx context.Context, userId uuid.UUID, req *dto.SemanticSearchRequest) (*dto.SemanticSearchPage, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
 
 	// Verify Semantic Search Access and Limits
@@ -298,116 +712,165 @@ func (c *noteService) SemanticSearch(ctx context.Context, userId uuid.UUID, sear
 		return nil, err
 	}
 
-	var err error // Fix undefined err
+	cursor, err := pkgSearch.DecodeCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
-	var notes []*entity.Note
-	var searchType string
-	scoreMap := make(map[uuid.UUID]float64) // Track scores for semantic results
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
 
 	// === SLASH COMMAND PARSING ===
-	// Extract filters like /nb:, /note:
-	filters := pkgSearch.ParseQuery(search)
-	hasFilters := filters.NotebookName != "" || filters.NoteTitle != ""
-
-	if hasFilters {
-		// STRATEGY: LITERAL FILTER (Bypass AI)
-		searchType = "literal_filter"
+	// Extract filters like /nb:, /tag:, /after: and merge them with the query params
+	filters := pkgSearch.ParseQuery(req.Query)
+	if req.Tag != "" {
+		filters.Tag = strings.TrimPrefix(strings.ToLower(req.Tag), "#")
+	}
+	if req.HasChecklist {
+		filters.HasChecklist = true
+	}
+	if req.After != nil {
+		filters.After = req.After
+	}
+	if req.Before != nil {
+		filters.Before = req.Before
+	}
+	if sortBy := pkgSearch.NormalizeSort(req.Sort); sortBy != "" {
+		filters.Sort = sortBy
+	}
+	constraints := searchConstraints(userId, filters, req.NotebookId, req.IncludeChildren)
+	query := filters.SearchQuery
 
-		specs := []specification.Specification{
-			specification.NoteOwnedByUser{UserID: userId}, // Explicit table alias to avoid ambiguity
+	var notes []*entity.Note
+	var nextCursor string
+	var searchType string
+	scoreMap := make(map[uuid.UUID]float64)   // Vector similarity per note
+	lexicalMap := make(map[uuid.UUID]float64) // Full-text rank per note
+	fusedMap := make(map[uuid.UUID]float64)   // RRF score per note
+
+	mode := req.Mode
+	if mode == "" || mode == dto.SearchModeAuto {
+		// Default, as before hybrid search: decide between Literal or Semantic based on query
+		mode = dto.SearchModeSemantic
+		if pkgSearch.DetermineStrategy(query) == pkgSearch.StrategyLiteral {
+			mode = dto.SearchModeLiteral
 		}
+	}
 
-		if filters.NotebookName != "" {
-			specs = append(specs, specification.ByNotebookName{Name: filters.NotebookName})
-		}
-		if filters.NoteTitle != "" {
-			specs = append(specs, specification.ByNoteTitle{Title: filters.NoteTitle})
-		}
-		if filters.SearchQuery != "" {
-			// If there's text remaining, search it in Title OR Content
-			specs = append(specs, specification.NoteSearchQuery{Query: filters.SearchQuery})
+	switch {
+	case query == "":
+		// STRATEGY: FILTER ONLY (Bypass AI) - list matching notes, newest first
+		searchType = "literal_filter"
+		notes, nextCursor, err = c.listNotes(ctx, uow, constraints, filters.Sort, cursor, limit)
+		if err != nil {
+			return nil, err
 		}
 
-		notes, err = uow.NoteRepository().FindAll(ctx, specs...)
+	case mode == dto.SearchModeLiteral:
+		searchType = "literal"
+		// Literal Search: SQL ILIKE
+		specs := append(constraints, specification.NoteSearchQuery{Query: query})
+		notes, nextCursor, err = c.listNotes(ctx, uow, specs, filters.Sort, cursor, limit)
 		if err != nil {
 			return nil, err
 		}
 
-	} else {
-		// === SMART SEARCH STRATEGY ===
-		// No manual filters -> decide between Literal or Semantic based on query
-		strategy := pkgSearch.DetermineStrategy(search)
+	default:
+		// Ranked search: build a relevance-ordered pool deep enough for the requested page
+		depth := cursor.Offset + limit + 1
+		if depth > maxSearchDepth {
+			depth = maxSearchDepth
+		}
 
-		if strategy == pkgSearch.StrategyLiteral {
-			searchType = "literal"
-			// Literal Search: SQL ILIKE
-			notes, err = uow.NoteRepository().FindAll(ctx,
-				specification.UserOwnedBy{UserID: userId},
-				specification.NoteSearchQuery{Query: search},
-			)
+		var ranked []uuid.UUID
+		if mode == dto.SearchModeSemantic {
+			searchType = "semantic"
+			// Semantic Search: Vector Embedding
+			ranked, scoreMap, err = c.semanticRanking(ctx, uow, userId, query, depth*semanticChunksPerHit)
 			if err != nil {
 				return nil, err
 			}
 		} else {
-			searchType = "semantic"
-			// Semantic Search: Vector Embedding
-			embeddingRes, err := c.embeddingProvider.Generate(
-				search,
-				"RETRIEVAL_QUERY",
-			)
+			searchType = "hybrid"
+			// Hybrid Search: full-text ranking + vector ranking, fused with Reciprocal Rank Fusion
+			lexicalResults, err := uow.NoteRepository().FullTextSearch(ctx, query, depth, constraints...)
 			if err != nil {
 				return nil, err
 			}
+			lexicalIds := make([]uuid.UUID, len(lexicalResults))
+			for i, sn := range lexicalResults {
+				lexicalIds[i] = sn.Note.Id
+				lexicalMap[sn.Note.Id] = sn.Rank
+			}
 
-			// Get threshold from configuration (not hardcoded)
-			threshold := c.getSemanticSearchThreshold(ctx, uow)
-
-			// Search Similar WITH SCORE (Threshold filtering)
-			scoredResults, err := uow.NoteEmbeddingRepository().SearchSimilarWithScore(ctx, embeddingRes.Embedding.Values, 10, userId, threshold)
+			semanticIds, scores, err := c.semanticRanking(ctx, uow, userId, query, depth*semanticChunksPerHit)
 			if err != nil {
-				return nil, err
+				// Embedding provider unavailable: degrade to keyword results instead of failing
+				fmt.Printf("[WARN] Hybrid search falling back to full-text only: %v\n", err)
+				semanticIds = nil
+			} else {
+				scoreMap = scores
 			}
 
-			if len(scoredResults) == 0 {
-				return []*dto.SemanticSearchResponse{}, nil
+			for _, f := range pkgSearch.ReciprocalRankFusion(pkgSearch.DefaultRRFK, lexicalIds, semanticIds) {
+				ranked = append(ranked, f.Id)
+				fusedMap[f.Id] = f.Score
 			}
+		}
 
-			// Extract Note IDs and track scores (Deduplicated)
-			ids := make([]uuid.UUID, 0)
-			seen := make(map[uuid.UUID]bool)
+		// Vector hits are not filtered in SQL, so constraints are applied here
+		pool, err := c.findNotesInOrder(ctx, uow, ranked, constraints...)
+		if err != nil {
+			return nil, err
+		}
+		if filters.Sort == pkgSearch.SortUpdated || filters.Sort == pkgSearch.SortCreated {
+			sort.SliceStable(pool, func(i, j int) bool {
+				return noteSortTime(pool[i], filters.Sort).After(noteSortTime(pool[j], filters.Sort))
+			})
+		}
+		if len(pool) > depth {
+			pool = pool[:depth]
+		}
 
-			for _, sr := range scoredResults {
-				if !seen[sr.Embedding.NoteId] {
-					ids = append(ids, sr.Embedding.NoteId)
-					seen[sr.Embedding.NoteId] = true
-					scoreMap[sr.Embedding.NoteId] = sr.Similarity
-				}
-			}
+		end := cursor.Offset + limit
+		if cursor.Offset < len(pool) {
+			notes = pool[cursor.Offset:min(end, len(pool))]
+		}
+		if end < len(pool) {
+			nextCursor = pkgSearch.EncodeCursor(pkgSearch.Cursor{Offset: end})
+		}
+	}
 
-			fetchedNotes, err := uow.NoteRepository().FindAll(ctx, specification.ByIDs{IDs: ids}, specification.UserOwnedBy{UserID: userId})
-			if err != nil {
-				return nil, err
-			}
+	// === SNIPPETS ===
+	// Best-effort: a failure here should not hide the results themselves
+	snippets := make(map[uuid.UUID]string)
+	if query != "" && len(notes) > 0 {
+		ids := make([]uuid.UUID, len(notes))
+		for i, note := range notes {
+			ids[i] = note.Id
+		}
+		if headlines, err := uow.NoteRepository().Headlines(ctx, ids, query); err == nil {
+			snippets = headlines
+		} else {
+			fmt.Printf("[WARN] Failed to build search snippets: %v\n", err)
+		}
+	}
 
-			// Preserve order of Scored Results (highly relevant first)
-			notes = make([]*entity.Note, 0)
-
-			// Re-use seen map to ensure we don't add the same note twice
-			// (if multiple chunks of the same note are in top K)
-			added := make(map[uuid.UUID]bool)
-
-			for _, sr := range scoredResults {
-				if added[sr.Embedding.NoteId] {
-					continue
-				}
-				for _, note := range fetchedNotes {
-					if sr.Embedding.NoteId == note.Id {
-						notes = append(notes, note)
-						added[note.Id] = true
-						break
-					}
-				}
-			}
+	// === TAGS ===
+	tagsByNote := make(map[uuid.UUID][]*entity.Tag)
+	if len(notes) > 0 {
+		ids := make([]uuid.UUID, len(notes))
+		for i, note := range notes {
+			ids[i] = note.Id
+		}
+		tagsByNote, err = uow.TagRepository().FindByNoteIds(ctx, ids)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -425,23 +888,308 @@ func (c *noteService) SemanticSearch(ctx context.Context, userId uuid.UUID, sear
 			CreatedAt:  note.CreatedAt,
 			UpdatedAt:  note.UpdatedAt,
 			SearchType: searchType, // <-- INJECTED INDICATOR
+			Snippet:    snippets[note.Id],
+			Tags:       toNoteTagResponses(tagsByNote[note.Id]),
 		}
 
-		// Include relevance score for semantic search results
+		// Include per-signal scores so clients can explain the ranking
 		if score, ok := scoreMap[note.Id]; ok {
 			resp.RelevanceScore = &score
 		}
+		if score, ok := lexicalMap[note.Id]; ok {
+			resp.LexicalScore = &score
+		}
+		if score, ok := fusedMap[note.Id]; ok {
+			resp.FusedScore = &score
+		}
 
 		response = append(response, resp)
 	}
 
-	// Increment Usage after successful search
+	// Increment Usage after successful search (first page only - paging is not a new search)
 	// We do this asynchronously or synchronously? Safest to do sync to block spam.
-	if err := c.accessVerifier.IncrementSemanticSearchUsage(ctx, uow, userId); err != nil {
-		// Log error but don't fail the request as data is already retrieved
-		// ideally logging it
-		fmt.Printf("Error incrementing usage: %v\n", err)
+	if req.Cursor == "" {
+		if err := c.accessVerifier.IncrementSemanticSearchUsage(ctx, uow, userId); err != nil {
+			// Log error but don't fail the request as data is already retrieved
+			// ideally logging it
+			fmt.Printf("Error incrementing usage: %v\n", err)
+		}
 	}
 
-	return response, nil
+	return &dto.SemanticSearchPage{Results: response, NextCursor: nextCursor}, nil
+}
+
+// searchConstraints turns parsed filters into note specifications (access always included)
+func searchConstraints(userId uuid.UUID, filters pkgSearch.SearchFilters, notebookId *uuid.UUID, includeChildren bool) []specification.Specification {
+	specs := []specification.Specification{
+		specification.NoteAccessibleBy{UserID: userId}, // Own notes and notes of shared notebooks
+	}
+
+	switch {
+	case notebookId != nil && includeChildren:
+		specs = append(specs, specification.InNotebookSubtree{UserID: userId, RootID: notebookId})
+	case notebookId != nil:
+		specs = append(specs, specification.ByNotebookID{NotebookID: *notebookId})
+	}
+	if filters.NotebookName != "" {
+		if filters.IncludeChildren {
+			specs = append(specs, specification.InNotebookSubtree{UserID: userId, Name: filters.NotebookName})
+		} else {
+			specs = append(specs, specification.ByNotebookName{Name: filters.NotebookName})
+		}
+	}
+	if filters.NoteTitle != "" {
+		specs = append(specs, specification.ByNoteTitle{Title: filters.NoteTitle})
+	}
+	if filters.Tag != "" {
+		specs = append(specs, specification.NoteHasTag{UserID: userId, Tag: filters.Tag})
+	}
+	if filters.HasChecklist {
+		specs = append(specs, specification.NoteHasChecklist{})
+	}
+	if filters.After != nil || filters.Before != nil {
+		specs = append(specs, specification.NoteUpdatedBetween{After: filters.After, Before: filters.Before})
+	}
+
+	return specs
+}
+
+// listNotes returns one page of notes matching specs ordered by date (newest first), paged by keyset
+func (c *noteService) listNotes(ctx context.Context, uow unitofwork.UnitOfWork, specs []specification.Specification, sortBy string, cursor pkgSearch.Cursor, limit int) ([]*entity.Note, string, error) {
+	column := "notes.updated_at"
+	if sortBy == pkgSearch.SortCreated {
+		column = "notes.created_at"
+	}
+
+	if cursor.SortValue != nil {
+		specs = append(specs, specification.NoteKeysetAfter{Column: column, Value: *cursor.SortValue, Id: cursor.Id})
+	}
+	specs = append(specs,
+		specification.OrderBy{Field: column, Desc: true},
+		specification.OrderBy{Field: "notes.id", Desc: true},
+		specification.Pagination{Limit: limit + 1},
+	)
+
+	notes, err := uow.NoteRepository().FindAll(ctx, specs...)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(notes) <= limit {
+		return notes, "", nil
+	}
+
+	notes = notes[:limit]
+	last := notes[limit-1]
+	value := noteSortTime(last, sortBy)
+	return notes, pkgSearch.EncodeCursor(pkgSearch.Cursor{SortValue: &value, Id: last.Id}), nil
+}
+
+// noteSortTime returns the timestamp a note is ordered by for the given sort
+func noteSortTime(note *entity.Note, sortBy string) time.Time {
+	if sortBy != pkgSearch.SortCreated && note.UpdatedAt != nil {
+		return *note.UpdatedAt
+	}
+	return note.CreatedAt
+}
+
+// semanticRanking embeds the query and returns matching note IDs, most similar first,
+// with the best chunk similarity per note
+func (c *noteService) semanticRanking(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, query string, limit int) ([]uuid.UUID, map[uuid.UUID]float64, error) {
+	embeddingRes, err := c.embeddingProvider.Generate(
+		query,
+		"RETRIEVAL_QUERY",
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get threshold from configuration (not hardcoded)
+	threshold := c.getSemanticSearchThreshold(ctx, uow)
+
+	// Search Similar WITH SCORE (Threshold filtering)
+	scoredResults, err := uow.NoteEmbeddingRepository().SearchSimilarWithScore(ctx, embeddingRes.Embedding.Values, limit, userId, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Deduplicate chunks: results are ordered by similarity, so the first chunk of a note is its best
+	ids := make([]uuid.UUID, 0)
+	scores := make(map[uuid.UUID]float64)
+	for _, sr := range scoredResults {
+		if _, seen := scores[sr.Embedding.NoteId]; !seen {
+			ids = append(ids, sr.Embedding.NoteId)
+			scores[sr.Embedding.NoteId] = sr.Similarity
+		}
+	}
+
+	return ids, scores, nil
+}
+
+// findNotesInOrder fetches notes by ID matching specs, preserving the order of ids
+func (c *noteService) findNotesInOrder(ctx context.Context, uow unitofwork.UnitOfWork, ids []uuid.UUID, specs ...specification.Specification) ([]*entity.Note, error) {
+	if len(ids) == 0 {
+		return []*entity.Note{}, nil
+	}
+
+	fetchedNotes, err := uow.NoteRepository().FindAll(ctx, append(specs, specification.ByIDs{IDs: ids})...)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uuid.UUID]*entity.Note, len(fetchedNotes))
+	for _, note := range fetchedNotes {
+		byId[note.Id] = note
+	}
+
+	notes := make([]*entity.Note, 0, len(ids))
+	for _, id := range ids {
+		if note, ok := byId[id]; ok {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+// Graph returns the link graph of the user's notes, or of one notebook (optionally with its descendants).
+// Targets outside the notebook are included as out-of-scope nodes; links to no live note are reported as unresolved.
+func (c *noteService) Graph(ctx context.Context, userId uuid.UUID, req *dto.NoteGraphRequest) (*dto.NoteGraphResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	specs := []specification.Specification{specification.NoteAccessibleBy{UserID: userId}}
+	switch {
+	case req.NotebookId != nil && req.IncludeChildren:
+		specs = append(specs, specification.InNotebookSubtree{UserID: userId, RootID: req.NotebookId})
+	case req.NotebookId != nil:
+		specs = append(specs, specification.ByNotebookID{NotebookID: *req.NotebookId})
+	}
+	notes, err := uow.NoteRepository().FindAll(ctx, append(specs, specification.OrderBy{Field: "notes.title"})...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.NoteGraphResponse{
+		Nodes:      make([]dto.NoteGraphNode, 0, len(notes)),
+		Edges:      make([]dto.NoteGraphEdge, 0),
+		Unresolved: make([]dto.NoteGraphUnresolvedLink, 0),
+	}
+	if len(notes) == 0 {
+		return res, nil
+	}
+
+	nodes := make(map[uuid.UUID]bool, len(notes))
+	noteIds := make([]uuid.UUID, len(notes))
+	for i, note := range notes {
+		nodes[note.Id] = true
+		noteIds[i] = note.Id
+		res.Nodes = append(res.Nodes, dto.NoteGraphNode{Id: note.Id, Title: note.Title, NotebookId: note.NotebookId, InScope: true})
+	}
+
+	// Links of shared notes are stored under their owners, so they are selected by source
+	linkSpecs := []specification.Specification{specification.LinkSourceAccessibleBy{UserID: userId}}
+	if req.NotebookId != nil {
+		linkSpecs = append(linkSpecs, specification.ByLinkSources{NoteIDs: noteIds})
+	}
+	links, err := uow.NoteLinkRepository().FindAll(ctx, linkSpecs...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Link targets outside the scope become extra nodes, as long as they are live notes
+	var outsideIds []uuid.UUID
+	for _, link := range links {
+		if link.TargetNoteId != nil && !nodes[*link.TargetNoteId] {
+			outsideIds = append(outsideIds, *link.TargetNoteId)
+		}
+	}
+	if len(outsideIds) > 0 {
+		outside, err := uow.NoteRepository().FindAll(ctx,
+			specification.ByIDs{IDs: uniqueIds(outsideIds)},
+			specification.NoteAccessibleBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range outside {
+			nodes[note.Id] = true
+			res.Nodes = append(res.Nodes, dto.NoteGraphNode{Id: note.Id, Title: note.Title, NotebookId: note.NotebookId})
+		}
+	}
+
+	// A wiki link and a URL link between the same notes make one edge
+	seen := make(map[[2]uuid.UUID]bool)
+	for _, link := range links {
+		if !nodes[link.SourceNoteId] {
+			continue // Link from a trashed note
+		}
+		if link.TargetNoteId == nil || !nodes[*link.TargetNoteId] {
+			res.Unresolved = append(res.Unresolved, dto.NoteGraphUnresolvedLink{
+				Source: link.SourceNoteId,
+				Target: link.Target,
+				Kind:   link.Kind,
+			})
+			continue
+		}
+		key := [2]uuid.UUID{link.SourceNoteId, *link.TargetNoteId}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		res.Edges = append(res.Edges, dto.NoteGraphEdge{Source: link.SourceNoteId, Target: *link.TargetNoteId, Kind: link.Kind})
+	}
+
+	return res, nil
+}
+
+// syncNoteLinks stores the links found in the note's content, resolved against the user's live notes,
+// and points other notes' unresolved [[links]] matching its title at it
+func syncNoteLinks(ctx context.Context, uow unitofwork.UnitOfWork, note *entity.Note) error {
+	parsed := lexical.ExtractLinks(note.Content)
+	links := make([]*entity.NoteLink, 0, len(parsed))
+	now := time.Now()
+
+	for _, pl := range parsed {
+		target := pl.Target
+		if runes := []rune(target); len(runes) > 255 {
+			target = string(runes[:255])
+		}
+		link := &entity.NoteLink{
+			Id:           uuid.New(),
+			UserId:       note.UserId,
+			SourceNoteId: note.Id,
+			Kind:         pl.Kind,
+			Target:       target,
+			CreatedAt:    now,
+		}
+
+		var targetSpec specification.Specification = specification.ByNoteTitleExact{Title: pl.Target}
+		if pl.IsID {
+			id, err := uuid.Parse(pl.Target)
+			if err != nil {
+				continue
+			}
+			targetSpec = specification.ByID{ID: id}
+		}
+		// Several notes may share a title: the most recently edited one wins
+		found, err := uow.NoteRepository().FindOne(ctx,
+			targetSpec,
+			specification.NoteOwnedByUser{UserID: note.UserId},
+			specification.OrderBy{Field: "notes.updated_at", Desc: true},
+		)
+		if err != nil {
+			return err
+		}
+		if found != nil {
+			if found.Id == note.Id {
+				continue // Self links carry no information
+			}
+			link.TargetNoteId = &found.Id
+		}
+		links = append(links, link)
+	}
+
+	if err := uow.NoteLinkRepository().ReplaceForNote(ctx, note.Id, links); err != nil {
+		return err
+	}
+	_, err := uow.NoteLinkRepository().ResolveTitle(ctx, note.UserId, note.Title, note.Id)
+	return err
 }
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotebookAccessDenied is returned when the user can see a notebook or note but their role is too low for the action
+var ErrNotebookAccessDenied = errors.New("notebook access denied")
+
+// notebookRole returns the user's role on the notebook: owner for the notebook's owner, otherwise the
+// highest role of the user's accepted memberships on the notebook or its ancestors ("" without access)
+func notebookRole(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, notebook *entity.Notebook) (entity.NotebookRole, error) {
+	if notebook.UserId == userId {
+		return entity.NotebookRoleOwner, nil
+	}
+
+	members, err := uow.NotebookMemberRepository().FindAll(ctx,
+		specification.OnNotebookOrAncestors{NotebookID: notebook.Id},
+		specification.ByMemberUser{UserID: userId},
+		specification.ByMemberStatus{Status: string(entity.NotebookMemberStatusAccepted)},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var role entity.NotebookRole
+	for _, member := range members {
+		if member.Role.Valid() && !role.Allows(member.Role) {
+			role = member.Role
+		}
+	}
+	return role, nil
+}
+
+// findNotebookWithRole loads a live notebook the user can access with at least the given role.
+// It returns nil, nil when the notebook is missing or not visible to the user, and
+// ErrNotebookAccessDenied when the user's role is too low.
+func findNotebookWithRole(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, id uuid.UUID, min entity.NotebookRole) (*entity.Notebook, entity.NotebookRole, error) {
+	notebook, err := uow.NotebookRepository().FindOne(ctx, specification.ByID{ID: id})
+	if err != nil || notebook == nil {
+		return nil, "", err
+	}
+
+	role, err := notebookRole(ctx, uow, userId, notebook)
+	if err != nil {
+		return nil, "", err
+	}
+	if role == "" {
+		return nil, "", nil
+	}
+	if !role.Allows(min) {
+		return nil, role, ErrNotebookAccessDenied
+	}
+	return notebook, role, nil
+}
+
+// findNoteWithRole loads a live note the user can access with at least the given role on its notebook,
+// with the same results as findNotebookWithRole
+func findNoteWithRole(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, id uuid.UUID, min entity.NotebookRole) (*entity.Note, error) {
+	note, err := uow.NoteRepository().FindOne(ctx, specification.ByID{ID: id})
+	if err != nil || note == nil {
+		return nil, err
+	}
+	if note.UserId == userId {
+		return note, nil
+	}
+
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, note.NotebookId, min)
+	if err != nil || notebook == nil {
+		return nil, err
+	}
+	return note, nil
+}
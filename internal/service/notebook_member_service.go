@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/pkg/mailer"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrMemberNotFound is returned when the membership does not exist or is on a notebook the user cannot see
+	ErrMemberNotFound = errors.New("notebook member not found")
+	// ErrMemberAlreadyInvited is returned when the email already has a membership or invitation on the notebook
+	ErrMemberAlreadyInvited = errors.New("email is already a member of the notebook or invited")
+	// ErrMemberIsOwner is returned when the notebook's owner is invited to their own notebook
+	ErrMemberIsOwner = errors.New("the notebook owner cannot be invited")
+	// ErrInvitationNotFound is returned when no pending invitation with the id is addressed to the user
+	ErrInvitationNotFound = errors.New("invitation not found")
+)
+
+type INotebookMemberService interface {
+	// List returns the members and pending invitations of the notebook itself
+	List(ctx context.Context, userId uuid.UUID, notebookId uuid.UUID) ([]*dto.NotebookMemberResponse, error)
+	Invite(ctx context.Context, userId uuid.UUID, notebookId uuid.UUID, req *dto.InviteNotebookMemberRequest) (*dto.NotebookMemberResponse, error)
+	UpdateRole(ctx context.Context, userId uuid.UUID, id uuid.UUID, req *dto.UpdateNotebookMemberRequest) (*dto.NotebookMemberResponse, error)
+	// Remove deletes a membership or invitation; members can always remove (leave) their own
+	Remove(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	// Invitations returns the pending invitations addressed to the user's email
+	Invitations(ctx context.Context, userId uuid.UUID) ([]*dto.NotebookInvitationResponse, error)
+	Accept(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.NotebookMemberResponse, error)
+	Decline(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+}
+
+type notebookMemberService struct {
+	uowFactory   unitofwork.RepositoryFactory
+	emailService mailer.IEmailService
+}
+
+func NewNotebookMemberService(uowFactory unitofwork.RepositoryFactory, emailService mailer.IEmailService) INotebookMemberService {
+	return &notebookMemberService{
+		uowFactory:   uowFactory,
+		emailService: emailService,
+	}
+}
+
+func (c *notebookMemberService) List(ctx context.Context, userId uuid.UUID, notebookId uuid.UUID) ([]*dto.NotebookMemberResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, notebookId, entity.NotebookRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if notebook == nil {
+		return nil, ErrNotebookNotFound
+	}
+
+	members, err := uow.NotebookMemberRepository().FindAll(ctx,
+		specification.ByNotebookID{NotebookID: notebook.Id},
+		specification.OrderBy{Field: "created_at"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := c.userNames(ctx, uow, members)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.NotebookMemberResponse, 0, len(members))
+	for _, member := range members {
+		res = append(res, toNotebookMemberResponse(member, names))
+	}
+	return res, nil
+}
+
+func (c *notebookMemberService) Invite(ctx context.Context, userId uuid.UUID, notebookId uuid.UUID, req *dto.InviteNotebookMemberRequest) (*dto.NotebookMemberResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, notebookId, entity.NotebookRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if notebook == nil {
+		return nil, ErrNotebookNotFound
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	owner, err := uow.UserRepository().FindOne(ctx, specification.ByID{ID: notebook.UserId})
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil && strings.EqualFold(owner.Email, email) {
+		return nil, ErrMemberIsOwner
+	}
+
+	existing, err := uow.NotebookMemberRepository().FindOne(ctx,
+		specification.ByNotebookID{NotebookID: notebook.Id},
+		specification.ByMemberEmail{Email: email},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrMemberAlreadyInvited
+	}
+
+	member := entity.NotebookMember{
+		Id:         uuid.New(),
+		NotebookId: notebook.Id,
+		Email:      email,
+		Role:       entity.NotebookRole(req.Role),
+		Status:     entity.NotebookMemberStatusPending,
+		InvitedBy:  userId,
+		CreatedAt:  time.Now(),
+	}
+	if err := uow.NotebookMemberRepository().Create(ctx, &member); err != nil {
+		return nil, err
+	}
+
+	inviter, err := uow.UserRepository().FindOne(ctx, specification.ByID{ID: userId})
+	if err != nil {
+		return nil, err
+	}
+	inviterName := "Someone"
+	if inviter != nil {
+		inviterName = inviter.FullName
+		if inviterName == "" {
+			inviterName = inviter.Email
+		}
+	}
+	go func() {
+		emailErr := c.emailService.SendNotebookInvitation(email, inviterName, notebook.Name, req.Role)
+		if emailErr != nil {
+			fmt.Printf("Error sending notebook invitation email: %v\n", emailErr)
+		}
+	}()
+
+	return toNotebookMemberResponse(&member, nil), nil
+}
+
+func (c *notebookMemberService) UpdateRole(ctx context.Context, userId uuid.UUID, id uuid.UUID, req *dto.UpdateNotebookMemberRequest) (*dto.NotebookMemberResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	member, err := c.findManagedMember(ctx, uow, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Role = entity.NotebookRole(req.Role)
+	member.UpdatedAt = time.Now()
+	if err := uow.NotebookMemberRepository().Update(ctx, member); err != nil {
+		return nil, err
+	}
+
+	names, err := c.userNames(ctx, uow, []*entity.NotebookMember{member})
+	if err != nil {
+		return nil, err
+	}
+	return toNotebookMemberResponse(member, names), nil
+}
+
+func (c *notebookMemberService) Remove(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	member, err := uow.NotebookMemberRepository().FindOne(ctx, specification.ByID{ID: id})
+	if err != nil {
+		return err
+	}
+	// Leaving a notebook needs no role
+	if member == nil || member.UserId == nil || *member.UserId != userId {
+		member, err = c.findManagedMember(ctx, uow, userId, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return uow.NotebookMemberRepository().Delete(ctx, member.Id)
+}
+
+// findManagedMember loads a membership of a notebook the user has the owner role on
+func (c *notebookMemberService) findManagedMember(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, id uuid.UUID) (*entity.NotebookMember, error) {
+	member, err := uow.NotebookMemberRepository().FindOne(ctx, specification.ByID{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, member.NotebookId, entity.NotebookRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if notebook == nil {
+		return nil, ErrMemberNotFound
+	}
+	return member, nil
+}
+
+func (c *notebookMemberService) Invitations(ctx context.Context, userId uuid.UUID) ([]*dto.NotebookInvitationResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	user, err := uow.UserRepository().FindOne(ctx, specification.ByID{ID: userId})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.NotebookInvitationResponse, 0)
+	if user == nil {
+		return res, nil
+	}
+
+	invitations, err := uow.NotebookMemberRepository().FindAll(ctx,
+		specification.ByMemberEmail{Email: user.Email},
+		specification.ByMemberStatus{Status: string(entity.NotebookMemberStatusPending)},
+		specification.OrderBy{Field: "created_at", Desc: true},
+	)
+	if err != nil || len(invitations) == 0 {
+		return res, err
+	}
+
+	notebookIds := make([]uuid.UUID, len(invitations))
+	inviterIds := make([]uuid.UUID, len(invitations))
+	for i, invitation := range invitations {
+		notebookIds[i] = invitation.NotebookId
+		inviterIds[i] = invitation.InvitedBy
+	}
+	notebooks, err := uow.NotebookRepository().FindAll(ctx, specification.ByIDs{IDs: uniqueIds(notebookIds)})
+	if err != nil {
+		return nil, err
+	}
+	notebookNames := make(map[uuid.UUID]string, len(notebooks))
+	for _, notebook := range notebooks {
+		notebookNames[notebook.Id] = notebook.Name
+	}
+	inviters, err := uow.UserRepository().FindAll(ctx, specification.ByIDs{IDs: uniqueIds(inviterIds)})
+	if err != nil {
+		return nil, err
+	}
+	inviterNames := make(map[uuid.UUID]string, len(inviters))
+	for _, inviter := range inviters {
+		inviterNames[inviter.Id] = inviter.FullName
+	}
+
+	for _, invitation := range invitations {
+		name, ok := notebookNames[invitation.NotebookId]
+		if !ok {
+			continue // Notebook is in the trash
+		}
+		res = append(res, &dto.NotebookInvitationResponse{
+			Id:           invitation.Id,
+			NotebookId:   invitation.NotebookId,
+			NotebookName: name,
+			Role:         string(invitation.Role),
+			InvitedBy:    invitation.InvitedBy,
+			InviterName:  inviterNames[invitation.InvitedBy],
+			CreatedAt:    invitation.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (c *notebookMemberService) Accept(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.NotebookMemberResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	invitation, user, err := c.findInvitation(ctx, uow, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.UserId = &user.Id
+	invitation.Status = entity.NotebookMemberStatusAccepted
+	invitation.AcceptedAt = &now
+	invitation.UpdatedAt = now
+	if err := uow.NotebookMemberRepository().Update(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	return toNotebookMemberResponse(invitation, map[uuid.UUID]string{user.Id: user.FullName}), nil
+}
+
+func (c *notebookMemberService) Decline(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	invitation, _, err := c.findInvitation(ctx, uow, userId, id)
+	if err != nil {
+		return err
+	}
+	return uow.NotebookMemberRepository().Delete(ctx, invitation.Id)
+}
+
+// findInvitation loads a pending invitation addressed to the user's email
+func (c *notebookMemberService) findInvitation(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, id uuid.UUID) (*entity.NotebookMember, *entity.User, error) {
+	user, err := uow.UserRepository().FindOne(ctx, specification.ByID{ID: userId})
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrInvitationNotFound
+	}
+
+	invitation, err := uow.NotebookMemberRepository().FindOne(ctx,
+		specification.ByID{ID: id},
+		specification.ByMemberEmail{Email: user.Email},
+		specification.ByMemberStatus{Status: string(entity.NotebookMemberStatusPending)},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if invitation == nil {
+		return nil, nil, ErrInvitationNotFound
+	}
+	return invitation, user, nil
+}
+
+// userNames maps the accepted members' user ids to their full names
+func (c *notebookMemberService) userNames(ctx context.Context, uow unitofwork.UnitOfWork, members []*entity.NotebookMember) (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string)
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if member.UserId != nil {
+			ids = append(ids, *member.UserId)
+		}
+	}
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	users, err := uow.UserRepository().FindAll(ctx, specification.ByIDs{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		names[user.Id] = user.FullName
+	}
+	return names, nil
+}
+
+func toNotebookMemberResponse(member *entity.NotebookMember, names map[uuid.UUID]string) *dto.NotebookMemberResponse {
+	res := &dto.NotebookMemberResponse{
+		Id:         member.Id,
+		NotebookId: member.NotebookId,
+		Email:      member.Email,
+		UserId:     member.UserId,
+		Role:       string(member.Role),
+		Status:     string(member.Status),
+		InvitedBy:  member.InvitedBy,
+		AcceptedAt: member.AcceptedAt,
+		CreatedAt:  member.CreatedAt,
+	}
+	if member.UserId != nil {
+		res.FullName = names[*member.UserId]
+	}
+	return res
+}
@@ -3,24 +3,46 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"ai-notetaking-be/internal/dto"
 	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/contract"
 	"ai-notetaking-be/internal/repository/specification"
 	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/export"
+	"ai-notetaking-be/pkg/lexical"
 
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrInvalidNotebookDeleteMode is returned for an unknown delete mode
+	ErrInvalidNotebookDeleteMode = errors.New("invalid notebook delete mode")
+	// ErrNotebookNotEmpty is returned by the refuse delete mode when the notebook has content
+	ErrNotebookNotEmpty = errors.New("notebook is not empty")
+	// ErrNotebookHasNoParent is returned when notes should move to the parent of a top-level notebook
+	ErrNotebookHasNoParent = errors.New("notebook has no parent to move its notes to")
+	// ErrNotebookNotFound is returned when the notebook is not a live notebook the user can access
+	ErrNotebookNotFound = errors.New("notebook not found")
+	// ErrInvalidExportFormat is returned for an export format other than md, html or json
+	ErrInvalidExportFormat = errors.New("invalid export format")
+)
+
 type INotebookService interface {
 	GetAll(ctx context.Context, userId uuid.UUID) ([]*dto.GetAllNotebookResponse, error)
 	Create(ctx context.Context, userId uuid.UUID, req *dto.CreateNotebookRequest) (*dto.CreateNotebookResponse, error)
 	Show(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ShowNotebookResponse, error)
 	Update(ctx context.Context, userId uuid.UUID, req *dto.UpdateNotebookRequest) (*dto.UpdateNotebookResponse, error)
-	Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	Delete(ctx context.Context, userId uuid.UUID, req *dto.DeleteNotebookRequest) (*dto.DeleteNotebookResponse, error)
+	Duplicate(ctx context.Context, userId uuid.UUID, req *dto.DuplicateNotebookRequest) (*dto.DuplicateNotebookResponse, error)
 	MoveNotebook(ctx context.Context, userId uuid.UUID, req *dto.MoveNotebookRequest) (*dto.MoveNotebookResponse, error)
+	Reindex(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ReindexResponse, error)
+	// Export loads the notebook subtree (or every notebook of the user when id is nil) for writing as an archive
+	Export(ctx context.Context, userId uuid.UUID, id *uuid.UUID, format string) (*export.Archive, error)
 }
 
 type notebookService struct {
@@ -41,12 +63,21 @@ func NewNotebookService(
 func (c *notebookService) GetAll(ctx context.Context, userId uuid.UUID) ([]*dto.GetAllNotebookResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
 
-	// Fetch Notebooks
-	notebooks, err := uow.NotebookRepository().FindAll(ctx, specification.UserOwnedBy{UserID: userId})
+	// Fetch Notebooks (own and shared)
+	notebooks, err := uow.NotebookRepository().FindAll(ctx, specification.NotebookAccessibleBy{UserID: userId})
+	if err != nil {
+		return nil, err
+	}
+	roles, err := c.sharedRoles(ctx, uow, userId, notebooks)
 	if err != nil {
 		return nil, err
 	}
 
+	visible := make(map[uuid.UUID]bool, len(notebooks))
+	for _, notebook := range notebooks {
+		visible[notebook.Id] = true
+	}
+
 	ids := make([]uuid.UUID, 0)
 	result := make([]*dto.GetAllNotebookResponse, 0)
 	for _, notebook := range notebooks {
@@ -54,10 +85,19 @@ func (c *notebookService) GetAll(ctx context.Context, userId uuid.UUID) ([]*dto.
 			Id:        notebook.Id,
 			Name:      notebook.Name,
 			ParentId:  notebook.ParentId,
+			Role:      string(entity.NotebookRoleOwner),
 			CreatedAt: notebook.CreatedAt,
 			UpdatedAt: notebook.UpdatedAt,
 			Notes:     make([]*dto.GetAllNotebookResponseNote, 0),
 		}
+		if notebook.UserId != userId {
+			res.Role = string(roles[notebook.Id])
+			res.Shared = true
+		}
+		// The top of a shared subtree shows as a root: its parent is not shared with the user
+		if res.ParentId != nil && !visible[*res.ParentId] {
+			res.ParentId = nil
+		}
 
 		// Prepend to result (Legacy behavior reversed order?)
 		// Legacy: result = append([]*dto...{&res}, result...) -> Prepend.
@@ -73,10 +113,10 @@ func (c *notebookService) GetAll(ctx context.Context, userId uuid.UUID) ([]*dto.
 	}
 
 	// Fetch Notes
-	// Filter by NotebookIDs AND UserId (redundant but safe)
+	// Filter by NotebookIDs AND access (redundant but safe)
 	notes, err := uow.NoteRepository().FindAll(ctx,
 		specification.ByNotebookIDs{NotebookIDs: ids},
-		specification.UserOwnedBy{UserID: userId},
+		specification.NoteAccessibleBy{UserID: userId},
 	)
 	if err != nil {
 		return nil, err
@@ -100,6 +140,46 @@ func (c *notebookService) GetAll(ctx context.Context, userId uuid.UUID) ([]*dto.
 	return result, nil
 }
 
+// sharedRoles resolves the user's role on every shared notebook of the list. A notebook inherits the
+// memberships of its ancestors, which are in the list too up to the notebook the membership is on.
+func (c *notebookService) sharedRoles(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, notebooks []*entity.Notebook) (map[uuid.UUID]entity.NotebookRole, error) {
+	roles := make(map[uuid.UUID]entity.NotebookRole)
+
+	members, err := uow.NotebookMemberRepository().FindAll(ctx,
+		specification.ByMemberUser{UserID: userId},
+		specification.ByMemberStatus{Status: string(entity.NotebookMemberStatusAccepted)},
+	)
+	if err != nil || len(members) == 0 {
+		return roles, err
+	}
+	direct := make(map[uuid.UUID]entity.NotebookRole, len(members))
+	for _, member := range members {
+		if !direct[member.NotebookId].Allows(member.Role) {
+			direct[member.NotebookId] = member.Role
+		}
+	}
+
+	parentOf := make(map[uuid.UUID]*uuid.UUID, len(notebooks))
+	for _, notebook := range notebooks {
+		parentOf[notebook.Id] = notebook.ParentId
+	}
+	for _, notebook := range notebooks {
+		if notebook.UserId == userId {
+			continue
+		}
+		var role entity.NotebookRole
+		seen := make(map[uuid.UUID]bool)
+		for id := &notebook.Id; id != nil && !seen[*id]; id = parentOf[*id] {
+			seen[*id] = true
+			if r, ok := direct[*id]; ok && !role.Allows(r) {
+				role = r
+			}
+		}
+		roles[notebook.Id] = role
+	}
+	return roles, nil
+}
+
 func (c *notebookService) Create(ctx context.Context, userId uuid.UUID, req *dto.CreateNotebookRequest) (*dto.CreateNotebookResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
 	notebook := entity.Notebook{
@@ -110,6 +190,18 @@ func (c *notebookService) Create(ctx context.Context, userId uuid.UUID, req *dto
 		CreatedAt: time.Now(),
 	}
 
+	if req.ParentId != nil {
+		// Members need the editor role; the notebook belongs to the parent's owner
+		parent, _, err := findNotebookWithRole(ctx, uow, userId, *req.ParentId, entity.NotebookRoleEditor)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, ErrNotebookNotFound
+		}
+		notebook.UserId = parent.UserId
+	}
+
 	err := uow.NotebookRepository().Create(ctx, &notebook)
 	if err != nil {
 		return nil, err
@@ -122,11 +214,8 @@ func (c *notebookService) Create(ctx context.Context, userId uuid.UUID, req *dto
 
 func (c *notebookService) Show(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ShowNotebookResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
-	// Check strictly by ID and UserID
-	notebook, err := uow.NotebookRepository().FindOne(ctx,
-		specification.ByID{ID: id},
-		specification.UserOwnedBy{UserID: userId},
-	)
+	// Check strictly by ID and access
+	notebook, role, err := findNotebookWithRole(ctx, uow, userId, id, entity.NotebookRoleViewer)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +228,9 @@ func (c *notebookService) Show(ctx context.Context, userId uuid.UUID, id uuid.UU
 		Id:        notebook.Id,
 		Name:      notebook.Name,
 		ParentId:  notebook.ParentId,
+		Role:      string(role),
+		Shared:    notebook.UserId != userId,
+		Version:   notebook.Version,
 		CreatedAt: notebook.CreatedAt,
 		UpdatedAt: notebook.UpdatedAt,
 	}
@@ -149,131 +241,422 @@ func (c *notebookService) Show(ctx context.Context, userId uuid.UUID, id uuid.UU
 func (c *notebookService) Update(ctx context.Context, userId uuid.UUID, req *dto.UpdateNotebookRequest) (*dto.UpdateNotebookResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
 
-	// Fetch first to check ownership
-	notebook, err := uow.NotebookRepository().FindOne(ctx,
-		specification.ByID{ID: req.Id},
-		specification.UserOwnedBy{UserID: userId},
-	)
+	// Fetch first to check access
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, req.Id, entity.NotebookRoleEditor)
 	if err != nil {
 		return nil, err
 	}
 	if notebook == nil {
 		return nil, nil
 	} // Not found
+	if notebook.Version != req.Version {
+		return nil, notebookVersionConflict(notebook, req)
+	}
 
 	now := time.Now()
 	notebook.Name = req.Name
 	notebook.UpdatedAt = &now
 
+	if err := uow.Begin(ctx); err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
 	if err := uow.NotebookRepository().Update(ctx, notebook); err != nil {
+		if errors.Is(err, contract.ErrStaleVersion) {
+			// Another save landed since the version check
+			if current, _ := uow.NotebookRepository().FindOne(ctx, specification.ByID{ID: req.Id}); current != nil {
+				return nil, notebookVersionConflict(current, req)
+			}
+		}
 		return nil, err
 	}
 
-	// Fetch Notes specifically for embedding update
-	// "GetByNotebookIds" logic
-	notes, err := uow.NoteRepository().FindAll(ctx,
-		specification.ByNotebookID{NotebookID: notebook.Id},
-		specification.UserOwnedBy{UserID: userId},
-	)
+	// Notes are embedded with their notebook name
+	noteIds, err := c.findNoteIds(ctx, uow, []uuid.UUID{notebook.Id}, notebook.UserId)
 	if err != nil {
 		return nil, err
 	}
+	if err := uow.EmbeddingJobRepository().EnqueueMany(ctx, noteIds, false); err != nil {
+		return nil, err
+	}
 
-	for _, note := range notes {
-		msg := dto.PublishEmbedNoteMessage{
-			NoteId: note.Id,
-		}
-		msgJson, _ := json.Marshal(msg)
-		// Publisher logic
-		if err := c.publisherService.Publish(ctx, msgJson); err != nil {
-			return nil, err
-		}
+	if err := uow.Commit(); err != nil {
+		return nil, err
 	}
+	c.publisherService.Notify(noteIds...)
 
 	return &dto.UpdateNotebookResponse{
-		Id: notebook.Id,
+		Id:      notebook.Id,
+		Version: notebook.Version,
 	}, nil
 }
 
-func (c *notebookService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+// notebookVersionConflict describes how the stored notebook differs from the stale copy in req
+func notebookVersionConflict(notebook *entity.Notebook, req *dto.UpdateNotebookRequest) *dto.VersionConflictError {
+	var markdown string
+	if req.Name != notebook.Name {
+		markdown = fmt.Sprintf("**Name:** ~~%s~~ → %s\n", lexical.EscapeMarkdown(req.Name), lexical.EscapeMarkdown(notebook.Name))
+	}
+	return &dto.VersionConflictError{
+		Version:   notebook.Version,
+		UpdatedAt: notebook.UpdatedAt,
+		Markdown:  markdown,
+	}
+}
+
+// Reindex forces re-embedding of every note in the notebook and its child notebooks
+func (c *notebookService) Reindex(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*dto.ReindexResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
 
-	// Check ownership
-	notebook, err := uow.NotebookRepository().FindOne(ctx,
-		specification.ByID{ID: id},
-		specification.UserOwnedBy{UserID: userId},
-	)
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, id, entity.NotebookRoleEditor)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if notebook == nil {
-		return nil
-	} // Not found?
+		return nil, nil
+	}
+
+	notebookIds, err := uow.NotebookRepository().FindSubtreeIds(ctx, notebook.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	noteIds, err := c.findNoteIds(ctx, uow, notebookIds, notebook.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := uow.EmbeddingJobRepository().EnqueueMany(ctx, noteIds, true); err != nil {
+		return nil, err
+	}
+	c.publisherService.Notify(noteIds...)
+
+	return &dto.ReindexResponse{Queued: len(noteIds)}, nil
+}
+
+// findNoteIds returns the ids of the owner's live notes in the notebooks
+func (c *notebookService) findNoteIds(ctx context.Context, uow unitofwork.UnitOfWork, notebookIds []uuid.UUID, ownerId uuid.UUID) ([]uuid.UUID, error) {
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByNotebookIDs{NotebookIDs: notebookIds},
+		specification.UserOwnedBy{UserID: ownerId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	noteIds := make([]uuid.UUID, len(notes))
+	for i, note := range notes {
+		noteIds[i] = note.Id
+	}
+	return noteIds, nil
+}
+
+// Delete moves the notebook to the trash in a single transaction. The mode decides what happens to
+// its contents: cascade trashes the whole subtree, move_to_parent first hands child notebooks and
+// notes to the parent notebook, refuse fails unless the notebook is empty.
+func (c *notebookService) Delete(ctx context.Context, userId uuid.UUID, req *dto.DeleteNotebookRequest) (*dto.DeleteNotebookResponse, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = dto.NotebookDeleteCascade
+	}
+	if mode != dto.NotebookDeleteCascade && mode != dto.NotebookDeleteMoveToParent && mode != dto.NotebookDeleteRefuse {
+		return nil, ErrInvalidNotebookDeleteMode
+	}
+
+	uow := c.uowFactory.NewUnitOfWork(ctx)
 
 	// Transaction
 	if err := uow.Begin(ctx); err != nil {
-		return err
+		return nil, err
 	}
 	defer uow.Rollback()
 
-	// 1. Delete Notebook
-	if err := uow.NotebookRepository().Delete(ctx, id); err != nil {
-		return err
+	// Check ownership; members need the owner role, and the notebook goes to its owner's trash
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, req.Id, entity.NotebookRoleOwner)
+	if err != nil {
+		return nil, err
 	}
+	if notebook == nil {
+		return nil, nil
+	} // Not found
+	ownerId := notebook.UserId
 
-	// 2. Delete Note Embeddings by NotebookID
-	if err := uow.NoteEmbeddingRepository().DeleteByNotebookId(ctx, id); err != nil {
-		return err
+	res := &dto.DeleteNotebookResponse{
+		Id:   notebook.Id,
+		Mode: mode,
 	}
 
-	// 3. Nullify Parent ID for child notebooks?
-	// Legacy: notebookRepo.NullifyParentById(ctx, id, userId)
-	// We need logic: UPDATE notebooks SET parent_id = NULL WHERE parent_id = ? AND user_id = ?
+	// 1. Deal with direct children according to the mode
 	children, err := uow.NotebookRepository().FindAll(ctx,
-		specification.ByParentID{ParentID: &id},
-		specification.UserOwnedBy{UserID: userId},
+		specification.ByParentID{ParentID: &notebook.Id},
+		specification.UserOwnedBy{UserID: ownerId},
 	)
-	if err == nil {
+	if err != nil {
+		return nil, err
+	}
+	childNotes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByNotebookID{NotebookID: notebook.Id},
+		specification.UserOwnedBy{UserID: ownerId},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// One timestamp for the whole deletion, so the subtree shares the deleted_at of its root
+	now := time.Now()
+	movedNoteIds := make([]uuid.UUID, 0)
+	switch mode {
+	case dto.NotebookDeleteRefuse:
+		if len(children) > 0 || len(childNotes) > 0 {
+			return nil, ErrNotebookNotEmpty
+		}
+	case dto.NotebookDeleteMoveToParent:
+		// Notes cannot live outside a notebook
+		if len(childNotes) > 0 && notebook.ParentId == nil {
+			return nil, ErrNotebookHasNoParent
+		}
 		for _, child := range children {
-			child.ParentId = nil
+			child.ParentId = notebook.ParentId
+			child.UpdatedAt = &now
 			if err := uow.NotebookRepository().Update(ctx, child); err != nil {
-				return err
+				return nil, err
 			}
 		}
-	} else {
-		return err
+		for _, note := range childNotes {
+			note.NotebookId = *notebook.ParentId
+			note.UpdatedAt = &now
+			if err := uow.NoteRepository().Move(ctx, note); err != nil {
+				return nil, err
+			}
+			movedNoteIds = append(movedNoteIds, note.Id)
+		}
+		res.MovedNotebooks = len(children)
+		res.MovedNotes = len(childNotes)
+	}
+
+	// 2. Collect what is left of the subtree; child notebooks go to the trash with their parent
+	notebookIds, err := uow.NotebookRepository().FindSubtreeIds(ctx, notebook.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByNotebookIDs{NotebookIDs: notebookIds},
+		specification.UserOwnedBy{UserID: ownerId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	noteIds := make([]uuid.UUID, 0, len(notes))
+	for _, note := range notes {
+		noteIds = append(noteIds, note.Id)
+	}
+
+	// 3. Move notebooks and notes to the trash under this notebook
+	if err := uow.NotebookRepository().MoveToTrash(ctx, notebookIds, notebook.Id, now); err != nil {
+		return nil, err
+	}
+	if err := uow.NoteRepository().MoveToTrash(ctx, noteIds, notebook.Id, now); err != nil {
+		return nil, err
+	}
+
+	// 4. Drop embeddings so trashed notes stop appearing in search and chat; restore re-embeds them
+	if err := uow.NoteEmbeddingRepository().DeleteByNoteIds(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	// Moved notes are embedded with their notebook name
+	if err := uow.EmbeddingJobRepository().EnqueueMany(ctx, movedNoteIds, false); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	c.publisherService.Notify(movedNoteIds...)
+	res.TrashedNotebooks = len(notebookIds)
+	res.TrashedNotes = len(noteIds)
+
+	return res, nil
+}
+
+// Duplicate copies the notebook with all descendant notebooks and their notes, then queues the copies for embedding.
+// The copy belongs to the owner of the notebook it is placed in. A shared notebook whose parent the user
+// cannot edit is copied to the top level of the user's own notebooks.
+func (c *notebookService) Duplicate(ctx context.Context, userId uuid.UUID, req *dto.DuplicateNotebookRequest) (*dto.DuplicateNotebookResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	source, _, err := findNotebookWithRole(ctx, uow, userId, req.Id, entity.NotebookRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, nil
+	}
+
+	parentId := source.ParentId
+	ownerId := userId
+	if req.ParentId != nil {
+		// Check parent access
+		parent, _, err := findNotebookWithRole(ctx, uow, userId, *req.ParentId, entity.NotebookRoleEditor)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, nil
+		}
+		parentId = req.ParentId
+		ownerId = parent.UserId
+	} else if parentId != nil {
+		parent, _, err := findNotebookWithRole(ctx, uow, userId, *parentId, entity.NotebookRoleEditor)
+		if err != nil && !errors.Is(err, ErrNotebookAccessDenied) {
+			return nil, err
+		}
+		if parent == nil {
+			parentId = nil
+		} else {
+			ownerId = parent.UserId
+		}
 	}
 
-	// 4. Delete Notes
-	// Legacy: noteRepo.DeleteByNotebookId(ctx, id, userId)
-	// Fetch notes in notebook and delete them?
-	// Finding them first allows using Delete(id).
-	// But Delete(id) implies 1 by 1.
-	// Can we use DeleteByNotebookID? NoteRepo doesn't have it in Contract.
-	// Generic Delete doesn't support "Delete WHERE notebook_id=X".
-	// Efficient way: Fetch IDs, Delete Loop.
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = source.Name + " (Copy)"
+	}
 
-	notes, err := uow.NoteRepository().FindAll(ctx, specification.ByNotebookID{NotebookID: id})
+	if err := uow.Begin(ctx); err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	subtreeIds, err := uow.NotebookRepository().FindSubtreeIds(ctx, source.Id)
+	if err != nil {
+		return nil, err
+	}
+	notebooks, err := uow.NotebookRepository().FindAll(ctx,
+		specification.ByIDs{IDs: subtreeIds},
+		specification.UserOwnedBy{UserID: source.UserId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByNotebookIDs{NotebookIDs: subtreeIds},
+		specification.UserOwnedBy{UserID: source.UserId},
+	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// Copy notebooks parents first, so every copy can point at its copied parent
+	childrenOf := make(map[uuid.UUID][]*entity.Notebook)
+	for _, notebook := range notebooks {
+		if notebook.ParentId != nil && notebook.Id != source.Id {
+			childrenOf[*notebook.ParentId] = append(childrenOf[*notebook.ParentId], notebook)
+		}
+	}
+
+	now := time.Now()
+	copies := make(map[uuid.UUID]uuid.UUID, len(notebooks))
+	queue := []*entity.Notebook{source}
+	for len(queue) > 0 {
+		notebook := queue[0]
+		queue = queue[1:]
+
+		copied := entity.Notebook{
+			Id:        uuid.New(),
+			Name:      notebook.Name,
+			ParentId:  notebook.ParentId,
+			UserId:    ownerId,
+			CreatedAt: now,
+		}
+		if notebook.Id == source.Id {
+			copied.Name = name
+			copied.ParentId = parentId
+		} else {
+			copiedParent := copies[*notebook.ParentId]
+			copied.ParentId = &copiedParent
+		}
+		if err := uow.NotebookRepository().Create(ctx, &copied); err != nil {
+			return nil, err
+		}
+		copies[notebook.Id] = copied.Id
+		queue = append(queue, childrenOf[notebook.Id]...)
+	}
+
+	sourceNoteIds := make([]uuid.UUID, len(notes))
+	for i, note := range notes {
+		sourceNoteIds[i] = note.Id
+	}
+	// Tags belong to a user, so they are only kept when the copy stays with the same owner
+	tagsByNote := make(map[uuid.UUID][]*entity.Tag)
+	if ownerId == source.UserId {
+		tagsByNote, err = uow.TagRepository().FindByNoteIds(ctx, sourceNoteIds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	noteIds := make([]uuid.UUID, 0, len(notes))
 	for _, note := range notes {
-		if err := uow.NoteRepository().Delete(ctx, note.Id); err != nil {
-			return err
+		copied := entity.Note{
+			Id:         uuid.New(),
+			Title:      note.Title,
+			Content:    note.Content,
+			NotebookId: copies[note.NotebookId],
+			UserId:     ownerId,
+			CreatedAt:  now,
+		}
+		if err := uow.NoteRepository().Create(ctx, &copied); err != nil {
+			return nil, err
+		}
+		// The copy starts its own history
+		if err := uow.NoteRevisionRepository().Create(ctx, &entity.NoteRevision{
+			Id:        uuid.New(),
+			NoteId:    copied.Id,
+			UserId:    userId,
+			Title:     copied.Title,
+			Content:   copied.Content,
+			Source:    entity.NoteRevisionSourceCreate,
+			CreatedAt: now,
+		}); err != nil {
+			return nil, err
+		}
+		if err := syncNoteLinks(ctx, uow, &copied); err != nil {
+			return nil, err
+		}
+		// The copy keeps the source's tags
+		if tags := tagsByNote[note.Id]; len(tags) > 0 {
+			tagIds := make([]uuid.UUID, len(tags))
+			for i, tag := range tags {
+				tagIds[i] = tag.Id
+			}
+			if _, err := uow.TagRepository().Attach(ctx, tagIds, []uuid.UUID{copied.Id}); err != nil {
+				return nil, err
+			}
 		}
+		noteIds = append(noteIds, copied.Id)
+	}
+	if err := uow.EmbeddingJobRepository().EnqueueMany(ctx, noteIds, false); err != nil {
+		return nil, err
 	}
 
-	return uow.Commit()
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	c.publisherService.Notify(noteIds...)
+
+	return &dto.DuplicateNotebookResponse{
+		Id:        copies[source.Id],
+		Notebooks: len(copies),
+		Notes:     len(noteIds),
+	}, nil
 }
 
+// MoveNotebook needs the owner role on the notebook and the editor role on the new parent. Notebooks
+// never change owner by moving, and only their owner can move them to the top level.
 func (c *notebookService) MoveNotebook(ctx context.Context, userId uuid.UUID, req *dto.MoveNotebookRequest) (*dto.MoveNotebookResponse, error) {
 	uow := c.uowFactory.NewUnitOfWork(ctx)
 
-	notebook, err := uow.NotebookRepository().FindOne(ctx,
-		specification.ByID{ID: req.Id},
-		specification.UserOwnedBy{UserID: userId},
-	)
+	notebook, _, err := findNotebookWithRole(ctx, uow, userId, req.Id, entity.NotebookRoleOwner)
 	if err != nil {
 		return nil, err
 	}
@@ -282,17 +665,19 @@ func (c *notebookService) MoveNotebook(ctx context.Context, userId uuid.UUID, re
 	}
 
 	if req.ParentId != nil {
-		// Check parent ownership
-		parent, err := uow.NotebookRepository().FindOne(ctx,
-			specification.ByID{ID: *req.ParentId},
-			specification.UserOwnedBy{UserID: userId},
-		)
+		// Check parent access
+		parent, _, err := findNotebookWithRole(ctx, uow, userId, *req.ParentId, entity.NotebookRoleEditor)
 		if err != nil {
 			return nil, err
 		}
 		if parent == nil {
 			return nil, nil
 		}
+		if parent.UserId != notebook.UserId {
+			return nil, ErrNotebookAccessDenied
+		}
+	} else if notebook.UserId != userId {
+		return nil, ErrNotebookAccessDenied
 	}
 
 	notebook.ParentId = req.ParentId
@@ -305,3 +690,89 @@ func (c *notebookService) MoveNotebook(ctx context.Context, userId uuid.UUID, re
 		Id: req.Id,
 	}, nil
 }
+
+func (c *notebookService) Export(ctx context.Context, userId uuid.UUID, id *uuid.UUID, format string) (*export.Archive, error) {
+	if !export.ValidFormat(format) {
+		return nil, ErrInvalidExportFormat
+	}
+
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	// The whole account covers the user's own notebooks; one notebook may also be a shared one
+	ownerId := userId
+	var specs []specification.Specification
+	if id != nil {
+		source, _, err := findNotebookWithRole(ctx, uow, userId, *id, entity.NotebookRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil {
+			return nil, ErrNotebookNotFound
+		}
+		ownerId = source.UserId
+		subtreeIds, err := uow.NotebookRepository().FindSubtreeIds(ctx, source.Id)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, specification.ByIDs{IDs: subtreeIds})
+	}
+
+	notebooks, err := uow.NotebookRepository().FindAll(ctx, append(specs, specification.UserOwnedBy{UserID: ownerId})...)
+	if err != nil {
+		return nil, err
+	}
+	notebookIds := make([]uuid.UUID, len(notebooks))
+	archive := &export.Archive{
+		Format:     format,
+		Notebooks:  make([]export.Notebook, len(notebooks)),
+		ExportedAt: time.Now(),
+	}
+	for i, notebook := range notebooks {
+		notebookIds[i] = notebook.Id
+		archive.Notebooks[i] = export.Notebook{
+			Id:        notebook.Id,
+			Name:      notebook.Name,
+			ParentId:  notebook.ParentId,
+			CreatedAt: notebook.CreatedAt,
+			UpdatedAt: notebook.UpdatedAt,
+		}
+	}
+	if len(notebookIds) == 0 {
+		return archive, nil
+	}
+
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByNotebookIDs{NotebookIDs: notebookIds},
+		specification.UserOwnedBy{UserID: ownerId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	noteIds := make([]uuid.UUID, len(notes))
+	for i, note := range notes {
+		noteIds[i] = note.Id
+	}
+	tagsByNote, err := uow.TagRepository().FindByNoteIds(ctx, noteIds)
+	if err != nil {
+		return nil, err
+	}
+
+	archive.Notes = make([]export.Note, len(notes))
+	for i, note := range notes {
+		tags := make([]string, 0, len(tagsByNote[note.Id]))
+		for _, tag := range tagsByNote[note.Id] {
+			tags = append(tags, tag.Name)
+		}
+		archive.Notes[i] = export.Note{
+			Id:         note.Id,
+			Title:      note.Title,
+			Content:    note.Content,
+			NotebookId: note.NotebookId,
+			Tags:       tags,
+			CreatedAt:  note.CreatedAt,
+			UpdatedAt:  note.UpdatedAt,
+		}
+	}
+
+	return archive, nil
+}
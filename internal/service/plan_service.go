@@ -22,8 +22,12 @@ type PlanService interface {
 	GetUserUsageStatus(ctx context.Context, userId uuid.UUID) (*dto.UsageStatusResponse, error)
 	CheckCanCreateNotebook(ctx context.Context, userId uuid.UUID) error
 	CheckCanCreateNote(ctx context.Context, userId uuid.UUID, notebookId uuid.UUID) error
+	CheckCanStoreAttachment(ctx context.Context, userId uuid.UUID, size int64) error
+	GetUserPlanLimits(ctx context.Context, userId uuid.UUID) (*dto.PlanLimitsDTO, error)
 }
 
+const bytesPerMB = 1 << 20
+
 type planService struct {
 	uowFactory unitofwork.RepositoryFactory
 }
@@ -71,6 +75,7 @@ func (s *planService) GetAllActivePlansWithFeatures(ctx context.Context) ([]*dto
 			Limits: dto.PlanLimitsDTO{
 				MaxNotebooks:        plan.MaxNotebooks,
 				MaxNotesPerNotebook: plan.MaxNotesPerNotebook,
+				MaxStorageMB:        plan.MaxStorageMB,
 				AiChatDaily:         plan.AiChatDailyLimit,
 				SemanticSearchDaily: plan.SemanticSearchDailyLimit,
 			},
@@ -109,6 +114,13 @@ func (s *planService) GetUserUsageStatus(ctx context.Context, userId uuid.UUID)
 		return nil, err
 	}
 
+	// Attachment storage, charged to the owner of the notes
+	storageBytes, err := uow.NoteAttachmentRepository().SumSize(ctx, specification.UserOwnedBy{UserID: userId})
+	if err != nil {
+		return nil, err
+	}
+	storageMB := int((storageBytes + bytesPerMB - 1) / bytesPerMB)
+
 	// Check and reset daily usage if needed
 	if err := s.checkAndResetDailyUsage(ctx, uow, user); err != nil {
 		return nil, err
@@ -135,6 +147,11 @@ func (s *planService) GetUserUsageStatus(ctx context.Context, userId uuid.UUID)
 				Limit:  plan.MaxNotesPerNotebook,
 				CanUse: plan.MaxNotesPerNotebook < 0 || int(noteCount) < plan.MaxNotesPerNotebook,
 			},
+			Attachments: dto.UsageLimit{
+				Used:   storageMB,
+				Limit:  plan.MaxStorageMB,
+				CanUse: s.canUseLimit(int(storageBytes/bytesPerMB), plan.MaxStorageMB),
+			},
 		},
 		Daily: dto.DailyLimits{
 			AiChat: dto.UsageLimit{
@@ -242,6 +259,54 @@ func (s *planService) CheckCanCreateNote(ctx context.Context, userId uuid.UUID,
 	return nil
 }
 
+// CheckCanStoreAttachment checks if size more bytes of attachments fit in the user's storage quota
+func (s *planService) CheckCanStoreAttachment(ctx context.Context, userId uuid.UUID, size int64) error {
+	uow := s.uowFactory.NewUnitOfWork(ctx)
+
+	plan, err := s.getUserPlan(ctx, uow, userId)
+	if err != nil {
+		return err
+	}
+
+	// -1 means unlimited
+	if plan.MaxStorageMB < 0 {
+		return nil
+	}
+
+	used, err := uow.NoteAttachmentRepository().SumSize(ctx, specification.UserOwnedBy{UserID: userId})
+	if err != nil {
+		return err
+	}
+
+	if used+size > int64(plan.MaxStorageMB)*bytesPerMB {
+		return &dto.LimitExceededError{
+			Limit: plan.MaxStorageMB,
+			Used:  int((used + bytesPerMB - 1) / bytesPerMB),
+		}
+	}
+
+	return nil
+}
+
+// GetUserPlanLimits returns the limits of the user's current plan (-1 means unlimited),
+// for callers that create many items at once and track usage themselves
+func (s *planService) GetUserPlanLimits(ctx context.Context, userId uuid.UUID) (*dto.PlanLimitsDTO, error) {
+	uow := s.uowFactory.NewUnitOfWork(ctx)
+
+	plan, err := s.getUserPlan(ctx, uow, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PlanLimitsDTO{
+		MaxNotebooks:        plan.MaxNotebooks,
+		MaxNotesPerNotebook: plan.MaxNotesPerNotebook,
+		MaxStorageMB:        plan.MaxStorageMB,
+		AiChatDaily:         plan.AiChatDailyLimit,
+		SemanticSearchDaily: plan.SemanticSearchDailyLimit,
+	}, nil
+}
+
 // getUserPlan gets the user's current plan or returns default free plan
 func (s *planService) getUserPlan(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID) (*entity.SubscriptionPlan, error) {
 	// Get all subscriptions for the user, ordered by creation (newest first)
@@ -290,6 +355,7 @@ func (s *planService) getUserPlan(ctx context.Context, uow unitofwork.UnitOfWork
 		Slug:                     "free",
 		MaxNotebooks:             3,
 		MaxNotesPerNotebook:      10,
+		MaxStorageMB:             100,
 		AiChatDailyLimit:         0,
 		SemanticSearchDailyLimit: 0,
 		AiChatEnabled:            false,
@@ -1,33 +1,44 @@
 package service
 
 import (
-	"context"
+	"encoding/json"
+	"log"
+
+	"ai-notetaking-be/internal/dto"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/google/uuid"
 )
 
 type IPublisherService interface {
-	Publish(ctx context.Context, payload []byte) error
+	// Notify wakes the worker for jobs enqueued through the EmbeddingJobRepository of a unit of work,
+	// so they commit together with the notes; call it once that unit of work is committed
+	Notify(noteIds ...uuid.UUID)
 }
 
+// publisherService wakes the embedding worker.
+// The embedding_jobs table is the source of truth (survives restarts), written by the services
+// in their own transactions; the in-process GoChannel is only a doorbell to wake the worker immediately.
 type publisherService struct {
 	pubSub *gochannel.GoChannel
 
 	topicName string
 }
 
-func (ps *publisherService) Publish(ctx context.Context, payload []byte) error {
-	err := ps.pubSub.Publish(
+func (ps *publisherService) Notify(noteIds ...uuid.UUID) {
+	if len(noteIds) == 0 {
+		return
+	}
+
+	payload, _ := json.Marshal(dto.PublishEmbedNoteMessage{NoteId: noteIds[0]})
+	if err := ps.pubSub.Publish(
 		ps.topicName,
 		message.NewMessage(watermill.NewUUID(), payload),
-	)
-	if err != nil {
-		return err
+	); err != nil {
+		log.Printf("[WARN] Failed to signal embedding worker for %d notes: %v", len(noteIds), err)
 	}
-
-	return nil
 }
 
 func NewPublisherService(topicName string, pubSub *gochannel.GoChannel) IPublisherService {
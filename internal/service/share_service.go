@@ -0,0 +1,430 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/lexical"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrShareNotFound means the share link does not exist, was revoked, expired, or its
+// note / notebook is gone. Public callers cannot tell these cases apart.
+var ErrShareNotFound = errors.New("share link not found")
+
+// ErrShareTargetInvalid means a share request did not name exactly one note or notebook
+var ErrShareTargetInvalid = errors.New("exactly one of note_id and notebook_id is required")
+
+// ErrShareTargetNotFound means the note or notebook to share is not a live one of the user
+var ErrShareTargetNotFound = errors.New("note or notebook not found")
+
+// ErrShareExpiryInPast means the requested expiry time has already passed
+var ErrShareExpiryInPast = errors.New("expires_at must be in the future")
+
+// ErrSharePasswordRequired means the link is password protected and no password was given
+var ErrSharePasswordRequired = errors.New("password required")
+
+// ErrSharePasswordInvalid means the given password does not match the link's
+var ErrSharePasswordInvalid = errors.New("invalid password")
+
+const shareTokenBytes = 24 // 32 URL-safe characters
+
+type IShareService interface {
+	Create(ctx context.Context, userId uuid.UUID, req *dto.CreateShareRequest) (*dto.ShareResponse, error)
+	// List returns the user's active (not revoked, not expired) share links, newest first
+	List(ctx context.Context, userId uuid.UUID) ([]*dto.ShareResponse, error)
+	Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	// View opens a share link and counts the view
+	View(ctx context.Context, token string, password string) (*dto.PublicShareResponse, error)
+	// ViewNote returns one note of a share link: the shared note, or a note of the shared notebook tree
+	ViewNote(ctx context.Context, token string, password string, noteId uuid.UUID) (*dto.PublicNoteResponse, error)
+}
+
+type shareService struct {
+	uowFactory unitofwork.RepositoryFactory
+	clientURL  string // Frontend base URL the share URLs point to
+}
+
+func NewShareService(uowFactory unitofwork.RepositoryFactory, clientURL string) IShareService {
+	return &shareService{
+		uowFactory: uowFactory,
+		clientURL:  strings.TrimRight(clientURL, "/"),
+	}
+}
+
+func (c *shareService) Create(ctx context.Context, userId uuid.UUID, req *dto.CreateShareRequest) (*dto.ShareResponse, error) {
+	if (req.NoteId == nil) == (req.NotebookId == nil) {
+		return nil, ErrShareTargetInvalid
+	}
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, ErrShareExpiryInPast
+	}
+
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	var title string
+	if req.NoteId != nil {
+		note, err := uow.NoteRepository().FindOne(ctx,
+			specification.ByID{ID: *req.NoteId},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return nil, ErrShareTargetNotFound
+		}
+		title = note.Title
+	} else {
+		notebook, err := uow.NotebookRepository().FindOne(ctx,
+			specification.ByID{ID: *req.NotebookId},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if notebook == nil {
+			return nil, ErrShareTargetNotFound
+		}
+		title = notebook.Name
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link := entity.ShareLink{
+		Id:         uuid.New(),
+		UserId:     userId,
+		Token:      token,
+		NoteId:     req.NoteId,
+		NotebookId: req.NotebookId,
+		ExpiresAt:  req.ExpiresAt,
+		CreatedAt:  now,
+	}
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		hashStr := string(hash)
+		link.PasswordHash = &hashStr
+	}
+
+	if err := uow.ShareLinkRepository().Create(ctx, &link); err != nil {
+		return nil, err
+	}
+
+	return c.toShareResponse(&link, title), nil
+}
+
+func (c *shareService) List(ctx context.Context, userId uuid.UUID) ([]*dto.ShareResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	links, err := uow.ShareLinkRepository().FindAll(ctx,
+		specification.UserOwnedBy{UserID: userId},
+		specification.ShareLinkActive{At: time.Now()},
+		specification.OrderBy{Field: "created_at", Desc: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var noteIds, notebookIds []uuid.UUID
+	for _, link := range links {
+		if link.NoteId != nil {
+			noteIds = append(noteIds, *link.NoteId)
+		} else if link.NotebookId != nil {
+			notebookIds = append(notebookIds, *link.NotebookId)
+		}
+	}
+
+	// Links to trashed notes and notebooks are left out until they are restored
+	titles := make(map[uuid.UUID]string, len(links))
+	if len(noteIds) > 0 {
+		notes, err := uow.NoteRepository().FindAll(ctx,
+			specification.ByIDs{IDs: noteIds},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			titles[note.Id] = note.Title
+		}
+	}
+	if len(notebookIds) > 0 {
+		notebooks, err := uow.NotebookRepository().FindAll(ctx,
+			specification.ByIDs{IDs: notebookIds},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, notebook := range notebooks {
+			titles[notebook.Id] = notebook.Name
+		}
+	}
+
+	res := make([]*dto.ShareResponse, 0, len(links))
+	for _, link := range links {
+		title, ok := titles[shareTargetId(link)]
+		if !ok {
+			continue
+		}
+		res = append(res, c.toShareResponse(link, title))
+	}
+	return res, nil
+}
+
+func (c *shareService) Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	link, err := uow.ShareLinkRepository().FindOne(ctx,
+		specification.ByID{ID: id},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return ErrShareNotFound
+	}
+	if link.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	link.RevokedAt = &now
+	return uow.ShareLinkRepository().Update(ctx, link)
+}
+
+func (c *shareService) View(ctx context.Context, token string, password string) (*dto.PublicShareResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	link, err := c.openLink(ctx, uow, token, password)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.PublicShareResponse{}
+	if link.NoteId != nil {
+		note, err := uow.NoteRepository().FindOne(ctx,
+			specification.ByID{ID: *link.NoteId},
+			specification.UserOwnedBy{UserID: link.UserId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return nil, ErrShareNotFound
+		}
+		res.ResourceType = "note"
+		res.Note = toPublicNoteResponse(note)
+	} else {
+		tree, err := c.notebookTree(ctx, uow, link)
+		if err != nil {
+			return nil, err
+		}
+		res.ResourceType = "notebook"
+		res.Notebook = tree
+	}
+
+	if err := uow.ShareLinkRepository().RecordView(ctx, link.Id, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *shareService) ViewNote(ctx context.Context, token string, password string, noteId uuid.UUID) (*dto.PublicNoteResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	link, err := c.openLink(ctx, uow, token, password)
+	if err != nil {
+		return nil, err
+	}
+	if link.NoteId != nil && *link.NoteId != noteId {
+		return nil, ErrShareNotFound
+	}
+
+	note, err := uow.NoteRepository().FindOne(ctx,
+		specification.ByID{ID: noteId},
+		specification.UserOwnedBy{UserID: link.UserId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, ErrShareNotFound
+	}
+
+	if link.NotebookId != nil {
+		subtreeIds, err := uow.NotebookRepository().FindSubtreeIds(ctx, *link.NotebookId)
+		if err != nil {
+			return nil, err
+		}
+		inTree := false
+		for _, id := range subtreeIds {
+			if id == note.NotebookId {
+				inTree = true
+				break
+			}
+		}
+		if !inTree {
+			return nil, ErrShareNotFound
+		}
+	}
+
+	return toPublicNoteResponse(note), nil
+}
+
+// openLink finds an active link by token and checks its password
+func (c *shareService) openLink(ctx context.Context, uow unitofwork.UnitOfWork, token string, password string) (*entity.ShareLink, error) {
+	if token == "" {
+		return nil, ErrShareNotFound
+	}
+	link, err := uow.ShareLinkRepository().FindOne(ctx, specification.ByShareToken{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || !link.IsActive(time.Now()) {
+		return nil, ErrShareNotFound
+	}
+
+	if link.PasswordHash != nil {
+		if password == "" {
+			return nil, ErrSharePasswordRequired
+		}
+		if bcrypt.CompareHashAndPassword([]byte(*link.PasswordHash), []byte(password)) != nil {
+			return nil, ErrSharePasswordInvalid
+		}
+	}
+	return link, nil
+}
+
+// notebookTree loads the shared notebook with its live sub-notebooks and note titles
+func (c *shareService) notebookTree(ctx context.Context, uow unitofwork.UnitOfWork, link *entity.ShareLink) (*dto.PublicNotebookResponse, error) {
+	root, err := uow.NotebookRepository().FindOne(ctx,
+		specification.ByID{ID: *link.NotebookId},
+		specification.UserOwnedBy{UserID: link.UserId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, ErrShareNotFound
+	}
+
+	subtreeIds, err := uow.NotebookRepository().FindSubtreeIds(ctx, root.Id)
+	if err != nil {
+		return nil, err
+	}
+	notebooks, err := uow.NotebookRepository().FindAll(ctx,
+		specification.ByIDs{IDs: subtreeIds},
+		specification.UserOwnedBy{UserID: link.UserId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByNotebookIDs{NotebookIDs: subtreeIds},
+		specification.UserOwnedBy{UserID: link.UserId},
+		specification.OrderBy{Field: "title"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uuid.UUID]*dto.PublicNotebookResponse, len(notebooks))
+	for _, notebook := range notebooks {
+		nodes[notebook.Id] = &dto.PublicNotebookResponse{
+			Id:       notebook.Id,
+			Name:     notebook.Name,
+			Notes:    []dto.PublicNoteSummary{},
+			Children: []*dto.PublicNotebookResponse{},
+		}
+	}
+	sort.SliceStable(notebooks, func(i, j int) bool { return notebooks[i].Name < notebooks[j].Name })
+	for _, notebook := range notebooks {
+		if notebook.Id == root.Id || notebook.ParentId == nil {
+			continue
+		}
+		if parent, ok := nodes[*notebook.ParentId]; ok {
+			parent.Children = append(parent.Children, nodes[notebook.Id])
+		}
+	}
+	for _, note := range notes {
+		if node, ok := nodes[note.NotebookId]; ok {
+			node.Notes = append(node.Notes, dto.PublicNoteSummary{
+				Id:        note.Id,
+				Title:     note.Title,
+				UpdatedAt: note.UpdatedAt,
+			})
+		}
+	}
+
+	return nodes[root.Id], nil
+}
+
+func (c *shareService) toShareResponse(link *entity.ShareLink, title string) *dto.ShareResponse {
+	resourceType := "notebook"
+	if link.NoteId != nil {
+		resourceType = "note"
+	}
+	return &dto.ShareResponse{
+		Id:           link.Id,
+		Token:        link.Token,
+		URL:          c.clientURL + "/share/" + link.Token,
+		ResourceType: resourceType,
+		NoteId:       link.NoteId,
+		NotebookId:   link.NotebookId,
+		Title:        title,
+		HasPassword:  link.PasswordHash != nil,
+		ExpiresAt:    link.ExpiresAt,
+		ViewCount:    link.ViewCount,
+		LastViewedAt: link.LastViewedAt,
+		CreatedAt:    link.CreatedAt,
+	}
+}
+
+func toPublicNoteResponse(note *entity.Note) *dto.PublicNoteResponse {
+	return &dto.PublicNoteResponse{
+		Id:        note.Id,
+		Title:     note.Title,
+		Html:      lexical.RenderHTML(note.Content),
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+	}
+}
+
+func shareTargetId(link *entity.ShareLink) uuid.UUID {
+	if link.NoteId != nil {
+		return *link.NoteId
+	}
+	if link.NotebookId != nil {
+		return *link.NotebookId
+	}
+	return uuid.Nil
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
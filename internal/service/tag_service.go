@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+
+	"github.com/google/uuid"
+)
+
+// ErrTagNotFound means a tag id does not belong to the user
+var ErrTagNotFound = errors.New("tag not found")
+
+// ErrTagNameTaken means the user already has a tag with that name (case-insensitive)
+var ErrTagNameTaken = errors.New("tag name already exists")
+
+// ErrTagNameInvalid means the tag name is empty once trimmed
+var ErrTagNameInvalid = errors.New("tag name is empty")
+
+// ErrTagNoteNotFound means a note id given to a tag operation is not a live note of the user
+var ErrTagNoteNotFound = errors.New("note not found")
+
+type ITagService interface {
+	List(ctx context.Context, userId uuid.UUID) ([]*dto.TagResponse, error)
+	Create(ctx context.Context, userId uuid.UUID, req *dto.CreateTagRequest) (*dto.TagResponse, error)
+	Update(ctx context.Context, userId uuid.UUID, req *dto.UpdateTagRequest) (*dto.TagResponse, error)
+	Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	// Tag attaches every tag to every note
+	Tag(ctx context.Context, userId uuid.UUID, req *dto.BulkTagRequest) (*dto.BulkTagResponse, error)
+	// Untag detaches every tag from every note
+	Untag(ctx context.Context, userId uuid.UUID, req *dto.BulkTagRequest) (*dto.BulkTagResponse, error)
+	// SetNoteTags replaces the tag set of one note
+	SetNoteTags(ctx context.Context, userId uuid.UUID, req *dto.SetNoteTagsRequest) ([]dto.NoteTagResponse, error)
+}
+
+type tagService struct {
+	uowFactory unitofwork.RepositoryFactory
+}
+
+func NewTagService(uowFactory unitofwork.RepositoryFactory) ITagService {
+	return &tagService{uowFactory: uowFactory}
+}
+
+func (c *tagService) List(ctx context.Context, userId uuid.UUID) ([]*dto.TagResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	tags, err := uow.TagRepository().FindAll(ctx,
+		specification.UserOwnedBy{UserID: userId},
+		specification.OrderBy{Field: "LOWER(name)"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIds := make([]uuid.UUID, len(tags))
+	for i, tag := range tags {
+		tagIds[i] = tag.Id
+	}
+	counts, err := uow.TagRepository().CountNotes(ctx, tagIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, toTagResponse(tag, counts[tag.Id]))
+	}
+	return res, nil
+}
+
+func (c *tagService) Create(ctx context.Context, userId uuid.UUID, req *dto.CreateTagRequest) (*dto.TagResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	name := normalizeTagName(req.Name)
+	if name == "" {
+		return nil, ErrTagNameInvalid
+	}
+	if err := c.ensureNameAvailable(ctx, uow, userId, name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	color := strings.ToUpper(req.Color)
+	if color == "" {
+		color = dto.DefaultTagColor
+	}
+
+	tag := entity.Tag{
+		Id:        uuid.New(),
+		UserId:    userId,
+		Name:      name,
+		Color:     color,
+		CreatedAt: time.Now(),
+	}
+	if err := uow.TagRepository().Create(ctx, &tag); err != nil {
+		return nil, err
+	}
+
+	return toTagResponse(&tag, 0), nil
+}
+
+func (c *tagService) Update(ctx context.Context, userId uuid.UUID, req *dto.UpdateTagRequest) (*dto.TagResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	tag, err := c.findTag(ctx, uow, userId, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := normalizeTagName(req.Name)
+	if name == "" {
+		return nil, ErrTagNameInvalid
+	}
+	if !strings.EqualFold(name, tag.Name) {
+		if err := c.ensureNameAvailable(ctx, uow, userId, name, tag.Id); err != nil {
+			return nil, err
+		}
+	}
+	tag.Name = name
+	if req.Color != "" {
+		tag.Color = strings.ToUpper(req.Color)
+	}
+
+	if err := uow.TagRepository().Update(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	counts, err := uow.TagRepository().CountNotes(ctx, []uuid.UUID{tag.Id})
+	if err != nil {
+		return nil, err
+	}
+	return toTagResponse(tag, counts[tag.Id]), nil
+}
+
+func (c *tagService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	if _, err := c.findTag(ctx, uow, userId, id); err != nil {
+		return err
+	}
+
+	if err := uow.Begin(ctx); err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	if err := uow.TagRepository().Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return uow.Commit()
+}
+
+func (c *tagService) Tag(ctx context.Context, userId uuid.UUID, req *dto.BulkTagRequest) (*dto.BulkTagResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	tagIds, noteIds, err := c.verifyBulkRequest(ctx, uow, userId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := uow.TagRepository().Attach(ctx, tagIds, noteIds)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BulkTagResponse{Changed: changed}, nil
+}
+
+func (c *tagService) Untag(ctx context.Context, userId uuid.UUID, req *dto.BulkTagRequest) (*dto.BulkTagResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	tagIds, noteIds, err := c.verifyBulkRequest(ctx, uow, userId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := uow.TagRepository().Detach(ctx, tagIds, noteIds)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BulkTagResponse{Changed: changed}, nil
+}
+
+func (c *tagService) SetNoteTags(ctx context.Context, userId uuid.UUID, req *dto.SetNoteTagsRequest) ([]dto.NoteTagResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	noteIds := []uuid.UUID{req.NoteId}
+	if err := c.verifyNotes(ctx, uow, userId, noteIds); err != nil {
+		return nil, err
+	}
+	tagIds := uniqueIds(req.TagIds)
+	if err := c.verifyTags(ctx, uow, userId, tagIds); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Begin(ctx); err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	if err := uow.TagRepository().DetachAllFromNotes(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	if _, err := uow.TagRepository().Attach(ctx, tagIds, noteIds); err != nil {
+		return nil, err
+	}
+	tagsByNote, err := uow.TagRepository().FindByNoteIds(ctx, noteIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	return toNoteTagResponses(tagsByNote[req.NoteId]), nil
+}
+
+func (c *tagService) findTag(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, id uuid.UUID) (*entity.Tag, error) {
+	tag, err := uow.TagRepository().FindOne(ctx,
+		specification.ByID{ID: id},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// ensureNameAvailable fails with ErrTagNameTaken when another tag of the user (not exceptId) has the name
+func (c *tagService) ensureNameAvailable(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, name string, exceptId uuid.UUID) error {
+	existing, err := uow.TagRepository().FindOne(ctx,
+		specification.UserOwnedBy{UserID: userId},
+		specification.ByTagName{Name: name},
+	)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Id != exceptId {
+		return ErrTagNameTaken
+	}
+	return nil
+}
+
+func (c *tagService) verifyBulkRequest(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, req *dto.BulkTagRequest) ([]uuid.UUID, []uuid.UUID, error) {
+	tagIds := uniqueIds(req.TagIds)
+	noteIds := uniqueIds(req.NoteIds)
+	if err := c.verifyTags(ctx, uow, userId, tagIds); err != nil {
+		return nil, nil, err
+	}
+	if err := c.verifyNotes(ctx, uow, userId, noteIds); err != nil {
+		return nil, nil, err
+	}
+	return tagIds, noteIds, nil
+}
+
+// verifyTags checks that every (deduplicated) tag id belongs to the user
+func (c *tagService) verifyTags(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, tagIds []uuid.UUID) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+	count, err := uow.TagRepository().Count(ctx,
+		specification.ByIDs{IDs: tagIds},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(tagIds)) {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// verifyNotes checks that every (deduplicated) note id is a live note of the user
+func (c *tagService) verifyNotes(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, noteIds []uuid.UUID) error {
+	count, err := uow.NoteRepository().Count(ctx,
+		specification.ByIDs{IDs: noteIds},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(noteIds)) {
+		return ErrTagNoteNotFound
+	}
+	return nil
+}
+
+// normalizeTagName trims whitespace and a leading '#'
+func normalizeTagName(name string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+}
+
+func uniqueIds(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	res := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+func toTagResponse(tag *entity.Tag, noteCount int64) *dto.TagResponse {
+	return &dto.TagResponse{
+		Id:        tag.Id,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		NoteCount: noteCount,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+// toNoteTagResponses converts a note's tags, always returning a non-nil slice
+func toNoteTagResponses(tags []*entity.Tag) []dto.NoteTagResponse {
+	res := make([]dto.NoteTagResponse, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, dto.NoteTagResponse{Id: tag.Id, Name: tag.Name, Color: tag.Color})
+	}
+	return res
+}
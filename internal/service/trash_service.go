@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// ErrTrashParentUnavailable means a trashed note's notebook is gone and no target notebook was given
+var ErrTrashParentUnavailable = errors.New("original notebook is no longer available")
+
+// ErrTrashItemNotFound means the id is not a trashed note or notebook of the user
+var ErrTrashItemNotFound = errors.New("trash item not found")
+
+// trashPurgeInterval is how often expired trash is purged
+const trashPurgeInterval = time.Hour
+
+type ITrashService interface {
+	List(ctx context.Context, userId uuid.UUID) ([]*dto.TrashItemResponse, error)
+	Restore(ctx context.Context, userId uuid.UUID, id uuid.UUID, req *dto.RestoreTrashRequest) (*dto.RestoreTrashResponse, error)
+	Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	Empty(ctx context.Context, userId uuid.UUID) (*dto.EmptyTrashResponse, error)
+	// PurgeExpired permanently deletes everything trashed longer than the retention period
+	PurgeExpired(ctx context.Context) (int, error)
+	// Run purges expired trash periodically until ctx is cancelled
+	Run(ctx context.Context)
+}
+
+type trashService struct {
+	uowFactory       unitofwork.RepositoryFactory
+	publisherService IPublisherService
+	storage          storage.Storage
+	retention        time.Duration
+}
+
+func NewTrashService(
+	uowFactory unitofwork.RepositoryFactory,
+	publisherService IPublisherService,
+	store storage.Storage,
+	retention time.Duration,
+) ITrashService {
+	return &trashService{
+		uowFactory:       uowFactory,
+		publisherService: publisherService,
+		storage:          store,
+		retention:        retention,
+	}
+}
+
+func (c *trashService) List(ctx context.Context, userId uuid.UUID) ([]*dto.TrashItemResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	notebooks, err := uow.NotebookRepository().FindAll(ctx,
+		specification.TrashedRoots{},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.TrashedRoots{},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.TrashItemResponse, 0, len(notebooks)+len(notes))
+	for _, notebook := range notebooks {
+		notebookCount, err := uow.NotebookRepository().Count(ctx,
+			specification.ByTrashRoot{RootID: notebook.Id},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		noteCount, err := uow.NoteRepository().Count(ctx,
+			specification.ByTrashRoot{RootID: notebook.Id},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c.toTrashItemResponse(dto.TrashItemNotebook, notebook.Id, notebook.Name, notebook.ParentId, notebook.DeletedAt, notebookCount, noteCount))
+	}
+	for _, note := range notes {
+		notebookId := note.NotebookId
+		result = append(result, c.toTrashItemResponse(dto.TrashItemNote, note.Id, note.Title, &notebookId, note.DeletedAt, 0, 1))
+	}
+
+	// Most recently deleted first
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DeletedAt.After(result[j].DeletedAt)
+	})
+
+	return result, nil
+}
+
+func (c *trashService) Restore(ctx context.Context, userId uuid.UUID, id uuid.UUID, req *dto.RestoreTrashRequest) (*dto.RestoreTrashResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	notebook, err := uow.NotebookRepository().FindOne(ctx,
+		specification.ByID{ID: id},
+		specification.TrashedRoots{},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var note *entity.Note
+	if notebook == nil {
+		note, err = uow.NoteRepository().FindOne(ctx,
+			specification.ByID{ID: id},
+			specification.TrashedRoots{},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return nil, ErrTrashItemNotFound
+		}
+	}
+
+	// An explicit target must be a live notebook of the user
+	if req != nil && req.NotebookId != nil {
+		target, err := uow.NotebookRepository().FindOne(ctx,
+			specification.ByID{ID: *req.NotebookId},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if target == nil {
+			return nil, ErrTrashParentUnavailable
+		}
+	}
+
+	if err := uow.Begin(ctx); err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	var res *dto.RestoreTrashResponse
+	var restoredNotes []uuid.UUID
+	if notebook != nil {
+		res, restoredNotes, err = c.restoreNotebook(ctx, uow, userId, notebook, req)
+	} else {
+		res, err = c.restoreNote(ctx, uow, userId, note, req)
+		restoredNotes = []uuid.UUID{note.Id}
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Embeddings were dropped when the items were trashed
+	if err := uow.EmbeddingJobRepository().EnqueueMany(ctx, restoredNotes, false); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	c.publisherService.Notify(restoredNotes...)
+
+	return res, nil
+}
+
+// restoreNotebook brings back the notebook with everything deleted along with it. The hierarchy
+// inside the subtree is preserved; if the original parent is gone the notebook returns to the top level.
+func (c *trashService) restoreNotebook(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, notebook *entity.Notebook, req *dto.RestoreTrashRequest) (*dto.RestoreTrashResponse, []uuid.UUID, error) {
+	notebooks, err := uow.NotebookRepository().FindAll(ctx,
+		specification.ByTrashRoot{RootID: notebook.Id},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByTrashRoot{RootID: notebook.Id},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parentId := notebook.ParentId
+	if req != nil && req.NotebookId != nil {
+		parentId = req.NotebookId
+	} else if parentId != nil {
+		parent, err := uow.NotebookRepository().FindOne(ctx,
+			specification.ByID{ID: *parentId},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if parent == nil {
+			parentId = nil
+		}
+	}
+
+	notebookIds := make([]uuid.UUID, 0, len(notebooks))
+	for _, nb := range notebooks {
+		notebookIds = append(notebookIds, nb.Id)
+	}
+	noteIds := make([]uuid.UUID, 0, len(notes))
+	for _, n := range notes {
+		noteIds = append(noteIds, n.Id)
+	}
+
+	if err := uow.NotebookRepository().Restore(ctx, notebookIds); err != nil {
+		return nil, nil, err
+	}
+	if err := uow.NoteRepository().Restore(ctx, noteIds); err != nil {
+		return nil, nil, err
+	}
+
+	if !sameParent(parentId, notebook.ParentId) {
+		restored, err := uow.NotebookRepository().FindOne(ctx, specification.ByID{ID: notebook.Id})
+		if err != nil {
+			return nil, nil, err
+		}
+		now := time.Now()
+		restored.ParentId = parentId
+		restored.UpdatedAt = &now
+		if err := uow.NotebookRepository().Update(ctx, restored); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &dto.RestoreTrashResponse{
+		Id:        notebook.Id,
+		Type:      dto.TrashItemNotebook,
+		ParentId:  parentId,
+		Notebooks: len(notebookIds),
+		Notes:     len(noteIds),
+	}, noteIds, nil
+}
+
+// restoreNote brings back a single note into its notebook, or into the requested one
+func (c *trashService) restoreNote(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, note *entity.Note, req *dto.RestoreTrashRequest) (*dto.RestoreTrashResponse, error) {
+	notebookId := note.NotebookId
+	if req != nil && req.NotebookId != nil {
+		notebookId = *req.NotebookId
+	} else {
+		notebook, err := uow.NotebookRepository().FindOne(ctx,
+			specification.ByID{ID: notebookId},
+			specification.UserOwnedBy{UserID: userId},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if notebook == nil {
+			return nil, ErrTrashParentUnavailable
+		}
+	}
+
+	if err := uow.NoteRepository().Restore(ctx, []uuid.UUID{note.Id}); err != nil {
+		return nil, err
+	}
+
+	if notebookId != note.NotebookId {
+		restored, err := uow.NoteRepository().FindOne(ctx, specification.ByID{ID: note.Id})
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		restored.NotebookId = notebookId
+		restored.UpdatedAt = &now
+		if err := uow.NoteRepository().Move(ctx, restored); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.RestoreTrashResponse{
+		Id:       note.Id,
+		Type:     dto.TrashItemNote,
+		ParentId: &notebookId,
+		Notes:    1,
+	}, nil
+}
+
+func (c *trashService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	notebookCount, err := uow.NotebookRepository().Count(ctx,
+		specification.ByID{ID: id},
+		specification.TrashedRoots{},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return err
+	}
+	noteCount, err := uow.NoteRepository().Count(ctx,
+		specification.ByID{ID: id},
+		specification.TrashedRoots{},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return err
+	}
+	if notebookCount == 0 && noteCount == 0 {
+		return ErrTrashItemNotFound
+	}
+
+	notebooks, err := uow.NotebookRepository().FindAll(ctx,
+		specification.ByTrashRoot{RootID: id},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return err
+	}
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByTrashRoot{RootID: id},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := uow.Begin(ctx); err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	keys, err := purgeTrash(ctx, uow, notebooks, notes)
+	if err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+	removeAttachmentFiles(ctx, c.storage, keys)
+	return nil
+}
+
+func (c *trashService) Empty(ctx context.Context, userId uuid.UUID) (*dto.EmptyTrashResponse, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+
+	notebooks, err := uow.NotebookRepository().FindAll(ctx,
+		specification.Trashed{},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.Trashed{},
+		specification.UserOwnedBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uow.Begin(ctx); err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	keys, err := purgeTrash(ctx, uow, notebooks, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	removeAttachmentFiles(ctx, c.storage, keys)
+
+	return &dto.EmptyTrashResponse{
+		Notebooks: len(notebooks),
+		Notes:     len(notes),
+	}, nil
+}
+
+func (c *trashService) PurgeExpired(ctx context.Context) (int, error) {
+	uow := c.uowFactory.NewUnitOfWork(ctx)
+	cutoff := time.Now().Add(-c.retention)
+
+	notebooks, err := uow.NotebookRepository().FindAll(ctx, specification.DeletedBefore{Time: cutoff})
+	if err != nil {
+		return 0, err
+	}
+	notes, err := uow.NoteRepository().FindAll(ctx, specification.DeletedBefore{Time: cutoff})
+	if err != nil {
+		return 0, err
+	}
+	if len(notebooks) == 0 && len(notes) == 0 {
+		return 0, nil
+	}
+
+	if err := uow.Begin(ctx); err != nil {
+		return 0, err
+	}
+	defer uow.Rollback()
+
+	keys, err := purgeTrash(ctx, uow, notebooks, notes)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return 0, err
+	}
+	removeAttachmentFiles(ctx, c.storage, keys)
+	return len(notebooks) + len(notes), nil
+}
+
+func (c *trashService) Run(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := c.PurgeExpired(ctx)
+		if err != nil {
+			fmt.Printf("[WARN] Trash purge failed: %v\n", err)
+		} else if purged > 0 {
+			fmt.Printf("[INFO] Trash purge removed %d items\n", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *trashService) toTrashItemResponse(itemType string, id uuid.UUID, title string, parentId *uuid.UUID, deletedAt *time.Time, notebookCount, noteCount int64) *dto.TrashItemResponse {
+	res := &dto.TrashItemResponse{
+		Id:            id,
+		Type:          itemType,
+		Title:         title,
+		ParentId:      parentId,
+		NotebookCount: notebookCount,
+		NoteCount:     noteCount,
+	}
+	if deletedAt != nil {
+		res.DeletedAt = *deletedAt
+		res.PurgeAt = deletedAt.Add(c.retention)
+	}
+	return res
+}
+
+// purgeTrash permanently deletes notes and notebooks together with everything that hangs off the notes.
+// It returns the storage keys of the deleted attachments, whose files are removed once the transaction commits.
+func purgeTrash(ctx context.Context, uow unitofwork.UnitOfWork, notebooks []*entity.Notebook, notes []*entity.Note) ([]string, error) {
+	noteIds := make([]uuid.UUID, 0, len(notes))
+	for _, note := range notes {
+		noteIds = append(noteIds, note.Id)
+	}
+	notebookIds := make([]uuid.UUID, 0, len(notebooks))
+	for _, notebook := range notebooks {
+		notebookIds = append(notebookIds, notebook.Id)
+	}
+
+	var keys []string
+	if len(noteIds) > 0 {
+		attachments, err := uow.NoteAttachmentRepository().FindAll(ctx, specification.ByAttachmentNoteIDs{NoteIDs: noteIds})
+		if err != nil {
+			return nil, err
+		}
+		for _, attachment := range attachments {
+			keys = append(keys, attachment.StorageKey)
+		}
+	}
+
+	if err := uow.NoteEmbeddingRepository().DeleteByNoteIdsUnscoped(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	if err := uow.EmbeddingJobRepository().DeleteByNoteIds(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	if err := uow.NoteRevisionRepository().DeleteByNoteIds(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	if err := uow.NoteAttachmentRepository().DeleteByNoteIds(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	if err := uow.TagRepository().DetachAllFromNotes(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	if err := uow.NoteLinkRepository().DeleteByNoteIds(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	if err := uow.NoteRepository().DeleteUnscopedByIds(ctx, noteIds); err != nil {
+		return nil, err
+	}
+	if err := uow.NotebookRepository().DeleteUnscopedByIds(ctx, notebookIds); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Chat streaming messages. A client sends chat.stream with a request_id it chooses and the body of
+// send-chat/stream as data; the hub answers with chat.token* then chat.citations and chat.done, or
+// chat.error, each carrying the request_id and the data of the matching SSE event.
+const (
+	MsgChatStream    = "chat.stream"
+	MsgChatToken     = "chat.token"
+	MsgChatCitations = "chat.citations"
+	MsgChatDone      = "chat.done"
+	MsgChatError     = "chat.error"
+)
+
+const maxRequestIDLength = 64
+
+var (
+	errChatUnavailable = errors.New("chat streaming is not available")
+	errChatBusy        = errors.New("a chat stream is already running on this connection")
+	errChatDisconnect  = errors.New("client disconnected")
+)
+
+// ChatStreamer runs a chat.stream request for the user, calling emit for every event of the
+// send-chat/stream endpoint ("token", "citations", "done" or "error"). Once emit fails the
+// client is gone and generation should stop.
+type ChatStreamer interface {
+	StreamChat(ctx context.Context, userID uuid.UUID, request json.RawMessage, emit func(event string, data interface{}) error)
+}
+
+type chatRequest struct {
+	RequestID string          `json:"request_id"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type chatEvent struct {
+	Type      string      `json:"type"`
+	RequestID string      `json:"request_id"`
+	Data      interface{} `json:"data"`
+}
+
+// startChatStream answers a chat.stream message on its own goroutine, so the connection keeps
+// reading (and answering pings) while the model generates. One stream runs per connection.
+func (h *Hub) startChatStream(c *Client, message []byte) {
+	var req chatRequest
+	if err := json.Unmarshal(message, &req); err != nil || len(req.RequestID) > maxRequestIDLength {
+		h.sendTo(c, encodeChatEvent(MsgChatError, req.RequestID, roomError{Error: errRoomMessage.Error()}))
+		return
+	}
+	if h.chat == nil {
+		h.sendTo(c, encodeChatEvent(MsgChatError, req.RequestID, roomError{Error: errChatUnavailable.Error()}))
+		return
+	}
+	if !c.streaming.CompareAndSwap(false, true) {
+		h.sendTo(c, encodeChatEvent(MsgChatError, req.RequestID, roomError{Error: errChatBusy.Error()}))
+		return
+	}
+
+	go func() {
+		defer c.streaming.Store(false)
+		h.chat.StreamChat(context.Background(), c.UserID, req.Data, func(event string, data interface{}) error {
+			if !h.sendIfConnected(c, encodeChatEvent("chat."+event, req.RequestID, data)) {
+				return errChatDisconnect
+			}
+			return nil
+		})
+	}()
+}
+
+// sendIfConnected queues a message for a client that is still registered. Send is closed under
+// h.mu when the client unregisters, so holding the read lock makes the send safe. A client whose
+// buffer is full counts as gone: dropping a token would corrupt the streamed answer.
+func (h *Hub) sendIfConnected(c *Client, data []byte) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	registered := false
+	for _, client := range h.clients[c.UserID] {
+		if client == c {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		return false
+	}
+
+	select {
+	case c.Send <- data:
+		return true
+	default:
+		h.logger.Warn("Hub", "Client Send buffer full, stopping chat stream", map[string]interface{}{"user_id": c.UserID})
+		return false
+	}
+}
+
+func encodeChatEvent(eventType string, requestID string, data interface{}) []byte {
+	encoded, _ := json.Marshal(chatEvent{Type: eventType, RequestID: requestID, Data: data})
+	return encoded
+}
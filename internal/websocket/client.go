@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/websocket/v2"
@@ -12,7 +13,7 @@ const (
 	writeWait      = 10 * time.Second
 	pongWait       = 60 * time.Second
 	pingPeriod     = (pongWait * 9) / 10
-	maxMessageSize = 512
+	maxMessageSize = 64 * 1024 // Editor updates can be large
 )
 
 // Client is a middleman between the websocket connection and the hub.
@@ -22,9 +23,18 @@ type Client struct {
 	// The websocket connection.
 	Conn *websocket.Conn
 
+	// ID of this connection; one user may have several (devices, tabs)
+	ID uuid.UUID
+
 	// UserID associated with this connection
 	UserID uuid.UUID
 
+	// Note rooms the connection has joined (guarded by Hub.roomsMu)
+	rooms map[uuid.UUID]bool
+
+	// Whether a chat.stream answer is being generated for this connection
+	streaming atomic.Bool
+
 	// Buffered channel of outbound messages.
 	Send chan []byte
 }
@@ -46,7 +56,7 @@ func (c *Client) readPump() {
 
 	log.Printf("readPump started for user %s", c.UserID)
 	for {
-		_, _, err := c.Conn.ReadMessage()
+		_, message, err := c.Conn.ReadMessage()
 		if err != nil {
 			// Log ALL errors for debugging now
 			log.Printf("readPump error for user %s: %v", c.UserID, err)
@@ -55,7 +65,8 @@ func (c *Client) readPump() {
 			}
 			break
 		}
-		// We can handle incoming messages here (e.g. "mark_read") if we want bidirectional
+		// Collaboration and chat streaming messages; other messages are ignored
+		c.Hub.handleMessage(c, message)
 	}
 }
 
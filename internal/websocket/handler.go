@@ -8,7 +8,14 @@ import (
 // ServeWs handles websocket requests from the peer.
 // ServeWs handles websocket requests from the peer.
 func ServeWs(hub *Hub, c *websocket.Conn, userID uuid.UUID) {
-	client := &Client{Hub: hub, Conn: c, UserID: userID, Send: make(chan []byte, 256)}
+	client := &Client{
+		Hub:    hub,
+		Conn:   c,
+		ID:     uuid.New(),
+		UserID: userID,
+		rooms:  make(map[uuid.UUID]bool),
+		Send:   make(chan []byte, 256),
+	}
 	client.Hub.register <- client
 
 	// Allow collection of memory referenced by the caller by doing all work in
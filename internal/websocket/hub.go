@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log"
 	"sync"
+	"time"
 
 	"ai-notetaking-be/internal/model"
 	"ai-notetaking-be/internal/pkg/logger"
@@ -26,19 +27,40 @@ type Hub struct {
 	// Lock for safe map access
 	mu sync.RWMutex
 
+	// Note rooms for collaborative editing: NoteID -> Client -> Participant
+	rooms   map[uuid.UUID]map[*Client]*Participant
+	roomsMu sync.RWMutex
+
+	// Permission check for joining note rooms
+	authorizer NoteRoomAuthorizer
+
+	// Answers chat.stream messages
+	chat ChatStreamer
+
 	// Redis connection for cross-instance communication
 	rdb *redis.Client
 
+	// Identifies this instance, so it can skip its own room messages coming back from Redis
+	instanceID string
+
+	// Redis presence writes, applied by runPresence
+	presence chan presenceOp
+
 	// Dedicated Logger
 	logger logger.ILogger
 }
 
-func NewHub(rdb *redis.Client, log logger.ILogger) *Hub {
+func NewHub(rdb *redis.Client, log logger.ILogger, authorizer NoteRoomAuthorizer, chat ChatStreamer) *Hub {
 	return &Hub{
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		clients:    make(map[uuid.UUID][]*Client),
+		rooms:      make(map[uuid.UUID]map[*Client]*Participant),
+		authorizer: authorizer,
+		chat:       chat,
 		rdb:        rdb,
+		instanceID: uuid.New().String(),
+		presence:   make(chan presenceOp, presenceQueueSize),
 		logger:     log,
 	}
 }
@@ -47,10 +69,17 @@ func (h *Hub) Run() {
 	// Start Redis Subscriber if Redis is available
 	if h.rdb != nil {
 		go h.subscribeToRedis()
+		go h.runPresence()
 	}
 
+	presenceTicker := time.NewTicker(presenceRefresh)
+	defer presenceTicker.Stop()
+
 	for {
 		select {
+		case <-presenceTicker.C:
+			h.refreshPresence()
+
 		case client := <-h.register:
 			h.mu.Lock()
 			h.clients[client.UserID] = append(h.clients[client.UserID], client)
@@ -58,6 +87,9 @@ func (h *Hub) Run() {
 			h.logger.Info("Hub", "Client registered", map[string]interface{}{"user_id": client.UserID})
 
 		case client := <-h.unregister:
+			// Leave the note rooms first: nothing may be sent to the client once Send is closed
+			h.leaveAllRooms(client)
+
 			h.mu.Lock()
 			if clients, ok := h.clients[client.UserID]; ok {
 				for i, c := range clients {
@@ -171,14 +203,28 @@ func (h *Hub) subscribeToRedis() {
 	for msg := range ch {
 		// Parse message
 		var payload struct {
-			TargetUserID string          `json:"target_user_id"`
-			Message      json.RawMessage `json:"message"`
+			TargetUserID  string          `json:"target_user_id"`
+			RoomID        string          `json:"room_id"`
+			Origin        string          `json:"origin"`
+			ExcludeClient string          `json:"exclude_client"`
+			Message       json.RawMessage `json:"message"`
 		}
 		if err := json.Unmarshal([]byte(msg.Payload), &payload); err != nil {
 			log.Printf("Redis msg parse error: %v", err)
 			continue
 		}
 
+		// Note room message: the origin instance already delivered it locally
+		if payload.RoomID != "" {
+			noteID, err := uuid.Parse(payload.RoomID)
+			if err != nil || payload.Origin == h.instanceID {
+				continue
+			}
+			exclude, _ := uuid.Parse(payload.ExcludeClient)
+			h.deliverToRoom(noteID, exclude, payload.Message)
+			continue
+		}
+
 		// Check for Broadcast
 		if payload.TargetUserID == "*" {
 			// Broadcast to all local clients
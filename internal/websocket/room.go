@@ -0,0 +1,484 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collaboration messages. Clients send join, leave, presence and update; the hub answers with
+// joined, presence, left, update and error. Every message carries the note_id of its room.
+const (
+	MsgRoomJoin     = "collab.join"
+	MsgRoomLeave    = "collab.leave"
+	MsgRoomPresence = "collab.presence"
+	MsgRoomUpdate   = "collab.update"
+	MsgRoomJoined   = "collab.joined"
+	MsgRoomLeft     = "collab.left"
+	MsgRoomError    = "collab.error"
+)
+
+// Presence modes
+const (
+	ModeViewing = "viewing"
+	ModeEditing = "editing"
+)
+
+const (
+	maxRoomsPerClient = 20
+	authorizeTimeout  = 5 * time.Second
+	// Editors are authorized again before relaying updates once their last check is this old,
+	// so members removed or downgraded to viewer stop editing without reconnecting
+	roleRecheck = 5 * time.Second
+	// Presence is kept in Redis so joiners see the participants of every instance. Entries are
+	// refreshed while the client is connected and ignored once older than presenceTTL.
+	presenceTTL       = 2 * time.Minute
+	presenceRefresh   = time.Minute
+	presenceKeyPrefix = "collab:presence:"
+	presenceQueueSize = 1024
+)
+
+var (
+	errRoomForbidden = errors.New("note not found or access denied")
+	errRoomNotJoined = errors.New("join the note first")
+	errRoomReadOnly  = errors.New("read-only access")
+	errRoomLimit     = errors.New("too many open notes")
+	errRoomMessage   = errors.New("invalid message")
+)
+
+// NoteRoomAuthorizer decides who may join a note's room: every user who can read the note may join,
+// canEdit tells whether they may also send editor updates
+type NoteRoomAuthorizer interface {
+	AuthorizeCollaboration(ctx context.Context, userID uuid.UUID, noteID uuid.UUID) (canEdit bool, err error)
+}
+
+// Participant is one connection in a note room
+type Participant struct {
+	ClientID  uuid.UUID       `json:"client_id"`
+	UserID    uuid.UUID       `json:"user_id"`
+	CanEdit   bool            `json:"can_edit"`
+	Mode      string          `json:"mode"`             // viewing | editing
+	Cursor    json.RawMessage `json:"cursor,omitempty"` // Editor-defined cursor / selection, relayed as is
+	UpdatedAt time.Time       `json:"updated_at"`
+
+	authorizedAt time.Time // Last role check
+}
+
+// presenceOp is a Redis presence write; data nil removes the entry
+type presenceOp struct {
+	noteID   uuid.UUID
+	clientID uuid.UUID
+	data     []byte
+}
+
+// roomRequest is a collaboration message sent by a client
+type roomRequest struct {
+	Type   string          `json:"type"`
+	NoteID uuid.UUID       `json:"note_id"`
+	Mode   string          `json:"mode"`
+	Cursor json.RawMessage `json:"cursor"`
+	Update json.RawMessage `json:"update"` // Opaque editor update, relayed to the other participants
+}
+
+type roomEvent struct {
+	Type   string      `json:"type"`
+	NoteID uuid.UUID   `json:"note_id"`
+	Data   interface{} `json:"data"`
+}
+
+type roomJoined struct {
+	CanEdit      bool           `json:"can_edit"`
+	Participants []*Participant `json:"participants"`
+}
+
+type roomLeft struct {
+	ClientID uuid.UUID `json:"client_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+type roomUpdate struct {
+	ClientID uuid.UUID       `json:"client_id"`
+	UserID   uuid.UUID       `json:"user_id"`
+	Update   json.RawMessage `json:"update"`
+}
+
+type roomError struct {
+	Error string `json:"error"`
+}
+
+// handleMessage dispatches a message read from the client. Anything that is not a
+// collaboration or chat streaming message is ignored.
+func (h *Hub) handleMessage(c *Client, message []byte) {
+	var req roomRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		return
+	}
+	if req.Type == MsgChatStream {
+		h.startChatStream(c, message)
+		return
+	}
+
+	var err error
+	switch req.Type {
+	case MsgRoomJoin:
+		err = h.joinRoom(c, req.NoteID)
+	case MsgRoomLeave:
+		h.leaveRoom(c, req.NoteID)
+	case MsgRoomPresence:
+		err = h.updatePresence(c, &req)
+	case MsgRoomUpdate:
+		err = h.relayUpdate(c, &req)
+	default:
+		return
+	}
+
+	if err != nil {
+		h.sendTo(c, encodeRoomEvent(MsgRoomError, req.NoteID, roomError{Error: err.Error()}))
+	}
+}
+
+func (h *Hub) joinRoom(c *Client, noteID uuid.UUID) error {
+	if noteID == uuid.Nil {
+		return errRoomMessage
+	}
+
+	h.roomsMu.RLock()
+	_, joined := h.rooms[noteID][c]
+	count := len(c.rooms)
+	h.roomsMu.RUnlock()
+	if !joined && count >= maxRoomsPerClient {
+		return errRoomLimit
+	}
+
+	canEdit := false
+	if !joined {
+		if h.authorizer == nil {
+			return errRoomForbidden
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), authorizeTimeout)
+		defer cancel()
+		var err error
+		canEdit, err = h.authorizer.AuthorizeCollaboration(ctx, c.UserID, noteID)
+		if err != nil {
+			h.logger.Info("Hub", "Room join denied", map[string]interface{}{"user_id": c.UserID, "note_id": noteID, "error": err.Error()})
+			return errRoomForbidden
+		}
+	}
+
+	h.roomsMu.Lock()
+	participant, joined := h.rooms[noteID][c]
+	if !joined {
+		participant = &Participant{
+			ClientID:     c.ID,
+			UserID:       c.UserID,
+			CanEdit:      canEdit,
+			Mode:         ModeViewing,
+			UpdatedAt:    time.Now(),
+			authorizedAt: time.Now(),
+		}
+		if h.rooms[noteID] == nil {
+			h.rooms[noteID] = make(map[*Client]*Participant)
+		}
+		h.rooms[noteID][c] = participant
+		c.rooms[noteID] = true
+	}
+	snapshot := *participant
+	h.roomsMu.Unlock()
+
+	if !joined {
+		h.storePresence(noteID, &snapshot)
+		h.publishToRoom(noteID, c, encodeRoomEvent(MsgRoomPresence, noteID, &snapshot))
+	}
+
+	h.sendTo(c, encodeRoomEvent(MsgRoomJoined, noteID, roomJoined{
+		CanEdit:      snapshot.CanEdit,
+		Participants: h.participants(noteID),
+	}))
+	return nil
+}
+
+func (h *Hub) leaveRoom(c *Client, noteID uuid.UUID) {
+	h.roomsMu.Lock()
+	_, joined := h.rooms[noteID][c]
+	if joined {
+		delete(h.rooms[noteID], c)
+		if len(h.rooms[noteID]) == 0 {
+			delete(h.rooms, noteID)
+		}
+		delete(c.rooms, noteID)
+	}
+	h.roomsMu.Unlock()
+	if !joined {
+		return
+	}
+
+	h.removePresence(noteID, c.ID)
+	h.publishToRoom(noteID, c, encodeRoomEvent(MsgRoomLeft, noteID, roomLeft{ClientID: c.ID, UserID: c.UserID}))
+}
+
+// leaveAllRooms removes a disconnecting client from its rooms. Once it returns, the hub no longer
+// sends room messages to the client, so its Send channel can be closed.
+func (h *Hub) leaveAllRooms(c *Client) {
+	h.roomsMu.RLock()
+	noteIDs := make([]uuid.UUID, 0, len(c.rooms))
+	for noteID := range c.rooms {
+		noteIDs = append(noteIDs, noteID)
+	}
+	h.roomsMu.RUnlock()
+
+	for _, noteID := range noteIDs {
+		h.leaveRoom(c, noteID)
+	}
+}
+
+func (h *Hub) updatePresence(c *Client, req *roomRequest) error {
+	h.roomsMu.Lock()
+	participant, joined := h.rooms[req.NoteID][c]
+	if !joined {
+		h.roomsMu.Unlock()
+		return errRoomNotJoined
+	}
+	switch req.Mode {
+	case "":
+	case ModeViewing:
+		participant.Mode = ModeViewing
+	case ModeEditing:
+		if !participant.CanEdit {
+			h.roomsMu.Unlock()
+			return errRoomReadOnly
+		}
+		participant.Mode = ModeEditing
+	default:
+		h.roomsMu.Unlock()
+		return errRoomMessage
+	}
+	if req.Cursor != nil {
+		participant.Cursor = req.Cursor
+	}
+	participant.UpdatedAt = time.Now()
+	snapshot := *participant
+	h.roomsMu.Unlock()
+
+	h.storePresence(req.NoteID, &snapshot)
+	h.publishToRoom(req.NoteID, c, encodeRoomEvent(MsgRoomPresence, req.NoteID, &snapshot))
+	return nil
+}
+
+func (h *Hub) relayUpdate(c *Client, req *roomRequest) error {
+	if len(req.Update) == 0 {
+		return errRoomMessage
+	}
+	if err := h.checkCanEdit(c, req.NoteID); err != nil {
+		return err
+	}
+
+	h.publishToRoom(req.NoteID, c, encodeRoomEvent(MsgRoomUpdate, req.NoteID, roomUpdate{
+		ClientID: c.ID,
+		UserID:   c.UserID,
+		Update:   req.Update,
+	}))
+	return nil
+}
+
+// checkCanEdit tells whether the participant may still edit, authorizing them again once their
+// last check is older than roleRecheck. A participant who lost access leaves the room; one who
+// became a viewer stays in it read-only.
+func (h *Hub) checkCanEdit(c *Client, noteID uuid.UUID) error {
+	h.roomsMu.RLock()
+	participant, joined := h.rooms[noteID][c]
+	var canEdit bool
+	var authorizedAt time.Time
+	if joined {
+		canEdit, authorizedAt = participant.CanEdit, participant.authorizedAt
+	}
+	h.roomsMu.RUnlock()
+	switch {
+	case !joined:
+		return errRoomNotJoined
+	case !canEdit:
+		return errRoomReadOnly
+	case time.Since(authorizedAt) < roleRecheck:
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authorizeTimeout)
+	defer cancel()
+	canEdit, err := h.authorizer.AuthorizeCollaboration(ctx, c.UserID, noteID)
+	if err != nil {
+		h.logger.Info("Hub", "Room access revoked", map[string]interface{}{"user_id": c.UserID, "note_id": noteID, "error": err.Error()})
+		h.leaveRoom(c, noteID)
+		return errRoomForbidden
+	}
+
+	h.roomsMu.Lock()
+	participant, joined = h.rooms[noteID][c]
+	downgraded := joined && !canEdit
+	var snapshot Participant
+	if joined {
+		participant.authorizedAt = time.Now()
+		if downgraded {
+			participant.CanEdit = false
+			participant.Mode = ModeViewing
+			participant.UpdatedAt = time.Now()
+			snapshot = *participant
+		}
+	}
+	h.roomsMu.Unlock()
+
+	switch {
+	case !joined:
+		return errRoomNotJoined
+	case downgraded:
+		h.storePresence(noteID, &snapshot)
+		h.publishToRoom(noteID, c, encodeRoomEvent(MsgRoomPresence, noteID, &snapshot))
+		return errRoomReadOnly
+	}
+	return nil
+}
+
+// participants lists the room's participants on every instance (only local ones without Redis)
+func (h *Hub) participants(noteID uuid.UUID) []*Participant {
+	byClient := make(map[uuid.UUID]*Participant)
+
+	if h.rdb != nil {
+		entries, err := h.rdb.HGetAll(context.Background(), presenceKeyPrefix+noteID.String()).Result()
+		if err != nil {
+			h.logger.Warn("Hub", "Failed to read room presence", map[string]interface{}{"note_id": noteID, "error": err.Error()})
+		}
+		cutoff := time.Now().Add(-presenceTTL)
+		for _, entry := range entries {
+			var p Participant
+			if json.Unmarshal([]byte(entry), &p) == nil && p.UpdatedAt.After(cutoff) {
+				byClient[p.ClientID] = &p
+			}
+		}
+	}
+
+	h.roomsMu.RLock()
+	for _, p := range h.rooms[noteID] {
+		snapshot := *p
+		byClient[p.ClientID] = &snapshot
+	}
+	h.roomsMu.RUnlock()
+
+	res := make([]*Participant, 0, len(byClient))
+	for _, p := range byClient {
+		res = append(res, p)
+	}
+	return res
+}
+
+// storePresence queues the participant's presence for Redis. Presence writes run on their own
+// goroutine (runPresence), so a slow Redis does not hold up the hub or the client's reads.
+func (h *Hub) storePresence(noteID uuid.UUID, p *Participant) {
+	if h.rdb == nil {
+		return
+	}
+	data, _ := json.Marshal(p)
+	h.queuePresence(presenceOp{noteID: noteID, clientID: p.ClientID, data: data})
+}
+
+func (h *Hub) removePresence(noteID uuid.UUID, clientID uuid.UUID) {
+	if h.rdb == nil {
+		return
+	}
+	h.queuePresence(presenceOp{noteID: noteID, clientID: clientID})
+}
+
+// queuePresence drops the write when Redis falls behind; entries are refreshed every
+// presenceRefresh and ignored once older than presenceTTL
+func (h *Hub) queuePresence(op presenceOp) {
+	select {
+	case h.presence <- op:
+	default:
+		h.logger.Warn("Hub", "Presence queue full, dropping presence update", map[string]interface{}{"note_id": op.noteID})
+	}
+}
+
+// runPresence applies the queued presence writes in order
+func (h *Hub) runPresence() {
+	ctx := context.Background()
+	for op := range h.presence {
+		key := presenceKeyPrefix + op.noteID.String()
+		if op.data == nil {
+			h.rdb.HDel(ctx, key, op.clientID.String())
+			continue
+		}
+		if err := h.rdb.HSet(ctx, key, op.clientID.String(), op.data).Err(); err != nil {
+			h.logger.Warn("Hub", "Failed to store room presence", map[string]interface{}{"note_id": op.noteID, "error": err.Error()})
+			continue
+		}
+		h.rdb.Expire(ctx, key, presenceTTL)
+	}
+}
+
+// refreshPresence keeps the Redis presence of this instance's participants from expiring
+func (h *Hub) refreshPresence() {
+	if h.rdb == nil {
+		return
+	}
+	type entry struct {
+		noteID      uuid.UUID
+		participant Participant
+	}
+	var entries []entry
+	now := time.Now()
+	h.roomsMu.Lock()
+	for noteID, room := range h.rooms {
+		for _, p := range room {
+			p.UpdatedAt = now
+			entries = append(entries, entry{noteID: noteID, participant: *p})
+		}
+	}
+	h.roomsMu.Unlock()
+
+	for i := range entries {
+		h.storePresence(entries[i].noteID, &entries[i].participant)
+	}
+}
+
+// publishToRoom delivers a room message to the local participants except the sender and fans it out
+// to the other instances through Redis
+func (h *Hub) publishToRoom(noteID uuid.UUID, sender *Client, data []byte) {
+	h.deliverToRoom(noteID, sender.ID, data)
+
+	if h.rdb != nil {
+		payload := map[string]interface{}{
+			"room_id":        noteID.String(),
+			"origin":         h.instanceID,
+			"exclude_client": sender.ID.String(),
+			"message":        json.RawMessage(data),
+		}
+		jsonPayload, _ := json.Marshal(payload)
+		h.rdb.Publish(context.Background(), "cluster_events", jsonPayload)
+	}
+}
+
+// deliverToRoom sends a message to every local participant of the room except one client
+func (h *Hub) deliverToRoom(noteID uuid.UUID, exclude uuid.UUID, data []byte) {
+	h.roomsMu.RLock()
+	defer h.roomsMu.RUnlock()
+	for client := range h.rooms[noteID] {
+		if client.ID != exclude {
+			h.sendTo(client, data)
+		}
+	}
+}
+
+// sendTo queues a room message for the client. A client that does not keep up loses the
+// message rather than being disconnected; editors resync from the saved note.
+func (h *Hub) sendTo(c *Client, data []byte) {
+	select {
+	case c.Send <- data:
+	default:
+		h.logger.Warn("Hub", "Client Send buffer full, dropping room message", map[string]interface{}{"user_id": c.UserID})
+	}
+}
+
+func encodeRoomEvent(eventType string, noteID uuid.UUID, data interface{}) []byte {
+	encoded, _ := json.Marshal(roomEvent{Type: eventType, NoteID: noteID, Data: data})
+	return encoded
+}
@@ -0,0 +1,76 @@
+package indexing
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+)
+
+// Manager handles bulk re-embedding of notes
+type Manager struct {
+	currentModel string
+}
+
+// NewManager creates a new indexing manager for the active embedding model
+func NewManager(currentModel string) *Manager {
+	return &Manager{currentModel: currentModel}
+}
+
+// Reindex queues every note for re-embedding, or only the ones not yet embedded with the current model
+func (m *Manager) Reindex(ctx context.Context, uow unitofwork.UnitOfWork, req dto.AdminReindexRequest) (*dto.AdminReindexResponse, error) {
+	exceptModel := ""
+	if req.OnlyOutdatedModel {
+		exceptModel = m.currentModel
+	}
+
+	queued, err := uow.EmbeddingJobRepository().EnqueueAll(ctx, exceptModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AdminReindexResponse{
+		Queued:       queued,
+		CurrentModel: m.currentModel,
+	}, nil
+}
+
+// GetProgress reports the embedding queue state against the total number of notes
+func (m *Manager) GetProgress(ctx context.Context, uow unitofwork.UnitOfWork) (*dto.AdminReindexProgressResponse, error) {
+	totalNotes, err := uow.NoteRepository().Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := uow.EmbeddingJobRepository().CountByStatus(ctx, specification.ForLiveNotes{})
+	if err != nil {
+		return nil, err
+	}
+
+	onCurrentModel, err := uow.EmbeddingJobRepository().Count(ctx,
+		specification.ByEmbeddingJobStatus{Status: string(entity.EmbeddingJobStatusCompleted)},
+		specification.ByEmbeddingModel{ModelName: m.currentModel},
+		specification.ForLiveNotes{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := 100.0
+	if totalNotes > 0 {
+		progress = float64(onCurrentModel) / float64(totalNotes) * 100
+	}
+
+	return &dto.AdminReindexProgressResponse{
+		CurrentModel:    m.currentModel,
+		TotalNotes:      totalNotes,
+		Pending:         counts[entity.EmbeddingJobStatusPending] + counts[entity.EmbeddingJobStatusProcessing],
+		Processing:      counts[entity.EmbeddingJobStatusProcessing],
+		Indexed:         counts[entity.EmbeddingJobStatusCompleted],
+		Failed:          counts[entity.EmbeddingJobStatusDead],
+		OnCurrentModel:  onCurrentModel,
+		ProgressPercent: progress,
+	}, nil
+}
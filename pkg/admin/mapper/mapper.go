@@ -67,6 +67,7 @@ func PlanToAdminResponse(p *entity.SubscriptionPlan) *dto.AdminPlanResponse {
 		Features: dto.PlanFeaturesDTO{
 			MaxNotebooks:             p.MaxNotebooks,
 			MaxNotesPerNotebook:      p.MaxNotesPerNotebook,
+			MaxStorageMB:             p.MaxStorageMB,
 			SemanticSearch:           p.SemanticSearchEnabled,
 			AiChat:                   p.AiChatEnabled,
 			AiChatDailyLimit:         p.AiChatDailyLimit,
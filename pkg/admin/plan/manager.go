@@ -31,6 +31,7 @@ func (m *Manager) Create(ctx context.Context, uow unitofwork.UnitOfWork, req dto
 		BillingPeriod:            entity.BillingPeriod(req.BillingPeriod),
 		MaxNotebooks:             req.Features.MaxNotebooks,
 		MaxNotesPerNotebook:      req.Features.MaxNotesPerNotebook,
+		MaxStorageMB:             req.Features.MaxStorageMB,
 		SemanticSearchEnabled:    req.Features.SemanticSearch,
 		AiChatEnabled:            req.Features.AiChat,
 		AiChatDailyLimit:         req.Features.AiChatDailyLimit,
@@ -87,6 +88,7 @@ func (m *Manager) Update(ctx context.Context, uow unitofwork.UnitOfWork, id uuid
 	if req.Features != nil {
 		plan.MaxNotebooks = req.Features.MaxNotebooks
 		plan.MaxNotesPerNotebook = req.Features.MaxNotesPerNotebook
+		plan.MaxStorageMB = req.Features.MaxStorageMB
 		plan.SemanticSearchEnabled = req.Features.SemanticSearch
 		plan.AiChatEnabled = req.Features.AiChat
 		plan.AiChatDailyLimit = req.Features.AiChatDailyLimit
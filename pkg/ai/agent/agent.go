@@ -0,0 +1,347 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"ai-notetaking-be/internal/constant"
+	"ai-notetaking-be/internal/dto"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/lexical"
+	"ai-notetaking-be/pkg/llm"
+	"ai-notetaking-be/pkg/rag/search"
+	"ai-notetaking-be/pkg/store"
+
+	"github.com/google/uuid"
+)
+
+// ErrToolsUnsupported means the configured model provider cannot call tools
+var ErrToolsUnsupported = errors.New("the model provider does not support tool calling")
+
+const (
+	maxSteps         = 5    // Model calls that may request tools before the agent has to answer
+	maxToolCalls     = 10   // Tool calls per answer; later calls are refused
+	maxSearchResults = 10   // Notes returned by search_notes
+	maxNotebooks     = 100  // Notebooks returned by list_notebooks
+	maxNoteRunes     = 8000 // Content returned by read_note
+	maxExcerptRunes  = 300  // Excerpt of each search result
+	maxTitleRunes    = 255  // Title accepted by create_note, as for notes created in the editor
+)
+
+const systemPrompt = `You are an assistant working with the user's notes through tools.
+- Use search_notes to find notes and read_note to read them before answering from them. Base your answer on what the tools return and name the notes you used.
+- Use list_notebooks to find where a new note belongs.
+- create_note and append_to_note only propose a change; the user confirms it in the chat. Tell the user what you proposed and that it waits for their confirmation. Never claim the change is done.
+- Answer in the language the user writes in.`
+
+const finalPrompt = "You cannot call more tools. Answer the user now with what the tools returned so far."
+
+// fallbackReply is used when the model ends without any text
+const fallbackReply = "Maaf, saya belum dapat menyelesaikan permintaan ini. Silakan coba lagi dengan instruksi yang lebih spesifik."
+
+// Result is the answer of an agent turn and the tool calls made to write it
+type Result struct {
+	Reply     string
+	Citations []dto.CitationDTO // Notes read while answering
+	Runs      []llm.ToolRun     // Status is one of constant.ChatToolCallStatus*
+}
+
+// Agent answers in agent mode: the model calls typed tools over the user's notes in a bounded loop.
+// Read tools run right away under the user's access rules. Write tools are only checked and recorded
+// as pending; they change notes once the user confirms them.
+type Agent struct {
+	llmProvider        llm.LLMProvider
+	searchOrchestrator *search.Orchestrator
+	sessionRepo        store.SessionStore
+	logger             *log.Logger
+}
+
+// NewAgent creates an agent; llmProvider has to implement llm.ToolCaller for Run to succeed
+func NewAgent(llmProvider llm.LLMProvider, searchOrchestrator *search.Orchestrator, sessionRepo store.SessionStore, logger *log.Logger) *Agent {
+	return &Agent{
+		llmProvider:        llmProvider,
+		searchOrchestrator: searchOrchestrator,
+		sessionRepo:        sessionRepo,
+		logger:             logger,
+	}
+}
+
+// turn holds what a single Run needs while executing tools
+type turn struct {
+	*Agent
+	uow       unitofwork.UnitOfWork
+	userId    uuid.UUID
+	sessionId uuid.UUID
+	result    *Result
+	cited     map[uuid.UUID]bool
+}
+
+// Run answers query, letting the model call tools for at most maxSteps rounds
+func (a *Agent) Run(
+	ctx context.Context,
+	uow unitofwork.UnitOfWork,
+	userId uuid.UUID,
+	sessionId uuid.UUID,
+	query string,
+	history []llm.Message,
+) (*Result, error) {
+	caller, ok := a.llmProvider.(llm.ToolCaller)
+	if !ok {
+		return nil, ErrToolsUnsupported
+	}
+
+	messages := make([]llm.Message, 0, len(history)+2)
+	messages = append(messages, llm.Message{Role: "system", Content: systemPrompt})
+	messages = append(messages, history...)
+	messages = append(messages, llm.Message{Role: "user", Content: query})
+
+	t := &turn{
+		Agent:     a,
+		uow:       uow,
+		userId:    userId,
+		sessionId: sessionId,
+		result:    &Result{},
+		cited:     make(map[uuid.UUID]bool),
+	}
+	tools := Tools()
+
+	for step := 1; step <= maxSteps; step++ {
+		reply, err := caller.ChatWithTools(ctx, messages, tools)
+		if err != nil {
+			return nil, err
+		}
+		if len(reply.ToolCalls) == 0 {
+			t.result.Reply = replyText(reply)
+			return t.result, nil
+		}
+
+		a.logger.Printf("[AGENT] Step %d: %d tool calls", step, len(reply.ToolCalls))
+		messages = append(messages, *reply)
+		for _, call := range reply.ToolCalls {
+			messages = append(messages, llm.Message{
+				Role:       "tool",
+				Content:    t.call(ctx, call),
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	// Out of steps: the model answers from what it has gathered
+	a.logger.Printf("[AGENT] Step limit reached, asking for the final answer")
+	messages = append(messages, llm.Message{Role: "system", Content: finalPrompt})
+	reply, err := caller.ChatWithTools(ctx, messages, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.result.Reply = replyText(reply)
+	return t.result, nil
+}
+
+func replyText(reply *llm.Message) string {
+	if text := strings.TrimSpace(reply.Content); text != "" {
+		return text
+	}
+	return fallbackReply
+}
+
+// call executes a tool call, records it and returns what the model gets back
+func (t *turn) call(ctx context.Context, call llm.ToolCall) string {
+	output, status, err := t.execute(ctx, call)
+	if err != nil {
+		t.logger.Printf("[AGENT] Tool %s failed: %v", call.Name, err)
+		output, status = map[string]string{"error": err.Error()}, constant.ChatToolCallStatusFailed
+	}
+
+	result := encode(output)
+	t.result.Runs = append(t.result.Runs, llm.ToolRun{
+		Name:      call.Name,
+		Arguments: arguments(call.Arguments),
+		Result:    result,
+		Status:    status,
+	})
+	return result
+}
+
+// execute runs a read tool or checks a write tool, returning the value reported to the model
+func (t *turn) execute(ctx context.Context, call llm.ToolCall) (interface{}, string, error) {
+	if len(t.result.Runs) >= maxToolCalls {
+		return nil, "", fmt.Errorf("tool call limit of %d reached, answer with what you have", maxToolCalls)
+	}
+	done := constant.ChatToolCallStatusDone
+
+	switch call.Name {
+	case ToolSearchNotes:
+		args, err := decodeSearchNotes(call.Arguments)
+		if err != nil {
+			return nil, "", err
+		}
+		output, err := t.searchNotes(ctx, args)
+		return output, done, err
+
+	case ToolReadNote:
+		args, err := decodeReadNote(call.Arguments)
+		if err != nil {
+			return nil, "", err
+		}
+		output, err := t.readNote(ctx, args)
+		return output, done, err
+
+	case ToolListNotebooks:
+		output, err := t.listNotebooks(ctx)
+		return output, done, err
+
+	case ToolCreateNote:
+		args, err := DecodeCreateNote(call.Arguments)
+		if err != nil {
+			return nil, "", err
+		}
+		notebook, err := t.uow.NotebookRepository().FindOne(ctx,
+			specification.ByID{ID: args.NotebookId},
+			specification.NotebookAccessibleBy{UserID: t.userId},
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		if notebook == nil {
+			return nil, "", errors.New("notebook not found")
+		}
+		return awaitingConfirmation(fmt.Sprintf("Creating the note %q in the notebook %q", args.Title, notebook.Name)),
+			constant.ChatToolCallStatusPending, nil
+
+	case ToolAppendToNote:
+		args, err := DecodeAppendToNote(call.Arguments)
+		if err != nil {
+			return nil, "", err
+		}
+		note, err := t.uow.NoteRepository().FindOne(ctx,
+			specification.ByID{ID: args.NoteId},
+			specification.NoteAccessibleBy{UserID: t.userId},
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		if note == nil {
+			return nil, "", errors.New("note not found")
+		}
+		return awaitingConfirmation(fmt.Sprintf("Appending to the note %q", note.Title)),
+			constant.ChatToolCallStatusPending, nil
+	}
+
+	return nil, "", fmt.Errorf("unknown tool %q", call.Name)
+}
+
+func (t *turn) searchNotes(ctx context.Context, args *SearchNotesArgs) (interface{}, error) {
+	session, _ := t.sessionRepo.Get(ctx, t.sessionId.String())
+	documents, err := t.searchOrchestrator.Execute(ctx, t.uow, t.userId, args.Query, search.ConfigForSession(session))
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) > args.Limit {
+		documents = documents[:args.Limit]
+	}
+
+	type match struct {
+		NoteId  string `json:"note_id"`
+		Title   string `json:"title"`
+		Excerpt string `json:"excerpt"`
+	}
+	matches := make([]match, len(documents))
+	for i, doc := range documents {
+		matches[i] = match{
+			NoteId:  doc.ID,
+			Title:   doc.Title,
+			Excerpt: truncate(lexical.ParseContent(doc.Content), maxExcerptRunes),
+		}
+	}
+	return map[string]interface{}{"notes": matches}, nil
+}
+
+func (t *turn) readNote(ctx context.Context, args *ReadNoteArgs) (interface{}, error) {
+	note, err := t.uow.NoteRepository().FindOne(ctx,
+		specification.ByID{ID: args.NoteId},
+		specification.NoteAccessibleBy{UserID: t.userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, errors.New("note not found")
+	}
+
+	if !t.cited[note.Id] {
+		t.cited[note.Id] = true
+		t.result.Citations = append(t.result.Citations, dto.CitationDTO{NoteId: note.Id, Title: note.Title})
+	}
+
+	return map[string]interface{}{
+		"note_id":     note.Id,
+		"title":       note.Title,
+		"notebook_id": note.NotebookId,
+		"content":     truncate(lexical.ParseContent(note.Content), maxNoteRunes),
+	}, nil
+}
+
+func (t *turn) listNotebooks(ctx context.Context) (interface{}, error) {
+	notebooks, err := t.uow.NotebookRepository().FindAll(ctx,
+		specification.NotebookAccessibleBy{UserID: t.userId},
+		specification.OrderBy{Field: "name"},
+		specification.Pagination{Limit: maxNotebooks},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	type listed struct {
+		NotebookId uuid.UUID  `json:"notebook_id"`
+		Name       string     `json:"name"`
+		ParentId   *uuid.UUID `json:"parent_id,omitempty"`
+	}
+	out := make([]listed, len(notebooks))
+	for i, notebook := range notebooks {
+		out[i] = listed{NotebookId: notebook.Id, Name: notebook.Name, ParentId: notebook.ParentId}
+	}
+	return map[string]interface{}{"notebooks": out}, nil
+}
+
+// awaitingConfirmation is what the model learns about a proposed write
+func awaitingConfirmation(action string) map[string]string {
+	return map[string]string{
+		"status":  "awaiting_confirmation",
+		"message": action + " waits for the user to confirm it in the chat.",
+	}
+}
+
+// encode renders a tool result as JSON for the model and the chat history
+func encode(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error())
+	}
+	return string(b)
+}
+
+// arguments keeps the arguments as produced by the model, quoting them when they are not JSON
+func arguments(raw json.RawMessage) json.RawMessage {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" {
+		return json.RawMessage("{}")
+	}
+	if !json.Valid([]byte(trimmed)) {
+		quoted, _ := json.Marshal(trimmed)
+		return quoted
+	}
+	return json.RawMessage(trimmed)
+}
+
+func truncate(s string, maxRunes int) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) <= maxRunes {
+		return string(runes)
+	}
+	return string(runes[:maxRunes]) + "…"
+}
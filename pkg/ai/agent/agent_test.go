@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"ai-notetaking-be/internal/constant"
+	"ai-notetaking-be/pkg/llm"
+
+	"github.com/google/uuid"
+)
+
+// textModel is a provider without tool calling
+type textModel struct{}
+
+func (textModel) Chat(ctx context.Context, history []llm.Message, options ...llm.Option) (string, error) {
+	return "", errors.New("not used")
+}
+
+func (textModel) ChatStream(ctx context.Context, history []llm.Message, onDelta llm.StreamHandler, options ...llm.Option) (string, error) {
+	return "", errors.New("not used")
+}
+
+func (textModel) Generate(ctx context.Context, prompt string, options ...llm.Option) (string, error) {
+	return "", errors.New("not used")
+}
+
+// scriptedModel requests the same tool call until it is called without tools
+type scriptedModel struct {
+	textModel
+	calls     int
+	withTools int
+}
+
+func (m *scriptedModel) ChatWithTools(ctx context.Context, history []llm.Message, tools []llm.Tool, options ...llm.Option) (*llm.Message, error) {
+	m.calls++
+	if len(tools) == 0 {
+		return &llm.Message{Role: "assistant", Content: "final answer"}, nil
+	}
+	m.withTools++
+	return &llm.Message{Role: "assistant", ToolCalls: []llm.ToolCall{
+		{ID: "call", Name: "delete_everything", Arguments: json.RawMessage(`{"really": true}`)},
+	}}, nil
+}
+
+func TestRunIsBounded(t *testing.T) {
+	model := &scriptedModel{}
+	agent := NewAgent(model, nil, nil, log.New(io.Discard, "", 0))
+
+	result, err := agent.Run(context.Background(), nil, uuid.New(), uuid.New(), "clean up my notes", nil)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if model.withTools != maxSteps || model.calls != maxSteps+1 {
+		t.Errorf("model called %d times (%d with tools), want %d then a final answer", model.calls, model.withTools, maxSteps)
+	}
+	if result.Reply != "final answer" {
+		t.Errorf("reply = %q", result.Reply)
+	}
+	if len(result.Runs) != maxSteps {
+		t.Fatalf("recorded %d tool calls, want %d", len(result.Runs), maxSteps)
+	}
+	for _, run := range result.Runs {
+		if run.Status != constant.ChatToolCallStatusFailed {
+			t.Errorf("unknown tool recorded as %q", run.Status)
+		}
+	}
+}
+
+func TestRunRequiresToolCalling(t *testing.T) {
+	agent := NewAgent(textModel{}, nil, nil, log.New(io.Discard, "", 0))
+	if _, err := agent.Run(context.Background(), nil, uuid.New(), uuid.New(), "hi", nil); !errors.Is(err, ErrToolsUnsupported) {
+		t.Errorf("err = %v, want ErrToolsUnsupported", err)
+	}
+}
+
+func TestDecodeWriteArguments(t *testing.T) {
+	notebookId := uuid.New()
+	args, err := DecodeCreateNote(json.RawMessage(`{"notebook_id":"` + notebookId.String() + `","title":"  Plan  ","content":"- a"}`))
+	if err != nil {
+		t.Fatalf("valid arguments rejected: %v", err)
+	}
+	if args.NotebookId != notebookId || args.Title != "Plan" {
+		t.Errorf("decoded %+v", args)
+	}
+
+	for name, raw := range map[string]string{
+		"missing notebook": `{"title":"Plan"}`,
+		"blank title":      `{"notebook_id":"` + notebookId.String() + `","title":" "}`,
+		"bad id":           `{"notebook_id":"nope","title":"Plan"}`,
+	} {
+		if _, err := DecodeCreateNote(json.RawMessage(raw)); !errors.Is(err, errInvalidArguments) {
+			t.Errorf("%s: err = %v, want invalid arguments", name, err)
+		}
+	}
+
+	if _, err := DecodeAppendToNote(json.RawMessage(`{"note_id":"` + uuid.NewString() + `"}`)); !errors.Is(err, errInvalidArguments) {
+		t.Errorf("append without content: err = %v, want invalid arguments", err)
+	}
+	if got := arguments(json.RawMessage("not json")); string(got) != `"not json"` {
+		t.Errorf("malformed arguments stored as %s", got)
+	}
+}
@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ai-notetaking-be/pkg/llm"
+
+	"github.com/google/uuid"
+)
+
+// Tool names as the model sees them
+const (
+	ToolSearchNotes   = "search_notes"
+	ToolReadNote      = "read_note"
+	ToolListNotebooks = "list_notebooks"
+	ToolCreateNote    = "create_note"
+	ToolAppendToNote  = "append_to_note"
+)
+
+// IsWriteTool reports whether a tool changes the user's notes and therefore needs their confirmation
+func IsWriteTool(name string) bool {
+	return name == ToolCreateNote || name == ToolAppendToNote
+}
+
+// SearchNotesArgs are the arguments of search_notes
+type SearchNotesArgs struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+// ReadNoteArgs are the arguments of read_note
+type ReadNoteArgs struct {
+	NoteId uuid.UUID `json:"note_id"`
+}
+
+// CreateNoteArgs are the arguments of create_note; Content is Markdown
+type CreateNoteArgs struct {
+	NotebookId uuid.UUID `json:"notebook_id"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+}
+
+// AppendToNoteArgs are the arguments of append_to_note; Content is Markdown
+type AppendToNoteArgs struct {
+	NoteId  uuid.UUID `json:"note_id"`
+	Content string    `json:"content"`
+}
+
+// errInvalidArguments wraps argument problems reported back to the model
+var errInvalidArguments = errors.New("invalid arguments")
+
+// DecodeCreateNote parses and checks the arguments of a create_note call
+func DecodeCreateNote(raw json.RawMessage) (*CreateNoteArgs, error) {
+	var args CreateNoteArgs
+	if err := decode(raw, &args); err != nil {
+		return nil, err
+	}
+	args.Title = strings.TrimSpace(args.Title)
+	switch {
+	case args.NotebookId == uuid.Nil:
+		return nil, invalid("notebook_id is required")
+	case args.Title == "":
+		return nil, invalid("title is required")
+	case len([]rune(args.Title)) > maxTitleRunes:
+		return nil, invalid("title is too long")
+	}
+	return &args, nil
+}
+
+// DecodeAppendToNote parses and checks the arguments of an append_to_note call
+func DecodeAppendToNote(raw json.RawMessage) (*AppendToNoteArgs, error) {
+	var args AppendToNoteArgs
+	if err := decode(raw, &args); err != nil {
+		return nil, err
+	}
+	switch {
+	case args.NoteId == uuid.Nil:
+		return nil, invalid("note_id is required")
+	case strings.TrimSpace(args.Content) == "":
+		return nil, invalid("content is required")
+	}
+	return &args, nil
+}
+
+func decodeSearchNotes(raw json.RawMessage) (*SearchNotesArgs, error) {
+	var args SearchNotesArgs
+	if err := decode(raw, &args); err != nil {
+		return nil, err
+	}
+	args.Query = strings.TrimSpace(args.Query)
+	if args.Query == "" {
+		return nil, invalid("query is required")
+	}
+	if args.Limit <= 0 || args.Limit > maxSearchResults {
+		args.Limit = maxSearchResults
+	}
+	return &args, nil
+}
+
+func decodeReadNote(raw json.RawMessage) (*ReadNoteArgs, error) {
+	var args ReadNoteArgs
+	if err := decode(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.NoteId == uuid.Nil {
+		return nil, invalid("note_id is required")
+	}
+	return &args, nil
+}
+
+// decode reads a JSON object of arguments; models omit it entirely for tools without parameters
+func decode(raw json.RawMessage, v interface{}) error {
+	if len(strings.TrimSpace(string(raw))) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return invalid(err.Error())
+	}
+	return nil
+}
+
+func invalid(reason string) error {
+	return fmt.Errorf("%w: %s", errInvalidArguments, reason)
+}
+
+// Tools returns the definitions sent to the model
+func Tools() []llm.Tool {
+	return []llm.Tool{
+		{
+			Name:        ToolSearchNotes,
+			Description: "Search the user's notes by meaning. Returns the id, title and an excerpt of the best matches.",
+			Parameters: object(map[string]interface{}{
+				"query": property("string", "What to look for"),
+				"limit": property("integer", "Maximum number of notes to return (1-10)"),
+			}, "query"),
+		},
+		{
+			Name:        ToolReadNote,
+			Description: "Read the full content of a note by id.",
+			Parameters: object(map[string]interface{}{
+				"note_id": property("string", "Id of the note"),
+			}, "note_id"),
+		},
+		{
+			Name:        ToolListNotebooks,
+			Description: "List the notebooks the user can access, with their ids and parent notebooks.",
+			Parameters:  object(map[string]interface{}{}),
+		},
+		{
+			Name:        ToolCreateNote,
+			Description: "Propose a new note. The user has to confirm it before the note is created.",
+			Parameters: object(map[string]interface{}{
+				"notebook_id": property("string", "Id of the notebook to create the note in"),
+				"title":       property("string", "Title of the note"),
+				"content":     property("string", "Content of the note in Markdown"),
+			}, "notebook_id", "title", "content"),
+		},
+		{
+			Name:        ToolAppendToNote,
+			Description: "Propose adding text at the end of an existing note. The user has to confirm it before the note changes.",
+			Parameters: object(map[string]interface{}{
+				"note_id": property("string", "Id of the note"),
+				"content": property("string", "Text to append in Markdown"),
+			}, "note_id", "content"),
+		},
+	}
+}
+
+func object(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func property(kind, description string) map[string]interface{} {
+	return map[string]interface{}{"type": kind, "description": description}
+}
@@ -43,6 +43,18 @@ func (p *BypassPipeline) Execute(
 	history []llm.Message,
 	nuance *NuanceConfig,
 ) (*BypassResult, error) {
+	return p.ExecuteStream(ctx, query, history, nuance, nil)
+}
+
+// ExecuteStream runs the bypass pipeline and streams the LLM answer through onDelta.
+// A nil onDelta performs a regular blocking call.
+func (p *BypassPipeline) ExecuteStream(
+	ctx context.Context,
+	query string,
+	history []llm.Message,
+	nuance *NuanceConfig,
+	onDelta llm.StreamHandler,
+) (*BypassResult, error) {
 
 	var messages []llm.Message
 
@@ -74,10 +86,20 @@ func (p *BypassPipeline) Execute(
 	}
 
 	// Call LLM directly
-	response, err := p.llmProvider.Chat(ctx, messages, opts...)
+	var response string
+	var err error
+	if onDelta != nil {
+		response, err = p.llmProvider.ChatStream(ctx, messages, onDelta, opts...)
+	} else {
+		response, err = p.llmProvider.Chat(ctx, messages, opts...)
+	}
 	if err != nil {
 		p.logger.Printf("[BYPASS] LLM error: %v", err)
-		return nil, err
+		if response == "" {
+			return nil, err
+		}
+		// Stream aborted midway - keep the partial answer so it can be persisted
+		p.logger.Printf("[BYPASS] Stream aborted after %d characters", len(response))
 	}
 
 	p.logger.Printf("[BYPASS] Response generated successfully")
@@ -51,3 +51,26 @@ func (p *RAGPipeline) Execute(
 		SessionState: result.SessionState,
 	}, nil
 }
+
+// ExecuteStream runs the RAG pipeline, streaming the generated answer through onDelta
+func (p *RAGPipeline) ExecuteStream(
+	ctx context.Context,
+	userId uuid.UUID,
+	sessionId uuid.UUID,
+	query string,
+	history []llm.Message,
+	uow unitofwork.UnitOfWork,
+	onDelta llm.StreamHandler,
+) (*RAGResult, error) {
+
+	result, err := p.executor.ExecuteStream(ctx, userId, sessionId, query, history, uow, onDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RAGResult{
+		Reply:        result.Reply,
+		Citations:    result.Citations,
+		SessionState: result.SessionState,
+	}, nil
+}
@@ -9,6 +9,7 @@ const (
 	PrefixBypassNuance = "/bypass/nuance:" // Combined: bypass + nuance
 	PrefixBypass       = "/bypass"
 	PrefixNuance       = "/nuance:"
+	PrefixAgent        = "/agent"
 )
 
 // Mode represents the pipeline routing mode
@@ -19,13 +20,14 @@ const (
 	ModeBypass       Mode = "BYPASS"        // Pure LLM without RAG
 	ModeBypassNuance Mode = "BYPASS_NUANCE" // Pure LLM with nuance injection
 	ModeRAGNuance    Mode = "RAG_NUANCE"    // RAG with nuance injection
+	ModeAgent        Mode = "AGENT"         // LLM calling note tools in a bounded loop
 )
 
 // ParsedPrompt contains routing information extracted from prompt
 type ParsedPrompt struct {
 	OriginalPrompt string // Full original prompt
 	CleanPrompt    string // Prompt without prefix
-	Mode           Mode   // BYPASS, BYPASS_NUANCE, RAG_NUANCE, or RAG
+	Mode           Mode   // BYPASS, BYPASS_NUANCE, RAG_NUANCE, AGENT, or RAG
 	NuanceKey      string // If mode includes nuance, the nuance key (e.g., "engineering")
 }
 
@@ -34,6 +36,7 @@ type ParsedPrompt struct {
 //   - /bypass/nuance:key <prompt> → Bypass pipeline with nuance injection
 //   - /bypass <prompt> → Pure LLM mode without RAG
 //   - /nuance:key <prompt> → RAG mode with nuance injection
+//   - /agent <prompt> → Agent mode with note tools
 //   - <prompt> → Default RAG mode
 func Parse(prompt string) *ParsedPrompt {
 	trimmed := strings.TrimSpace(prompt)
@@ -82,7 +85,19 @@ func Parse(prompt string) *ParsedPrompt {
 		}
 	}
 
-	// 4. Default: RAG mode
+	// 4. Check for /agent (tool-calling agent)
+	if strings.HasPrefix(lower, PrefixAgent) {
+		rest := trimmed[len(PrefixAgent):]
+		if rest == "" || rest[0] == ' ' {
+			return &ParsedPrompt{
+				OriginalPrompt: prompt,
+				CleanPrompt:    strings.TrimSpace(rest),
+				Mode:           ModeAgent,
+			}
+		}
+	}
+
+	// 5. Default: RAG mode
 	return &ParsedPrompt{
 		OriginalPrompt: prompt,
 		CleanPrompt:    prompt,
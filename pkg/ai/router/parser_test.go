@@ -0,0 +1,23 @@
+package router
+
+import "testing"
+
+func TestParseAgent(t *testing.T) {
+	cases := []struct {
+		prompt string
+		mode   Mode
+		clean  string
+	}{
+		{"/agent summarize my meeting notes", ModeAgent, "summarize my meeting notes"},
+		{"  /Agent   find the budget note", ModeAgent, "find the budget note"},
+		{"/agent", ModeAgent, ""},
+		{"/agents are cool", ModeRAG, "/agents are cool"},
+		{"/bypass /agent hello", ModeBypass, "/agent hello"},
+	}
+	for _, c := range cases {
+		parsed := Parse(c.prompt)
+		if parsed.Mode != c.mode || parsed.CleanPrompt != c.clean {
+			t.Errorf("Parse(%q) = %s %q, want %s %q", c.prompt, parsed.Mode, parsed.CleanPrompt, c.mode, c.clean)
+		}
+	}
+}
@@ -102,7 +102,7 @@ func (r *ReferenceResolver) resolveByUUID(
 
 	return uow.NoteRepository().FindOne(ctx,
 		specification.ByID{ID: noteId},
-		specification.UserOwnedBy{UserID: userId},
+		specification.NoteAccessibleBy{UserID: userId},
 	)
 }
 
@@ -115,7 +115,7 @@ func (r *ReferenceResolver) resolveByTitle(
 ) (*entity.Note, error) {
 	// Try exact match first
 	notes, err := uow.NoteRepository().FindAll(ctx,
-		specification.UserOwnedBy{UserID: userId},
+		specification.NoteAccessibleBy{UserID: userId},
 		specification.ByNoteTitle{Title: title},
 	)
 	if err != nil {
@@ -138,7 +138,7 @@ func (r *ReferenceResolver) resolveByPartial(
 	uow unitofwork.UnitOfWork,
 ) (*entity.Note, error) {
 	notes, err := uow.NoteRepository().FindAll(ctx,
-		specification.UserOwnedBy{UserID: userId},
+		specification.NoteAccessibleBy{UserID: userId},
 		specification.NoteSearchQuery{Query: query},
 	)
 	if err != nil {
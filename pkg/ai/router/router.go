@@ -2,13 +2,16 @@ package router
 
 import (
 	"context"
+	"errors"
 	"log"
 
 	"ai-notetaking-be/internal/dto"
 	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/ai/agent"
 	"ai-notetaking-be/pkg/ai/pipeline"
 	"ai-notetaking-be/pkg/llm"
+	"ai-notetaking-be/pkg/rag/response"
 
 	"github.com/google/uuid"
 )
@@ -18,7 +21,8 @@ type ExecuteResult struct {
 	Reply     string
 	Citations []dto.CitationDTO
 	Mode      Mode
-	NuanceKey string // If nuance was applied, this is the key
+	NuanceKey string        // If nuance was applied, this is the key
+	ToolRuns  []llm.ToolRun // Tool calls made in agent mode
 }
 
 // NuanceResolver resolves nuance configurations from the database
@@ -30,6 +34,7 @@ type NuanceResolver interface {
 type Router struct {
 	ragPipeline    *pipeline.RAGPipeline
 	bypassPipeline *pipeline.BypassPipeline
+	agent          *agent.Agent
 	nuanceResolver NuanceResolver
 	logger         *log.Logger
 }
@@ -38,12 +43,14 @@ type Router struct {
 func NewRouter(
 	ragPipeline *pipeline.RAGPipeline,
 	bypassPipeline *pipeline.BypassPipeline,
+	agent *agent.Agent,
 	nuanceResolver NuanceResolver,
 	logger *log.Logger,
 ) *Router {
 	return &Router{
 		ragPipeline:    ragPipeline,
 		bypassPipeline: bypassPipeline,
+		agent:          agent,
 		nuanceResolver: nuanceResolver,
 		logger:         logger,
 	}
@@ -61,13 +68,44 @@ func (r *Router) Execute(
 	uow unitofwork.UnitOfWork,
 	sessionMode string, // Existing session mode (empty string if not set)
 ) (*ExecuteResult, error) {
+	return r.execute(ctx, userId, sessionId, prompt, history, uow, sessionMode, nil)
+}
+
+// ExecuteStream is the streaming counterpart of Execute.
+// Routing is identical; the selected pipeline pushes answer deltas through onDelta.
+func (r *Router) ExecuteStream(
+	ctx context.Context,
+	userId uuid.UUID,
+	sessionId uuid.UUID,
+	prompt string,
+	history []llm.Message,
+	uow unitofwork.UnitOfWork,
+	sessionMode string,
+	onDelta llm.StreamHandler,
+) (*ExecuteResult, error) {
+	return r.execute(ctx, userId, sessionId, prompt, history, uow, sessionMode, onDelta)
+}
+
+func (r *Router) execute(
+	ctx context.Context,
+	userId uuid.UUID,
+	sessionId uuid.UUID,
+	prompt string,
+	history []llm.Message,
+	uow unitofwork.UnitOfWork,
+	sessionMode string,
+	onDelta llm.StreamHandler,
+) (*ExecuteResult, error) {
 
 	// 1. Parse prompt for routing directives
 	parsed := Parse(prompt)
 
-	// 2. Determine effective mode (session mode takes precedence if already set)
+	// 2. Determine effective mode (session mode takes precedence if already set).
+	// Agent mode is never sticky: /agent applies to its own message, even in a locked session.
 	effectiveMode := parsed.Mode
-	if sessionMode == string(ModeBypass) {
+	if parsed.Mode == ModeAgent {
+		r.logger.Printf("[ROUTER] Agent requested, session mode %q left unchanged", sessionMode)
+	} else if sessionMode == string(ModeBypass) {
 		// Session is locked to bypass mode - override parsed mode
 		effectiveMode = ModeBypass
 		r.logger.Printf("[ROUTER] Session locked to BYPASS mode, using clean prompt")
@@ -100,7 +138,7 @@ func (r *Router) Execute(
 		r.logger.Printf("[ROUTER] Empty prompt after prefix extraction, treating as help request")
 		helpMsg := r.getHelpMessage(effectiveMode, parsed.NuanceKey)
 		return &ExecuteResult{
-			Reply:     helpMsg,
+			Reply:     response.Emit(onDelta, helpMsg),
 			Citations: nil,
 			Mode:      effectiveMode,
 		}, nil
@@ -124,11 +162,11 @@ func (r *Router) Execute(
 	// 4. Route based on effective mode
 	switch effectiveMode {
 	case ModeBypass:
-		return r.executeBypass(ctx, parsed.CleanPrompt, history, nil)
+		return r.executeBypass(ctx, parsed.CleanPrompt, history, nil, onDelta)
 
 	case ModeBypassNuance:
 		// Bypass + Nuance: Use bypass pipeline with nuance injection
-		result, err := r.executeBypass(ctx, parsed.CleanPrompt, history, nuanceConfig)
+		result, err := r.executeBypass(ctx, parsed.CleanPrompt, history, nuanceConfig, onDelta)
 		if err != nil {
 			return nil, err
 		}
@@ -139,15 +177,18 @@ func (r *Router) Execute(
 	case ModeRAGNuance:
 		// RAG + Nuance: Use RAG pipeline with nuance context
 		// TODO: Inject nuance into RAG response generation
-		result, err := r.executeRAG(ctx, userId, sessionId, parsed.CleanPrompt, history, uow, ModeRAGNuance)
+		result, err := r.executeRAG(ctx, userId, sessionId, parsed.CleanPrompt, history, uow, ModeRAGNuance, onDelta)
 		if err != nil {
 			return nil, err
 		}
 		result.NuanceKey = parsed.NuanceKey
 		return result, nil
 
+	case ModeAgent:
+		return r.executeAgent(ctx, userId, sessionId, parsed.CleanPrompt, history, uow, onDelta)
+
 	default: // ModeRAG
-		return r.executeRAG(ctx, userId, sessionId, parsed.CleanPrompt, history, uow, ModeRAG)
+		return r.executeRAG(ctx, userId, sessionId, parsed.CleanPrompt, history, uow, ModeRAG, onDelta)
 	}
 }
 
@@ -179,6 +220,7 @@ func (r *Router) executeBypass(
 	query string,
 	history []llm.Message,
 	nuance *pipeline.NuanceConfig,
+	onDelta llm.StreamHandler,
 ) (*ExecuteResult, error) {
 	if nuance != nil {
 		r.logger.Printf("[ROUTER] Executing BYPASS pipeline with nuance: %s", nuance.Key)
@@ -186,7 +228,7 @@ func (r *Router) executeBypass(
 		r.logger.Printf("[ROUTER] Executing BYPASS pipeline")
 	}
 
-	result, err := r.bypassPipeline.Execute(ctx, query, history, nuance)
+	result, err := r.bypassPipeline.ExecuteStream(ctx, query, history, nuance, onDelta)
 	if err != nil {
 		r.logger.Printf("[ROUTER] Bypass pipeline error: %v", err)
 		return nil, err
@@ -216,10 +258,11 @@ func (r *Router) executeRAG(
 	history []llm.Message,
 	uow unitofwork.UnitOfWork,
 	mode Mode,
+	onDelta llm.StreamHandler,
 ) (*ExecuteResult, error) {
 	r.logger.Printf("[ROUTER] Executing RAG pipeline")
 
-	result, err := r.ragPipeline.Execute(ctx, userId, sessionId, query, history, uow)
+	result, err := r.ragPipeline.ExecuteStream(ctx, userId, sessionId, query, history, uow, onDelta)
 	if err != nil {
 		r.logger.Printf("[ROUTER] RAG pipeline error: %v", err)
 		return nil, err
@@ -232,6 +275,39 @@ func (r *Router) executeRAG(
 	}, nil
 }
 
+// executeAgent runs the tool-calling agent. Its answer is emitted in one piece once the loop ends.
+func (r *Router) executeAgent(
+	ctx context.Context,
+	userId uuid.UUID,
+	sessionId uuid.UUID,
+	query string,
+	history []llm.Message,
+	uow unitofwork.UnitOfWork,
+	onDelta llm.StreamHandler,
+) (*ExecuteResult, error) {
+	r.logger.Printf("[ROUTER] Executing AGENT")
+
+	result, err := r.agent.Run(ctx, uow, userId, sessionId, query, history)
+	if errors.Is(err, agent.ErrToolsUnsupported) {
+		r.logger.Printf("[ROUTER] Agent unavailable: %v", err)
+		return &ExecuteResult{
+			Reply: response.Emit(onDelta, "Mode agent tidak tersedia untuk model AI yang sedang digunakan. Silakan kirim pertanyaan Anda tanpa /agent."),
+			Mode:  ModeAgent,
+		}, nil
+	}
+	if err != nil {
+		r.logger.Printf("[ROUTER] Agent error: %v", err)
+		return nil, err
+	}
+
+	return &ExecuteResult{
+		Reply:     response.Emit(onDelta, result.Reply),
+		Citations: result.Citations,
+		Mode:      ModeAgent,
+		ToolRuns:  result.Runs,
+	}, nil
+}
+
 // getHelpMessage returns a helpful message when user sends empty prompt with prefix
 func (r *Router) getHelpMessage(mode Mode, nuanceKey string) string {
 	switch mode {
@@ -241,6 +317,8 @@ func (r *Router) getHelpMessage(mode Mode, nuanceKey string) string {
 		return "Mode bypass+nuance '" + nuanceKey + "' diaktifkan. AI akan merespons dengan gaya khusus tanpa mengakses catatan.\n\nContoh: /bypass/nuance:engineering Jelaskan konsep ini."
 	case ModeRAGNuance:
 		return "Mode RAG+nuance '" + nuanceKey + "' diaktifkan. AI akan mencari catatan dan merespons dengan gaya khusus.\n\nContoh: /nuance:teacher Jelaskan materi ini."
+	case ModeAgent:
+		return "Mode agent: AI dapat mencari, membaca, dan membuat catatan untuk Anda. Perubahan pada catatan baru dijalankan setelah Anda mengonfirmasinya.\n\nContoh: /agent Rangkum catatan rapat minggu ini ke catatan baru."
 	default:
 		return "Silakan ketik pertanyaan Anda."
 	}
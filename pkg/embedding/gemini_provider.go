@@ -8,6 +8,8 @@ import (
 	"net/http"
 )
 
+const geminiEmbeddingModel = "text-embedding-004"
+
 type GeminiProvider struct {
 	ApiKey string
 }
@@ -18,9 +20,13 @@ func NewGeminiProvider(apiKey string) EmbeddingProvider {
 	}
 }
 
+func (p *GeminiProvider) ModelName() string {
+	return geminiEmbeddingModel
+}
+
 func (p *GeminiProvider) Generate(text string, taskType string) (*EmbeddingResponse, error) {
 	// Gemini Text-Embedding-004 logic (Ported from original GetGeminiEmbedding)
-	modelName := "text-embedding-004"
+	modelName := geminiEmbeddingModel
 
 	geminiReq := EmbeddingRequest{
 		Model: modelName,
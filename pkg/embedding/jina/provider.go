@@ -39,6 +39,10 @@ func NewJinaProvider(apiKey string) *JinaProvider {
 	}
 }
 
+func (p *JinaProvider) ModelName() string {
+	return p.model
+}
+
 func (p *JinaProvider) Generate(text string, taskType string) (*embedding.EmbeddingResponse, error) {
 	// Jina docs recommend array of inputs. We wrap single text.
 	reqBody := embeddingRequest{
@@ -38,6 +38,10 @@ type ollamaEmbeddingResponse struct {
 	Embedding []float64 `json:"embedding"` // Ollama returns float64 usually
 }
 
+func (p *OllamaProvider) ModelName() string {
+	return p.Model
+}
+
 func (p *OllamaProvider) Generate(text string, taskType string) (*EmbeddingResponse, error) {
 	// TaskType is ignored for Nomic/Ollama usually, but kept for interface compatibility
 
@@ -3,4 +3,6 @@ package embedding
 // EmbeddingProvider defines the interface for generating text embeddings
 type EmbeddingProvider interface {
 	Generate(text string, taskType string) (*EmbeddingResponse, error)
+	// ModelName identifies the embedding model so stored vectors can be traced to it
+	ModelName() string
 }
@@ -0,0 +1,346 @@
+// Package export writes notebooks and notes as a zip archive mirroring the notebook hierarchy
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ai-notetaking-be/pkg/lexical"
+
+	"github.com/google/uuid"
+)
+
+// Formats
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+)
+
+// ManifestFile sits at the archive root and maps every file back to its ids
+const ManifestFile = "manifest.json"
+
+// ManifestVersion is bumped when the manifest layout changes
+const ManifestVersion = 1
+
+const maxFileNameRunes = 100
+
+// ValidFormat reports whether format is one of the supported formats
+func ValidFormat(format string) bool {
+	return format == FormatMarkdown || format == FormatHTML || format == FormatJSON
+}
+
+type Notebook struct {
+	Id        uuid.UUID
+	Name      string
+	ParentId  *uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+type Note struct {
+	Id         uuid.UUID
+	Title      string
+	Content    string // Lexical JSON
+	NotebookId uuid.UUID
+	Tags       []string
+	CreatedAt  time.Time
+	UpdatedAt  *time.Time
+}
+
+// Archive is the content of one export. Notebooks whose parent is not part of the
+// archive are top-level folders.
+type Archive struct {
+	Format     string
+	Notebooks  []Notebook
+	Notes      []Note
+	ExportedAt time.Time
+}
+
+type Manifest struct {
+	Version    int                `json:"version"`
+	Format     string             `json:"format"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Notebooks  []ManifestNotebook `json:"notebooks"`
+	Notes      []ManifestNote     `json:"notes"`
+}
+
+type ManifestNotebook struct {
+	Id        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	ParentId  *uuid.UUID `json:"parent_id"`
+	Path      string     `json:"path"` // Folder in the archive
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ManifestNote struct {
+	Id         uuid.UUID  `json:"id"`
+	Title      string     `json:"title"`
+	NotebookId uuid.UUID  `json:"notebook_id"`
+	Path       string     `json:"path"` // File in the archive
+	Tags       []string   `json:"tags"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+}
+
+// noteFile is the document written for each note in the JSON format
+type noteFile struct {
+	Id         uuid.UUID       `json:"id"`
+	Title      string          `json:"title"`
+	NotebookId uuid.UUID       `json:"notebook_id"`
+	Tags       []string        `json:"tags"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  *time.Time      `json:"updated_at"`
+	Content    json.RawMessage `json:"content"` // Lexical JSON, or a JSON string for legacy plain-text notes
+}
+
+// Write streams the archive as a zip to w
+func (a *Archive) Write(w io.Writer) error {
+	manifest := a.Manifest()
+	zw := zip.NewWriter(w)
+
+	notesById := make(map[uuid.UUID]*Note, len(a.Notes))
+	for i := range a.Notes {
+		notesById[a.Notes[i].Id] = &a.Notes[i]
+	}
+
+	for _, nb := range manifest.Notebooks {
+		if _, err := zw.Create(nb.Path + "/"); err != nil {
+			return err
+		}
+	}
+	for _, entry := range manifest.Notes {
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     entry.Path,
+			Method:   zip.Deflate,
+			Modified: modifiedAt(entry.CreatedAt, entry.UpdatedAt),
+		})
+		if err != nil {
+			return err
+		}
+		body, err := a.render(notesById[entry.Id])
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(body); err != nil {
+			return err
+		}
+	}
+
+	f, err := zw.Create(ManifestFile)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Manifest lays out the archive: one folder per notebook (parents first, sibling names made
+// unique) and one file per note inside its notebook's folder.
+func (a *Archive) Manifest() *Manifest {
+	manifest := &Manifest{
+		Version:    ManifestVersion,
+		Format:     a.Format,
+		ExportedAt: a.ExportedAt,
+		Notebooks:  make([]ManifestNotebook, 0, len(a.Notebooks)),
+		Notes:      make([]ManifestNote, 0, len(a.Notes)),
+	}
+
+	inArchive := make(map[uuid.UUID]bool, len(a.Notebooks))
+	for _, nb := range a.Notebooks {
+		inArchive[nb.Id] = true
+	}
+	childrenOf := make(map[uuid.UUID][]Notebook)
+	var roots []Notebook
+	for _, nb := range a.Notebooks {
+		if nb.ParentId != nil && inArchive[*nb.ParentId] {
+			childrenOf[*nb.ParentId] = append(childrenOf[*nb.ParentId], nb)
+		} else {
+			roots = append(roots, nb)
+		}
+	}
+
+	folderOf := make(map[uuid.UUID]string, len(a.Notebooks))
+	var walk func(dir string, notebooks []Notebook)
+	walk = func(dir string, notebooks []Notebook) {
+		sortNotebooks(notebooks)
+		names := newNameSet()
+		for _, nb := range notebooks {
+			folder := path.Join(dir, names.unique(sanitizeName(nb.Name, "Untitled notebook"), ""))
+			folderOf[nb.Id] = folder
+			manifest.Notebooks = append(manifest.Notebooks, ManifestNotebook{
+				Id:        nb.Id,
+				Name:      nb.Name,
+				ParentId:  nb.ParentId,
+				Path:      folder,
+				CreatedAt: nb.CreatedAt,
+				UpdatedAt: nb.UpdatedAt,
+			})
+			walk(folder, childrenOf[nb.Id])
+		}
+	}
+	walk("", roots)
+
+	notes := append([]Note(nil), a.Notes...)
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].Title != notes[j].Title {
+			return notes[i].Title < notes[j].Title
+		}
+		return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+	})
+	namesIn := make(map[string]*nameSet)
+	for _, note := range notes {
+		dir, ok := folderOf[note.NotebookId]
+		if !ok {
+			continue // Notebook not exported
+		}
+		if namesIn[dir] == nil {
+			namesIn[dir] = newNameSet()
+		}
+		name := namesIn[dir].unique(sanitizeName(note.Title, "Untitled"), "."+a.Format)
+		tags := note.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		manifest.Notes = append(manifest.Notes, ManifestNote{
+			Id:         note.Id,
+			Title:      note.Title,
+			NotebookId: note.NotebookId,
+			Path:       path.Join(dir, name),
+			Tags:       tags,
+			CreatedAt:  note.CreatedAt,
+			UpdatedAt:  note.UpdatedAt,
+		})
+	}
+
+	return manifest
+}
+
+func (a *Archive) render(note *Note) ([]byte, error) {
+	switch a.Format {
+	case FormatHTML:
+		return []byte(renderHTMLDocument(note)), nil
+	case FormatJSON:
+		content := json.RawMessage(note.Content)
+		if !json.Valid(content) {
+			content, _ = json.Marshal(note.Content)
+		}
+		tags := note.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		return json.MarshalIndent(noteFile{
+			Id:         note.Id,
+			Title:      note.Title,
+			NotebookId: note.NotebookId,
+			Tags:       tags,
+			CreatedAt:  note.CreatedAt,
+			UpdatedAt:  note.UpdatedAt,
+			Content:    content,
+		}, "", "  ")
+	default:
+		return []byte(renderMarkdown(note)), nil
+	}
+}
+
+// renderMarkdown writes the note with a front matter (id, tags) that the vault import reads back
+func renderMarkdown(note *Note) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("id: " + note.Id.String() + "\n")
+	if len(note.Tags) > 0 {
+		quoted := make([]string, len(note.Tags))
+		for i, tag := range note.Tags {
+			quoted[i] = fmt.Sprintf("%q", tag)
+		}
+		sb.WriteString("tags: [" + strings.Join(quoted, ", ") + "]\n")
+	}
+	sb.WriteString("---\n\n")
+	sb.WriteString(strings.TrimSpace(lexical.ToMarkdown(note.Content)))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func renderHTMLDocument(note *Note) string {
+	title := html.EscapeString(note.Title)
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString(`<meta name="note-id" content="` + note.Id.String() + "\">\n")
+	if len(note.Tags) > 0 {
+		sb.WriteString(`<meta name="keywords" content="` + html.EscapeString(strings.Join(note.Tags, ", ")) + "\">\n")
+	}
+	sb.WriteString("<title>" + title + "</title>\n</head>\n<body>\n")
+	sb.WriteString("<h1>" + title + "</h1>\n")
+	sb.WriteString(lexical.RenderHTML(note.Content))
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+func modifiedAt(createdAt time.Time, updatedAt *time.Time) time.Time {
+	if updatedAt != nil {
+		return *updatedAt
+	}
+	return createdAt
+}
+
+func sortNotebooks(notebooks []Notebook) {
+	sort.SliceStable(notebooks, func(i, j int) bool {
+		if notebooks[i].Name != notebooks[j].Name {
+			return notebooks[i].Name < notebooks[j].Name
+		}
+		return notebooks[i].CreatedAt.Before(notebooks[j].CreatedAt)
+	})
+}
+
+// sanitizeName makes a name safe as a single path segment on common file systems
+func sanitizeName(name, fallback string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r < ' ' || r == 0x7f:
+			return -1
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '-'
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if utf8.RuneCountInString(name) > maxFileNameRunes {
+		name = strings.TrimSpace(string([]rune(name)[:maxFileNameRunes]))
+	}
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// nameSet hands out case-insensitively unique names within one folder
+type nameSet struct {
+	taken map[string]bool
+}
+
+func newNameSet() *nameSet {
+	return &nameSet{taken: make(map[string]bool)}
+}
+
+func (s *nameSet) unique(base, ext string) string {
+	name := base + ext
+	for i := 2; s.taken[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	s.taken[strings.ToLower(name)] = true
+	return name
+}
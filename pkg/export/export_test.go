@@ -0,0 +1,115 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"ai-notetaking-be/pkg/lexical"
+	"ai-notetaking-be/pkg/vault"
+
+	"github.com/google/uuid"
+)
+
+func testArchive(format string) (*Archive, uuid.UUID, uuid.UUID) {
+	root, child := uuid.New(), uuid.New()
+	outside := uuid.New()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	content := `{"root":{"type":"root","children":[{"type":"paragraph","children":[{"type":"text","text":"See [[Plan]] <now>"}]}]}}`
+	return &Archive{
+		Format:     format,
+		ExportedAt: now,
+		Notebooks: []Notebook{
+			{Id: root, Name: "Work: 2024", ParentId: &outside, CreatedAt: now},
+			{Id: child, Name: "Plan", ParentId: &root, CreatedAt: now},
+		},
+		Notes: []Note{
+			{Id: uuid.New(), Title: "Plan", Content: content, NotebookId: root, Tags: []string{"q2"}, CreatedAt: now},
+			{Id: uuid.New(), Title: "plan", Content: "legacy text", NotebookId: root, CreatedAt: now.Add(time.Hour)},
+			{Id: uuid.New(), Title: "", Content: content, NotebookId: child, CreatedAt: now},
+		},
+	}, root, child
+}
+
+func TestManifestPaths(t *testing.T) {
+	a, _, _ := testArchive(FormatMarkdown)
+	m := a.Manifest()
+
+	var folders, files []string
+	for _, nb := range m.Notebooks {
+		folders = append(folders, nb.Path)
+	}
+	for _, n := range m.Notes {
+		files = append(files, n.Path)
+	}
+	if got, want := strings.Join(folders, ","), "Work- 2024,Work- 2024/Plan"; got != want {
+		t.Errorf("folders = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(files, ","), "Work- 2024/Plan/Untitled.md,Work- 2024/Plan.md,Work- 2024/plan (2).md"; got != want {
+		t.Errorf("files = %s, want %s", got, want)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatMarkdown, FormatHTML, FormatJSON} {
+		a, _, _ := testArchive(format)
+		var buf bytes.Buffer
+		if err := a.Write(&buf); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		files := make(map[string]string)
+		for _, f := range zr.File {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			files[f.Name] = string(data)
+		}
+
+		var m Manifest
+		if err := json.Unmarshal([]byte(files[ManifestFile]), &m); err != nil || len(m.Notes) != 3 || m.Format != format {
+			t.Fatalf("%s: manifest = %+v (%v)", format, m, err)
+		}
+
+		plan := files["Work- 2024/Plan."+format]
+		switch format {
+		case FormatHTML:
+			if !strings.Contains(plan, "See [[Plan]] &lt;now&gt;") || !strings.Contains(plan, "<title>Plan</title>") {
+				t.Errorf("html = %s", plan)
+			}
+		case FormatJSON:
+			var doc map[string]interface{}
+			if err := json.Unmarshal([]byte(files["Work- 2024/plan (2).json"]), &doc); err != nil || doc["content"] != "legacy text" {
+				t.Errorf("json = %v (%v)", doc, err)
+			}
+		case FormatMarkdown:
+			// The Markdown archive reads back as a vault with the same tags
+			v, err := vault.Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "export.zip")
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, n := range v.Notes {
+				if n.Title == "Plan" && n.Folder == "" {
+					found = true
+					// Text is escaped in the file and reads back unchanged
+					text := lexical.ExtractText(lexical.FromMarkdown(n.Markdown))
+					if len(n.Tags) != 1 || n.Tags[0] != "q2" || !strings.Contains(text, "See [[Plan]] <now>") {
+						t.Errorf("round trip = %+v", n)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("notes = %+v", v.Notes)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package extract pulls plain text out of uploaded files (text and PDF) so it can be indexed
+package extract
+
+import (
+	"errors"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTextBytes caps the extracted text of one file
+const MaxTextBytes = 1 << 20
+
+var (
+	ErrUnsupported = errors.New("no text can be extracted from this file type")
+	ErrEncrypted   = errors.New("encrypted PDFs are not supported")
+	ErrInvalidPDF  = errors.New("file is not a valid PDF")
+)
+
+// Supported reports whether Text handles the content type
+func Supported(contentType string) bool {
+	mediaType := baseType(contentType)
+	return isText(mediaType) || mediaType == "application/pdf"
+}
+
+// Text returns the plain text of a file, dispatching on its content type.
+// The result is trimmed and truncated to MaxTextBytes.
+func Text(contentType string, data []byte) (string, error) {
+	mediaType := baseType(contentType)
+
+	var text string
+	switch {
+	case isText(mediaType):
+		text = plainText(data)
+	case mediaType == "application/pdf":
+		var err error
+		if text, err = pdfText(data); err != nil {
+			return "", err
+		}
+	default:
+		return "", ErrUnsupported
+	}
+
+	return truncate(strings.TrimSpace(text), MaxTextBytes), nil
+}
+
+func baseType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
+
+func isText(mediaType string) bool {
+	switch mediaType {
+	case "application/json", "application/xml", "application/x-yaml", "application/yaml":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "text/")
+}
+
+// plainText decodes UTF-8 text, dropping a byte order mark, NUL bytes and invalid sequences
+func plainText(data []byte) string {
+	s := strings.TrimPrefix(string(data), "\uFEFF")
+	s = strings.ReplaceAll(s, "\x00", "")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ToValidUTF8(s, "")
+}
+
+// truncate cuts s to at most n bytes without splitting a rune
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,103 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF writes a minimal PDF whose objects are the given stream dictionaries and bodies
+func buildPDF(t *testing.T, streams ...[2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	for i, s := range streams {
+		fmt.Fprintf(&buf, "%d 0 obj\n<< %s /Length %d >>\nstream\n%s\nendstream\nendobj\n", i+3, s[0], len(s[1]), s[1])
+	}
+	buf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func deflate(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestPDFText(t *testing.T) {
+	pdf := buildPDF(t,
+		[2]string{"", `BT /F1 12 Tf 72 712 Td (Hello \(PDF\) world) Tj 0 -14 Td [(Sec) 20 (ond) -300 (line)] TJ ET`},
+		[2]string{"/Filter /FlateDecode", deflate(t, "BT 1 0 0 1 72 600 Tm (Compressed) Tj 1 0 0 1 72 580 Tm <FEFF00480069> Tj ET")},
+		[2]string{"/Subtype /Image /Width 1 /Height 1", "(not text) Tj"},
+		[2]string{"/Filter /DCTDecode", "binary"},
+	)
+
+	got, err := Text("application/pdf", pdf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Hello (PDF) world\nSecond line\n\nCompressed\nHi" // Streams are separated by a blank line
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestPDFSkipsGlyphIds(t *testing.T) {
+	pdf := buildPDF(t, [2]string{"", "BT <0003000400050006> Tj (Readable) Tj ET"})
+	got, err := Text("application/pdf", pdf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Readable" {
+		t.Fatalf("got %q, want only the readable string", got)
+	}
+}
+
+func TestPDFErrors(t *testing.T) {
+	if _, err := Text("application/pdf", []byte("not a pdf")); !errors.Is(err, ErrInvalidPDF) {
+		t.Errorf("got %v, want ErrInvalidPDF", err)
+	}
+	encrypted := append(buildPDF(t), []byte("trailer << /Encrypt 9 0 R >>")...)
+	if _, err := Text("application/pdf", encrypted); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("got %v, want ErrEncrypted", err)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got, err := Text("text/markdown; charset=utf-8", []byte("\uFEFF# Title\r\nbody\x00 \xff\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "# Title\nbody" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	if Supported("image/png") {
+		t.Error("images should not be supported")
+	}
+	if !Supported("text/csv") || !Supported("application/pdf") {
+		t.Error("text and PDF should be supported")
+	}
+	if _, err := Text("image/png", []byte{0x89, 'P', 'N', 'G'}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("got %v, want ErrUnsupported", err)
+	}
+}
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	s := strings.Repeat("é", 10) // 2 bytes each
+	if got := truncate(s, 5); got != "éé" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,411 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// PDF extraction is deliberately small: it decodes the page content streams (uncompressed or FlateDecode)
+// and collects the strings shown by the text operators. Fonts are not interpreted, so text set in
+// fonts without a standard encoding (most CID/Type0 fonts) is skipped rather than returned as garbage.
+
+// maxStreamBytes caps one decompressed stream, guarding against zip bombs
+const maxStreamBytes = 16 << 20
+
+var (
+	streamKeyword = []byte("stream")
+	// Dictionaries of streams that never hold page text: images, fonts, metadata, object and xref streams
+	nonTextStream = regexp.MustCompile(`/Subtype\s*/(Image|XML|Type1C|CIDFontType0C|OpenType)\b|/Type\s*/(XRef|ObjStm|Metadata|EmbeddedFile)\b|/Length[123]\b|/N\s+\d`)
+	flateFilter   = regexp.MustCompile(`/Filter\s*\[?\s*/FlateDecode\s*\]?`)
+	anyFilter     = regexp.MustCompile(`/Filter\b`)
+)
+
+func pdfText(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\n\f\r "), []byte("%PDF-")) {
+		return "", ErrInvalidPDF
+	}
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return "", ErrEncrypted
+	}
+
+	var sb strings.Builder
+	for _, content := range contentStreams(data) {
+		sb.WriteString(showText(content))
+		sb.WriteByte('\n')
+		if sb.Len() > MaxTextBytes {
+			break
+		}
+	}
+	return tidyLines(sb.String()), nil
+}
+
+// contentStreams returns the decoded streams that may contain page content
+func contentStreams(data []byte) [][]byte {
+	var streams [][]byte
+	pos := 0
+	for {
+		i := bytes.Index(data[pos:], streamKeyword)
+		if i < 0 {
+			return streams
+		}
+		start := pos + i
+		pos = start + len(streamKeyword)
+
+		// Skip "endstream" and words merely containing "stream"
+		if start >= 3 && string(data[start-3:start]) == "end" {
+			continue
+		}
+		if pos < len(data) && data[pos] != '\r' && data[pos] != '\n' {
+			continue
+		}
+
+		dict := streamDictionary(data[:start])
+		if pos < len(data) && data[pos] == '\r' {
+			pos++
+		}
+		if pos < len(data) && data[pos] == '\n' {
+			pos++
+		}
+		end := bytes.Index(data[pos:], []byte("endstream"))
+		if end < 0 {
+			return streams
+		}
+		raw := data[pos : pos+end]
+		pos += end
+
+		if nonTextStream.Match(dict) {
+			continue
+		}
+		decoded, ok := decodeStream(dict, raw)
+		if !ok || bytes.Contains(decoded, []byte("begincmap")) {
+			continue
+		}
+		streams = append(streams, decoded)
+	}
+}
+
+// streamDictionary returns the bytes between the enclosing "obj" keyword and the stream
+func streamDictionary(before []byte) []byte {
+	if i := bytes.LastIndex(before, []byte("obj")); i >= 0 {
+		return before[i:]
+	}
+	return nil
+}
+
+func decodeStream(dict, raw []byte) ([]byte, bool) {
+	if !anyFilter.Match(dict) {
+		return raw, true
+	}
+	if !flateFilter.Match(dict) {
+		return nil, false // DCT, LZW, ... or a filter chain: not text we can read
+	}
+	r, err := zlib.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, false
+	}
+	defer r.Close()
+	decoded, err := io.ReadAll(io.LimitReader(r, maxStreamBytes))
+	if err != nil && len(decoded) == 0 {
+		return nil, false
+	}
+	return decoded, true
+}
+
+// operand is a string, a number or an array of both, as found before a text operator
+type operand struct {
+	str   []byte
+	isStr bool
+	num   float64
+	arr   []operand
+}
+
+// showText runs the content stream's text operators and returns what they show
+func showText(content []byte) string {
+	var sb strings.Builder
+	var operands []operand
+	var array []operand
+	inArray := false
+	lastY, haveY := 0.0, false
+
+	push := func(op operand) {
+		if inArray {
+			array = append(array, op)
+		} else {
+			operands = append(operands, op)
+		}
+	}
+	emit := func(s []byte) {
+		sb.WriteString(decodePDFString(s))
+	}
+	newline := func() {
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteByte('\n')
+		}
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case isPDFSpace(c):
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			s, next := literalString(content, i)
+			push(operand{str: s, isStr: true})
+			i = next
+		case c == '<' && i+1 < len(content) && content[i+1] == '<':
+			i += 2
+		case c == '>' && i+1 < len(content) && content[i+1] == '>':
+			i += 2
+		case c == '<':
+			s, next := hexString(content, i)
+			push(operand{str: s, isStr: true})
+			i = next
+		case c == '[':
+			inArray, array = true, nil
+			i++
+		case c == ']':
+			inArray = false
+			operands = append(operands, operand{arr: array})
+			i++
+		case c == '/':
+			i++
+			for i < len(content) && !isPDFSpace(content[i]) && !isPDFDelimiter(content[i]) {
+				i++
+			}
+			push(operand{})
+		default:
+			j := i
+			for j < len(content) && !isPDFSpace(content[j]) && !isPDFDelimiter(content[j]) {
+				j++
+			}
+			if j == i {
+				i++ // Stray delimiter
+				continue
+			}
+			word := string(content[i:j])
+			i = j
+
+			if n, err := strconv.ParseFloat(word, 64); err == nil {
+				push(operand{num: n})
+				continue
+			}
+
+			switch word {
+			case "Tj":
+				if s := lastString(operands); s != nil {
+					emit(s)
+				}
+			case "'", "\"":
+				newline()
+				if s := lastString(operands); s != nil {
+					emit(s)
+				}
+			case "TJ":
+				if len(operands) > 0 {
+					for _, item := range operands[len(operands)-1].arr {
+						if item.isStr {
+							emit(item.str)
+						} else if item.num < -250 {
+							sb.WriteByte(' ') // Large negative kerning separates words
+						}
+					}
+				}
+			case "T*":
+				newline()
+			case "Td", "TD":
+				if len(operands) >= 2 && operands[len(operands)-1].num != 0 {
+					newline()
+				} else {
+					sb.WriteByte(' ')
+				}
+			case "Tm":
+				if len(operands) >= 6 {
+					y := operands[len(operands)-1].num
+					if haveY && y != lastY {
+						newline()
+					} else {
+						sb.WriteByte(' ')
+					}
+					lastY, haveY = y, true
+				}
+			case "ET":
+				newline()
+			case "ID":
+				// Inline image data runs until EI
+				if end := bytes.Index(content[i:], []byte("EI")); end >= 0 {
+					i += end + 2
+				} else {
+					i = len(content)
+				}
+			}
+			operands = operands[:0]
+		}
+	}
+	return sb.String()
+}
+
+func lastString(operands []operand) []byte {
+	if len(operands) == 0 || !operands[len(operands)-1].isStr {
+		return nil
+	}
+	return operands[len(operands)-1].str
+}
+
+// literalString parses a (...) string starting at content[start], handling escapes and nested parentheses
+func literalString(content []byte, start int) ([]byte, int) {
+	var out []byte
+	depth := 0
+	i := start + 1
+	for ; i < len(content); i++ {
+		c := content[i]
+		switch c {
+		case '\\':
+			i++
+			if i >= len(content) {
+				return out, i
+			}
+			switch e := content[i]; e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if i+1 < len(content) && content[i+1] == '\n' {
+					i++
+				}
+			case '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					for k := 0; k < 3 && i < len(content) && content[i] >= '0' && content[i] <= '7'; k++ {
+						v = v*8 + int(content[i]-'0')
+						i++
+					}
+					i--
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			if depth == 0 {
+				return out, i + 1
+			}
+			depth--
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out, i
+}
+
+// hexString parses a <...> string starting at content[start]
+func hexString(content []byte, start int) ([]byte, int) {
+	var digits []byte
+	i := start + 1
+	for ; i < len(content) && content[i] != '>'; i++ {
+		if c := content[i]; (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for k := range out {
+		v, _ := strconv.ParseUint(string(digits[2*k:2*k+2]), 16, 8)
+		out[k] = byte(v)
+	}
+	return out, i + 1
+}
+
+// decodePDFString reads UTF-16BE (with BOM) or single-byte text, dropping strings that
+// are mostly unprintable (glyph ids of fonts without a standard encoding)
+func decodePDFString(s []byte) string {
+	var runes []rune
+	if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+		units := make([]uint16, 0, len(s)/2)
+		for k := 2; k+1 < len(s); k += 2 {
+			units = append(units, uint16(s[k])<<8|uint16(s[k+1]))
+		}
+		runes = utf16.Decode(units)
+	} else {
+		runes = make([]rune, len(s))
+		for k, b := range s {
+			runes[k] = rune(b) // PDFDocEncoding matches Latin-1 for printable text
+		}
+	}
+
+	printable := 0
+	for _, r := range runes {
+		if unicode.IsPrint(r) || unicode.IsSpace(r) {
+			printable++
+		}
+	}
+	if len(runes) == 0 || printable*2 < len(runes) {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, r := range runes {
+		if unicode.IsPrint(r) || r == '\n' || r == '\t' {
+			sb.WriteRune(r)
+		} else if unicode.IsSpace(r) {
+			sb.WriteByte(' ')
+		}
+	}
+	return sb.String()
+}
+
+// tidyLines collapses runs of spaces, trims lines and keeps at most one blank line in a row
+func tidyLines(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		out = append(out, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,426 @@
+package lexical
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// Default chunking parameters (in characters), used when nothing is configured
+const (
+	DefaultChunkSize    = 1500 // approx 375 tokens - safe for embedding context limits
+	DefaultChunkOverlap = 200
+)
+
+// Chunk is a semantically coherent piece of a note, ready to be embedded
+type Chunk struct {
+	Text        string
+	HeadingPath []string // Enclosing headings, outermost first
+}
+
+// HeadingPathString joins the heading path for display ("Setup > Install")
+func (c Chunk) HeadingPathString() string {
+	return strings.Join(c.HeadingPath, " > ")
+}
+
+// Chunker splits note content along its structure (headings, paragraphs, lists, tables, code)
+// instead of cutting at a fixed character offset
+type Chunker struct {
+	size    int
+	overlap int
+	parser  *Parser
+}
+
+// NewChunker creates a chunker. Non-positive size falls back to DefaultChunkSize;
+// overlap is clamped so that every chunk still makes progress.
+func NewChunker(size, overlap int) *Chunker {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	if overlap < 0 {
+		overlap = 0
+	}
+	if overlap >= size {
+		overlap = size / 4
+	}
+	return &Chunker{
+		size:    size,
+		overlap: overlap,
+		parser:  NewParser(),
+	}
+}
+
+// Chunk splits content into chunks. Lexical JSON is walked node by node;
+// anything else is treated as Markdown / plain text.
+func (c *Chunker) Chunk(content string) []Chunk {
+	return c.pack(documentBlocks(c.parser, content))
+}
+
+// block is a structural unit of the note (heading, paragraph, list, table, code)
+type block struct {
+	path   []string
+	level  int      // > 0 for a heading: structure only, its title is already part of path
+	header string   // Repeated at the top of every piece when the block is split (table header)
+	units  []string // Smallest pieces the block may be split into (list items, table rows, code lines)
+}
+
+func (b block) text() string {
+	return b.header + strings.Join(b.units, "\n")
+}
+
+// documentBlocks splits content into blocks. Lexical JSON is walked node by node;
+// anything else is treated as Markdown / plain text.
+func documentBlocks(p *Parser, content string) []block {
+	if blocks, ok := lexicalBlocks(p, content); ok {
+		return blocks
+	}
+	return markdownBlocks(content)
+}
+
+// ============================================================================
+// Lexical
+// ============================================================================
+
+func lexicalBlocks(p *Parser, content string) ([]block, bool) {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, `{"root":`) {
+		return nil, false
+	}
+
+	var root LexicalRoot
+	if err := json.Unmarshal([]byte(trimmed), &root); err != nil {
+		return nil, false
+	}
+
+	var blocks []block
+	var headings headingStack
+
+	for _, node := range root.Root.Children {
+		switch node.Type {
+		case "heading":
+			level, title := headingLevel(node.Tag), strings.TrimSpace(plainText(node))
+			headings.push(level, title)
+			if title != "" {
+				blocks = append(blocks, block{path: headings.path(), level: level, units: []string{title}})
+			}
+
+		case "list":
+			var sb strings.Builder
+			p.handleList(node, &sb, 0)
+			if units := groupListLines(sb.String()); len(units) > 0 {
+				blocks = append(blocks, block{path: headings.path(), units: units})
+			}
+
+		case "table":
+			var sb strings.Builder
+			p.handleTable(node, &sb)
+			if b, ok := tableBlock(sb.String()); ok {
+				b.path = headings.path()
+				blocks = append(blocks, b)
+			}
+
+		case "code":
+			if text := strings.TrimRight(codeText(node), "\n"); strings.TrimSpace(text) != "" {
+				blocks = append(blocks, block{path: headings.path(), units: strings.Split(text, "\n")})
+			}
+
+		case "horizontalrule":
+			// Visual separator only
+
+		default:
+			var sb strings.Builder
+			p.walkNode(node, &sb, 0)
+			if text := strings.TrimSpace(sb.String()); text != "" {
+				blocks = append(blocks, block{path: headings.path(), units: []string{text}})
+			}
+		}
+	}
+
+	return blocks, true
+}
+
+// plainText concatenates the text of a node without formatting markers
+func plainText(node Node) string {
+	var sb strings.Builder
+	var walk func(n Node)
+	walk = func(n Node) {
+		sb.WriteString(n.Text)
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return sb.String()
+}
+
+// codeText renders a code node, keeping its line breaks
+func codeText(node Node) string {
+	var sb strings.Builder
+	var walk func(n Node)
+	walk = func(n Node) {
+		if n.Type == "linebreak" {
+			sb.WriteString("\n")
+			return
+		}
+		sb.WriteString(n.Text)
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return sb.String()
+}
+
+func headingLevel(tag string) int {
+	if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+		return int(tag[1] - '0')
+	}
+	return 1
+}
+
+// groupListLines splits rendered list markdown into one unit per top-level item
+// (nested items stay with their parent)
+func groupListLines(rendered string) []string {
+	var units []string
+	for _, line := range strings.Split(rendered, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(units) > 0 && strings.HasPrefix(line, " ") {
+			units[len(units)-1] += "\n" + line
+			continue
+		}
+		units = append(units, line)
+	}
+	return units
+}
+
+// tableBlock turns a rendered markdown table into a block whose header row is repeated on split
+func tableBlock(rendered string) (block, bool) {
+	var lines []string
+	for _, line := range strings.Split(rendered, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.Join(strings.Fields(line), " ")) // Cells keep padding from paragraph newlines
+		}
+	}
+	if len(lines) == 0 {
+		return block{}, false
+	}
+	if len(lines) >= 2 && isTableSeparator(lines[1]) {
+		if len(lines) == 2 {
+			return block{units: lines}, true
+		}
+		return block{header: lines[0] + "\n" + lines[1] + "\n", units: lines[2:]}, true
+	}
+	return block{units: lines}, true
+}
+
+func isTableSeparator(line string) bool {
+	trimmed := strings.Trim(strings.TrimSpace(line), "|")
+	return trimmed != "" && strings.Trim(trimmed, "-|: ") == ""
+}
+
+// headingStack tracks the current heading path while walking the document
+type headingStack struct {
+	levels []int
+	titles []string
+}
+
+func (h *headingStack) push(level int, title string) {
+	for len(h.levels) > 0 && h.levels[len(h.levels)-1] >= level {
+		h.levels = h.levels[:len(h.levels)-1]
+		h.titles = h.titles[:len(h.titles)-1]
+	}
+	if title == "" {
+		return
+	}
+	h.levels = append(h.levels, level)
+	h.titles = append(h.titles, title)
+}
+
+func (h *headingStack) path() []string {
+	if len(h.titles) == 0 {
+		return nil
+	}
+	return append([]string(nil), h.titles...)
+}
+
+// ============================================================================
+// Packing
+// ============================================================================
+
+// pack merges consecutive blocks of the same section into chunks of at most size characters.
+// A heading change always starts a new chunk so the heading path stays accurate.
+func (c *Chunker) pack(blocks []block) []Chunk {
+	var chunks []Chunk
+	var parts []string
+	var path []string
+	length := 0
+
+	emit := func() {
+		if len(parts) == 0 {
+			return
+		}
+		chunks = append(chunks, Chunk{Text: strings.Join(parts, "\n\n"), HeadingPath: path})
+		parts = nil
+		length = 0
+	}
+
+	add := func(text string) {
+		textLen := utf8.RuneCountInString(text)
+		if len(parts) > 0 && length+2+textLen > c.size {
+			prev := strings.Join(parts, "\n\n")
+			emit()
+			// Carry the end of the previous chunk over, unless it would not leave room
+			if tail := tailText(prev, c.overlap); tail != "" && utf8.RuneCountInString(tail)+2+textLen <= c.size {
+				parts = append(parts, tail)
+				length = utf8.RuneCountInString(tail)
+			}
+		}
+		if len(parts) > 0 {
+			length += 2
+		}
+		parts = append(parts, text)
+		length += textLen
+	}
+
+	first := true
+	for _, b := range blocks {
+		if b.level > 0 {
+			continue
+		}
+		if first || !samePath(b.path, path) {
+			first = false
+			emit()
+			path = b.path
+		}
+
+		text := b.text()
+		if utf8.RuneCountInString(text) <= c.size {
+			add(text)
+			continue
+		}
+
+		// Oversized block: cut along its units; each piece becomes its own chunk
+		emit()
+		for _, piece := range c.splitBlock(b) {
+			add(piece)
+			emit()
+		}
+	}
+	emit()
+
+	return chunks
+}
+
+// splitBlock cuts an oversized block at unit boundaries, repeating the header on every piece.
+// Units that are too long on their own are split at word boundaries.
+func (c *Chunker) splitBlock(b block) []string {
+	budget := c.size - utf8.RuneCountInString(b.header)
+	if budget < c.size/4 {
+		// Header too large to repeat meaningfully
+		budget = c.size
+		b = block{units: append([]string{strings.TrimRight(b.header, "\n")}, b.units...)}
+	}
+
+	var pieces []string
+	var current []string
+	length := 0
+
+	flush := func() {
+		if len(current) > 0 {
+			pieces = append(pieces, b.header+strings.Join(current, "\n"))
+			current = nil
+			length = 0
+		}
+	}
+
+	for _, unit := range b.units {
+		unitLen := utf8.RuneCountInString(unit)
+		if unitLen > budget {
+			flush()
+			for _, part := range splitLong(unit, budget, c.overlap) {
+				pieces = append(pieces, b.header+part)
+			}
+			continue
+		}
+		if len(current) > 0 && length+1+unitLen > budget {
+			flush()
+		}
+		if len(current) > 0 {
+			length++
+		}
+		current = append(current, unit)
+		length += unitLen
+	}
+	flush()
+
+	return pieces
+}
+
+// splitLong cuts text into pieces of at most size characters with the given overlap,
+// preferring to break at whitespace
+func splitLong(text string, size, overlap int) []string {
+	runes := []rune(text)
+	if len(runes) <= size {
+		return []string{text}
+	}
+
+	var pieces []string
+	start := 0
+	for start < len(runes) {
+		end := start + size
+		if end >= len(runes) {
+			pieces = append(pieces, strings.TrimSpace(string(runes[start:])))
+			break
+		}
+
+		// Back off to the last whitespace in the second half of the window
+		cut := end
+		for i := end; i > start+size/2; i-- {
+			if runes[i] == ' ' || runes[i] == '\n' {
+				cut = i
+				break
+			}
+		}
+		pieces = append(pieces, strings.TrimSpace(string(runes[start:cut])))
+
+		next := cut - overlap
+		if next <= start {
+			next = cut
+		}
+		start = next
+	}
+
+	return pieces
+}
+
+// tailText returns roughly the last n characters of text, starting at a word boundary
+func tailText(text string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	tail := runes[len(runes)-n:]
+	for i, r := range tail {
+		if r == ' ' || r == '\n' {
+			return strings.TrimSpace(string(tail[i:]))
+		}
+	}
+	return strings.TrimSpace(string(tail))
+}
+
+func samePath(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
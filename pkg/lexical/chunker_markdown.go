@@ -0,0 +1,95 @@
+package lexical
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mdHeadingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdListItemRe = regexp.MustCompile(`^([-*+]|\d+[.)])\s+`)
+)
+
+// markdownBlocks splits Markdown / plain text into blocks.
+// Plain text without any markup degrades to one block per paragraph (blank-line separated).
+func markdownBlocks(content string) []block {
+	var blocks []block
+	var headings headingStack
+
+	var kind string // "", "paragraph", "list", "table"
+	var lines []string
+
+	flush := func() {
+		if len(lines) == 0 {
+			kind = ""
+			return
+		}
+		switch kind {
+		case "list":
+			blocks = append(blocks, block{path: headings.path(), units: groupListLines(strings.Join(lines, "\n"))})
+		case "table":
+			if b, ok := tableBlock(strings.Join(lines, "\n")); ok {
+				b.path = headings.path()
+				blocks = append(blocks, b)
+			}
+		default:
+			blocks = append(blocks, block{path: headings.path(), units: []string{strings.Join(lines, "\n")}})
+		}
+		kind = ""
+		lines = nil
+	}
+
+	all := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(all); i++ {
+		line := all[i]
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code: keep the whole fence together, one unit per line
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			fence := trimmed[:3]
+			code := []string{line}
+			for i++; i < len(all); i++ {
+				code = append(code, all[i])
+				if strings.HasPrefix(strings.TrimSpace(all[i]), fence) {
+					break
+				}
+			}
+			blocks = append(blocks, block{path: headings.path(), units: code})
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if m := mdHeadingRe.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			headings.push(len(m[1]), m[2])
+			if m[2] != "" {
+				blocks = append(blocks, block{path: headings.path(), level: len(m[1]), units: []string{m[2]}})
+			}
+			continue
+		}
+
+		lineKind := "paragraph"
+		switch {
+		case strings.HasPrefix(trimmed, "|"):
+			lineKind = "table"
+		case mdListItemRe.MatchString(trimmed):
+			lineKind = "list"
+		case kind == "list" && strings.HasPrefix(line, " "):
+			lineKind = "list" // Continuation / nested item
+		}
+
+		if kind != "" && kind != lineKind {
+			flush()
+		}
+		kind = lineKind
+		lines = append(lines, line)
+	}
+	flush()
+
+	return blocks
+}
@@ -0,0 +1,124 @@
+package lexical
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const chunkerTestDoc = `{"root":{"type":"root","children":[
+	{"type":"heading","tag":"h1","children":[{"type":"text","text":"Project"}]},
+	{"type":"paragraph","children":[{"type":"text","text":"Overview of the project."}]},
+	{"type":"heading","tag":"h2","children":[{"type":"text","text":"Setup"}]},
+	{"type":"list","listType":"bullet","children":[
+		{"type":"listitem","children":[{"type":"text","text":"Install Go"}]},
+		{"type":"listitem","children":[{"type":"text","text":"Run migrations"}]}
+	]},
+	{"type":"heading","tag":"h2","children":[{"type":"text","text":"Data"}]},
+	{"type":"table","children":[
+		{"type":"tablerow","children":[
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"text","text":"Name"}]}]},
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"text","text":"Value"}]}]}
+		]},
+		{"type":"tablerow","children":[
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"text","text":"alpha"}]}]},
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"text","text":"1"}]}]}
+		]},
+		{"type":"tablerow","children":[
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"text","text":"beta"}]}]},
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"text","text":"2"}]}]}
+		]}
+	]},
+	{"type":"code","children":[
+		{"type":"code-highlight","text":"func main() {"},
+		{"type":"linebreak"},
+		{"type":"code-highlight","text":"}"}
+	]}
+]}}`
+
+func TestChunkerLexicalHeadingPaths(t *testing.T) {
+	chunks := NewChunker(1000, 0).Chunk(chunkerTestDoc)
+
+	want := []string{"Project", "Project > Setup", "Project > Data"}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(chunks), len(want), chunks)
+	}
+	for i, w := range want {
+		if got := chunks[i].HeadingPathString(); got != w {
+			t.Errorf("chunk %d path = %q, want %q", i, got, w)
+		}
+	}
+
+	if !strings.Contains(chunks[1].Text, "- Install Go") || !strings.Contains(chunks[1].Text, "- Run migrations") {
+		t.Errorf("list chunk missing items: %q", chunks[1].Text)
+	}
+	if !strings.Contains(chunks[2].Text, "| alpha | 1 |") || !strings.Contains(chunks[2].Text, "func main() {\n}") {
+		t.Errorf("data chunk missing table or code: %q", chunks[2].Text)
+	}
+}
+
+func TestChunkerSplitsTableAtRowsWithHeader(t *testing.T) {
+	chunks := NewChunker(50, 0).Chunk(chunkerTestDoc)
+
+	var tableChunks []Chunk
+	for _, c := range chunks {
+		if strings.Contains(c.Text, "alpha") || strings.Contains(c.Text, "beta") {
+			tableChunks = append(tableChunks, c)
+		}
+	}
+	if len(tableChunks) != 2 {
+		t.Fatalf("expected table split into 2 chunks, got %d: %+v", len(tableChunks), tableChunks)
+	}
+	for _, c := range tableChunks {
+		if !strings.HasPrefix(c.Text, "| Name | Value |\n|---|---|\n") {
+			t.Errorf("split table chunk lost its header: %q", c.Text)
+		}
+	}
+}
+
+func TestChunkerMarkdownFallback(t *testing.T) {
+	content := "# Guide\n\nIntro text.\n\n## Steps\n\n1. First\n2. Second\n   continued\n\n```\ncode line\n```\n"
+	chunks := NewChunker(1000, 0).Chunk(content)
+
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2: %+v", len(chunks), chunks)
+	}
+	if chunks[0].HeadingPathString() != "Guide" || chunks[0].Text != "Intro text." {
+		t.Errorf("unexpected first chunk: %+v", chunks[0])
+	}
+	if chunks[1].HeadingPathString() != "Guide > Steps" {
+		t.Errorf("unexpected second path: %q", chunks[1].HeadingPathString())
+	}
+	if !strings.Contains(chunks[1].Text, "2. Second\n   continued") || !strings.Contains(chunks[1].Text, "```\ncode line\n```") {
+		t.Errorf("unexpected second chunk: %q", chunks[1].Text)
+	}
+}
+
+func TestChunkerRespectsSizeAndOverlap(t *testing.T) {
+	words := make([]string, 400)
+	for i := range words {
+		words[i] = "word"
+	}
+	content := strings.Join(words, " ")
+
+	chunks := NewChunker(200, 50).Chunk(content)
+	if len(chunks) < 2 {
+		t.Fatalf("expected plain text to be split, got %d chunks", len(chunks))
+	}
+	for i, c := range chunks {
+		if n := utf8.RuneCountInString(c.Text); n > 200 {
+			t.Errorf("chunk %d has %d chars, exceeds size 200", i, n)
+		}
+		if strings.HasPrefix(c.Text, " ") || strings.HasSuffix(c.Text, " ") {
+			t.Errorf("chunk %d not trimmed: %q", i, c.Text)
+		}
+	}
+
+	total := 0
+	for _, c := range chunks {
+		total += utf8.RuneCountInString(c.Text)
+	}
+	if total <= len(content) {
+		t.Errorf("expected overlapping chunks, total %d <= content %d", total, len(content))
+	}
+}
@@ -0,0 +1,164 @@
+package lexical
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells bounds the LCS table; larger changes degrade to "everything in between replaced"
+const maxDiffCells = 1 << 22
+
+// Diff is a structural comparison of two documents
+type Diff struct {
+	Markdown string // Changes grouped by section, as ```diff fences under the heading path
+	Added    int    // Units (paragraphs, list items, table rows, code lines, headings) only in the new version
+	Removed  int    // Units only in the old version
+}
+
+// diffUnit is the smallest piece compared: a heading, paragraph, list item, table row or code line
+type diffUnit struct {
+	path string
+	text string
+}
+
+type diffOp struct {
+	kind byte // ' ', '-', '+'
+	unit diffUnit
+}
+
+// DiffContent compares two versions of a note (Lexical JSON or Markdown) block by block
+// and renders the changes as Markdown.
+func DiffContent(oldContent, newContent string) Diff {
+	p := NewParser()
+	ops := diffUnits(structuralUnits(documentBlocks(p, oldContent)), structuralUnits(documentBlocks(p, newContent)))
+
+	var d Diff
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// A hunk is a run of consecutive changes, titled with the section it starts in
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		if path := ops[i].unit.path; path != "" {
+			sb.WriteString("#### " + path + "\n\n")
+		}
+		sb.WriteString("```diff\n")
+		for ; i < len(ops) && ops[i].kind != ' '; i++ {
+			if ops[i].kind == '+' {
+				d.Added++
+			} else {
+				d.Removed++
+			}
+			for _, line := range strings.Split(ops[i].unit.text, "\n") {
+				sb.WriteString(fmt.Sprintf("%c %s\n", ops[i].kind, line))
+			}
+		}
+		sb.WriteString("```\n")
+	}
+
+	d.Markdown = sb.String()
+	return d
+}
+
+// structuralUnits flattens blocks into comparable units
+func structuralUnits(blocks []block) []diffUnit {
+	var units []diffUnit
+	for _, b := range blocks {
+		path := strings.Join(b.path, " > ")
+		if b.level > 0 {
+			units = append(units, diffUnit{path: path, text: strings.Repeat("#", b.level) + " " + b.units[0]})
+			continue
+		}
+		if b.header != "" {
+			units = append(units, diffUnit{path: path, text: strings.TrimRight(b.header, "\n")})
+		}
+		for _, u := range b.units {
+			units = append(units, diffUnit{path: path, text: u})
+		}
+	}
+	return units
+}
+
+// diffUnits computes an edit script (longest common subsequence on unit text)
+func diffUnits(a, b []diffUnit) []diffOp {
+	// Trim the common prefix and suffix; typical edits touch a small region
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix].text == b[prefix].text {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix].text == b[len(b)-1-suffix].text {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, u := range b[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', unit: u})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, u := range midA {
+			ops = append(ops, diffOp{kind: '-', unit: u})
+		}
+		for _, u := range midB {
+			ops = append(ops, diffOp{kind: '+', unit: u})
+		}
+	} else {
+		ops = append(ops, lcsOps(midA, midB)...)
+	}
+
+	for _, u := range b[len(b)-suffix:] {
+		ops = append(ops, diffOp{kind: ' ', unit: u})
+	}
+	return ops
+}
+
+func lcsOps(a, b []diffUnit) []diffOp {
+	n, m := len(a), len(b)
+	// lcs[i][j] = length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i].text == b[j].text {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i].text == b[j].text:
+			ops = append(ops, diffOp{kind: ' ', unit: b[j]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: '-', unit: a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', unit: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{kind: '-', unit: a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{kind: '+', unit: b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,40 @@
+package lexical
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffContentGroupsChangesBySection(t *testing.T) {
+	newDoc := strings.Replace(chunkerTestDoc, `"text":"Run migrations"`, `"text":"Run all migrations"`, 1)
+	newDoc = strings.Replace(newDoc, `"text":"beta"`, `"text":"gamma"`, 1)
+
+	d := DiffContent(chunkerTestDoc, newDoc)
+
+	if d.Added != 2 || d.Removed != 2 {
+		t.Errorf("got +%d -%d, want +2 -2", d.Added, d.Removed)
+	}
+	for _, want := range []string{
+		"#### Project > Setup\n\n```diff\n- - Run migrations\n+ - Run all migrations\n```",
+		"#### Project > Data\n\n```diff\n- | beta | 2 |\n+ | gamma | 2 |\n```",
+	} {
+		if !strings.Contains(d.Markdown, want) {
+			t.Errorf("diff missing %q:\n%s", want, d.Markdown)
+		}
+	}
+	if strings.Contains(d.Markdown, "Install Go") {
+		t.Errorf("unchanged units should not be listed:\n%s", d.Markdown)
+	}
+}
+
+func TestDiffContentIdenticalAndMarkdown(t *testing.T) {
+	if d := DiffContent(chunkerTestDoc, chunkerTestDoc); d.Markdown != "" || d.Added != 0 || d.Removed != 0 {
+		t.Errorf("identical documents produced a diff: %+v", d)
+	}
+
+	d := DiffContent("# Plan\n\nStep one.", "# Plan\n\nStep one.\n\n## Later\n\nStep two.")
+	want := "#### Plan > Later\n\n```diff\n+ ## Later\n+ Step two.\n```\n"
+	if d.Markdown != want || d.Added != 2 || d.Removed != 0 {
+		t.Errorf("got %+v, want markdown %q", d, want)
+	}
+}
@@ -0,0 +1,244 @@
+package lexical
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Style values kept in HTML output; anything else (url(), expressions, ...) is dropped
+	safeStyleValue = regexp.MustCompile(`^[#\w\s(),.%-]+$`)
+	safeURLScheme  = regexp.MustCompile(`(?i)^(https?:|mailto:|tel:|/|#|\./|\.\./)`)
+	hasURLScheme   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// RenderHTML converts Lexical JSON into an HTML fragment. All text and attributes are escaped,
+// and links with unsafe schemes (javascript:, data:, ...) lose their href.
+// Content that is not Lexical JSON is rendered as escaped paragraphs.
+func RenderHTML(content string) string {
+	trimmed := strings.TrimSpace(content)
+	var root LexicalRoot
+	if !strings.HasPrefix(trimmed, `{"root":`) || json.Unmarshal([]byte(trimmed), &root) != nil {
+		return plainTextHTML(content)
+	}
+
+	var sb strings.Builder
+	renderHTMLChildren(root.Root, &sb)
+	return sb.String()
+}
+
+func plainTextHTML(text string) string {
+	var sb strings.Builder
+	for _, block := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(strings.ReplaceAll(html.EscapeString(block), "\n", "<br>"))
+		sb.WriteString("</p>\n")
+	}
+	return sb.String()
+}
+
+func renderHTMLChildren(node Node, sb *strings.Builder) {
+	for _, child := range node.Children {
+		renderHTMLNode(child, sb)
+	}
+}
+
+func renderHTMLNode(node Node, sb *strings.Builder) {
+	switch node.Type {
+	case "paragraph":
+		sb.WriteString("<p" + alignAttr(node) + ">")
+		renderHTMLChildren(node, sb)
+		sb.WriteString("</p>\n")
+
+	case "heading":
+		tag := "h1"
+		if len(node.Tag) == 2 && node.Tag[0] == 'h' && node.Tag[1] >= '1' && node.Tag[1] <= '6' {
+			tag = node.Tag
+		}
+		sb.WriteString("<" + tag + alignAttr(node) + ">")
+		renderHTMLChildren(node, sb)
+		sb.WriteString("</" + tag + ">\n")
+
+	case "quote":
+		sb.WriteString("<blockquote>")
+		renderHTMLChildren(node, sb)
+		sb.WriteString("</blockquote>\n")
+
+	case "list":
+		renderHTMLList(node, sb)
+
+	case "code":
+		sb.WriteString("<pre><code")
+		if lang := node.Language; lang != "" {
+			sb.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+		}
+		sb.WriteString(">")
+		for _, child := range node.Children {
+			if child.Type == "linebreak" {
+				sb.WriteString("\n")
+				continue
+			}
+			sb.WriteString(html.EscapeString(child.Text))
+		}
+		sb.WriteString("</code></pre>\n")
+
+	case "table":
+		sb.WriteString("<table>\n")
+		for _, row := range node.Children {
+			if row.Type != "tablerow" {
+				continue
+			}
+			sb.WriteString("<tr>")
+			for _, cell := range row.Children {
+				tag := "td"
+				if cell.HeaderState != 0 {
+					tag = "th"
+				}
+				sb.WriteString("<" + tag)
+				if cell.ColSpan > 1 {
+					sb.WriteString(fmt.Sprintf(` colspan="%d"`, cell.ColSpan))
+				}
+				if cell.RowSpan > 1 {
+					sb.WriteString(fmt.Sprintf(` rowspan="%d"`, cell.RowSpan))
+				}
+				sb.WriteString(">")
+				// Cells wrap their content in paragraphs; render it inline
+				for _, content := range cell.Children {
+					if content.Type == "paragraph" {
+						renderHTMLChildren(content, sb)
+					} else {
+						renderHTMLNode(content, sb)
+					}
+				}
+				sb.WriteString("</" + tag + ">")
+			}
+			sb.WriteString("</tr>\n")
+		}
+		sb.WriteString("</table>\n")
+
+	case "horizontalrule":
+		sb.WriteString("<hr>\n")
+
+	case "linebreak":
+		sb.WriteString("<br>")
+
+	case "text", "code-highlight":
+		renderHTMLText(node, sb)
+
+	case "link", "autolink":
+		// Browsers ignore whitespace and control characters inside schemes ("java\tscript:")
+		bare := strings.Map(func(r rune) rune {
+			if r <= ' ' || r == 0x7f {
+				return -1
+			}
+			return r
+		}, node.URL)
+		if bare != "" && (safeURLScheme.MatchString(bare) || !hasURLScheme.MatchString(bare)) {
+			href := strings.ReplaceAll(strings.TrimSpace(node.URL), " ", "%20")
+			sb.WriteString(`<a href="` + html.EscapeString(href) + `">`)
+		} else {
+			sb.WriteString("<a>")
+		}
+		renderHTMLChildren(node, sb)
+		sb.WriteString("</a>")
+
+	default:
+		renderHTMLChildren(node, sb)
+	}
+}
+
+func renderHTMLList(node Node, sb *strings.Builder) {
+	tag := "ul"
+	attrs := ""
+	switch node.ListType {
+	case "number":
+		tag = "ol"
+		if node.Start > 1 {
+			attrs = fmt.Sprintf(` start="%d"`, node.Start)
+		}
+	case "check":
+		attrs = ` class="checklist"`
+	}
+
+	sb.WriteString("<" + tag + attrs + ">\n")
+	for _, item := range node.Children {
+		if item.Type != "listitem" {
+			continue
+		}
+		sb.WriteString("<li>")
+		if node.ListType == "check" && !isNestedListItem(item) {
+			if item.Checked {
+				sb.WriteString(`<input type="checkbox" disabled checked> `)
+			} else {
+				sb.WriteString(`<input type="checkbox" disabled> `)
+			}
+		}
+		renderHTMLChildren(item, sb)
+		sb.WriteString("</li>\n")
+	}
+	sb.WriteString("</" + tag + ">\n")
+}
+
+// isNestedListItem reports whether a list item only wraps a nested list
+func isNestedListItem(item Node) bool {
+	return len(item.Children) > 0 && item.Children[0].Type == "list"
+}
+
+func renderHTMLText(node Node, sb *strings.Builder) {
+	format := textFormat(node)
+
+	var open, close []string
+	wrap := func(bit int, tag string) {
+		if format&bit != 0 {
+			open = append(open, "<"+tag+">")
+			close = append([]string{"</" + tag + ">"}, close...)
+		}
+	}
+	wrap(FormatBold, "strong")
+	wrap(FormatItalic, "em")
+	wrap(FormatUnderline, "u")
+	wrap(FormatStrikethrough, "s")
+	wrap(FormatCode, "code")
+	wrap(FormatSubscript, "sub")
+	wrap(FormatSuperscript, "sup")
+	wrap(FormatHighlight, "mark")
+
+	style := safeStyle(node.Style)
+	if style != "" {
+		sb.WriteString(`<span style="` + html.EscapeString(style) + `">`)
+	}
+	sb.WriteString(strings.Join(open, ""))
+	sb.WriteString(html.EscapeString(node.Text))
+	sb.WriteString(strings.Join(close, ""))
+	if style != "" {
+		sb.WriteString("</span>")
+	}
+}
+
+// safeStyle keeps only whitelisted color styles with plain values
+func safeStyle(style string) string {
+	styles := ParseStyle(style)
+	var kept []string
+	for _, k := range []string{"color", "background-color"} {
+		if v, ok := styles[k]; ok && safeStyleValue.MatchString(v) {
+			kept = append(kept, k+": "+v)
+		}
+	}
+	return strings.Join(kept, "; ")
+}
+
+func alignAttr(node Node) string {
+	if align, ok := node.Format.(string); ok {
+		switch align {
+		case "center", "right", "justify":
+			return ` style="text-align: ` + align + `"`
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package lexical
+
+import (
+	"strings"
+	"testing"
+)
+
+const htmlTestDoc = `{"root":{"type":"root","children":[
+	{"type":"heading","tag":"h2","children":[{"type":"text","text":"Plan <v2>"}]},
+	{"type":"paragraph","format":"center","children":[
+		{"type":"text","format":3,"text":"bold & italic"},
+		{"type":"text","style":"color: #F97316; background: url(x)","text":" red"},
+		{"type":"link","url":"https://example.com/?a=1&b=\"2\"","children":[{"type":"text","text":"site"}]},
+		{"type":"link","url":"java\tscript:alert(1)","children":[{"type":"text","text":"bad"}]}
+	]},
+	{"type":"list","listType":"check","children":[
+		{"type":"listitem","checked":true,"children":[{"type":"text","text":"done"}]},
+		{"type":"listitem","children":[{"type":"list","listType":"number","start":3,"children":[
+			{"type":"listitem","children":[{"type":"text","text":"third"}]}
+		]}]}
+	]},
+	{"type":"code","language":"go","children":[
+		{"type":"code-highlight","text":"if a < b {"},
+		{"type":"linebreak"},
+		{"type":"code-highlight","text":"}"}
+	]},
+	{"type":"table","children":[
+		{"type":"tablerow","children":[
+			{"type":"tablecell","headerState":1,"children":[{"type":"paragraph","children":[{"type":"text","text":"Name"}]}]}
+		]},
+		{"type":"tablerow","children":[
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"text","text":"alpha"}]}]}
+		]}
+	]}
+]}}`
+
+func TestRenderHTML(t *testing.T) {
+	got := RenderHTML(htmlTestDoc)
+
+	for _, want := range []string{
+		"<h2>Plan &lt;v2&gt;</h2>",
+		`<p style="text-align: center"><strong><em>bold &amp; italic</em></strong>`,
+		`<span style="color: #F97316"> red</span>`,
+		`<a href="https://example.com/?a=1&amp;b=&#34;2&#34;">site</a>`,
+		"<a>bad</a>",
+		`<ul class="checklist">`,
+		`<li><input type="checkbox" disabled checked> done</li>`,
+		`<ol start="3">`,
+		"<pre><code class=\"language-go\">if a &lt; b {\n}</code></pre>",
+		"<tr><th>Name</th></tr>",
+		"<tr><td>alpha</td></tr>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "script") || strings.Contains(got, "url(") {
+		t.Errorf("unsafe content kept:\n%s", got)
+	}
+}
+
+func TestRenderHTMLPlainText(t *testing.T) {
+	got := RenderHTML("line <1>\nline 2\n\nnext")
+	want := "<p>line &lt;1&gt;<br>line 2</p>\n<p>next</p>\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package lexical
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Link kinds
+const (
+	LinkKindWiki = "wiki" // [[Title]] or [[Title|alias]]
+	LinkKindURL  = "url"  // Link to a note URL (.../note/<uuid>)
+)
+
+// Link is a reference from a note's content to another note
+type Link struct {
+	Kind   string
+	Target string // Note title (wiki) or note id (url, and [[<uuid>]])
+	IsID   bool   // Target is a note id rather than a title
+}
+
+var (
+	wikiLinkPattern = regexp.MustCompile(`\[\[([^\[\]\n]+)\]\]`)
+	noteURLPattern  = regexp.MustCompile(`(?i)/notes?/([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})\b`)
+	noteIDPattern   = regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// ExtractLinks returns the note links in a document, in order of first appearance and without duplicates.
+// Wiki links are read from the text (so formatting inside [[...]] does not break them), note URLs
+// from Lexical link nodes and from bare URLs in the text. Non-Lexical content is scanned as plain text.
+func ExtractLinks(content string) []Link {
+	var urls []string
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, `{"root":`) {
+		var root LexicalRoot
+		if err := json.Unmarshal([]byte(trimmed), &root); err == nil {
+			var walk func(n Node)
+			walk = func(n Node) {
+				if n.URL != "" {
+					urls = append(urls, n.URL)
+				}
+				for _, child := range n.Children {
+					walk(child)
+				}
+			}
+			walk(root.Root)
+		}
+	}
+	text := ExtractText(content)
+
+	var links []Link
+	seen := make(map[string]bool)
+	add := func(link Link) {
+		key := link.Kind + ":" + strings.ToLower(link.Target)
+		if link.Target == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		links = append(links, link)
+	}
+
+	for _, match := range wikiLinkPattern.FindAllStringSubmatch(text, -1) {
+		// [[Title|alias]] links to Title
+		target := strings.TrimSpace(strings.SplitN(match[1], "|", 2)[0])
+		add(Link{Kind: LinkKindWiki, Target: target, IsID: noteIDPattern.MatchString(target)})
+	}
+	urls = append(urls, text)
+	for _, url := range urls {
+		for _, match := range noteURLPattern.FindAllStringSubmatch(url, -1) {
+			add(Link{Kind: LinkKindURL, Target: strings.ToLower(match[1]), IsID: true})
+		}
+	}
+
+	return links
+}
@@ -0,0 +1,43 @@
+package lexical
+
+import (
+	"reflect"
+	"testing"
+)
+
+const linksTestDoc = `{"root":{"type":"root","version":1,"children":[
+{"type":"paragraph","version":1,"children":[
+  {"type":"text","version":1,"text":"See [["},
+  {"type":"text","version":1,"format":1,"text":"Project Plan"},
+  {"type":"text","version":1,"text":"]] and [[Budget|the budget]], again [[project plan]]."}
+]},
+{"type":"paragraph","version":1,"children":[
+  {"type":"link","version":1,"url":"https://app.example.com/note/3F2504E0-4F89-11D3-9A0C-0305E82C3301","children":[
+    {"type":"text","version":1,"text":"meeting notes"}
+  ]},
+  {"type":"text","version":1,"text":" and https://app.example.com/notes/3f2504e0-4f89-11d3-9a0c-0305e82c3301"}
+]}
+]}}`
+
+func TestExtractLinksLexical(t *testing.T) {
+	got := ExtractLinks(linksTestDoc)
+	want := []Link{
+		{Kind: LinkKindWiki, Target: "Project Plan"},
+		{Kind: LinkKindWiki, Target: "Budget"},
+		{Kind: LinkKindURL, Target: "3f2504e0-4f89-11d3-9a0c-0305e82c3301", IsID: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestExtractLinksPlainText(t *testing.T) {
+	got := ExtractLinks("Link to [[3f2504e0-4f89-11d3-9a0c-0305e82c3301]] and [[ Ideas ]], not [[]] or /notebook/x.")
+	want := []Link{
+		{Kind: LinkKindWiki, Target: "3f2504e0-4f89-11d3-9a0c-0305e82c3301", IsID: true},
+		{Kind: LinkKindWiki, Target: "Ideas"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,559 @@
+package lexical
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrNotLexical means content that should be a Lexical document cannot be read as one
+var ErrNotLexical = errors.New("content is not a Lexical document")
+
+// jsonNode is a serialized Lexical node. Nodes are built as maps (not Node) so that
+// zero-valued fields the editor expects (format: 0, indent: 0, ...) are kept.
+type jsonNode map[string]interface{}
+
+var (
+	mdHeadingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRulePattern      = regexp.MustCompile(`^\s{0,3}((-\s*){3,}|(\*\s*){3,}|(_\s*){3,})$`)
+	mdListItemPattern  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	mdCheckboxPattern  = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdFencePattern     = regexp.MustCompile("^\\s{0,3}(```+|~~~+)\\s*([\\w+#.-]*)")
+	mdTableRulePattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// FromMarkdown converts a Markdown document into a Lexical JSON string the editor can load.
+// Supported blocks: headings, paragraphs, bullet/number/check lists (nested by indentation),
+// quotes, fenced code, horizontal rules and tables. Inline bold, italic, strikethrough,
+// highlight (==text==), code, <u>/<sub>/<sup> and [links](url) are mapped to text formats
+// and link nodes; [[wikilinks]] are kept as text so ExtractLinks can index them.
+func FromMarkdown(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	children := parseBlocks(lines)
+	if len(children) == 0 {
+		children = []jsonNode{paragraphNode(nil)}
+	}
+
+	root := jsonNode{"root": elementNode("root", children)}
+	out, _ := json.Marshal(root)
+	return string(out)
+}
+
+// AppendMarkdown adds a Markdown document after the blocks of a Lexical document, keeping the
+// existing nodes as they are. Content that is not a Lexical document is refused with ErrNotLexical
+// rather than rewritten.
+func AppendMarkdown(content, markdown string) (string, error) {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return FromMarkdown(markdown), nil
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(trimmed), &doc); err != nil {
+		return "", ErrNotLexical
+	}
+	root, ok := doc["root"].(map[string]interface{})
+	if !ok {
+		return "", ErrNotLexical
+	}
+
+	children, _ := root["children"].([]interface{})
+	for _, block := range parseBlocks(strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")) {
+		children = append(children, block)
+	}
+	root["children"] = children
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func parseBlocks(lines []string) []jsonNode {
+	var blocks []jsonNode
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, paragraphNode(inlineLines(paragraph)))
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case mdFencePattern.MatchString(line):
+			flush()
+			match := mdFencePattern.FindStringSubmatch(line)
+			fence := match[1]
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, codeNode(match[2], code))
+
+		case mdHeadingPattern.MatchString(trimmed):
+			flush()
+			match := mdHeadingPattern.FindStringSubmatch(trimmed)
+			heading := elementNode("heading", parseInline(match[2], 0))
+			heading["tag"] = "h" + strconv.Itoa(len(match[1]))
+			blocks = append(blocks, heading)
+
+		case mdRulePattern.MatchString(line):
+			flush()
+			blocks = append(blocks, jsonNode{"type": "horizontalrule", "version": 1})
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quote []string
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(t, ">") {
+					break
+				}
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(t, ">"), " "))
+			}
+			i--
+			blocks = append(blocks, elementNode("quote", inlineLines(quote)))
+
+		case strings.Contains(line, "|") && i+1 < len(lines) && strings.Contains(lines[i+1], "-") && mdTableRulePattern.MatchString(lines[i+1]):
+			flush()
+			rows := [][]string{splitTableRow(line)}
+			for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+				rows = append(rows, splitTableRow(lines[i]))
+			}
+			i--
+			blocks = append(blocks, tableNode(rows))
+
+		case mdListItemPattern.MatchString(line) && (len(paragraph) == 0 || !startsWithSpace(line)):
+			flush()
+			var items []listLine
+			for ; i < len(lines); i++ {
+				match := mdListItemPattern.FindStringSubmatch(lines[i])
+				if match == nil {
+					// Lazy continuation of the previous item
+					t := strings.TrimSpace(lines[i])
+					if t != "" && len(items) > 0 && startsWithSpace(lines[i]) {
+						items[len(items)-1].text += "\n" + t
+						continue
+					}
+					break
+				}
+				items = append(items, newListLine(match))
+			}
+			i--
+			blocks = append(blocks, buildLists(items)...)
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+func startsWithSpace(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// listLine is one parsed list item line
+type listLine struct {
+	indent   int
+	listType string // bullet, number or check
+	start    int
+	checked  bool
+	text     string
+}
+
+func newListLine(match []string) listLine {
+	item := listLine{
+		indent:   len(strings.ReplaceAll(match[1], "\t", "    ")),
+		listType: "bullet",
+		text:     match[3],
+	}
+	marker := match[2]
+	if n, err := strconv.Atoi(strings.TrimRight(marker, ".)")); err == nil {
+		item.listType = "number"
+		item.start = n
+	}
+	if check := mdCheckboxPattern.FindStringSubmatch(item.text); check != nil && item.listType == "bullet" {
+		item.listType = "check"
+		item.checked = check[1] != " "
+		item.text = check[2]
+	}
+	return item
+}
+
+// buildLists groups consecutive items into list nodes; items indented deeper than the
+// first item of a group become a nested list following the preceding list item.
+func buildLists(items []listLine) []jsonNode {
+	var lists []jsonNode
+	for i := 0; i < len(items); {
+		base := items[i]
+		var listItems []jsonNode
+		value := 1
+		if base.listType == "number" && base.start > 0 {
+			value = base.start
+		}
+		start := value
+
+		for i < len(items) && items[i].indent <= base.indent && items[i].listType == base.listType {
+			item := items[i]
+			i++
+
+			j := i
+			for j < len(items) && items[j].indent > base.indent {
+				j++
+			}
+
+			listItem := elementNode("listitem", inlineLines(strings.Split(item.text, "\n")))
+			listItem["value"] = value
+			if base.listType == "check" {
+				listItem["checked"] = item.checked
+			}
+			listItems = append(listItems, listItem)
+			value++
+
+			if j > i {
+				// Like the editor, nest lists in a list item of their own after the parent item
+				nested := elementNode("listitem", buildLists(items[i:j]))
+				nested["value"] = value
+				listItems = append(listItems, nested)
+				i = j
+			}
+		}
+
+		list := elementNode("list", listItems)
+		list["listType"] = base.listType
+		list["start"] = start
+		list["tag"] = "ul"
+		if base.listType == "number" {
+			list["tag"] = "ol"
+		}
+		lists = append(lists, list)
+	}
+	return lists
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func tableNode(rows [][]string) jsonNode {
+	cols := len(rows[0])
+	tableRows := make([]jsonNode, 0, len(rows))
+	for r, row := range rows {
+		cells := make([]jsonNode, 0, cols)
+		for c := 0; c < cols; c++ {
+			text := ""
+			if c < len(row) {
+				text = row[c]
+			}
+			headerState := 0
+			if r == 0 {
+				headerState = 1
+			}
+			cells = append(cells, jsonNode{
+				"type":            "tablecell",
+				"version":         1,
+				"children":        []jsonNode{paragraphNode(parseInline(text, 0))},
+				"direction":       "ltr",
+				"format":          "",
+				"indent":          0,
+				"colSpan":         1,
+				"rowSpan":         1,
+				"headerState":     headerState,
+				"backgroundColor": nil,
+			})
+		}
+		tableRows = append(tableRows, jsonNode{
+			"type":      "tablerow",
+			"version":   1,
+			"children":  cells,
+			"direction": "ltr",
+			"format":    "",
+			"indent":    0,
+		})
+	}
+	return elementNode("table", tableRows)
+}
+
+func codeNode(language string, lines []string) jsonNode {
+	var children []jsonNode
+	for i, line := range lines {
+		if i > 0 {
+			children = append(children, jsonNode{"type": "linebreak", "version": 1})
+		}
+		if line != "" {
+			children = append(children, textNode(line, 0))
+		}
+	}
+	code := elementNode("code", children)
+	code["language"] = strings.ToLower(language)
+	return code
+}
+
+func elementNode(nodeType string, children []jsonNode) jsonNode {
+	if children == nil {
+		children = []jsonNode{}
+	}
+	return jsonNode{
+		"type":      nodeType,
+		"version":   1,
+		"children":  children,
+		"direction": "ltr",
+		"format":    "",
+		"indent":    0,
+	}
+}
+
+func paragraphNode(children []jsonNode) jsonNode {
+	p := elementNode("paragraph", children)
+	p["textFormat"] = 0
+	return p
+}
+
+func textNode(text string, format int) jsonNode {
+	return jsonNode{
+		"type":    "text",
+		"version": 1,
+		"text":    text,
+		"format":  format,
+		"style":   "",
+		"mode":    "normal",
+		"detail":  0,
+	}
+}
+
+// inlineLines parses each line and joins them with line breaks
+func inlineLines(lines []string) []jsonNode {
+	var nodes []jsonNode
+	for i, line := range lines {
+		if i > 0 {
+			nodes = append(nodes, jsonNode{"type": "linebreak", "version": 1})
+		}
+		nodes = append(nodes, parseInline(line, 0)...)
+	}
+	return nodes
+}
+
+// inlineDelimiters maps paired inline markers to the format they toggle, longest first
+var inlineDelimiters = []struct {
+	marker string
+	format int
+}{
+	{"**", FormatBold},
+	{"__", FormatBold},
+	{"~~", FormatStrikethrough},
+	{"==", FormatHighlight},
+	{"*", FormatItalic},
+	{"_", FormatItalic},
+}
+
+// parseInline converts inline Markdown into text and link nodes; adjacent text with
+// the same format is merged.
+func parseInline(s string, format int) []jsonNode {
+	var nodes []jsonNode
+	var plain strings.Builder
+
+	emit := func(node jsonNode) {
+		if node["type"] == "text" && len(nodes) > 0 {
+			last := nodes[len(nodes)-1]
+			if last["type"] == "text" && last["format"] == node["format"] {
+				last["text"] = last["text"].(string) + node["text"].(string)
+				return
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	flushPlain := func() {
+		if plain.Len() > 0 {
+			emit(textNode(plain.String(), format))
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		rest := s[i:]
+
+		// Escaped punctuation
+		if rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_~=[]()#|!<>+.-", rune(rest[1])) {
+			plain.WriteByte(rest[1])
+			i += 2
+			continue
+		}
+
+		// Inline code
+		if rest[0] == '`' {
+			if end := strings.Index(rest[1:], "`"); end >= 0 {
+				flushPlain()
+				emit(textNode(rest[1:1+end], format|FormatCode))
+				i += end + 2
+				continue
+			}
+		}
+
+		// Wikilinks and embeds are kept verbatim
+		if strings.HasPrefix(rest, "[[") || strings.HasPrefix(rest, "![[") {
+			if end := strings.Index(rest, "]]"); end >= 0 {
+				plain.WriteString(strings.TrimPrefix(rest[:end+2], "!"))
+				i += end + 2
+				continue
+			}
+		}
+
+		// [label](url) and ![alt](url)
+		if rest[0] == '[' || strings.HasPrefix(rest, "![") {
+			offset := 0
+			if rest[0] == '!' {
+				offset = 1
+			}
+			if label, url, n, ok := parseLinkAt(rest[offset:]); ok {
+				flushPlain()
+				children := parseInline(label, format)
+				if len(children) == 0 {
+					children = []jsonNode{textNode(url, format)}
+				}
+				link := elementNode("link", children)
+				link["url"] = url
+				link["rel"] = "noreferrer"
+				link["target"] = nil
+				link["title"] = nil
+				emit(link)
+				i += offset + n
+				continue
+			}
+		}
+
+		// Formats without Markdown syntax, as written by ToMarkdown
+		if rest[0] == '<' {
+			if tag, f, ok := htmlFormatTag(rest); ok {
+				if end := strings.Index(rest, "</"+tag+">"); end > len(tag)+2 {
+					flushPlain()
+					for _, node := range parseInline(rest[len(tag)+2:end], format|f) {
+						emit(node)
+					}
+					i += end + len(tag) + 3
+					continue
+				}
+			}
+		}
+
+		matched := false
+		for _, d := range inlineDelimiters {
+			if !strings.HasPrefix(rest, d.marker) {
+				continue
+			}
+			// Intra-word underscores (snake_case) are not emphasis
+			if d.marker[0] == '_' && i > 0 && isWordByte(s[i-1]) {
+				break
+			}
+			inner := rest[len(d.marker):]
+			end := strings.Index(inner, d.marker)
+			if end <= 0 || inner[0] == ' ' || inner[end-1] == ' ' {
+				break
+			}
+			flushPlain()
+			for _, node := range parseInline(inner[:end], format|d.format) {
+				emit(node)
+			}
+			i += len(d.marker)*2 + end
+			matched = true
+			break
+		}
+		if matched {
+			continue
+		}
+
+		plain.WriteByte(rest[0])
+		i++
+	}
+	flushPlain()
+
+	return nodes
+}
+
+// htmlFormatTag recognizes an opening <u>, <sub> or <sup> tag at the start of s
+func htmlFormatTag(s string) (string, int, bool) {
+	for _, t := range []struct {
+		tag    string
+		format int
+	}{{"u", FormatUnderline}, {"sub", FormatSubscript}, {"sup", FormatSuperscript}} {
+		if strings.HasPrefix(s, "<"+t.tag+">") {
+			return t.tag, t.format, true
+		}
+	}
+	return "", 0, false
+}
+
+// parseLinkAt parses "[label](url)" at the start of s, returning the consumed length
+func parseLinkAt(s string) (label, url string, n int, ok bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", "", 0, false
+	}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if i+1 >= len(s) || s[i+1] != '(' {
+					return "", "", 0, false
+				}
+				end := strings.IndexByte(s[i+2:], ')')
+				if end < 0 {
+					return "", "", 0, false
+				}
+				target := strings.TrimSpace(s[i+2 : i+2+end])
+				// Drop an optional "title"
+				if sp := strings.IndexAny(target, " \t"); sp >= 0 {
+					target = target[:sp]
+				}
+				target = strings.Trim(target, "<>")
+				if target == "" {
+					return "", "", 0, false
+				}
+				return s[1:i], target, i + 3 + end, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
@@ -0,0 +1,124 @@
+package lexical
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const markdownTestDoc = "# Project **Plan**\n\n" +
+	"Intro with _italic_, `code`, ==marked== and a [site](https://example.com \"Example\").\n" +
+	"Second line links [[Budget|the budget]] and keeps snake_case.\n\n" +
+	"- one\n  - nested\n- two\n\n" +
+	"1. first\n2. second\n\n" +
+	"- [x] done\n- [ ] todo\n\n" +
+	"> quoted\n\n" +
+	"```go\nfunc main() {\n}\n```\n\n" +
+	"---\n\n" +
+	"| Name | Value |\n|---|---:|\n| alpha | 1 |\n"
+
+func TestFromMarkdownStructure(t *testing.T) {
+	out := FromMarkdown(markdownTestDoc)
+
+	var root LexicalRoot
+	if err := json.Unmarshal([]byte(out), &root); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+
+	var types []string
+	for _, child := range root.Root.Children {
+		types = append(types, child.Type)
+	}
+	want := "heading,paragraph,list,list,list,quote,code,horizontalrule,table"
+	if got := strings.Join(types, ","); got != want {
+		t.Fatalf("blocks = %s, want %s", got, want)
+	}
+
+	heading := root.Root.Children[0]
+	if heading.Tag != "h1" || len(heading.Children) != 2 || heading.Children[1].Format != float64(FormatBold) {
+		t.Errorf("heading = %+v", heading)
+	}
+
+	bullets := root.Root.Children[2]
+	if bullets.ListType != "bullet" || len(bullets.Children) != 3 || bullets.Children[1].Children[0].Type != "list" {
+		t.Errorf("nested list = %+v", bullets)
+	}
+	numbers := root.Root.Children[3]
+	if numbers.ListType != "number" || numbers.Tag != "ol" || numbers.Children[1].Value != 2 {
+		t.Errorf("number list = %+v", numbers)
+	}
+	checks := root.Root.Children[4]
+	if checks.ListType != "check" || !checks.Children[0].Checked || checks.Children[1].Checked {
+		t.Errorf("check list = %+v", checks)
+	}
+
+	table := root.Root.Children[8]
+	if len(table.Children) != 2 || len(table.Children[0].Children) != 2 || table.Children[0].Children[0].HeaderState != 1 {
+		t.Errorf("table = %+v", table)
+	}
+
+	text := ExtractText(out)
+	for _, s := range []string{"Project Plan", "[[Budget|the budget]]", "snake_case", "func main() {", "alpha"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("text missing %q: %s", s, text)
+		}
+	}
+}
+
+func TestFromMarkdownInline(t *testing.T) {
+	nodes := parseInline("a **b _c_** [l](u) \\*x\\*", 0)
+
+	var got []string
+	for _, n := range nodes {
+		switch n["type"] {
+		case "text":
+			got = append(got, n["text"].(string)+":"+strconv.Itoa(n["format"].(int)))
+		case "link":
+			got = append(got, "link:"+n["url"].(string))
+		}
+	}
+	want := "a :0,b :1,c:3, :0,link:u, *x*:0"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestAppendMarkdown(t *testing.T) {
+	out, err := AppendMarkdown(FromMarkdown("# Notes\n\nfirst"), "second with [link](/notes/3f2504e0-4f89-11d3-9a0c-0305e82c3301)")
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	var root LexicalRoot
+	if err := json.Unmarshal([]byte(out), &root); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if got := len(root.Root.Children); got != 3 {
+		t.Fatalf("got %d blocks, want 3", got)
+	}
+	if got := ExtractText(out); !strings.Contains(got, "first") || !strings.Contains(got, "second with link") {
+		t.Errorf("text = %q", got)
+	}
+	if links := ExtractLinks(out); len(links) != 1 || links[0].Kind != LinkKindURL {
+		t.Errorf("links = %+v", links)
+	}
+
+	if got, err := AppendMarkdown("", "only"); err != nil || got != FromMarkdown("only") {
+		t.Errorf("empty content = %s, %v", got, err)
+	}
+	for _, content := range []string{"plain text", `{"root":`, `{"root":[]}`, `[1]`} {
+		if _, err := AppendMarkdown(content, "more"); !errors.Is(err, ErrNotLexical) {
+			t.Errorf("content %q: err = %v, want ErrNotLexical", content, err)
+		}
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	got := EscapeMarkdown("Plan *v2* ~draft~ [x](y)\n<script>")
+	want := `Plan \*v2\* \~draft\~ \[x](y) \<script>`
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -3,11 +3,19 @@ package lexical
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+// mdOrderedMarker matches the "1." or "1)" that would start a numbered list
+var mdOrderedMarker = regexp.MustCompile(`^\d+[.)]`)
+
 // Parser handles Lexical JSON to Markdown conversion
-type Parser struct{}
+type Parser struct {
+	// escape backslash-escapes Markdown syntax in text and drops HTML-only annotations,
+	// so the output reads back to the same document (see ToMarkdown)
+	escape bool
+}
 
 // NewParser creates a new parser instance
 func NewParser() *Parser {
@@ -44,6 +52,23 @@ func ParseContent(content string) string {
 	return md
 }
 
+// ToMarkdown converts Lexical JSON into Markdown meant to be stored or re-imported: text is
+// escaped so that FromMarkdown reads it back to the same document, and editor-only annotations
+// (colors, alignment) are dropped. Content that is not Lexical JSON is returned unchanged.
+func ToMarkdown(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, `{"root":`) {
+		return content
+	}
+
+	p := &Parser{escape: true}
+	md, err := p.Parse(trimmed)
+	if err != nil {
+		return content
+	}
+	return md
+}
+
 // walkNode traverses the tree and writers markdown
 func (p *Parser) walkNode(node Node, sb *strings.Builder, depth int) {
 	switch node.Type {
@@ -56,9 +81,40 @@ func (p *Parser) walkNode(node Node, sb *strings.Builder, depth int) {
 	case "paragraph":
 		p.handleParagraph(node, sb, depth)
 
-	case "text":
+	case "heading":
+		var content strings.Builder
+		for _, child := range node.Children {
+			p.walkNode(child, &content, depth)
+		}
+		sb.WriteString(strings.Repeat("#", headingLevel(node.Tag)) + " ")
+		sb.WriteString(strings.ReplaceAll(strings.TrimSpace(content.String()), "\n", " "))
+		sb.WriteString("\n")
+
+	case "quote":
+		var content strings.Builder
+		for _, child := range node.Children {
+			p.walkNode(child, &content, depth)
+		}
+		for _, line := range strings.Split(strings.TrimRight(content.String(), "\n"), "\n") {
+			sb.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+
+	case "code":
+		sb.WriteString("```" + node.Language + "\n")
+		if text := codeText(node); text != "" {
+			sb.WriteString(strings.TrimRight(text, "\n") + "\n")
+		}
+		sb.WriteString("```\n")
+
+	case "text", "code-highlight":
 		p.handleText(node, sb)
 
+	case "linebreak":
+		sb.WriteString("\n")
+
+	case "tab":
+		sb.WriteString("\t")
+
 	case "list":
 		p.handleList(node, sb, depth)
 
@@ -88,7 +144,7 @@ func (p *Parser) walkNode(node Node, sb *strings.Builder, depth int) {
 
 func (p *Parser) handleParagraph(node Node, sb *strings.Builder, depth int) {
 	align := ""
-	if fmtStr, ok := node.Format.(string); ok && fmtStr != "" && fmtStr != "left" {
+	if fmtStr, ok := node.Format.(string); ok && fmtStr != "" && fmtStr != "left" && !p.escape {
 		align = fmtStr
 	}
 
@@ -96,8 +152,16 @@ func (p *Parser) handleParagraph(node Node, sb *strings.Builder, depth int) {
 		sb.WriteString(fmt.Sprintf("<div align=\"%s\">", align))
 	}
 
-	for _, child := range node.Children {
-		p.walkNode(child, sb, depth)
+	if p.escape {
+		var content strings.Builder
+		for _, child := range node.Children {
+			p.walkNode(child, &content, depth)
+		}
+		sb.WriteString(escapeLineStarts(content.String()))
+	} else {
+		for _, child := range node.Children {
+			p.walkNode(child, sb, depth)
+		}
 	}
 
 	if align != "" {
@@ -108,63 +172,57 @@ func (p *Parser) handleParagraph(node Node, sb *strings.Builder, depth int) {
 
 func (p *Parser) handleText(node Node, sb *strings.Builder) {
 	text := node.Text
+	fmtInt := textFormat(node)
+	isCode := (fmtInt & FormatCode) != 0
+	if p.escape && !isCode {
+		text = escapeMarkdown(text)
+	}
 
 	// Annotations
-	styleStyles := ParseStyle(node.Style)
-	openTag := styleStyles.BuildAnnotatedOpenTag()
+	openTag := ""
+	if !p.escape {
+		openTag = ParseStyle(node.Style).BuildAnnotatedOpenTag()
+	}
 	if openTag != "" {
 		sb.WriteString(openTag)
 	}
 
-	// Format
-	fmtInt := 0
-	if f, ok := node.Format.(float64); ok {
-		fmtInt = int(f)
-	} else if f, ok := node.Format.(int); ok {
-		fmtInt = f
-	}
-
-	isBold := (fmtInt & FormatBold) != 0
-	isItalic := (fmtInt & FormatItalic) != 0
-	isUnderline := (fmtInt & FormatUnderline) != 0
-	isCode := (fmtInt & FormatCode) != 0
-	isStrike := (fmtInt & FormatStrikethrough) != 0
-
-	// Apply wrappers (Code > Bold > Italic > Underline > Strike)
-	// Markdown doesn't support underline natively everywhere, using HTML <u>
-	if isCode {
-		sb.WriteString("`")
-	}
-	if isBold {
-		sb.WriteString("**")
-	}
-	if isItalic {
-		sb.WriteString("_")
-	}
-	if isUnderline {
-		sb.WriteString("<u>")
-	}
-	if isStrike {
-		sb.WriteString("~~")
-	}
-
-	sb.WriteString(text)
-
-	if isStrike {
-		sb.WriteString("~~")
-	}
-	if isUnderline {
-		sb.WriteString("</u>")
-	}
-	if isItalic {
-		sb.WriteString("_")
-	}
-	if isBold {
-		sb.WriteString("**")
+	// Markdown delimiters must hug the text: keep surrounding spaces outside of them
+	core := strings.TrimSpace(text)
+	if core == "" || fmtInt == 0 {
+		sb.WriteString(text)
+		if openTag != "" {
+			sb.WriteString("</span>")
+		}
+		return
 	}
-	if isCode {
-		sb.WriteString("`")
+	lead := text[:strings.Index(text, core)]
+	trail := text[len(lead)+len(core):]
+
+	// Apply wrappers (Bold > Italic > Strike > Highlight > Underline > Sub/Sup > Code).
+	// Code is innermost since nothing is parsed inside a code span; underline and
+	// sub/superscript have no Markdown syntax and use HTML tags
+	var open, close []string
+	wrap := func(bit int, openMark, closeMark string) {
+		if fmtInt&bit != 0 {
+			open = append(open, openMark)
+			close = append([]string{closeMark}, close...)
+		}
 	}
+	wrap(FormatBold, "**", "**")
+	wrap(FormatItalic, "_", "_")
+	wrap(FormatStrikethrough, "~~", "~~")
+	wrap(FormatHighlight, "==", "==")
+	wrap(FormatUnderline, "<u>", "</u>")
+	wrap(FormatSubscript, "<sub>", "</sub>")
+	wrap(FormatSuperscript, "<sup>", "</sup>")
+	wrap(FormatCode, "`", "`")
+
+	sb.WriteString(lead)
+	sb.WriteString(strings.Join(open, ""))
+	sb.WriteString(core)
+	sb.WriteString(strings.Join(close, ""))
+	sb.WriteString(trail)
 
 	if openTag != "" {
 		sb.WriteString("</span>")
@@ -177,7 +235,11 @@ func (p *Parser) handleLink(node Node, sb *strings.Builder) {
 	for _, child := range node.Children {
 		p.walkNode(child, sb, 0) // depth 0 for inline
 	}
-	sb.WriteString(fmt.Sprintf("](%s)", node.URL))
+	url := node.URL
+	if p.escape {
+		url = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(url)
+	}
+	sb.WriteString(fmt.Sprintf("](%s)", url))
 }
 
 func (p *Parser) handleList(node Node, sb *strings.Builder, depth int) {
@@ -193,8 +255,19 @@ func (p *Parser) handleList(node Node, sb *strings.Builder, depth int) {
 			continue
 		}
 
+		// The editor wraps nested lists in a list item of their own
+		if isNestedListItem(child) {
+			for _, nested := range child.Children {
+				if nested.Type == "list" {
+					p.handleList(nested, sb, depth+1)
+				}
+			}
+			continue
+		}
+
 		// Indentation for nested lists (2 spaces per depth level)
-		sb.WriteString(strings.Repeat("  ", depth))
+		indent := strings.Repeat("  ", depth)
+		sb.WriteString(indent)
 
 		// List Marker
 		switch listType {
@@ -216,18 +289,22 @@ func (p *Parser) handleList(node Node, sb *strings.Builder, depth int) {
 		}
 
 		// List Item Content
-		// Recursively walk children of list item
-		// Warning: If list item contains a NESTED LIST, it usually appears as a child of the listitem
+		// Line breaks continue the item on indented lines; lists nested directly in the
+		// item follow it one level deeper
+		var content strings.Builder
+		var nested []Node
 		for _, grandChild := range child.Children {
 			if grandChild.Type == "list" {
-				sb.WriteString("\n")
-				// Increase depth for nested list
-				p.handleList(grandChild, sb, depth+1)
+				nested = append(nested, grandChild)
 			} else {
-				p.walkNode(grandChild, sb, depth)
+				p.walkNode(grandChild, &content, depth)
 			}
 		}
+		sb.WriteString(strings.ReplaceAll(strings.TrimRight(content.String(), "\n"), "\n", "\n"+indent+"  "))
 		sb.WriteString("\n")
+		for _, list := range nested {
+			p.handleList(list, sb, depth+1)
+		}
 	}
 	// Extra newline after list
 	if depth == 0 {
@@ -253,7 +330,10 @@ func (p *Parser) handleTable(node Node, sb *strings.Builder) {
 				p.walkNode(content, &cellSb, 0)
 			}
 			// Clean newlines in cells as they break MD tables
-			cleanContent := strings.ReplaceAll(cellSb.String(), "\n", " ")
+			cleanContent := strings.TrimSpace(strings.ReplaceAll(cellSb.String(), "\n", " "))
+			if p.escape {
+				cleanContent = strings.ReplaceAll(cleanContent, "|", "\\|")
+			}
 			rowData = append(rowData, cleanContent)
 		}
 		rows = append(rows, rowData)
@@ -299,3 +379,61 @@ func (p *Parser) handleTable(node Node, sb *strings.Builder) {
 	}
 	sb.WriteString("\n")
 }
+
+// EscapeMarkdown makes text, e.g. a title, read literally inside a line of generated Markdown:
+// inline syntax is backslash-escaped, every ~ included, and line breaks become spaces
+func EscapeMarkdown(text string) string {
+	parts := strings.Split(strings.Join(strings.Fields(text), " "), "~")
+	for i, part := range parts {
+		parts[i] = escapeMarkdown(part)
+	}
+	return strings.Join(parts, `\~`)
+}
+
+// escapeMarkdown backslash-escapes the characters of text that FromMarkdown would read as
+// inline syntax. Wikilinks ([[Note]]) and intra-word underscores are left as they are.
+func escapeMarkdown(text string) string {
+	var sb strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		next := byte(0)
+		if i+1 < len(text) {
+			next = text[i+1]
+		}
+		switch {
+		case c == '\\' || c == '`' || c == '*':
+			sb.WriteByte('\\')
+		case c == '_' && (i == 0 || !isWordByte(text[i-1])):
+			sb.WriteByte('\\')
+		case (c == '~' || c == '=') && next == c:
+			sb.WriteByte('\\')
+		case c == '[' && next != '[' && (i == 0 || text[i-1] != '['):
+			sb.WriteByte('\\')
+		case c == '<' && (next == '/' || next >= 'a' && next <= 'z'):
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// escapeLineStarts escapes paragraph lines that would otherwise start a heading, quote,
+// list, rule or table
+func escapeLineStarts(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		switch trimmed[0] {
+		case '#', '>', '-', '+', '|':
+			lines[i] = "\\" + trimmed
+			continue
+		}
+		if m := mdOrderedMarker.FindStringIndex(trimmed); m != nil {
+			lines[i] = trimmed[:m[1]-1] + "\\" + trimmed[m[1]-1:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
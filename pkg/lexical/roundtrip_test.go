@@ -0,0 +1,96 @@
+package lexical
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestRoundTripGolden converts every testdata/roundtrip input (Markdown or Lexical JSON) to
+// Markdown and HTML and compares the results with the .golden files next to it
+// (go test ./pkg/lexical -run RoundTrip -update rewrites them).
+func TestRoundTripGolden(t *testing.T) {
+	inputs, err := filepath.Glob("testdata/roundtrip/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range inputs {
+		ext := filepath.Ext(input)
+		if ext != ".md" && ext != ".json" || strings.Contains(filepath.Base(input), ".golden.") {
+			continue
+		}
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			data, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc := string(data)
+			if ext == ".md" {
+				doc = FromMarkdown(doc)
+			}
+
+			md := ToMarkdown(doc)
+			checkGolden(t, input+".golden.md", md)
+			checkGolden(t, input+".golden.html", RenderHTML(doc))
+
+			// Markdown -> Lexical -> Markdown is stable and keeps the text
+			again := FromMarkdown(md)
+			if got := ToMarkdown(again); got != md {
+				t.Errorf("markdown not stable:\n%s\nsecond pass:\n%s", md, got)
+			}
+			if got, want := ExtractText(again), ExtractText(doc); got != want {
+				t.Errorf("text changed:\n%q\nwant\n%q", got, want)
+			}
+		})
+	}
+}
+
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch:\n%s\nwant:\n%s", filepath.Base(path), got, want)
+	}
+}
+
+func TestExtractPlainTextSpans(t *testing.T) {
+	doc := FromMarkdown("# Title\n\nSome **bold** text\n\n- item")
+	pt := ExtractPlainText(doc)
+
+	if pt.Text != ExtractText(doc) || pt.Text != "Title\nSome bold text\nitem" {
+		t.Fatalf("text = %q", pt.Text)
+	}
+	want := []struct {
+		text   string
+		format int
+	}{{"Title", 0}, {"Some ", 0}, {"bold", FormatBold}, {" text", 0}, {"item", 0}}
+	if len(pt.Spans) != len(want) {
+		t.Fatalf("spans = %+v", pt.Spans)
+	}
+	for i, span := range pt.Spans {
+		if got := pt.Text[span.Start:span.End]; got != want[i].text || span.Format != want[i].format {
+			t.Errorf("span %d = %q (format %d), want %q (format %d)", i, got, span.Format, want[i].text, want[i].format)
+		}
+	}
+	if p := pt.Spans[2].Path; len(p) != 2 || p[0] != 1 || p[1] != 1 {
+		t.Errorf("path of bold span = %v, want [1 1]", p)
+	}
+
+	if pt := ExtractPlainText("plain"); pt.Text != "plain" || pt.Spans != nil {
+		t.Errorf("plain text = %+v", pt)
+	}
+}
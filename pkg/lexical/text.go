@@ -0,0 +1,106 @@
+package lexical
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// blockTypes end with a line break when extracting text
+var blockTypes = map[string]bool{
+	"paragraph": true,
+	"heading":   true,
+	"quote":     true,
+	"listitem":  true,
+	"code":      true,
+	"tablerow":  true,
+}
+
+// TextSpan locates the text of one node in the plain text of a document
+type TextSpan struct {
+	Start  int    // Byte offset of the first character
+	End    int    // Byte offset after the last character
+	Path   []int  // Child indexes from the root down to the node
+	Type   string // text, code-highlight, tab, ...
+	Format int    // Text format bitmask
+}
+
+// PlainText is a document flattened to raw text, with the position of every text node
+type PlainText struct {
+	Text  string
+	Spans []TextSpan
+}
+
+// ExtractText returns the raw text of a Lexical document without any markup, for indexing.
+// Content that is not Lexical JSON is returned unchanged.
+func ExtractText(content string) string {
+	return ExtractPlainText(content).Text
+}
+
+// ExtractPlainText flattens a Lexical document like ExtractText and records where each text
+// node ends up, so that matches in the text can be mapped back to the editor tree.
+// Content that is not Lexical JSON is returned unchanged, without spans.
+func ExtractPlainText(content string) PlainText {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, `{"root":`) {
+		return PlainText{Text: content}
+	}
+
+	var root LexicalRoot
+	if err := json.Unmarshal([]byte(trimmed), &root); err != nil {
+		return PlainText{Text: content}
+	}
+
+	var sb strings.Builder
+	var spans []TextSpan
+	var walk func(n Node, path []int)
+	walk = func(n Node, path []int) {
+		switch n.Type {
+		case "linebreak":
+			sb.WriteString("\n")
+			return
+		case "tablecell":
+			walkChildren(n, path, walk)
+			sb.WriteString(" ")
+			return
+		}
+
+		if n.Text != "" {
+			start := sb.Len()
+			sb.WriteString(n.Text)
+			spans = append(spans, TextSpan{
+				Start:  start,
+				End:    sb.Len(),
+				Path:   append([]int(nil), path...),
+				Type:   n.Type,
+				Format: textFormat(n),
+			})
+		}
+		walkChildren(n, path, walk)
+		if blockTypes[n.Type] {
+			sb.WriteString("\n")
+		}
+	}
+	walk(root.Root, nil)
+
+	// Trim like ExtractText always did, shifting the spans along
+	raw := sb.String()
+	text := strings.TrimSpace(raw)
+	offset := len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+	kept := spans[:0]
+	for _, span := range spans {
+		span.Start = min(max(span.Start-offset, 0), len(text))
+		span.End = min(max(span.End-offset, 0), len(text))
+		if span.End > span.Start {
+			kept = append(kept, span)
+		}
+	}
+
+	return PlainText{Text: text, Spans: kept}
+}
+
+func walkChildren(n Node, path []int, walk func(Node, []int)) {
+	for i, child := range n.Children {
+		walk(child, append(path, i))
+	}
+}
@@ -39,6 +39,9 @@ type Node struct {
 	Checked bool `json:"checked,omitempty"`
 	Value   int  `json:"value,omitempty"`
 
+	// Code specific
+	Language string `json:"language,omitempty"`
+
 	// Table specific
 	ColSpan     int `json:"colSpan,omitempty"`
 	RowSpan     int `json:"rowSpan,omitempty"`
@@ -54,5 +57,16 @@ const (
 	FormatCode          = 16
 	FormatSubscript     = 32
 	FormatSuperscript   = 64
-	FormatHighlight     = 1 << 7 // ? verify
+	FormatHighlight     = 1 << 7 // IS_HIGHLIGHT in Lexical
 )
+
+// textFormat returns the format bitmask of a text node (JSON numbers decode as float64)
+func textFormat(node Node) int {
+	switch f := node.Format.(type) {
+	case float64:
+		return int(f)
+	case int:
+		return f
+	}
+	return 0
+}
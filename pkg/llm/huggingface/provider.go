@@ -2,12 +2,14 @@ package huggingface
 
 import (
 	"ai-notetaking-be/pkg/llm"
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
 type HuggingFaceProvider struct {
@@ -21,6 +23,7 @@ type chatRequest struct {
 	Model     string        `json:"model"`
 	Messages  []llm.Message `json:"messages"`
 	MaxTokens int           `json:"max_tokens,omitempty"`
+	Stream    bool          `json:"stream,omitempty"`
 }
 
 type chatResponse struct {
@@ -34,6 +37,18 @@ type chatResponse struct {
 	} `json:"error,omitempty"`
 }
 
+// chatStreamChunk is a single SSE "data:" payload when stream is enabled
+type chatStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
 func NewHuggingFaceProvider(apiKey, baseURL, model string) *HuggingFaceProvider {
 	if baseURL == "" {
 		baseURL = "https://router.huggingface.co/v1" // Default Router URL
@@ -105,6 +120,225 @@ func (p *HuggingFaceProvider) Chat(ctx context.Context, history []llm.Message, o
 	return chatResp.Choices[0].Message.Content, nil
 }
 
+// ChatStream requests an OpenAI-compatible SSE stream and forwards each delta to onDelta
+func (p *HuggingFaceProvider) ChatStream(ctx context.Context, history []llm.Message, onDelta llm.StreamHandler, options ...llm.Option) (string, error) {
+	opts := &llm.Options{
+		Model:     p.model,
+		MaxTokens: 500, // Default sane limit
+	}
+	for _, o := range options {
+		o(opts)
+	}
+
+	reqBody := chatRequest{
+		Model:     opts.Model,
+		Messages:  history,
+		MaxTokens: opts.MaxTokens,
+		Stream:    true,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/chat/completions", p.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("huggingface api error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue // Skip comments, event names and keep-alives
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return full.String(), nil
+		}
+
+		var chunk chatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return full.String(), fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+
+		if chunk.Error != nil {
+			return full.String(), fmt.Errorf("huggingface api returned error: %s", chunk.Error.Message)
+		}
+
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		full.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return full.String(), err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return full.String(), fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	return full.String(), nil
+}
+
+// toolChatRequest is the chat payload with function calling; messages need explicit
+// tool fields, so they are mapped instead of sent as llm.Message
+type toolChatRequest struct {
+	Model     string        `json:"model"`
+	Messages  []toolMessage `json:"messages"`
+	Tools     []toolSpec    `json:"tools,omitempty"`
+	MaxTokens int           `json:"max_tokens,omitempty"`
+}
+
+type toolMessage struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	ToolCalls  []toolCallSpec `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type toolSpec struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description,omitempty"`
+		Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+type toolCallSpec struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON object encoded as a string
+	} `json:"function"`
+}
+
+type toolChatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content   string         `json:"content"`
+			ToolCalls []toolCallSpec `json:"tool_calls"`
+		} `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// ChatWithTools sends the chat history with OpenAI-style tool definitions in a single blocking call
+func (p *HuggingFaceProvider) ChatWithTools(ctx context.Context, history []llm.Message, tools []llm.Tool, options ...llm.Option) (*llm.Message, error) {
+	opts := &llm.Options{
+		Model:     p.model,
+		MaxTokens: 500, // Default sane limit
+	}
+	for _, o := range options {
+		o(opts)
+	}
+
+	reqBody := toolChatRequest{
+		Model:     opts.Model,
+		Messages:  make([]toolMessage, len(history)),
+		MaxTokens: opts.MaxTokens,
+	}
+	for i, msg := range history {
+		reqBody.Messages[i] = toolMessage{Role: msg.Role, Content: msg.Content, ToolCallID: msg.ToolCallID}
+		for _, call := range msg.ToolCalls {
+			spec := toolCallSpec{ID: call.ID, Type: "function"}
+			spec.Function.Name = call.Name
+			spec.Function.Arguments = string(call.Arguments)
+			reqBody.Messages[i].ToolCalls = append(reqBody.Messages[i].ToolCalls, spec)
+		}
+	}
+	for _, tool := range tools {
+		spec := toolSpec{Type: "function"}
+		spec.Function.Name = tool.Name
+		spec.Function.Description = tool.Description
+		spec.Function.Parameters = tool.Parameters
+		reqBody.Tools = append(reqBody.Tools, spec)
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/chat/completions", p.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("huggingface api error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var chatResp toolChatResponse
+	if err := json.Unmarshal(bodyBytes, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if chatResp.Error != nil {
+		return nil, fmt.Errorf("huggingface api returned error: %s", chatResp.Error.Message)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("empty choices from huggingface api")
+	}
+
+	choice := chatResp.Choices[0].Message
+	message := &llm.Message{Role: "assistant", Content: choice.Content}
+	for _, call := range choice.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, llm.ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: json.RawMessage(call.Function.Arguments),
+		})
+	}
+	return message, nil
+}
+
 func (p *HuggingFaceProvider) Generate(ctx context.Context, prompt string, options ...llm.Option) (string, error) {
 	// Wrap single prompt into a user message
 	messages := []llm.Message{
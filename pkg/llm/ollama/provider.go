@@ -2,12 +2,14 @@ package ollama
 
 import (
 	"ai-notetaking-be/pkg/llm"
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -17,8 +19,9 @@ type OllamaProvider struct {
 	Client    *http.Client
 }
 
-// Ensure OllamaProvider implements LLMProvider
+// Ensure OllamaProvider implements LLMProvider and ToolCaller
 var _ llm.LLMProvider = &OllamaProvider{}
+var _ llm.ToolCaller = &OllamaProvider{}
 
 func NewOllamaProvider(baseURL, modelName string) *OllamaProvider {
 	return &OllamaProvider{
@@ -37,11 +40,32 @@ type ollamaChatRequest struct {
 	Messages []ollamaMessage `json:"messages"`
 	Stream   bool            `json:"stream"`
 	Options  *ollamaOptions  `json:"options,omitempty"`
+	Tools    []ollamaTool    `json:"tools,omitempty"`
 }
 
 type ollamaMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"` // Tool a "tool" message answers
+}
+
+type ollamaTool struct {
+	Type     string             `json:"type"`
+	Function ollamaToolFunction `json:"function"`
+}
+
+type ollamaToolFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"` // A JSON object, not a string
+	} `json:"function"`
 }
 
 type ollamaOptions struct {
@@ -58,6 +82,108 @@ type ollamaChatResponse struct {
 // --- Interface Implementation ---
 
 func (o *OllamaProvider) Chat(ctx context.Context, history []llm.Message, opts ...llm.Option) (string, error) {
+	// 1. Build payload (options, role mapping, model override)
+	reqPayload := o.buildChatRequest(history, false, opts...)
+
+	payloadBytes, err := json.Marshal(reqPayload)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := o.BaseURL + "/api/chat"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ollama error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// 2. Parse Response
+	var ollamaResp ollamaChatResponse
+	if err := json.Unmarshal(bodyBytes, &ollamaResp); err != nil {
+		return "", fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return ollamaResp.Message.Content, nil
+}
+
+// ChatStream sends the chat history with stream enabled and forwards every
+// NDJSON chunk to onDelta until Ollama reports done.
+func (o *OllamaProvider) ChatStream(ctx context.Context, history []llm.Message, onDelta llm.StreamHandler, opts ...llm.Option) (string, error) {
+	reqPayload := o.buildChatRequest(history, true, opts...)
+
+	payloadBytes, err := json.Marshal(reqPayload)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := o.BaseURL + "/api/chat"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("ollama error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return full.String(), fmt.Errorf("unmarshal stream chunk: %w", err)
+		}
+
+		if chunk.Message.Content != "" {
+			full.WriteString(chunk.Message.Content)
+			if err := onDelta(chunk.Message.Content); err != nil {
+				return full.String(), err
+			}
+		}
+
+		if chunk.Done {
+			return full.String(), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return full.String(), fmt.Errorf("read stream: %w", err)
+	}
+
+	return full.String(), nil
+}
+
+// buildChatRequest maps generic messages and options to an Ollama chat payload
+func (o *OllamaProvider) buildChatRequest(history []llm.Message, stream bool, opts ...llm.Option) ollamaChatRequest {
 	// 1. Process Options
 	options := &llm.Options{
 		Temperature: 0.7, // Default
@@ -68,6 +194,7 @@ func (o *OllamaProvider) Chat(ctx context.Context, history []llm.Message, opts .
 
 	// 2. Map generic messages to Ollama messages
 	ollamaMessages := make([]ollamaMessage, len(history))
+	toolNames := make(map[string]string) // Ollama answers a call by tool name, not by id
 	for i, msg := range history {
 		role := msg.Role
 		// Map standard roles if necessary, though "user", "assistant", "system" are standard
@@ -75,8 +202,16 @@ func (o *OllamaProvider) Chat(ctx context.Context, history []llm.Message, opts .
 			role = "assistant"
 		}
 		ollamaMessages[i] = ollamaMessage{
-			Role:    role,
-			Content: msg.Content,
+			Role:     role,
+			Content:  msg.Content,
+			ToolName: toolNames[msg.ToolCallID],
+		}
+		for _, call := range msg.ToolCalls {
+			var tc ollamaToolCall
+			tc.Function.Name = call.Name
+			tc.Function.Arguments = call.Arguments
+			ollamaMessages[i].ToolCalls = append(ollamaMessages[i].ToolCalls, tc)
+			toolNames[call.ID] = call.Name
 		}
 	}
 
@@ -89,7 +224,7 @@ func (o *OllamaProvider) Chat(ctx context.Context, history []llm.Message, opts .
 	reqPayload := ollamaChatRequest{
 		Model:    model,
 		Messages: ollamaMessages,
-		Stream:   false,
+		Stream:   stream,
 		Options: &ollamaOptions{
 			Temperature: options.Temperature,
 		},
@@ -99,41 +234,78 @@ func (o *OllamaProvider) Chat(ctx context.Context, history []llm.Message, opts .
 		reqPayload.Options.NumPredict = options.MaxTokens
 	}
 
+	return reqPayload
+}
+
+// ChatWithTools sends the chat history with the tool definitions in a single blocking call.
+// Ollama does not number tool calls, so ids are assigned in order of appearance.
+func (o *OllamaProvider) ChatWithTools(ctx context.Context, history []llm.Message, tools []llm.Tool, opts ...llm.Option) (*llm.Message, error) {
+	reqPayload := o.buildChatRequest(history, false, opts...)
+	for _, tool := range tools {
+		reqPayload.Tools = append(reqPayload.Tools, ollamaTool{
+			Type: "function",
+			Function: ollamaToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+
 	payloadBytes, err := json.Marshal(reqPayload)
 	if err != nil {
-		return "", fmt.Errorf("marshal request: %w", err)
+		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	// 4. Send Request
 	url := o.BaseURL + "/api/chat"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := o.Client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("ollama request failed: %w", err)
+		return nil, fmt.Errorf("ollama request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("read response: %w", err)
+		return nil, fmt.Errorf("read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return nil, fmt.Errorf("ollama error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
 	}
 
-	// 5. Parse Response
 	var ollamaResp ollamaChatResponse
 	if err := json.Unmarshal(bodyBytes, &ollamaResp); err != nil {
-		return "", fmt.Errorf("unmarshal response: %w", err)
+		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
 
-	return ollamaResp.Message.Content, nil
+	message := &llm.Message{
+		Role:    "assistant",
+		Content: ollamaResp.Message.Content,
+	}
+	offset := countToolCalls(history)
+	for i, call := range ollamaResp.Message.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, llm.ToolCall{
+			ID:        fmt.Sprintf("call_%d", offset+i),
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return message, nil
+}
+
+// countToolCalls counts the calls already made in a conversation, keeping assigned ids unique
+func countToolCalls(history []llm.Message) int {
+	n := 0
+	for _, msg := range history {
+		n += len(msg.ToolCalls)
+	}
+	return n
 }
 
 func (o *OllamaProvider) Generate(ctx context.Context, prompt string, opts ...llm.Option) (string, error) {
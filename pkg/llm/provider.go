@@ -6,8 +6,12 @@ import (
 
 // Message represents a chat message in a provider-agnostic format
 type Message struct {
-	Role    string // "user", "assistant", "system"
+	Role    string // "user", "assistant", "system", "tool"
 	Content string
+
+	// Tool calling (see ToolCaller); providers map these fields explicitly
+	ToolCalls  []ToolCall `json:"-"` // Calls requested by an assistant message
+	ToolCallID string     `json:"-"` // Call a "tool" message answers
 }
 
 // Option allows for optional parameters like Temperature, MaxTokens, etc.
@@ -31,11 +35,19 @@ func WithModel(model string) Option {
 	}
 }
 
+// StreamHandler receives each content delta as soon as the model produces it.
+// Returning an error aborts the stream (e.g. the client disconnected).
+type StreamHandler func(delta string) error
+
 // LLMProvider defines the contract for any LLM backend
 type LLMProvider interface {
 	// Chat sends a chat history to the model and returns the response
 	Chat(ctx context.Context, history []Message, options ...Option) (string, error)
 
+	// ChatStream behaves like Chat but yields the response incrementally through onDelta.
+	// It always returns the text accumulated so far, even when the stream is aborted.
+	ChatStream(ctx context.Context, history []Message, onDelta StreamHandler, options ...Option) (string, error)
+
 	// Generate sends a single prompt to the model (convenience method)
 	Generate(ctx context.Context, prompt string, options ...Option) (string, error)
 }
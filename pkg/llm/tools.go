@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Tool describes a function the model may call. Parameters is a JSON schema object.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{}
+}
+
+// ToolCall is a call the model requested; Arguments is the JSON object it produced
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments json.RawMessage
+}
+
+// ToolRun is a tool call that was executed (or only checked, for writes awaiting confirmation)
+// and what it returned to the model. Pipelines pass runs on to be stored with the answer.
+type ToolRun struct {
+	Name      string
+	Arguments json.RawMessage
+	Result    string
+	Status    string // "done" | "failed" | "pending"; writes end the turn pending
+}
+
+// ToolCaller is implemented by providers whose models support function calling
+type ToolCaller interface {
+	// ChatWithTools sends the chat history together with the tools the model may call.
+	// The returned assistant message carries either ToolCalls or the final Content.
+	// Results are sent back as "tool" messages whose ToolCallID names the call they answer.
+	ChatWithTools(ctx context.Context, history []Message, tools []Tool, options ...Option) (*Message, error)
+}
@@ -0,0 +1,152 @@
+package branch
+
+import (
+	"context"
+	"time"
+
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+
+	"github.com/google/uuid"
+)
+
+// Tree arranges the messages of a chat session by parent. Regenerating an answer or editing a
+// question adds a sibling, so every path from the greeting to a leaf is one branch of the conversation.
+//
+// Messages saved before branching existed have no parent; each of them follows the message created
+// before it, which turns a legacy session into a single branch.
+type Tree struct {
+	messages map[uuid.UUID]*entity.ChatMessage
+	parents  map[uuid.UUID]uuid.UUID
+	children map[uuid.UUID][]*entity.ChatMessage // oldest first; uuid.Nil holds the root
+	byTime   map[messageKey]uuid.UUID
+	newest   uuid.UUID
+}
+
+// messageKey matches raw messages saved without a link to their displayed message
+type messageKey struct {
+	role      string
+	createdAt time.Time
+}
+
+// Load builds the tree of all messages of a session
+func Load(ctx context.Context, uow unitofwork.UnitOfWork, sessionId uuid.UUID) (*Tree, error) {
+	messages, err := uow.ChatMessageRepository().FindAll(ctx,
+		specification.ByChatSessionID{ChatSessionID: sessionId},
+		specification.OrderBy{Field: "created_at"},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return NewTree(messages), nil
+}
+
+// NewTree builds the tree from the messages of a session ordered by creation time
+func NewTree(messages []*entity.ChatMessage) *Tree {
+	t := &Tree{
+		messages: make(map[uuid.UUID]*entity.ChatMessage, len(messages)),
+		parents:  make(map[uuid.UUID]uuid.UUID, len(messages)),
+		children: make(map[uuid.UUID][]*entity.ChatMessage),
+		byTime:   make(map[messageKey]uuid.UUID, len(messages)),
+	}
+	for _, message := range messages {
+		t.messages[message.Id] = message
+	}
+
+	previous := uuid.Nil
+	for _, message := range messages {
+		parent := previous
+		if message.ParentId != nil && t.messages[*message.ParentId] != nil {
+			parent = *message.ParentId
+		}
+		t.parents[message.Id] = parent
+		t.children[parent] = append(t.children[parent], message)
+		t.byTime[messageKey{message.Role, message.CreatedAt.UTC()}] = message.Id
+		previous = message.Id
+	}
+	t.newest = previous
+	return t
+}
+
+// Get returns a message of the tree, nil if the session has no such message
+func (t *Tree) Get(id uuid.UUID) *entity.ChatMessage {
+	return t.messages[id]
+}
+
+// Parent returns the message a message follows, nil for the root
+func (t *Tree) Parent(id uuid.UUID) *entity.ChatMessage {
+	return t.messages[t.parents[id]]
+}
+
+// Siblings returns the alternatives of a message, itself included, oldest first
+func (t *Tree) Siblings(id uuid.UUID) []*entity.ChatMessage {
+	if t.messages[id] == nil {
+		return nil
+	}
+	return t.children[t.parents[id]]
+}
+
+// Leaf returns the end of the branch through a message, following the newest child at each step
+func (t *Tree) Leaf(id uuid.UUID) uuid.UUID {
+	if t.messages[id] == nil {
+		return uuid.Nil
+	}
+	for {
+		children := t.children[id]
+		if len(children) == 0 {
+			return id
+		}
+		id = children[len(children)-1].Id
+	}
+}
+
+// Active returns the leaf of the selected branch. Sessions that never selected one, or whose
+// selected message is gone, continue from the newest message.
+func (t *Tree) Active(selected *uuid.UUID) uuid.UUID {
+	if selected != nil && t.messages[*selected] != nil {
+		return t.Leaf(*selected)
+	}
+	return t.newest
+}
+
+// Path returns the branch ending at a message, from the root down to the message itself
+func (t *Tree) Path(id uuid.UUID) []*entity.ChatMessage {
+	var path []*entity.ChatMessage
+	for t.messages[id] != nil {
+		path = append(path, t.messages[id])
+		id = t.parents[id]
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// MessageOf returns the displayed message a raw message belongs to, nil for the prompts seeded at
+// session creation. Raw messages saved before branching existed are matched by role and time.
+func (t *Tree) MessageOf(raw *entity.ChatMessageRaw) *uuid.UUID {
+	if raw.ChatMessageId != nil {
+		return raw.ChatMessageId
+	}
+	if id, ok := t.byTime[messageKey{raw.Role, raw.CreatedAt.UTC()}]; ok {
+		return &id
+	}
+	return nil
+}
+
+// RawsOnPath keeps the raw messages of the branch ending at a message, plus the seeded prompts
+func (t *Tree) RawsOnPath(raws []*entity.ChatMessageRaw, id uuid.UUID) []*entity.ChatMessageRaw {
+	onPath := make(map[uuid.UUID]bool)
+	for _, message := range t.Path(id) {
+		onPath[message.Id] = true
+	}
+
+	kept := make([]*entity.ChatMessageRaw, 0, len(raws))
+	for _, raw := range raws {
+		if messageId := t.MessageOf(raw); messageId == nil || onPath[*messageId] {
+			kept = append(kept, raw)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,132 @@
+package branch
+
+import (
+	"testing"
+	"time"
+
+	"ai-notetaking-be/internal/constant"
+	"ai-notetaking-be/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+var start = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func message(role string, second int, parent *entity.ChatMessage) *entity.ChatMessage {
+	m := &entity.ChatMessage{
+		Id:        uuid.New(),
+		Role:      role,
+		CreatedAt: start.Add(time.Duration(second) * time.Second),
+	}
+	if parent != nil {
+		m.ParentId = &parent.Id
+	}
+	return m
+}
+
+func ids(messages []*entity.ChatMessage) []uuid.UUID {
+	out := make([]uuid.UUID, len(messages))
+	for i, m := range messages {
+		out[i] = m.Id
+	}
+	return out
+}
+
+func sameIds(t *testing.T, name string, got []*entity.ChatMessage, want ...*entity.ChatMessage) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d messages, want %d", name, len(got), len(want))
+	}
+	for i, id := range ids(want) {
+		if got[i].Id != id {
+			t.Errorf("%s: position %d is %s, want %s", name, i, got[i].Id, id)
+		}
+	}
+}
+
+func TestTreeBranches(t *testing.T) {
+	greeting := message(constant.ChatMessageRoleModel, 0, nil)
+	q1 := message(constant.ChatMessageRoleUser, 10, greeting)
+	a1 := message(constant.ChatMessageRoleModel, 11, q1)
+	a1b := message(constant.ChatMessageRoleModel, 21, q1) // regenerated
+	q1e := message(constant.ChatMessageRoleUser, 30, greeting)
+	a1e := message(constant.ChatMessageRoleModel, 31, q1e) // edited question
+
+	tree := NewTree([]*entity.ChatMessage{greeting, q1, a1, a1b, q1e, a1e})
+
+	sameIds(t, "siblings of a1", tree.Siblings(a1.Id), a1, a1b)
+	sameIds(t, "siblings of q1e", tree.Siblings(q1e.Id), q1, q1e)
+	sameIds(t, "siblings of greeting", tree.Siblings(greeting.Id), greeting)
+
+	if got := tree.Leaf(q1.Id); got != a1b.Id {
+		t.Errorf("leaf of q1 = %s, want the regenerated answer", got)
+	}
+	if got := tree.Active(nil); got != a1e.Id {
+		t.Errorf("active without selection = %s, want the newest message", got)
+	}
+	if got := tree.Active(&a1.Id); got != a1.Id {
+		t.Errorf("active with selection = %s, want the selected message", got)
+	}
+	missing := uuid.New()
+	if got := tree.Active(&missing); got != a1e.Id {
+		t.Errorf("active with unknown selection = %s, want the newest message", got)
+	}
+
+	sameIds(t, "path to a1", tree.Path(a1.Id), greeting, q1, a1)
+	sameIds(t, "path to a1e", tree.Path(a1e.Id), greeting, q1e, a1e)
+	if tree.Parent(greeting.Id) != nil {
+		t.Error("greeting should have no parent")
+	}
+	if tree.Parent(a1b.Id) != q1 {
+		t.Error("parent of the regenerated answer should be its question")
+	}
+}
+
+func TestTreeLegacyMessages(t *testing.T) {
+	greeting := message(constant.ChatMessageRoleModel, 0, nil)
+	q1 := message(constant.ChatMessageRoleUser, 10, nil)
+	a1 := message(constant.ChatMessageRoleModel, 11, nil)
+	q2 := message(constant.ChatMessageRoleUser, 20, a1) // first message saved with a parent
+
+	tree := NewTree([]*entity.ChatMessage{greeting, q1, a1, q2})
+	sameIds(t, "legacy path", tree.Path(q2.Id), greeting, q1, a1, q2)
+}
+
+func TestRawsOnPath(t *testing.T) {
+	greeting := message(constant.ChatMessageRoleModel, 0, nil)
+	q1 := message(constant.ChatMessageRoleUser, 10, nil)
+	a1 := message(constant.ChatMessageRoleModel, 11, nil)
+	q1e := message(constant.ChatMessageRoleUser, 20, greeting)
+	a1e := message(constant.ChatMessageRoleModel, 21, q1e)
+	tree := NewTree([]*entity.ChatMessage{greeting, q1, a1, q1e, a1e})
+
+	raw := func(role string, at time.Time, link *uuid.UUID) *entity.ChatMessageRaw {
+		return &entity.ChatMessageRaw{Id: uuid.New(), Role: role, CreatedAt: at, ChatMessageId: link}
+	}
+	primingUser := raw(constant.ChatMessageRoleUser, start, nil)
+	primingModel := raw(constant.ChatMessageRoleModel, start.Add(time.Second), nil)
+	legacyQ1 := raw(constant.ChatMessageRoleUser, q1.CreatedAt.In(time.FixedZone("WIB", 7*3600)), nil)
+	legacyA1 := raw(constant.ChatMessageRoleModel, a1.CreatedAt, nil)
+	linkedQ1e := raw(constant.ChatMessageRoleUser, q1e.CreatedAt, &q1e.Id)
+	linkedA1e := raw(constant.ChatMessageRoleModel, a1e.CreatedAt, &a1e.Id)
+	raws := []*entity.ChatMessageRaw{primingUser, primingModel, legacyQ1, legacyA1, linkedQ1e, linkedA1e}
+
+	if got := tree.MessageOf(legacyQ1); got == nil || *got != q1.Id {
+		t.Errorf("legacy raw should match its message across time zones, got %v", got)
+	}
+
+	check := func(name string, got []*entity.ChatMessageRaw, want ...*entity.ChatMessageRaw) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d raws, want %d", name, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: position %d differs", name, i)
+			}
+		}
+	}
+	check("edited branch", tree.RawsOnPath(raws, a1e.Id), primingUser, primingModel, linkedQ1e, linkedA1e)
+	check("legacy branch", tree.RawsOnPath(raws, a1.Id), primingUser, primingModel, legacyQ1, legacyA1)
+	check("greeting only", tree.RawsOnPath(raws, greeting.Id), primingUser, primingModel)
+}
@@ -119,7 +119,7 @@ func (g *Grounder) groundSearch(
 	}
 
 	// Execute vector search
-	config := search.DefaultConfig()
+	config := search.ConfigForSession(session)
 	candidates, err := g.searchOrchestrator.Execute(ctx, uow, userId, query, config)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
@@ -407,7 +407,7 @@ func (g *Grounder) groundAggregate(
 		}
 
 		// Execute vector search
-		config := search.DefaultConfig()
+		config := search.ConfigForSession(session)
 		searchResults, err := g.searchOrchestrator.Execute(ctx, uow, userId, searchQuery, config)
 		if err != nil || len(searchResults) == 0 {
 			return &GroundingResult{
@@ -84,7 +84,7 @@ func (e *ActionExecutor) executeSearch(ctx context.Context, userId uuid.UUID, se
 		query = originalQuery
 	}
 
-	config := search.DefaultConfig()
+	config := search.ConfigForSession(e.session)
 	candidates, err := e.searchOrchestrator.Execute(ctx, e.uow, userId, query, config)
 	if err != nil {
 		return "", err
@@ -47,13 +47,27 @@ func (e *ExplicitExecutor) ExecuteWithContext(
 	notes []ExplicitContext,
 	history []llm.Message,
 ) (*ExecutionResult, error) {
+	return e.ExecuteWithContextStream(ctx, userId, sessionId, query, notes, history, nil)
+}
+
+// ExecuteWithContextStream is ExecuteWithContext with the answer streamed through onDelta.
+// A nil onDelta produces a regular blocking generation.
+func (e *ExplicitExecutor) ExecuteWithContextStream(
+	ctx context.Context,
+	userId uuid.UUID,
+	sessionId uuid.UUID,
+	query string,
+	notes []ExplicitContext,
+	history []llm.Message,
+	onDelta llm.StreamHandler,
+) (*ExecutionResult, error) {
 
 	e.logger.Printf("[EXPLICIT] Executing with %d pre-resolved notes", len(notes))
 
 	if len(notes) == 0 {
 		e.logger.Printf("[EXPLICIT] No notes provided, returning error message")
 		return &ExecutionResult{
-			Reply:     "No valid notes were found for the references you provided.",
+			Reply:     response.Emit(onDelta, "No valid notes were found for the references you provided."),
 			Citations: []dto.CitationDTO{},
 		}, nil
 	}
@@ -92,7 +106,7 @@ func (e *ExplicitExecutor) ExecuteWithContext(
 	// PHASE 3: GENERATION (Skip Phase 1 Intent + Phase 2 Search)
 	e.logger.Printf("[EXPLICIT] Generating response from explicit context (Scope: %s)", scope)
 
-	answer := e.generator.GenerateFromGroundedContext(ctx, query, groundedContext, history)
+	answer := e.generator.StreamFromGroundedContext(ctx, query, groundedContext, history, onDelta)
 
 	// Build citations
 	citations := make([]dto.CitationDTO, len(notes))
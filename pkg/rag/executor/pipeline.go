@@ -5,7 +5,6 @@ import (
 	"log"
 
 	"ai-notetaking-be/internal/dto"
-	"ai-notetaking-be/internal/repository/memory"
 	"ai-notetaking-be/internal/repository/unitofwork"
 	"ai-notetaking-be/pkg/llm"
 	ragcontext "ai-notetaking-be/pkg/rag/context"
@@ -23,7 +22,7 @@ type PipelineExecutor struct {
 	intentResolver *intent.Resolver
 	grounder       *ragcontext.Grounder
 	generator      *response.Generator
-	sessionRepo    *memory.SessionRepository
+	sessionRepo    store.SessionStore
 	logger         *log.Logger
 }
 
@@ -31,7 +30,7 @@ type PipelineExecutor struct {
 func NewPipelineExecutor(
 	llmProvider llm.LLMProvider,
 	searchOrchestrator *search.Orchestrator,
-	sessionRepo *memory.SessionRepository,
+	sessionRepo store.SessionStore,
 	logger *log.Logger,
 ) *PipelineExecutor {
 	return &PipelineExecutor{
@@ -50,6 +49,7 @@ type ExecutionResult struct {
 	SessionState       string
 	Mode               string
 	ResolvedReferences []dto.ResolvedReferenceDTO
+	ToolRuns           []llm.ToolRun // Tool calls of an agent mode answer
 }
 
 // Execute runs the complete three-phase pipeline
@@ -61,9 +61,35 @@ func (p *PipelineExecutor) Execute(
 	history []llm.Message,
 	uow unitofwork.UnitOfWork,
 ) (*ExecutionResult, error) {
+	return p.execute(ctx, userId, sessionId, query, history, uow, nil)
+}
+
+// ExecuteStream runs the same three phases but streams the Phase 3 answer through onDelta.
+// Phases 1 and 2 are unchanged; early replies (browse menu, errors) are emitted as one chunk.
+func (p *PipelineExecutor) ExecuteStream(
+	ctx context.Context,
+	userId uuid.UUID,
+	sessionId uuid.UUID,
+	query string,
+	history []llm.Message,
+	uow unitofwork.UnitOfWork,
+	onDelta llm.StreamHandler,
+) (*ExecutionResult, error) {
+	return p.execute(ctx, userId, sessionId, query, history, uow, onDelta)
+}
+
+func (p *PipelineExecutor) execute(
+	ctx context.Context,
+	userId uuid.UUID,
+	sessionId uuid.UUID,
+	query string,
+	history []llm.Message,
+	uow unitofwork.UnitOfWork,
+	onDelta llm.StreamHandler,
+) (*ExecutionResult, error) {
 
 	// Load or create session
-	session, found := p.sessionRepo.Get(sessionId.String())
+	session, found := p.sessionRepo.Get(ctx, sessionId.String())
 	if !found {
 		session = &store.Session{
 			ID:     sessionId.String(),
@@ -83,7 +109,7 @@ func (p *PipelineExecutor) Execute(
 	if err != nil {
 		p.logger.Printf("[ERROR] Intent resolution failed: %v", err)
 		return &ExecutionResult{
-			Reply: "Sorry, an error occurred while understanding your question.",
+			Reply: response.Emit(onDelta, "Sorry, an error occurred while understanding your question."),
 		}, nil
 	}
 
@@ -99,12 +125,12 @@ func (p *PipelineExecutor) Execute(
 	if err != nil {
 		p.logger.Printf("[ERROR] Context grounding failed: %v", err)
 		return &ExecutionResult{
-			Reply: "Sorry, an error occurred while loading the notes.",
+			Reply: response.Emit(onDelta, "Sorry, an error occurred while loading the notes."),
 		}, nil
 	}
 
 	// Save session state
-	p.sessionRepo.Save(groundingResult.Session)
+	p.sessionRepo.Save(ctx, groundingResult.Session)
 
 	// If not ready to answer (browse mode, etc.), return early
 	if !groundingResult.ShouldAnswer {
@@ -112,7 +138,7 @@ func (p *PipelineExecutor) Execute(
 		p.logger.Printf("[PHASE 2] Not answering - returning browse message")
 
 		return &ExecutionResult{
-			Reply:        groundingResult.BrowseMessage,
+			Reply:        response.Emit(onDelta, groundingResult.BrowseMessage),
 			Citations:    citations,
 			SessionState: groundingResult.Session.State,
 		}, nil
@@ -126,11 +152,12 @@ func (p *PipelineExecutor) Execute(
 	// ═══════════════════════════════════════════════════════════════
 	p.logger.Printf("[PHASE 3] Generating answer from grounded context...")
 
-	answer := p.generator.GenerateFromGroundedContext(
+	answer := p.generator.StreamFromGroundedContext(
 		ctx,
 		query,
 		groundingResult.Context,
 		history,
+		onDelta, // nil falls back to a blocking Chat call
 	)
 
 	// Build citations from grounded context
@@ -2,14 +2,17 @@ package history
 
 import (
 	"context"
+	"log"
 	"strings"
 
 	"ai-notetaking-be/internal/constant"
 	"ai-notetaking-be/internal/dto"
-	"ai-notetaking-be/internal/repository/memory"
+	"ai-notetaking-be/internal/entity"
 	"ai-notetaking-be/internal/repository/specification"
 	"ai-notetaking-be/internal/repository/unitofwork"
 	"ai-notetaking-be/pkg/llm"
+	"ai-notetaking-be/pkg/rag/branch"
+	"ai-notetaking-be/pkg/store"
 
 	"github.com/google/uuid"
 )
@@ -17,41 +20,92 @@ import (
 // Loader handles conversation history and citations
 type Loader struct {
 	uowFactory  unitofwork.RepositoryFactory
-	sessionRepo *memory.SessionRepository
+	sessionRepo store.SessionStore
+	llmProvider llm.LLMProvider // Compresses older turns into the running summary
+	logger      *log.Logger
 }
 
 // NewLoader creates a new history loader
-func NewLoader(uowFactory unitofwork.RepositoryFactory, sessionRepo *memory.SessionRepository) *Loader {
+func NewLoader(uowFactory unitofwork.RepositoryFactory, sessionRepo store.SessionStore, llmProvider llm.LLMProvider, logger *log.Logger) *Loader {
 	return &Loader{
 		uowFactory:  uowFactory,
 		sessionRepo: sessionRepo,
+		llmProvider: llmProvider,
+		logger:      logger,
 	}
 }
 
-// LoadConversationHistory loads recent chat history for LLM context.
-// When mode is "BYPASS", RAG system prompts are filtered out to prevent contamination.
-func (l *Loader) LoadConversationHistory(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID, mode string) ([]llm.Message, error) {
+// LoadConversationHistory loads the chat history for LLM context: the running summary of older
+// turns followed by the recent turns verbatim. Only the branch ending at leafId is loaded, so a
+// regenerated answer or edited question never sees the turns of the other branches.
+// Once the unsummarized turns exceed the token budget, the older ones are folded into the summary
+// (see summary.go).
+// When mode is "BYPASS" or "AGENT", RAG system prompts are filtered out to prevent contamination.
+func (l *Loader) LoadConversationHistory(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID, leafId uuid.UUID, mode string) ([]llm.Message, error) {
 	uow := l.uowFactory.NewUnitOfWork(ctx)
 
-	rawChats, err := uow.ChatMessageRawRepository().FindAll(ctx,
+	chatSession, err := uow.ChatSessionRepository().FindOne(ctx, specification.ByID{ID: sessionId})
+	if err != nil {
+		return nil, err
+	}
+
+	chatMessages, err := uow.ChatMessageRepository().FindAll(ctx,
 		specification.ByChatSessionID{ChatSessionID: sessionId},
-		specification.OrderBy{Field: "created_at", Desc: true},
+		specification.OrderBy{Field: "created_at"},
 	)
 	if err != nil {
 		return nil, err
 	}
+	tree := branch.NewTree(chatMessages)
 
-	limit := 10
-	if len(rawChats) > limit {
-		rawChats = rawChats[:limit]
+	var summary string
+	specs := []specification.Specification{
+		specification.ByChatSessionID{ChatSessionID: sessionId},
+		specification.OrderBy{Field: "created_at"},
+	}
+	if chatSession != nil && summaryApplies(chatSession, chatMessages, tree, leafId) {
+		summary = chatSession.Summary
+		specs = append(specs, specification.CreatedAfter{Time: *chatSession.SummarizedUntil})
 	}
 
-	messages := make([]llm.Message, 0, len(rawChats))
-	for i := len(rawChats) - 1; i >= 0; i-- {
-		chat := rawChats[i]
+	rawChats, err := uow.ChatMessageRawRepository().FindAll(ctx, specs...)
+	if err != nil {
+		return nil, err
+	}
+	rawChats = tree.RawsOnPath(rawChats, leafId)
+
+	budget, prompt := summaryOptions(ctx, uow)
+	if historyTokens(summary, rawChats) > budget {
+		if cut := summaryCut(rawChats, budget); cut > 0 {
+			older := rawChats[:cut]
+			rawChats = rawChats[cut:]
+
+			updated, err := l.summarize(ctx, prompt, summary, older, budget)
+			if err != nil {
+				// Answer without the older turns; they are summarized on a later request
+				l.logger.Printf("[HISTORY] Failed to summarize %d messages of session %s: %v", len(older), sessionId, err)
+			} else {
+				summary = truncateRunes(updated, budget*2)
+				last := older[len(older)-1]
+				if err := uow.ChatSessionRepository().UpdateSummary(ctx, sessionId, summary, last.CreatedAt, tree.MessageOf(last)); err != nil {
+					l.logger.Printf("[HISTORY] Failed to store summary of session %s: %v", sessionId, err)
+				} else {
+					l.logger.Printf("[HISTORY] Summarized %d older messages of session %s", len(older), sessionId)
+				}
+			}
+		}
+	}
 
-		// BYPASS MODE or BYPASS_NUANCE MODE: Filter out RAG system prompts to prevent contamination
-		if (mode == "BYPASS" || mode == "BYPASS_NUANCE") && isRAGSystemPrompt(chat.Chat) {
+	messages := make([]llm.Message, 0, len(rawChats)+1)
+	if summary != "" {
+		messages = append(messages, llm.Message{
+			Role:    "system",
+			Content: summaryHeader + summary,
+		})
+	}
+	for _, chat := range rawChats {
+		// BYPASS, BYPASS_NUANCE and AGENT MODE: Filter out RAG system prompts to prevent contamination
+		if (mode == "BYPASS" || mode == "BYPASS_NUANCE" || mode == "AGENT") && isRAGSystemPrompt(chat.Chat) {
 			continue
 		}
 
@@ -68,6 +122,30 @@ func (l *Loader) LoadConversationHistory(ctx context.Context, userId uuid.UUID,
 	return messages, nil
 }
 
+// summaryApplies reports whether the stored summary covers the start of the branch ending at leafId.
+// There is one summary per session: moving to another branch summarizes that branch again once it
+// exceeds the budget. Summaries written before branching existed record no message and cover every
+// message up to their time.
+func summaryApplies(chatSession *entity.ChatSession, messages []*entity.ChatMessage, tree *branch.Tree, leafId uuid.UUID) bool {
+	if chatSession.SummarizedUntil == nil {
+		return false
+	}
+
+	onPath := make(map[uuid.UUID]bool)
+	for _, message := range tree.Path(leafId) {
+		onPath[message.Id] = true
+	}
+	if chatSession.SummaryMessageId != nil {
+		return onPath[*chatSession.SummaryMessageId]
+	}
+	for _, message := range messages {
+		if !message.CreatedAt.After(*chatSession.SummarizedUntil) && !onPath[message.Id] {
+			return false
+		}
+	}
+	return true
+}
+
 // isRAGSystemPrompt detects if a message is a RAG system prompt.
 // These are the priming prompts seeded at session creation.
 func isRAGSystemPrompt(content string) bool {
@@ -100,8 +178,8 @@ func isRAGSystemPrompt(content string) bool {
 
 // PrepareCitations builds CONTEXTUAL citation list based on session state
 // Citations must reflect what was actually used to generate the answer
-func (l *Loader) PrepareCitations(userId uuid.UUID, sessionId uuid.UUID) []dto.CitationDTO {
-	session, found := l.sessionRepo.Get(sessionId.String())
+func (l *Loader) PrepareCitations(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) []dto.CitationDTO {
+	session, found := l.sessionRepo.Get(ctx, sessionId.String())
 	if !found {
 		return []dto.CitationDTO{}
 	}
@@ -0,0 +1,149 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"ai-notetaking-be/internal/constant"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/llm"
+)
+
+const (
+	// DefaultTokenBudget caps the estimated tokens of the summary plus the unsummarized turns
+	DefaultTokenBudget = 3000
+
+	// DefaultSummaryPrompt instructs the LLM how to fold older turns into the running summary
+	DefaultSummaryPrompt = `You maintain the running summary of a conversation between a user and an assistant that answers from the user's notes.
+Merge the new turns into the current summary. Keep decisions, facts and preferences the user stated, open questions and the titles of the notes discussed.
+Drop greetings and small talk. Write concise bullet points, at most 250 words, in the language of the conversation.
+Reply with the summary only.`
+
+	// minRecentMessages are always sent verbatim, however long they are
+	minRecentMessages = 4
+
+	// maxTranscriptMessageRunes caps each message quoted to the summarizer
+	maxTranscriptMessageRunes = 4000
+
+	summaryHeader = "Summary of the earlier conversation:\n"
+)
+
+// summaryOptions reads the token budget and summary prompt from ai_configurations, falling back to the defaults
+func summaryOptions(ctx context.Context, uow unitofwork.UnitOfWork) (int, string) {
+	budget := DefaultTokenBudget
+	prompt := DefaultSummaryPrompt
+
+	if config, err := uow.AiConfigRepository().FindConfigurationByKey(ctx, entity.AiConfigKeyChatHistoryTokenBudget); err == nil && config != nil {
+		if val, err := strconv.Atoi(config.Value); err == nil && val > 0 {
+			budget = val
+		}
+	}
+	if config, err := uow.AiConfigRepository().FindConfigurationByKey(ctx, entity.AiConfigKeyChatSummaryPrompt); err == nil && config != nil {
+		if val := strings.TrimSpace(config.Value); val != "" {
+			prompt = val
+		}
+	}
+
+	return budget, prompt
+}
+
+// estimateTokens approximates the token count of text (about four characters per token)
+func estimateTokens(text string) int {
+	return utf8.RuneCountInString(text)/4 + 1
+}
+
+func historyTokens(summary string, chats []*entity.ChatMessageRaw) int {
+	total := 0
+	if summary != "" {
+		total += estimateTokens(summary)
+	}
+	for _, chat := range chats {
+		total += estimateTokens(chat.Chat)
+	}
+	return total
+}
+
+// summaryCut returns how many of the oldest messages to fold into the summary. The newest messages
+// are kept verbatim up to half the budget, leaving room for the summary and the next turns so that
+// summarization does not run on every request. The cut never splits a question from its answer
+// or messages sharing a timestamp, since the summary boundary is stored as a time.
+func summaryCut(chats []*entity.ChatMessageRaw, budget int) int {
+	kept := 0
+	tokens := 0
+	for i := len(chats) - 1; i >= 0; i-- {
+		tokens += estimateTokens(chats[i].Chat)
+		if kept >= minRecentMessages && tokens > budget/2 {
+			break
+		}
+		kept++
+	}
+
+	cut := len(chats) - kept
+	for cut > 0 && (chats[cut].Role != constant.ChatMessageRoleUser || chats[cut].CreatedAt.Equal(chats[cut-1].CreatedAt)) {
+		cut--
+	}
+	return cut
+}
+
+// summarize asks the LLM to merge older messages into the current summary. Only the newest of them
+// that fit in twice the budget are quoted, so a long backlog does not overflow the model context.
+// RAG system prompts seeded at session creation are instructions, not conversation, and are left out.
+func (l *Loader) summarize(ctx context.Context, prompt, summary string, chats []*entity.ChatMessageRaw, budget int) (string, error) {
+	var turns []string
+	tokens := 0
+	for i := len(chats) - 1; i >= 0; i-- {
+		chat := chats[i]
+		if isRAGSystemPrompt(chat.Chat) {
+			continue
+		}
+		speaker := "User"
+		if chat.Role == constant.ChatMessageRoleModel {
+			speaker = "Assistant"
+		}
+		turn := speaker + ": " + truncateRunes(chat.Chat, maxTranscriptMessageRunes)
+		tokens += estimateTokens(turn)
+		if len(turns) > 0 && tokens > budget*2 {
+			break
+		}
+		turns = append(turns, turn)
+	}
+	if len(turns) == 0 {
+		return summary, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(prompt)
+	sb.WriteString("\n\n<current_summary>\n")
+	if summary == "" {
+		sb.WriteString("(empty)")
+	} else {
+		sb.WriteString(summary)
+	}
+	sb.WriteString("\n</current_summary>\n\n<new_turns>\n")
+	for i := len(turns) - 1; i >= 0; i-- {
+		sb.WriteString(turns[i])
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("</new_turns>")
+
+	updated, err := l.llmProvider.Generate(ctx, sb.String(), llm.WithTemperature(0.2))
+	if err != nil {
+		return "", err
+	}
+	updated = strings.TrimSpace(updated)
+	if updated == "" {
+		return "", errors.New("empty summary")
+	}
+	return updated, nil
+}
+
+func truncateRunes(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	return string([]rune(text)[:n])
+}
@@ -20,24 +20,26 @@ func NewFactory() *Factory {
 	return &Factory{}
 }
 
-// CreateUserMessage creates a chat message from user input
-func (f *Factory) CreateUserMessage(request *dto.SendChatRequest, now time.Time) entity.ChatMessage {
+// CreateUserMessage creates a chat message from user input, following the parent message
+func (f *Factory) CreateUserMessage(request *dto.SendChatRequest, parentId uuid.UUID, now time.Time) entity.ChatMessage {
 	return entity.ChatMessage{
 		Id:            uuid.New(),
 		Chat:          request.Chat,
 		Role:          constant.ChatMessageRoleUser,
 		ChatSessionId: request.ChatSessionId,
+		ParentId:      &parentId,
 		CreatedAt:     now,
 	}
 }
 
-// CreateModelMessage creates a chat message from model response
-func (f *Factory) CreateModelMessage(sessionId uuid.UUID, content string, now time.Time) entity.ChatMessage {
+// CreateModelMessage creates a chat message from model response, answering the parent message
+func (f *Factory) CreateModelMessage(sessionId uuid.UUID, parentId uuid.UUID, content string, now time.Time) entity.ChatMessage {
 	return entity.ChatMessage{
 		Id:            uuid.New(),
 		Chat:          content,
 		Role:          constant.ChatMessageRoleModel,
 		ChatSessionId: sessionId,
+		ParentId:      &parentId,
 		CreatedAt:     now.Add(1 * time.Second),
 	}
 }
@@ -53,6 +55,7 @@ func (f *Factory) SaveUserMessage(ctx context.Context, uow unitofwork.UnitOfWork
 		Chat:          message.Chat,
 		Role:          message.Role,
 		ChatSessionId: message.ChatSessionId,
+		ChatMessageId: &message.Id,
 		CreatedAt:     message.CreatedAt,
 	}
 	return uow.ChatMessageRawRepository().Create(ctx, &raw)
@@ -69,6 +72,7 @@ func (f *Factory) SaveModelMessage(ctx context.Context, uow unitofwork.UnitOfWor
 		Chat:          message.Chat,
 		Role:          message.Role,
 		ChatSessionId: message.ChatSessionId,
+		ChatMessageId: &message.Id,
 		CreatedAt:     message.CreatedAt,
 	}
 	if err := uow.ChatMessageRawRepository().Create(ctx, &raw); err != nil {
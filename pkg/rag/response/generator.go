@@ -34,6 +34,28 @@ func (g *Generator) GenerateFromGroundedContext(
 	groundedContext *ragcontext.GroundedContext,
 	history []llm.Message,
 ) string {
+	return g.generate(ctx, query, groundedContext, history, nil)
+}
+
+// StreamFromGroundedContext is the streaming variant of GenerateFromGroundedContext.
+// Every delta is pushed to onDelta; the full (or partial, if aborted) answer is returned.
+func (g *Generator) StreamFromGroundedContext(
+	ctx context.Context,
+	query string,
+	groundedContext *ragcontext.GroundedContext,
+	history []llm.Message,
+	onDelta llm.StreamHandler,
+) string {
+	return g.generate(ctx, query, groundedContext, history, onDelta)
+}
+
+func (g *Generator) generate(
+	ctx context.Context,
+	query string,
+	groundedContext *ragcontext.GroundedContext,
+	history []llm.Message,
+	onDelta llm.StreamHandler,
+) string {
 
 	// Handle META_ANALYSIS (Scope None)
 	if groundedContext != nil && groundedContext.Scope == intent.ScopeNone {
@@ -42,17 +64,20 @@ func (g *Generator) GenerateFromGroundedContext(
 		promptText := fmt.Sprintf("<task>\nAnswer the user's question regarding the CONVERSATION HISTORY above.\nDo NOT look for new information.\n</task>\n\nQuestion: %s", query)
 		fullHistory := append(history, llm.Message{Role: "user", Content: promptText})
 
-		response, err := g.llmProvider.Chat(ctx, fullHistory)
+		response, err := g.complete(ctx, fullHistory, onDelta)
 		if err != nil {
 			g.logger.Printf("[ERROR] LLM generation failed: %v", err)
-			return "Sorry, an error occurred while generating the answer."
+			if response != "" {
+				return response // Stream aborted midway, keep what was delivered
+			}
+			return Emit(onDelta, "Sorry, an error occurred while generating the answer.")
 		}
 		return response
 	}
 
 	if groundedContext == nil || len(groundedContext.Notes) == 0 {
 		g.logger.Printf("[ERROR] Cannot generate: no grounded context")
-		return "Sorry, no context is available to answer your question."
+		return Emit(onDelta, "Sorry, no context is available to answer your question.")
 	}
 
 	// Build grounded prompt
@@ -62,10 +87,13 @@ func (g *Generator) GenerateFromGroundedContext(
 	fullHistory := append(history, llm.Message{Role: "user", Content: promptText})
 
 	// Generate response
-	response, err := g.llmProvider.Chat(ctx, fullHistory)
+	response, err := g.complete(ctx, fullHistory, onDelta)
 	if err != nil {
 		g.logger.Printf("[ERROR] LLM generation failed: %v", err)
-		return "Maaf, terjadi kesalahan saat menyusun jawaban."
+		if response != "" {
+			return response // Stream aborted midway, keep what was delivered
+		}
+		return Emit(onDelta, "Maaf, terjadi kesalahan saat menyusun jawaban.")
 	}
 
 	g.logger.Printf("[GENERATION] Answer generated from %d notes (Scope: %s)",
@@ -74,6 +102,23 @@ func (g *Generator) GenerateFromGroundedContext(
 	return response
 }
 
+// complete calls the LLM, streaming through onDelta when a handler is given
+func (g *Generator) complete(ctx context.Context, messages []llm.Message, onDelta llm.StreamHandler) (string, error) {
+	if onDelta == nil {
+		return g.llmProvider.Chat(ctx, messages)
+	}
+	return g.llmProvider.ChatStream(ctx, messages, onDelta)
+}
+
+// Emit pushes a pre-built reply to onDelta as a single chunk (no-op when not streaming).
+// Used for canned/fallback messages so streaming clients still receive the full text.
+func Emit(onDelta llm.StreamHandler, text string) string {
+	if onDelta != nil && text != "" {
+		_ = onDelta(text)
+	}
+	return text
+}
+
 func (g *Generator) buildGroundedPrompt(query string, groundedContext *ragcontext.GroundedContext) string {
 	var prompt strings.Builder
 
@@ -35,6 +35,21 @@ type Config struct {
 	DBThreshold    float64
 	LogicThreshold float64
 	TopK           int
+	TagIDs         []uuid.UUID // Restrict to notes carrying any of these tags (empty = all notes)
+}
+
+// ConfigForSession returns the default configuration restricted to the session's tag scope
+func ConfigForSession(session *store.Session) Config {
+	config := DefaultConfig()
+	if session == nil {
+		return config
+	}
+	for _, id := range session.TagIDs {
+		if tagId, err := uuid.Parse(id); err == nil {
+			config.TagIDs = append(config.TagIDs, tagId)
+		}
+	}
+	return config
 }
 
 // DefaultConfig returns default search configuration
@@ -61,6 +76,12 @@ func (o *Orchestrator) Execute(
 		return nil, fmt.Errorf("embedding generation failed: %w", err)
 	}
 
+	// Restrict to the tag scope, if any
+	var specs []specification.Specification
+	if len(config.TagIDs) > 0 {
+		specs = append(specs, specification.NoteHasAnyTag{TagIDs: config.TagIDs})
+	}
+
 	// Execute vector search
 	scoredResults, err := uow.NoteEmbeddingRepository().SearchSimilarWithScore(
 		ctx,
@@ -68,6 +89,7 @@ func (o *Orchestrator) Execute(
 		config.TopK,
 		userId,
 		config.DBThreshold,
+		specs...,
 	)
 	if err != nil {
 		o.logger.Printf("[ERROR] Vector search failed: %v", err)
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"ai-notetaking-be/internal/entity"
-	"ai-notetaking-be/internal/repository/memory"
 	"ai-notetaking-be/internal/repository/specification"
 	"ai-notetaking-be/internal/repository/unitofwork"
 	"ai-notetaking-be/pkg/store"
@@ -16,31 +15,52 @@ import (
 
 // Manager handles session operations
 type Manager struct {
-	sessionRepo *memory.SessionRepository
+	sessionRepo store.SessionStore
 }
 
 // NewManager creates a new session manager
-func NewManager(sessionRepo *memory.SessionRepository) *Manager {
+func NewManager(sessionRepo store.SessionStore) *Manager {
 	return &Manager{sessionRepo: sessionRepo}
 }
 
-// LoadOrCreate retrieves or creates an in-memory session
-func (m *Manager) LoadOrCreate(userId uuid.UUID, sessionId uuid.UUID) *store.Session {
-	sessionID := sessionId.String()
-	session, found := m.sessionRepo.Get(sessionID)
-	if !found {
-		session = &store.Session{
-			ID:     sessionID,
-			UserID: userId.String(),
-			State:  store.StateBrowsing,
-		}
+// LoadOrCreate retrieves the session state, rebuilding it from the branch ending at leafId when the
+// store has lost it (expiry, restart, another instance). A session without history starts fresh.
+func (m *Manager) LoadOrCreate(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, sessionId uuid.UUID, leafId uuid.UUID) *store.Session {
+	if session, found := m.sessionRepo.Get(ctx, sessionId.String()); found {
+		return session
 	}
+
+	session, err := Rehydrate(ctx, uow, userId, sessionId, leafId)
+	if err != nil {
+		// The state only improves follow-up questions; answer without it
+		return NewSession(userId, sessionId)
+	}
+	if session.Mode != "" || len(session.Candidates) > 0 {
+		m.sessionRepo.Save(ctx, session)
+	}
+	return session
+}
+
+// Rebuild replaces the session state with the state of the branch ending at leafId. It runs when
+// the conversation moves to another branch, whose mode and focus may differ from the stored ones.
+// The tag scope is kept; it belongs to the requests, not to the branch.
+func (m *Manager) Rebuild(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, sessionId uuid.UUID, leafId uuid.UUID) *store.Session {
+	session, err := Rehydrate(ctx, uow, userId, sessionId, leafId)
+	if err != nil {
+		// Stale state is worse than none here; the next request starts fresh
+		m.sessionRepo.Delete(ctx, sessionId.String())
+		return NewSession(userId, sessionId)
+	}
+	if previous, found := m.sessionRepo.Get(ctx, sessionId.String()); found {
+		session.TagIDs = previous.TagIDs
+	}
+	m.sessionRepo.Save(ctx, session)
 	return session
 }
 
 // Save persists session state
-func (m *Manager) Save(session *store.Session) {
-	m.sessionRepo.Save(session)
+func (m *Manager) Save(ctx context.Context, session *store.Session) {
+	m.sessionRepo.Save(ctx, session)
 }
 
 // VerifyChatSession validates session ownership
@@ -0,0 +1,140 @@
+package session
+
+import (
+	"context"
+
+	"ai-notetaking-be/internal/constant"
+	"ai-notetaking-be/internal/entity"
+	"ai-notetaking-be/internal/repository/specification"
+	"ai-notetaking-be/internal/repository/unitofwork"
+	"ai-notetaking-be/pkg/ai/router"
+	"ai-notetaking-be/pkg/rag/branch"
+	"ai-notetaking-be/pkg/store"
+
+	"github.com/google/uuid"
+)
+
+// rehydrateWindow is how many recent messages of the branch are searched for the last cited notes and mode switch
+const rehydrateWindow = 20
+
+// NewSession returns the state of a conversation without history
+func NewSession(userId uuid.UUID, sessionId uuid.UUID) *store.Session {
+	return &store.Session{
+		ID:     sessionId.String(),
+		UserID: userId.String(),
+		State:  store.StateBrowsing,
+	}
+}
+
+// Rehydrate rebuilds session state from the persisted chat messages and citations of the branch
+// ending at leafId:
+//   - the mode is the last sticky mode (bypass, nuance) a user message switched to
+//   - the notes cited by the latest answer with citations become the focus (one note)
+//     or the candidates to choose from (several notes)
+//
+// Aggregated focus and the tag scope are not recorded in the history; the former comes back
+// as candidates and the latter is set again by the next request.
+func Rehydrate(ctx context.Context, uow unitofwork.UnitOfWork, userId uuid.UUID, sessionId uuid.UUID, leafId uuid.UUID) (*store.Session, error) {
+	session := NewSession(userId, sessionId)
+
+	tree, err := branch.Load(ctx, uow, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	messages := tree.Path(leafId)
+	if len(messages) > rehydrateWindow {
+		messages = messages[len(messages)-rehydrateWindow:]
+	}
+
+	// Newest first
+	var answerIds []uuid.UUID
+	for i := len(messages) - 1; i >= 0; i-- {
+		message := messages[i]
+		switch message.Role {
+		case constant.ChatMessageRoleUser:
+			if session.Mode == "" {
+				session.Mode = stickyMode(message.Chat)
+			}
+		case constant.ChatMessageRoleModel:
+			answerIds = append(answerIds, message.Id)
+		}
+	}
+	if len(answerIds) == 0 {
+		return session, nil
+	}
+
+	citations, err := uow.ChatMessageRepository().FindCitationsByMessageIds(ctx, answerIds)
+	if err != nil {
+		return nil, err
+	}
+	noteIds := latestCitedNotes(answerIds, citations)
+	if len(noteIds) == 0 {
+		return session, nil
+	}
+
+	// Notes deleted or no longer shared since the answer are left out
+	notes, err := uow.NoteRepository().FindAll(ctx,
+		specification.ByIDs{IDs: noteIds},
+		specification.NoteAccessibleBy{UserID: userId},
+	)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[uuid.UUID]*entity.Note, len(notes))
+	for _, note := range notes {
+		byId[note.Id] = note
+	}
+
+	var cited []*entity.Note
+	for _, id := range noteIds {
+		if note, ok := byId[id]; ok {
+			cited = append(cited, note)
+			session.Candidates = append(session.Candidates, store.Document{
+				ID:    note.Id.String(),
+				Title: note.Title,
+			})
+		}
+	}
+
+	if len(cited) == 1 {
+		session.FocusedNote = &store.Document{
+			ID:      cited[0].Id.String(),
+			Title:   cited[0].Title,
+			Content: cited[0].Content,
+		}
+		session.State = store.StateFocused
+	}
+	return session, nil
+}
+
+// stickyMode returns the session mode a user message switches to, or "" for plain RAG messages
+func stickyMode(chat string) string {
+	switch mode := router.Parse(chat).Mode; mode {
+	case router.ModeBypass, router.ModeBypassNuance, router.ModeRAGNuance:
+		return string(mode)
+	}
+	return ""
+}
+
+// latestCitedNotes returns the notes cited by the newest answer that has citations, in citation order
+func latestCitedNotes(answerIds []uuid.UUID, citations []*entity.ChatCitation) []uuid.UUID {
+	byMessage := make(map[uuid.UUID][]uuid.UUID)
+	for _, citation := range citations {
+		byMessage[citation.ChatMessageId] = append(byMessage[citation.ChatMessageId], citation.NoteId)
+	}
+
+	for _, id := range answerIds {
+		if noteIds := byMessage[id]; len(noteIds) > 0 {
+			seen := make(map[uuid.UUID]bool, len(noteIds))
+			unique := noteIds[:0]
+			for _, noteId := range noteIds {
+				if !seen[noteId] {
+					seen[noteId] = true
+					unique = append(unique, noteId)
+				}
+			}
+			return unique
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid search cursor")
+
+// Cursor is the opaque pagination position handed back to clients.
+// Ranked results page by Offset; date-ordered listings page by keyset (SortValue, Id).
+type Cursor struct {
+	Offset    int        `json:"o,omitempty"`
+	SortValue *time.Time `json:"t,omitempty"`
+	Id        uuid.UUID  `json:"i,omitempty"`
+}
+
+// EncodeCursor serializes a cursor into a URL-safe token
+func EncodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor. An empty token is the first page.
+func DecodeCursor(token string) (Cursor, error) {
+	var c Cursor
+	if token == "" {
+		return c, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil || c.Offset < 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRRFK is the usual Reciprocal Rank Fusion constant; larger values flatten the rank curve
+const DefaultRRFK = 60
+
+// FusedResult is one document after Reciprocal Rank Fusion
+type FusedResult struct {
+	Id    uuid.UUID
+	Score float64
+	Ranks []int // 1-based rank in each input list, 0 when absent from that list
+}
+
+// ReciprocalRankFusion merges several rankings (best first) into one.
+// Each document scores sum(1 / (k + rank)) over the lists it appears in; duplicates
+// within a list only count at their best rank. Ties keep first-seen order.
+func ReciprocalRankFusion(k int, rankings ...[]uuid.UUID) []FusedResult {
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+
+	index := make(map[uuid.UUID]int)
+	var results []FusedResult
+
+	for listIdx, ranking := range rankings {
+		rank := 0
+		for _, id := range ranking {
+			i, ok := index[id]
+			if !ok {
+				i = len(results)
+				index[id] = i
+				results = append(results, FusedResult{Id: id, Ranks: make([]int, len(rankings))})
+			}
+			if results[i].Ranks[listIdx] != 0 {
+				continue // Already counted at a better rank
+			}
+			rank++
+			results[i].Ranks[listIdx] = rank
+			results[i].Score += 1.0 / float64(k+rank)
+		}
+	}
+
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].Score > results[b].Score
+	})
+
+	return results
+}
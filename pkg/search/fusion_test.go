@@ -0,0 +1,46 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestReciprocalRankFusion(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	lexical := []uuid.UUID{a, b, c}
+	semantic := []uuid.UUID{c, a, a, d} // duplicate chunk hit for a
+
+	results := ReciprocalRankFusion(60, lexical, semantic)
+
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+
+	// a: 1/61 + 1/62, c: 1/63 + 1/61, b: 1/62, d: 1/63
+	wantOrder := []uuid.UUID{a, c, b, d}
+	for i, id := range wantOrder {
+		if results[i].Id != id {
+			t.Errorf("position %d: got %s, want %s", i, results[i].Id, id)
+		}
+	}
+
+	if got := results[0].Ranks; got[0] != 1 || got[1] != 2 {
+		t.Errorf("ranks for a = %v, want [1 2]", got)
+	}
+	if got := results[2].Ranks; got[0] != 2 || got[1] != 0 {
+		t.Errorf("ranks for b = %v, want [2 0]", got)
+	}
+	if got := results[3].Ranks; got[1] != 3 {
+		t.Errorf("rank for d in semantic list = %d, want 3 (duplicates skipped)", got[1])
+	}
+}
+
+func TestReciprocalRankFusionDefaultK(t *testing.T) {
+	id := uuid.New()
+	results := ReciprocalRankFusion(0, []uuid.UUID{id})
+	if len(results) != 1 || results[0].Score != 1.0/float64(DefaultRRFK+1) {
+		t.Fatalf("unexpected result: %+v", results)
+	}
+}
@@ -2,20 +2,46 @@ package search
 
 import (
 	"strings"
+	"time"
+)
+
+// Sort orders for search results
+const (
+	SortRelevance = "relevance"
+	SortUpdated   = "updated"
+	SortCreated   = "created"
 )
 
 // SearchFilters holds the extracted filters and the remaining clean query
 type SearchFilters struct {
-	NotebookName string
-	NoteTitle    string
-	SearchQuery  string // The remaining text to search in Content/Title
+	NotebookName    string
+	IncludeChildren bool // NotebookName also matches notes in descendant notebooks
+	NoteTitle       string
+	Tag             string
+	HasChecklist    bool
+	After           *time.Time // Inclusive lower bound on last modification
+	Before          *time.Time // Exclusive upper bound on last modification
+	Sort            string     // SortRelevance | SortUpdated | SortCreated, empty = default
+	SearchQuery     string     // The remaining text to search in Content/Title
+}
+
+// HasConstraints reports whether any filter (other than sort) narrows the result set
+func (f SearchFilters) HasConstraints() bool {
+	return f.NotebookName != "" || f.NoteTitle != "" || f.Tag != "" || f.HasChecklist ||
+		f.After != nil || f.Before != nil
 }
 
 // ParseQuery extracts slash commands from the raw query string
 // Supported:
 // /nb:<term> OR /in:<term> -> Filter by Notebook Name
+// /under:<term> -> Filter by Notebook Name, including child notebooks
 // /note:<term> -> Filter by Note Title
+// /tag:<name> -> Filter by tag
+// /has:checklist -> Only notes containing a checklist
+// /after:<date> /before:<date> -> Last modified within range (YYYY-MM-DD, both days inclusive)
+// /sort:relevance|updated|created -> Result order
 // <text> -> Remaining text is the SearchQuery
+// Malformed values (unknown /has:, bad dates, unknown sort) are kept as search text.
 func ParseQuery(raw string) SearchFilters {
 	filters := SearchFilters{}
 	parts := strings.Fields(raw)
@@ -29,8 +55,21 @@ func ParseQuery(raw string) SearchFilters {
 		} else if strings.HasPrefix(lowerPart, "/in:") {
 			// Alias for /nb:
 			filters.NotebookName = strings.TrimPrefix(lowerPart, "/in:")
+		} else if strings.HasPrefix(lowerPart, "/under:") {
+			filters.NotebookName = strings.TrimPrefix(lowerPart, "/under:")
+			filters.IncludeChildren = true
 		} else if strings.HasPrefix(lowerPart, "/note:") {
 			filters.NoteTitle = strings.TrimPrefix(lowerPart, "/note:")
+		} else if strings.HasPrefix(lowerPart, "/tag:") {
+			filters.Tag = strings.TrimPrefix(strings.TrimPrefix(lowerPart, "/tag:"), "#")
+		} else if lowerPart == "/has:checklist" {
+			filters.HasChecklist = true
+		} else if t, ok := parseDateCommand(part, "/after:", false); ok {
+			filters.After = &t
+		} else if t, ok := parseDateCommand(part, "/before:", true); ok {
+			filters.Before = &t
+		} else if sort, ok := parseSortCommand(lowerPart); ok {
+			filters.Sort = sort
 		} else {
 			cleanParts = append(cleanParts, part)
 		}
@@ -39,3 +78,49 @@ func ParseQuery(raw string) SearchFilters {
 	filters.SearchQuery = strings.Join(cleanParts, " ")
 	return filters
 }
+
+// ParseDateBound parses a date filter value: either a day (YYYY-MM-DD, UTC) or an RFC3339 instant.
+// For an upper bound (end=true) a day is extended to cover the whole day.
+func ParseDateBound(value string, end bool) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// NormalizeSort validates a sort value, returning "" for anything unknown
+func NormalizeSort(value string) string {
+	switch strings.ToLower(value) {
+	case SortRelevance:
+		return SortRelevance
+	case SortUpdated:
+		return SortUpdated
+	case SortCreated:
+		return SortCreated
+	}
+	return ""
+}
+
+// parseDateCommand matches the prefix in any case but parses the value as written, since RFC3339
+// needs its upper-case T and Z
+func parseDateCommand(part, prefix string, end bool) (time.Time, bool) {
+	if len(part) < len(prefix) || !strings.EqualFold(part[:len(prefix)], prefix) {
+		return time.Time{}, false
+	}
+	t, err := ParseDateBound(part[len(prefix):], end)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func parseSortCommand(part string) (string, bool) {
+	if !strings.HasPrefix(part, "/sort:") {
+		return "", false
+	}
+	sort := NormalizeSort(strings.TrimPrefix(part, "/sort:"))
+	return sort, sort != ""
+}
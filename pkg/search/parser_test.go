@@ -0,0 +1,69 @@
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQueryFilters(t *testing.T) {
+	f := ParseQuery("budget /under:Work /tag:#Finance /has:checklist /after:2024-01-01 /before:2024-01-31 /sort:updated plan")
+
+	if f.SearchQuery != "budget plan" {
+		t.Errorf("SearchQuery = %q", f.SearchQuery)
+	}
+	if f.NotebookName != "work" || !f.IncludeChildren {
+		t.Errorf("notebook = %q (children %v), want work subtree", f.NotebookName, f.IncludeChildren)
+	}
+	if f.Tag != "finance" || !f.HasChecklist || f.Sort != SortUpdated {
+		t.Errorf("unexpected filters: %+v", f)
+	}
+	if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); f.After == nil || !f.After.Equal(want) {
+		t.Errorf("After = %v, want %v", f.After, want)
+	}
+	// Upper bound covers the whole day
+	if want := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC); f.Before == nil || !f.Before.Equal(want) {
+		t.Errorf("Before = %v, want %v", f.Before, want)
+	}
+	if !f.HasConstraints() {
+		t.Error("expected constraints")
+	}
+}
+
+func TestParseQueryDateInstants(t *testing.T) {
+	f := ParseQuery("/AFTER:2024-01-01T08:30:00Z /Before:2024-01-02T10:00:00+07:00 standup")
+
+	if want := time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC); f.After == nil || !f.After.Equal(want) {
+		t.Errorf("After = %v, want %v", f.After, want)
+	}
+	if want := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC); f.Before == nil || !f.Before.Equal(want) {
+		t.Errorf("Before = %v, want %v", f.Before, want)
+	}
+	if f.SearchQuery != "standup" {
+		t.Errorf("SearchQuery = %q", f.SearchQuery)
+	}
+}
+
+func TestParseQueryKeepsMalformedCommandsAsText(t *testing.T) {
+	f := ParseQuery("/after:yesterday /sort:random /has:images /nb:inbox")
+
+	if f.SearchQuery != "/after:yesterday /sort:random /has:images" {
+		t.Errorf("SearchQuery = %q", f.SearchQuery)
+	}
+	if f.After != nil || f.Sort != "" || f.HasChecklist || f.IncludeChildren {
+		t.Errorf("unexpected filters: %+v", f)
+	}
+	if f.NotebookName != "inbox" {
+		t.Errorf("NotebookName = %q", f.NotebookName)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	c, err := DecodeCursor(EncodeCursor(Cursor{Offset: 20, SortValue: &ts}))
+	if err != nil || c.Offset != 20 || c.SortValue == nil || !c.SortValue.Equal(ts) {
+		t.Fatalf("round trip = %+v, %v", c, err)
+	}
+	if _, err := DecodeCursor("not a cursor!"); err != ErrInvalidCursor {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
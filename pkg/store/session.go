@@ -24,6 +24,9 @@ type Session struct {
 
 	// Metadata for last interaction
 	LastQuery string `json:"last_query"`
+
+	// Tag scope: when set, note search only considers notes carrying any of these tags
+	TagIDs []string `json:"tag_ids,omitempty"`
 }
 
 const (
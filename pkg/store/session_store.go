@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SessionStore keeps the conversation state of chat sessions between requests.
+// The state is a cache of what the chat history implies: implementations log and swallow
+// backend errors, and a miss is answered by rebuilding the state from the stored messages.
+type SessionStore interface {
+	Get(ctx context.Context, sessionID string) (*Session, bool)
+	Save(ctx context.Context, session *Session)
+	Delete(ctx context.Context, sessionID string)
+}
+
+// SessionFormatVersion is bumped whenever Session changes incompatibly.
+// State written in another format is treated as missing and rebuilt.
+const SessionFormatVersion = 1
+
+var ErrSessionFormat = errors.New("unsupported session format")
+
+type sessionEnvelope struct {
+	Version int             `json:"v"`
+	Session json.RawMessage `json:"session"`
+}
+
+// EncodeSession serializes a session together with the format version
+func EncodeSession(session *Session) ([]byte, error) {
+	body, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sessionEnvelope{Version: SessionFormatVersion, Session: body})
+}
+
+// DecodeSession reads a session written by EncodeSession, rejecting other format versions
+func DecodeSession(data []byte) (*Session, error) {
+	var envelope sessionEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Version != SessionFormatVersion {
+		return nil, fmt.Errorf("%w: version %d", ErrSessionFormat, envelope.Version)
+	}
+
+	var session Session
+	if err := json.Unmarshal(envelope.Session, &session); err != nil {
+		return nil, err
+	}
+	if session.ID == "" {
+		return nil, fmt.Errorf("%w: missing session id", ErrSessionFormat)
+	}
+	return &session, nil
+}
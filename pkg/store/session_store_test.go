@@ -0,0 +1,52 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	session := &Session{
+		ID:         "c0ffee",
+		UserID:     "u1",
+		State:      StateFocused,
+		Mode:       ModeBypass,
+		Candidates: []Document{{ID: "n1", Title: "First", Score: 0.5}, {ID: "n2", Title: "Second"}},
+		FocusedNote: &Document{
+			ID:      "n1",
+			Title:   "First",
+			Content: "body",
+		},
+		LastQuery: "what about the first one?",
+		TagIDs:    []string{"t1"},
+	}
+
+	data, err := EncodeSession(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeSession(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, session) {
+		t.Fatalf("got %+v, want %+v", got, session)
+	}
+}
+
+func TestDecodeSessionRejectsOtherVersions(t *testing.T) {
+	for _, data := range []string{
+		`{"v":0,"session":{"id":"s"}}`,
+		`{"v":2,"session":{"id":"s"}}`,
+		`{"id":"s","state":"BROWSING"}`, // Unversioned state from before the envelope
+		`{"v":1,"session":{}}`,
+	} {
+		if _, err := DecodeSession([]byte(data)); !errors.Is(err, ErrSessionFormat) {
+			t.Errorf("%s: got %v, want ErrSessionFormat", data, err)
+		}
+	}
+	if _, err := DecodeSession([]byte("not json")); err == nil {
+		t.Error("expected an error for malformed data")
+	}
+}
@@ -0,0 +1,307 @@
+// Package vault reads Markdown vaults (a zip of .md files, e.g. an exported Obsidian vault)
+package vault
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Archive limits, guarding against zip bombs
+const (
+	MaxNotes     = 5000
+	MaxNoteBytes = 5 << 20   // Per .md file, uncompressed
+	MaxTotalSize = 200 << 20 // All .md files, uncompressed
+	maxNameRunes = 255       // Note title / notebook name column size
+)
+
+var (
+	ErrInvalidArchive = errors.New("file is not a valid zip archive")
+	ErrNoNotes        = errors.New("archive contains no markdown notes")
+	ErrTooLarge       = errors.New("archive exceeds the import limits")
+)
+
+// Folder is a directory of the vault; it becomes a notebook
+type Folder struct {
+	Path   string // Slash-separated path inside the vault
+	Name   string
+	Parent string // Path of the parent folder, "" for top-level folders
+}
+
+// Note is a Markdown file of the vault
+type Note struct {
+	Folder   string // Path of the containing folder, "" for the vault root
+	Title    string // File name without extension (what [[wikilinks]] refer to)
+	Tags     []string
+	Markdown string // Body without front matter, wikilinks normalized to [[Title|alias]]
+}
+
+// Vault is the parsed archive. Folders are sorted so that parents come before children.
+type Vault struct {
+	Name    string
+	Folders []Folder
+	Notes   []Note
+}
+
+// Read parses a zip archive. name is the uploaded file name, used as the vault name unless
+// every note sits under a single top-level folder (the usual shape of a zipped vault).
+// Hidden files and folders (.obsidian, .trash, ...) and non-Markdown files are ignored.
+func Read(r io.ReaderAt, size int64, name string) (*Vault, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	type file struct {
+		path string
+		zf   *zip.File
+	}
+	var files []file
+	var total uint64
+	for _, zf := range archive.File {
+		p, ok := notePath(zf)
+		if !ok {
+			continue
+		}
+		if len(files) == MaxNotes || zf.UncompressedSize64 > MaxNoteBytes {
+			return nil, ErrTooLarge
+		}
+		total += zf.UncompressedSize64
+		if total > MaxTotalSize {
+			return nil, ErrTooLarge
+		}
+		files = append(files, file{path: p, zf: zf})
+	}
+	if len(files) == 0 {
+		return nil, ErrNoNotes
+	}
+
+	// Strip a single wrapping folder
+	vaultName := strings.TrimSuffix(path.Base(strings.ReplaceAll(name, "\\", "/")), path.Ext(name))
+	if top := commonTopFolder(files[0].path); top != "" {
+		shared := true
+		for _, f := range files {
+			if commonTopFolder(f.path) != top {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			vaultName = top
+			for i := range files {
+				files[i].path = strings.TrimPrefix(files[i].path, top+"/")
+			}
+		}
+	}
+	if strings.TrimSpace(vaultName) == "" || vaultName == "." {
+		vaultName = "Imported vault"
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+
+	vault := &Vault{Name: truncate(vaultName)}
+	folders := make(map[string]bool)
+	for _, f := range files {
+		content, err := readFile(f.zf)
+		if err != nil {
+			return nil, err
+		}
+
+		dir := path.Dir(f.path)
+		if dir == "." {
+			dir = ""
+		}
+		for d := dir; d != "" && !folders[d]; d = parentDir(d) {
+			folders[d] = true
+			vault.Folders = append(vault.Folders, Folder{Path: d, Name: truncate(path.Base(d)), Parent: parentDir(d)})
+		}
+
+		tags, body := ParseFrontMatter(content)
+		base := path.Base(f.path)
+		vault.Notes = append(vault.Notes, Note{
+			Folder:   dir,
+			Title:    truncate(strings.TrimSuffix(base, path.Ext(base))),
+			Tags:     tags,
+			Markdown: NormalizeWikilinks(body),
+		})
+	}
+	sort.Slice(vault.Folders, func(i, j int) bool { return vault.Folders[i].Path < vault.Folders[j].Path })
+
+	return vault, nil
+}
+
+// notePath returns the cleaned path of a Markdown entry, or false for anything to skip
+func notePath(zf *zip.File) (string, bool) {
+	if zf.FileInfo().IsDir() {
+		return "", false
+	}
+	p := path.Clean("/" + strings.ReplaceAll(zf.Name, "\\", "/"))[1:]
+	ext := strings.ToLower(path.Ext(p))
+	if ext != ".md" && ext != ".markdown" {
+		return "", false
+	}
+	for _, segment := range strings.Split(p, "/") {
+		if segment == "" || strings.HasPrefix(segment, ".") || segment == "__MACOSX" {
+			return "", false
+		}
+	}
+	if !utf8.ValidString(p) {
+		return "", false
+	}
+	return p, true
+}
+
+func readFile(zf *zip.File) (string, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return "", ErrInvalidArchive
+	}
+	defer rc.Close()
+
+	// The header size can lie; never read more than the limit
+	data, err := io.ReadAll(io.LimitReader(rc, MaxNoteBytes+1))
+	if err != nil {
+		return "", ErrInvalidArchive
+	}
+	if len(data) > MaxNoteBytes {
+		return "", ErrTooLarge
+	}
+	return strings.ToValidUTF8(strings.TrimPrefix(string(data), "\uFEFF"), "\uFFFD"), nil
+}
+
+func commonTopFolder(p string) string {
+	if i := strings.Index(p, "/"); i > 0 {
+		return p[:i]
+	}
+	return ""
+}
+
+func parentDir(p string) string {
+	if d := path.Dir(p); d != "." {
+		return d
+	}
+	return ""
+}
+
+func truncate(s string) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= maxNameRunes {
+		return s
+	}
+	return string([]rune(s)[:maxNameRunes])
+}
+
+var (
+	frontMatterListItem = regexp.MustCompile(`^\s*-\s*(.*)$`)
+	wikilinkPattern     = regexp.MustCompile(`(!?)\[\[([^\[\]\n]+)\]\]`)
+)
+
+// ParseFrontMatter splits YAML front matter off a Markdown document and returns its tags.
+// Tags may be an inline list ([a, b]), a comma or space separated string, or a block list;
+// quotes and a leading '#' are removed and duplicates (case-insensitive) dropped.
+func ParseFrontMatter(markdown string) ([]string, string) {
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	if !strings.HasPrefix(markdown, "---\n") {
+		return nil, markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if t := strings.TrimSpace(lines[i]); t == "---" || t == "..." {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, markdown
+	}
+	body := strings.TrimLeft(strings.Join(lines[end+1:], "\n"), "\n")
+
+	var raw []string
+	for i := 1; i < end; i++ {
+		key, value, ok := strings.Cut(lines[i], ":")
+		if !ok || strings.HasPrefix(key, " ") {
+			continue
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "tags" && key != "tag" {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, "["):
+			raw = append(raw, strings.Split(strings.Trim(value, "[]"), ",")...)
+		case value != "":
+			raw = append(raw, strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })...)
+		default:
+			for i+1 < end {
+				match := frontMatterListItem.FindStringSubmatch(lines[i+1])
+				if match == nil {
+					break
+				}
+				raw = append(raw, match[1])
+				i++
+			}
+		}
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range raw {
+		tag = strings.TrimPrefix(strings.Trim(strings.TrimSpace(tag), `"'`), "#")
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+	return tags, body
+}
+
+// NormalizeWikilinks rewrites Obsidian links to the [[Title|alias]] form notes are linked by:
+// folder paths, .md extensions and #heading / #^block anchors are dropped. Links to
+// attachments (other extensions) and to headings of the same note become plain text.
+func NormalizeWikilinks(markdown string) string {
+	return wikilinkPattern.ReplaceAllStringFunc(markdown, func(match string) string {
+		parts := wikilinkPattern.FindStringSubmatch(match)
+		target, alias, hasAlias := strings.Cut(parts[2], "|")
+		alias = strings.TrimSpace(alias)
+
+		anchor := ""
+		if i := strings.Index(target, "#"); i >= 0 {
+			target, anchor = target[:i], strings.TrimPrefix(target[i+1:], "^")
+		}
+		target = strings.TrimSpace(path.Base(strings.ReplaceAll(strings.TrimSpace(target), "\\", "/")))
+		if target == "." || target == "/" {
+			target = ""
+		}
+
+		ext := strings.ToLower(path.Ext(target))
+		if ext == ".md" || ext == ".markdown" {
+			target = strings.TrimSuffix(target, path.Ext(target))
+			ext = ""
+		}
+
+		if target == "" || ext != "" {
+			switch {
+			case hasAlias && alias != "":
+				return alias
+			case target != "":
+				return target
+			default:
+				return strings.TrimSpace(anchor)
+			}
+		}
+		if hasAlias && alias != "" {
+			return "[[" + target + "|" + alias + "]]"
+		}
+		return "[[" + target + "]]"
+	})
+}
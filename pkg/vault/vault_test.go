@@ -0,0 +1,100 @@
+package vault
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func buildZip(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestReadVaultStructure(t *testing.T) {
+	r := buildZip(t, map[string]string{
+		"My Vault/Home.md":                   "---\ntags: [start, \"#Home\"]\n---\nSee [[Projects/Plan#Goals|the plan]].",
+		"My Vault/Projects/Plan.md":          "# Plan",
+		"My Vault/Projects/2024/Q1.markdown": "Q1",
+		"My Vault/.obsidian/workspace.md":    "ignored",
+		"My Vault/assets/image.png":          "ignored",
+		"__MACOSX/My Vault/._Home.md":        "ignored",
+	})
+
+	v, err := Read(r, r.Size(), "export.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Name != "My Vault" {
+		t.Errorf("name = %q", v.Name)
+	}
+	wantFolders := []Folder{
+		{Path: "Projects", Name: "Projects"},
+		{Path: "Projects/2024", Name: "2024", Parent: "Projects"},
+	}
+	if !reflect.DeepEqual(v.Folders, wantFolders) {
+		t.Errorf("folders = %+v", v.Folders)
+	}
+	wantNotes := []Note{
+		{Folder: "", Title: "Home", Tags: []string{"start", "Home"}, Markdown: "See [[Plan|the plan]]."},
+		{Folder: "Projects/2024", Title: "Q1", Markdown: "Q1"},
+		{Folder: "Projects", Title: "Plan", Markdown: "# Plan"},
+	}
+	if !reflect.DeepEqual(v.Notes, wantNotes) {
+		t.Errorf("notes = %+v", v.Notes)
+	}
+}
+
+func TestReadVaultErrors(t *testing.T) {
+	if _, err := Read(bytes.NewReader([]byte("not a zip")), 9, "x.zip"); err != ErrInvalidArchive {
+		t.Errorf("err = %v, want ErrInvalidArchive", err)
+	}
+	r := buildZip(t, map[string]string{"a.txt": "no notes"})
+	if _, err := Read(r, r.Size(), "x.zip"); err != ErrNoNotes {
+		t.Errorf("err = %v, want ErrNoNotes", err)
+	}
+}
+
+func TestParseFrontMatterTags(t *testing.T) {
+	cases := []struct {
+		in       string
+		wantTags []string
+		wantBody string
+	}{
+		{"---\ntitle: x\ntags:\n  - a\n  - 'b/c'\nother: 1\n---\n\nBody", []string{"a", "b/c"}, "Body"},
+		{"---\ntags: one, two three\n---\nBody", []string{"one", "two", "three"}, "Body"},
+		{"---\ntag: A\ntags: [a, b]\n---\nBody", []string{"A", "b"}, "Body"},
+		{"No front matter\n---\n", nil, "No front matter\n---\n"},
+		{"---\nunterminated", nil, "---\nunterminated"},
+	}
+	for _, c := range cases {
+		tags, body := ParseFrontMatter(c.in)
+		if !reflect.DeepEqual(tags, c.wantTags) || body != c.wantBody {
+			t.Errorf("ParseFrontMatter(%q) = %v, %q", c.in, tags, body)
+		}
+	}
+}
+
+func TestNormalizeWikilinks(t *testing.T) {
+	in := "[[a/b/Note.md]] [[Note#Head]] [[Note#^block|alias]] ![[img.png]] [[doc.pdf|Spec]] [[#Local]] ![[Embedded]]"
+	want := "[[Note]] [[Note]] [[Note|alias]] img.png Spec Local [[Embedded]]"
+	if got := NormalizeWikilinks(in); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}